@@ -705,15 +705,68 @@ func (x *_Metadata_1_list) IsValid() bool {
 	return x.list != nil
 }
 
+var _ protoreflect.List = (*_Metadata_2_list)(nil)
+
+type _Metadata_2_list struct {
+	list *[]*SnapshotStoreManifest
+}
+
+func (x *_Metadata_2_list) Len() int {
+	if x.list == nil {
+		return 0
+	}
+	return len(*x.list)
+}
+
+func (x *_Metadata_2_list) Get(i int) protoreflect.Value {
+	return protoreflect.ValueOfMessage((*x.list)[i].ProtoReflect())
+}
+
+func (x *_Metadata_2_list) Set(i int, value protoreflect.Value) {
+	valueUnwrapped := value.Message()
+	concreteValue := valueUnwrapped.Interface().(*SnapshotStoreManifest)
+	(*x.list)[i] = concreteValue
+}
+
+func (x *_Metadata_2_list) Append(value protoreflect.Value) {
+	valueUnwrapped := value.Message()
+	concreteValue := valueUnwrapped.Interface().(*SnapshotStoreManifest)
+	*x.list = append(*x.list, concreteValue)
+}
+
+func (x *_Metadata_2_list) AppendMutable() protoreflect.Value {
+	v := new(SnapshotStoreManifest)
+	*x.list = append(*x.list, v)
+	return protoreflect.ValueOfMessage(v.ProtoReflect())
+}
+
+func (x *_Metadata_2_list) Truncate(n int) {
+	for i := n; i < len(*x.list); i++ {
+		(*x.list)[i] = nil
+	}
+	*x.list = (*x.list)[:n]
+}
+
+func (x *_Metadata_2_list) NewElement() protoreflect.Value {
+	v := new(SnapshotStoreManifest)
+	return protoreflect.ValueOfMessage(v.ProtoReflect())
+}
+
+func (x *_Metadata_2_list) IsValid() bool {
+	return x.list != nil
+}
+
 var (
 	md_Metadata              protoreflect.MessageDescriptor
 	fd_Metadata_chunk_hashes protoreflect.FieldDescriptor
+	fd_Metadata_stores       protoreflect.FieldDescriptor
 )
 
 func init() {
 	file_cosmos_store_snapshots_v2_snapshot_proto_init()
 	md_Metadata = File_cosmos_store_snapshots_v2_snapshot_proto.Messages().ByName("Metadata")
 	fd_Metadata_chunk_hashes = md_Metadata.Fields().ByName("chunk_hashes")
+	fd_Metadata_stores = md_Metadata.Fields().ByName("stores")
 }
 
 var _ protoreflect.Message = (*fastReflection_Metadata)(nil)
@@ -787,6 +840,12 @@ func (x *fastReflection_Metadata) Range(f func(protoreflect.FieldDescriptor, pro
 			return
 		}
 	}
+	if len(x.Stores) != 0 {
+		value := protoreflect.ValueOfList(&_Metadata_2_list{list: &x.Stores})
+		if !f(fd_Metadata_stores, value) {
+			return
+		}
+	}
 }
 
 // Has reports whether a field is populated.
@@ -804,6 +863,8 @@ func (x *fastReflection_Metadata) Has(fd protoreflect.FieldDescriptor) bool {
 	switch fd.FullName() {
 	case "cosmos.store.snapshots.v2.Metadata.chunk_hashes":
 		return len(x.ChunkHashes) != 0
+	case "cosmos.store.snapshots.v2.Metadata.stores":
+		return len(x.Stores) != 0
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: cosmos.store.snapshots.v2.Metadata"))
@@ -822,6 +883,8 @@ func (x *fastReflection_Metadata) Clear(fd protoreflect.FieldDescriptor) {
 	switch fd.FullName() {
 	case "cosmos.store.snapshots.v2.Metadata.chunk_hashes":
 		x.ChunkHashes = nil
+	case "cosmos.store.snapshots.v2.Metadata.stores":
+		x.Stores = nil
 	default:
 		if fd.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: cosmos.store.snapshots.v2.Metadata"))
@@ -844,6 +907,12 @@ func (x *fastReflection_Metadata) Get(descriptor protoreflect.FieldDescriptor) p
 		}
 		listValue := &_Metadata_1_list{list: &x.ChunkHashes}
 		return protoreflect.ValueOfList(listValue)
+	case "cosmos.store.snapshots.v2.Metadata.stores":
+		if len(x.Stores) == 0 {
+			return protoreflect.ValueOfList(&_Metadata_2_list{})
+		}
+		listValue := &_Metadata_2_list{list: &x.Stores}
+		return protoreflect.ValueOfList(listValue)
 	default:
 		if descriptor.IsExtension() {
 			panic(fmt.Errorf("proto3 declared messages do not support extensions: cosmos.store.snapshots.v2.Metadata"))
@@ -862,17 +931,566 @@ func (x *fastReflection_Metadata) Get(descriptor protoreflect.FieldDescriptor) p
 // empty, read-only value, then it panics.
 //
 // Set is a mutating operation and unsafe for concurrent use.
-func (x *fastReflection_Metadata) Set(fd protoreflect.FieldDescriptor, value protoreflect.Value) {
+func (x *fastReflection_Metadata) Set(fd protoreflect.FieldDescriptor, value protoreflect.Value) {
+	switch fd.FullName() {
+	case "cosmos.store.snapshots.v2.Metadata.chunk_hashes":
+		lv := value.List()
+		clv := lv.(*_Metadata_1_list)
+		x.ChunkHashes = *clv.list
+	case "cosmos.store.snapshots.v2.Metadata.stores":
+		lv := value.List()
+		clv := lv.(*_Metadata_2_list)
+		x.Stores = *clv.list
+	default:
+		if fd.IsExtension() {
+			panic(fmt.Errorf("proto3 declared messages do not support extensions: cosmos.store.snapshots.v2.Metadata"))
+		}
+		panic(fmt.Errorf("message cosmos.store.snapshots.v2.Metadata does not contain field %s", fd.FullName()))
+	}
+}
+
+// Mutable returns a mutable reference to a composite type.
+//
+// If the field is unpopulated, it may allocate a composite value.
+// For a field belonging to a oneof, it implicitly clears any other field
+// that may be currently set within the same oneof.
+// For extension fields, it implicitly stores the provided ExtensionType
+// if not already stored.
+// It panics if the field does not contain a composite type.
+//
+// Mutable is a mutating operation and unsafe for concurrent use.
+func (x *fastReflection_Metadata) Mutable(fd protoreflect.FieldDescriptor) protoreflect.Value {
+	switch fd.FullName() {
+	case "cosmos.store.snapshots.v2.Metadata.chunk_hashes":
+		if x.ChunkHashes == nil {
+			x.ChunkHashes = [][]byte{}
+		}
+		value := &_Metadata_1_list{list: &x.ChunkHashes}
+		return protoreflect.ValueOfList(value)
+	case "cosmos.store.snapshots.v2.Metadata.stores":
+		if x.Stores == nil {
+			x.Stores = []*SnapshotStoreManifest{}
+		}
+		value := &_Metadata_2_list{list: &x.Stores}
+		return protoreflect.ValueOfList(value)
+	default:
+		if fd.IsExtension() {
+			panic(fmt.Errorf("proto3 declared messages do not support extensions: cosmos.store.snapshots.v2.Metadata"))
+		}
+		panic(fmt.Errorf("message cosmos.store.snapshots.v2.Metadata does not contain field %s", fd.FullName()))
+	}
+}
+
+// NewField returns a new value that is assignable to the field
+// for the given descriptor. For scalars, this returns the default value.
+// For lists, maps, and messages, this returns a new, empty, mutable value.
+func (x *fastReflection_Metadata) NewField(fd protoreflect.FieldDescriptor) protoreflect.Value {
+	switch fd.FullName() {
+	case "cosmos.store.snapshots.v2.Metadata.chunk_hashes":
+		list := [][]byte{}
+		return protoreflect.ValueOfList(&_Metadata_1_list{list: &list})
+	case "cosmos.store.snapshots.v2.Metadata.stores":
+		list := []*SnapshotStoreManifest{}
+		return protoreflect.ValueOfList(&_Metadata_2_list{list: &list})
+	default:
+		if fd.IsExtension() {
+			panic(fmt.Errorf("proto3 declared messages do not support extensions: cosmos.store.snapshots.v2.Metadata"))
+		}
+		panic(fmt.Errorf("message cosmos.store.snapshots.v2.Metadata does not contain field %s", fd.FullName()))
+	}
+}
+
+// WhichOneof reports which field within the oneof is populated,
+// returning nil if none are populated.
+// It panics if the oneof descriptor does not belong to this message.
+func (x *fastReflection_Metadata) WhichOneof(d protoreflect.OneofDescriptor) protoreflect.FieldDescriptor {
+	switch d.FullName() {
+	default:
+		panic(fmt.Errorf("%s is not a oneof field in cosmos.store.snapshots.v2.Metadata", d.FullName()))
+	}
+	panic("unreachable")
+}
+
+// GetUnknown retrieves the entire list of unknown fields.
+// The caller may only mutate the contents of the RawFields
+// if the mutated bytes are stored back into the message with SetUnknown.
+func (x *fastReflection_Metadata) GetUnknown() protoreflect.RawFields {
+	return x.unknownFields
+}
+
+// SetUnknown stores an entire list of unknown fields.
+// The raw fields must be syntactically valid according to the wire format.
+// An implementation may panic if this is not the case.
+// Once stored, the caller must not mutate the content of the RawFields.
+// An empty RawFields may be passed to clear the fields.
+//
+// SetUnknown is a mutating operation and unsafe for concurrent use.
+func (x *fastReflection_Metadata) SetUnknown(fields protoreflect.RawFields) {
+	x.unknownFields = fields
+}
+
+// IsValid reports whether the message is valid.
+//
+// An invalid message is an empty, read-only value.
+//
+// An invalid message often corresponds to a nil pointer of the concrete
+// message type, but the details are implementation dependent.
+// Validity is not part of the protobuf data model, and may not
+// be preserved in marshaling or other operations.
+func (x *fastReflection_Metadata) IsValid() bool {
+	return x != nil
+}
+
+// ProtoMethods returns optional fastReflectionFeature-path implementations of various operations.
+// This method may return nil.
+//
+// The returned methods type is identical to
+// "google.golang.org/protobuf/runtime/protoiface".Methods.
+// Consult the protoiface package documentation for details.
+func (x *fastReflection_Metadata) ProtoMethods() *protoiface.Methods {
+	size := func(input protoiface.SizeInput) protoiface.SizeOutput {
+		x := input.Message.Interface().(*Metadata)
+		if x == nil {
+			return protoiface.SizeOutput{
+				NoUnkeyedLiterals: input.NoUnkeyedLiterals,
+				Size:              0,
+			}
+		}
+		options := runtime.SizeInputToOptions(input)
+		_ = options
+		var n int
+		var l int
+		_ = l
+		if len(x.ChunkHashes) > 0 {
+			for _, b := range x.ChunkHashes {
+				l = len(b)
+				n += 1 + l + runtime.Sov(uint64(l))
+			}
+		}
+		if len(x.Stores) > 0 {
+			for _, e := range x.Stores {
+				l = options.Size(e)
+				n += 1 + l + runtime.Sov(uint64(l))
+			}
+		}
+		if x.unknownFields != nil {
+			n += len(x.unknownFields)
+		}
+		return protoiface.SizeOutput{
+			NoUnkeyedLiterals: input.NoUnkeyedLiterals,
+			Size:              n,
+		}
+	}
+
+	marshal := func(input protoiface.MarshalInput) (protoiface.MarshalOutput, error) {
+		x := input.Message.Interface().(*Metadata)
+		if x == nil {
+			return protoiface.MarshalOutput{
+				NoUnkeyedLiterals: input.NoUnkeyedLiterals,
+				Buf:               input.Buf,
+			}, nil
+		}
+		options := runtime.MarshalInputToOptions(input)
+		_ = options
+		size := options.Size(x)
+		dAtA := make([]byte, size)
+		i := len(dAtA)
+		_ = i
+		var l int
+		_ = l
+		if x.unknownFields != nil {
+			i -= len(x.unknownFields)
+			copy(dAtA[i:], x.unknownFields)
+		}
+		if len(x.Stores) > 0 {
+			for iNdEx := len(x.Stores) - 1; iNdEx >= 0; iNdEx-- {
+				encoded, err := options.Marshal(x.Stores[iNdEx])
+				if err != nil {
+					return protoiface.MarshalOutput{
+						NoUnkeyedLiterals: input.NoUnkeyedLiterals,
+						Buf:               input.Buf,
+					}, err
+				}
+				i -= len(encoded)
+				copy(dAtA[i:], encoded)
+				i = runtime.EncodeVarint(dAtA, i, uint64(len(encoded)))
+				i--
+				dAtA[i] = 0x12
+			}
+		}
+		if len(x.ChunkHashes) > 0 {
+			for iNdEx := len(x.ChunkHashes) - 1; iNdEx >= 0; iNdEx-- {
+				i -= len(x.ChunkHashes[iNdEx])
+				copy(dAtA[i:], x.ChunkHashes[iNdEx])
+				i = runtime.EncodeVarint(dAtA, i, uint64(len(x.ChunkHashes[iNdEx])))
+				i--
+				dAtA[i] = 0xa
+			}
+		}
+		if input.Buf != nil {
+			input.Buf = append(input.Buf, dAtA...)
+		} else {
+			input.Buf = dAtA
+		}
+		return protoiface.MarshalOutput{
+			NoUnkeyedLiterals: input.NoUnkeyedLiterals,
+			Buf:               input.Buf,
+		}, nil
+	}
+	unmarshal := func(input protoiface.UnmarshalInput) (protoiface.UnmarshalOutput, error) {
+		x := input.Message.Interface().(*Metadata)
+		if x == nil {
+			return protoiface.UnmarshalOutput{
+				NoUnkeyedLiterals: input.NoUnkeyedLiterals,
+				Flags:             input.Flags,
+			}, nil
+		}
+		options := runtime.UnmarshalInputToOptions(input)
+		_ = options
+		dAtA := input.Buf
+		l := len(dAtA)
+		iNdEx := 0
+		for iNdEx < l {
+			preIndex := iNdEx
+			var wire uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrIntOverflow
+				}
+				if iNdEx >= l {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				wire |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			fieldNum := int32(wire >> 3)
+			wireType := int(wire & 0x7)
+			if wireType == 4 {
+				return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: Metadata: wiretype end group for non-group")
+			}
+			if fieldNum <= 0 {
+				return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: Metadata: illegal tag %d (wire type %d)", fieldNum, wire)
+			}
+			switch fieldNum {
+			case 1:
+				if wireType != 2 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: wrong wireType = %d for field ChunkHashes", wireType)
+				}
+				var byteLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrIntOverflow
+					}
+					if iNdEx >= l {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					byteLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if byteLen < 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrInvalidLength
+				}
+				postIndex := iNdEx + byteLen
+				if postIndex < 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrInvalidLength
+				}
+				if postIndex > l {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+				}
+				x.ChunkHashes = append(x.ChunkHashes, make([]byte, postIndex-iNdEx))
+				copy(x.ChunkHashes[len(x.ChunkHashes)-1], dAtA[iNdEx:postIndex])
+				iNdEx = postIndex
+			case 2:
+				if wireType != 2 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: wrong wireType = %d for field Stores", wireType)
+				}
+				var msglen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrIntOverflow
+					}
+					if iNdEx >= l {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					msglen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if msglen < 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrInvalidLength
+				}
+				postIndex := iNdEx + msglen
+				if postIndex < 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrInvalidLength
+				}
+				if postIndex > l {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+				}
+				x.Stores = append(x.Stores, &SnapshotStoreManifest{})
+				if err := options.Unmarshal(dAtA[iNdEx:postIndex], x.Stores[len(x.Stores)-1]); err != nil {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, err
+				}
+				iNdEx = postIndex
+			default:
+				iNdEx = preIndex
+				skippy, err := runtime.Skip(dAtA[iNdEx:])
+				if err != nil {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, err
+				}
+				if (skippy < 0) || (iNdEx+skippy) < 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrInvalidLength
+				}
+				if (iNdEx + skippy) > l {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+				}
+				if !options.DiscardUnknown {
+					x.unknownFields = append(x.unknownFields, dAtA[iNdEx:iNdEx+skippy]...)
+				}
+				iNdEx += skippy
+			}
+		}
+
+		if iNdEx > l {
+			return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+		}
+		return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, nil
+	}
+	return &protoiface.Methods{
+		NoUnkeyedLiterals: struct{}{},
+		Flags:             protoiface.SupportMarshalDeterministic | protoiface.SupportUnmarshalDiscardUnknown,
+		Size:              size,
+		Marshal:           marshal,
+		Unmarshal:         unmarshal,
+		Merge:             nil,
+		CheckInitialized:  nil,
+	}
+}
+
+var (
+	md_SnapshotStoreManifest             protoreflect.MessageDescriptor
+	fd_SnapshotStoreManifest_name        protoreflect.FieldDescriptor
+	fd_SnapshotStoreManifest_first_chunk protoreflect.FieldDescriptor
+	fd_SnapshotStoreManifest_chunks      protoreflect.FieldDescriptor
+	fd_SnapshotStoreManifest_hash        protoreflect.FieldDescriptor
+)
+
+func init() {
+	file_cosmos_store_snapshots_v2_snapshot_proto_init()
+	md_SnapshotStoreManifest = File_cosmos_store_snapshots_v2_snapshot_proto.Messages().ByName("SnapshotStoreManifest")
+	fd_SnapshotStoreManifest_name = md_SnapshotStoreManifest.Fields().ByName("name")
+	fd_SnapshotStoreManifest_first_chunk = md_SnapshotStoreManifest.Fields().ByName("first_chunk")
+	fd_SnapshotStoreManifest_chunks = md_SnapshotStoreManifest.Fields().ByName("chunks")
+	fd_SnapshotStoreManifest_hash = md_SnapshotStoreManifest.Fields().ByName("hash")
+}
+
+var _ protoreflect.Message = (*fastReflection_SnapshotStoreManifest)(nil)
+
+type fastReflection_SnapshotStoreManifest SnapshotStoreManifest
+
+func (x *SnapshotStoreManifest) ProtoReflect() protoreflect.Message {
+	return (*fastReflection_SnapshotStoreManifest)(x)
+}
+
+func (x *SnapshotStoreManifest) slowProtoReflect() protoreflect.Message {
+	mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+var _fastReflection_SnapshotStoreManifest_messageType fastReflection_SnapshotStoreManifest_messageType
+var _ protoreflect.MessageType = fastReflection_SnapshotStoreManifest_messageType{}
+
+type fastReflection_SnapshotStoreManifest_messageType struct{}
+
+func (x fastReflection_SnapshotStoreManifest_messageType) Zero() protoreflect.Message {
+	return (*fastReflection_SnapshotStoreManifest)(nil)
+}
+func (x fastReflection_SnapshotStoreManifest_messageType) New() protoreflect.Message {
+	return new(fastReflection_SnapshotStoreManifest)
+}
+func (x fastReflection_SnapshotStoreManifest_messageType) Descriptor() protoreflect.MessageDescriptor {
+	return md_SnapshotStoreManifest
+}
+
+// Descriptor returns message descriptor, which contains only the protobuf
+// type information for the message.
+func (x *fastReflection_SnapshotStoreManifest) Descriptor() protoreflect.MessageDescriptor {
+	return md_SnapshotStoreManifest
+}
+
+// Type returns the message type, which encapsulates both Go and protobuf
+// type information. If the Go type information is not needed,
+// it is recommended that the message descriptor be used instead.
+func (x *fastReflection_SnapshotStoreManifest) Type() protoreflect.MessageType {
+	return _fastReflection_SnapshotStoreManifest_messageType
+}
+
+// New returns a newly allocated and mutable empty message.
+func (x *fastReflection_SnapshotStoreManifest) New() protoreflect.Message {
+	return new(fastReflection_SnapshotStoreManifest)
+}
+
+// Interface unwraps the message reflection interface and
+// returns the underlying ProtoMessage interface.
+func (x *fastReflection_SnapshotStoreManifest) Interface() protoreflect.ProtoMessage {
+	return (*SnapshotStoreManifest)(x)
+}
+
+// Range iterates over every populated field in an undefined order,
+// calling f for each field descriptor and value encountered.
+// Range returns immediately if f returns false.
+// While iterating, mutating operations may only be performed
+// on the current field descriptor.
+func (x *fastReflection_SnapshotStoreManifest) Range(f func(protoreflect.FieldDescriptor, protoreflect.Value) bool) {
+	if x.Name != "" {
+		value := protoreflect.ValueOfString(x.Name)
+		if !f(fd_SnapshotStoreManifest_name, value) {
+			return
+		}
+	}
+	if x.FirstChunk != uint32(0) {
+		value := protoreflect.ValueOfUint32(x.FirstChunk)
+		if !f(fd_SnapshotStoreManifest_first_chunk, value) {
+			return
+		}
+	}
+	if x.Chunks != uint32(0) {
+		value := protoreflect.ValueOfUint32(x.Chunks)
+		if !f(fd_SnapshotStoreManifest_chunks, value) {
+			return
+		}
+	}
+	if len(x.Hash) != 0 {
+		value := protoreflect.ValueOfBytes(x.Hash)
+		if !f(fd_SnapshotStoreManifest_hash, value) {
+			return
+		}
+	}
+}
+
+// Has reports whether a field is populated.
+//
+// Some fields have the property of nullability where it is possible to
+// distinguish between the default value of a field and whether the field
+// was explicitly populated with the default value. Singular message fields,
+// member fields of a oneof, and proto2 scalar fields are nullable. Such
+// fields are populated only if explicitly set.
+//
+// In other cases (aside from the nullable cases above),
+// a proto3 scalar field is populated if it contains a non-zero value, and
+// a repeated field is populated if it is non-empty.
+func (x *fastReflection_SnapshotStoreManifest) Has(fd protoreflect.FieldDescriptor) bool {
+	switch fd.FullName() {
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.name":
+		return x.Name != ""
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.first_chunk":
+		return x.FirstChunk != uint32(0)
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.chunks":
+		return x.Chunks != uint32(0)
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.hash":
+		return len(x.Hash) != 0
+	default:
+		if fd.IsExtension() {
+			panic(fmt.Errorf("proto3 declared messages do not support extensions: cosmos.store.snapshots.v2.SnapshotStoreManifest"))
+		}
+		panic(fmt.Errorf("message cosmos.store.snapshots.v2.SnapshotStoreManifest does not contain field %s", fd.FullName()))
+	}
+}
+
+// Clear clears the field such that a subsequent Has call reports false.
+//
+// Clearing an extension field clears both the extension type and value
+// associated with the given field number.
+//
+// Clear is a mutating operation and unsafe for concurrent use.
+func (x *fastReflection_SnapshotStoreManifest) Clear(fd protoreflect.FieldDescriptor) {
+	switch fd.FullName() {
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.name":
+		x.Name = ""
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.first_chunk":
+		x.FirstChunk = uint32(0)
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.chunks":
+		x.Chunks = uint32(0)
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.hash":
+		x.Hash = nil
+	default:
+		if fd.IsExtension() {
+			panic(fmt.Errorf("proto3 declared messages do not support extensions: cosmos.store.snapshots.v2.SnapshotStoreManifest"))
+		}
+		panic(fmt.Errorf("message cosmos.store.snapshots.v2.SnapshotStoreManifest does not contain field %s", fd.FullName()))
+	}
+}
+
+// Get retrieves the value for a field.
+//
+// For unpopulated scalars, it returns the default value, where
+// the default value of a bytes scalar is guaranteed to be a copy.
+// For unpopulated composite types, it returns an empty, read-only view
+// of the value; to obtain a mutable reference, use Mutable.
+func (x *fastReflection_SnapshotStoreManifest) Get(descriptor protoreflect.FieldDescriptor) protoreflect.Value {
+	switch descriptor.FullName() {
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.name":
+		value := x.Name
+		return protoreflect.ValueOfString(value)
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.first_chunk":
+		value := x.FirstChunk
+		return protoreflect.ValueOfUint32(value)
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.chunks":
+		value := x.Chunks
+		return protoreflect.ValueOfUint32(value)
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.hash":
+		value := x.Hash
+		return protoreflect.ValueOfBytes(value)
+	default:
+		if descriptor.IsExtension() {
+			panic(fmt.Errorf("proto3 declared messages do not support extensions: cosmos.store.snapshots.v2.SnapshotStoreManifest"))
+		}
+		panic(fmt.Errorf("message cosmos.store.snapshots.v2.SnapshotStoreManifest does not contain field %s", descriptor.FullName()))
+	}
+}
+
+// Set stores the value for a field.
+//
+// For a field belonging to a oneof, it implicitly clears any other field
+// that may be currently set within the same oneof.
+// For extension fields, it implicitly stores the provided ExtensionType.
+// When setting a composite type, it is unspecified whether the stored value
+// aliases the source's memory in any way. If the composite value is an
+// empty, read-only value, then it panics.
+//
+// Set is a mutating operation and unsafe for concurrent use.
+func (x *fastReflection_SnapshotStoreManifest) Set(fd protoreflect.FieldDescriptor, value protoreflect.Value) {
 	switch fd.FullName() {
-	case "cosmos.store.snapshots.v2.Metadata.chunk_hashes":
-		lv := value.List()
-		clv := lv.(*_Metadata_1_list)
-		x.ChunkHashes = *clv.list
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.name":
+		x.Name = value.Interface().(string)
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.first_chunk":
+		x.FirstChunk = uint32(value.Uint())
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.chunks":
+		x.Chunks = uint32(value.Uint())
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.hash":
+		x.Hash = value.Bytes()
 	default:
 		if fd.IsExtension() {
-			panic(fmt.Errorf("proto3 declared messages do not support extensions: cosmos.store.snapshots.v2.Metadata"))
+			panic(fmt.Errorf("proto3 declared messages do not support extensions: cosmos.store.snapshots.v2.SnapshotStoreManifest"))
 		}
-		panic(fmt.Errorf("message cosmos.store.snapshots.v2.Metadata does not contain field %s", fd.FullName()))
+		panic(fmt.Errorf("message cosmos.store.snapshots.v2.SnapshotStoreManifest does not contain field %s", fd.FullName()))
 	}
 }
 
@@ -886,45 +1504,52 @@ func (x *fastReflection_Metadata) Set(fd protoreflect.FieldDescriptor, value pro
 // It panics if the field does not contain a composite type.
 //
 // Mutable is a mutating operation and unsafe for concurrent use.
-func (x *fastReflection_Metadata) Mutable(fd protoreflect.FieldDescriptor) protoreflect.Value {
+func (x *fastReflection_SnapshotStoreManifest) Mutable(fd protoreflect.FieldDescriptor) protoreflect.Value {
 	switch fd.FullName() {
-	case "cosmos.store.snapshots.v2.Metadata.chunk_hashes":
-		if x.ChunkHashes == nil {
-			x.ChunkHashes = [][]byte{}
-		}
-		value := &_Metadata_1_list{list: &x.ChunkHashes}
-		return protoreflect.ValueOfList(value)
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.name":
+		panic(fmt.Errorf("field name of message cosmos.store.snapshots.v2.SnapshotStoreManifest is not mutable"))
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.first_chunk":
+		panic(fmt.Errorf("field first_chunk of message cosmos.store.snapshots.v2.SnapshotStoreManifest is not mutable"))
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.chunks":
+		panic(fmt.Errorf("field chunks of message cosmos.store.snapshots.v2.SnapshotStoreManifest is not mutable"))
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.hash":
+		panic(fmt.Errorf("field hash of message cosmos.store.snapshots.v2.SnapshotStoreManifest is not mutable"))
 	default:
 		if fd.IsExtension() {
-			panic(fmt.Errorf("proto3 declared messages do not support extensions: cosmos.store.snapshots.v2.Metadata"))
+			panic(fmt.Errorf("proto3 declared messages do not support extensions: cosmos.store.snapshots.v2.SnapshotStoreManifest"))
 		}
-		panic(fmt.Errorf("message cosmos.store.snapshots.v2.Metadata does not contain field %s", fd.FullName()))
+		panic(fmt.Errorf("message cosmos.store.snapshots.v2.SnapshotStoreManifest does not contain field %s", fd.FullName()))
 	}
 }
 
 // NewField returns a new value that is assignable to the field
 // for the given descriptor. For scalars, this returns the default value.
 // For lists, maps, and messages, this returns a new, empty, mutable value.
-func (x *fastReflection_Metadata) NewField(fd protoreflect.FieldDescriptor) protoreflect.Value {
+func (x *fastReflection_SnapshotStoreManifest) NewField(fd protoreflect.FieldDescriptor) protoreflect.Value {
 	switch fd.FullName() {
-	case "cosmos.store.snapshots.v2.Metadata.chunk_hashes":
-		list := [][]byte{}
-		return protoreflect.ValueOfList(&_Metadata_1_list{list: &list})
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.name":
+		return protoreflect.ValueOfString("")
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.first_chunk":
+		return protoreflect.ValueOfUint32(uint32(0))
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.chunks":
+		return protoreflect.ValueOfUint32(uint32(0))
+	case "cosmos.store.snapshots.v2.SnapshotStoreManifest.hash":
+		return protoreflect.ValueOfBytes(nil)
 	default:
 		if fd.IsExtension() {
-			panic(fmt.Errorf("proto3 declared messages do not support extensions: cosmos.store.snapshots.v2.Metadata"))
+			panic(fmt.Errorf("proto3 declared messages do not support extensions: cosmos.store.snapshots.v2.SnapshotStoreManifest"))
 		}
-		panic(fmt.Errorf("message cosmos.store.snapshots.v2.Metadata does not contain field %s", fd.FullName()))
+		panic(fmt.Errorf("message cosmos.store.snapshots.v2.SnapshotStoreManifest does not contain field %s", fd.FullName()))
 	}
 }
 
 // WhichOneof reports which field within the oneof is populated,
 // returning nil if none are populated.
 // It panics if the oneof descriptor does not belong to this message.
-func (x *fastReflection_Metadata) WhichOneof(d protoreflect.OneofDescriptor) protoreflect.FieldDescriptor {
+func (x *fastReflection_SnapshotStoreManifest) WhichOneof(d protoreflect.OneofDescriptor) protoreflect.FieldDescriptor {
 	switch d.FullName() {
 	default:
-		panic(fmt.Errorf("%s is not a oneof field in cosmos.store.snapshots.v2.Metadata", d.FullName()))
+		panic(fmt.Errorf("%s is not a oneof field in cosmos.store.snapshots.v2.SnapshotStoreManifest", d.FullName()))
 	}
 	panic("unreachable")
 }
@@ -932,7 +1557,7 @@ func (x *fastReflection_Metadata) WhichOneof(d protoreflect.OneofDescriptor) pro
 // GetUnknown retrieves the entire list of unknown fields.
 // The caller may only mutate the contents of the RawFields
 // if the mutated bytes are stored back into the message with SetUnknown.
-func (x *fastReflection_Metadata) GetUnknown() protoreflect.RawFields {
+func (x *fastReflection_SnapshotStoreManifest) GetUnknown() protoreflect.RawFields {
 	return x.unknownFields
 }
 
@@ -943,7 +1568,7 @@ func (x *fastReflection_Metadata) GetUnknown() protoreflect.RawFields {
 // An empty RawFields may be passed to clear the fields.
 //
 // SetUnknown is a mutating operation and unsafe for concurrent use.
-func (x *fastReflection_Metadata) SetUnknown(fields protoreflect.RawFields) {
+func (x *fastReflection_SnapshotStoreManifest) SetUnknown(fields protoreflect.RawFields) {
 	x.unknownFields = fields
 }
 
@@ -955,7 +1580,7 @@ func (x *fastReflection_Metadata) SetUnknown(fields protoreflect.RawFields) {
 // message type, but the details are implementation dependent.
 // Validity is not part of the protobuf data model, and may not
 // be preserved in marshaling or other operations.
-func (x *fastReflection_Metadata) IsValid() bool {
+func (x *fastReflection_SnapshotStoreManifest) IsValid() bool {
 	return x != nil
 }
 
@@ -965,9 +1590,9 @@ func (x *fastReflection_Metadata) IsValid() bool {
 // The returned methods type is identical to
 // "google.golang.org/protobuf/runtime/protoiface".Methods.
 // Consult the protoiface package documentation for details.
-func (x *fastReflection_Metadata) ProtoMethods() *protoiface.Methods {
+func (x *fastReflection_SnapshotStoreManifest) ProtoMethods() *protoiface.Methods {
 	size := func(input protoiface.SizeInput) protoiface.SizeOutput {
-		x := input.Message.Interface().(*Metadata)
+		x := input.Message.Interface().(*SnapshotStoreManifest)
 		if x == nil {
 			return protoiface.SizeOutput{
 				NoUnkeyedLiterals: input.NoUnkeyedLiterals,
@@ -979,11 +1604,19 @@ func (x *fastReflection_Metadata) ProtoMethods() *protoiface.Methods {
 		var n int
 		var l int
 		_ = l
-		if len(x.ChunkHashes) > 0 {
-			for _, b := range x.ChunkHashes {
-				l = len(b)
-				n += 1 + l + runtime.Sov(uint64(l))
-			}
+		l = len(x.Name)
+		if l > 0 {
+			n += 1 + l + runtime.Sov(uint64(l))
+		}
+		if x.FirstChunk != 0 {
+			n += 1 + runtime.Sov(uint64(x.FirstChunk))
+		}
+		if x.Chunks != 0 {
+			n += 1 + runtime.Sov(uint64(x.Chunks))
+		}
+		l = len(x.Hash)
+		if l > 0 {
+			n += 1 + l + runtime.Sov(uint64(l))
 		}
 		if x.unknownFields != nil {
 			n += len(x.unknownFields)
@@ -995,7 +1628,7 @@ func (x *fastReflection_Metadata) ProtoMethods() *protoiface.Methods {
 	}
 
 	marshal := func(input protoiface.MarshalInput) (protoiface.MarshalOutput, error) {
-		x := input.Message.Interface().(*Metadata)
+		x := input.Message.Interface().(*SnapshotStoreManifest)
 		if x == nil {
 			return protoiface.MarshalOutput{
 				NoUnkeyedLiterals: input.NoUnkeyedLiterals,
@@ -1014,14 +1647,29 @@ func (x *fastReflection_Metadata) ProtoMethods() *protoiface.Methods {
 			i -= len(x.unknownFields)
 			copy(dAtA[i:], x.unknownFields)
 		}
-		if len(x.ChunkHashes) > 0 {
-			for iNdEx := len(x.ChunkHashes) - 1; iNdEx >= 0; iNdEx-- {
-				i -= len(x.ChunkHashes[iNdEx])
-				copy(dAtA[i:], x.ChunkHashes[iNdEx])
-				i = runtime.EncodeVarint(dAtA, i, uint64(len(x.ChunkHashes[iNdEx])))
-				i--
-				dAtA[i] = 0xa
-			}
+		if len(x.Hash) > 0 {
+			i -= len(x.Hash)
+			copy(dAtA[i:], x.Hash)
+			i = runtime.EncodeVarint(dAtA, i, uint64(len(x.Hash)))
+			i--
+			dAtA[i] = 0x22
+		}
+		if x.Chunks != 0 {
+			i = runtime.EncodeVarint(dAtA, i, uint64(x.Chunks))
+			i--
+			dAtA[i] = 0x18
+		}
+		if x.FirstChunk != 0 {
+			i = runtime.EncodeVarint(dAtA, i, uint64(x.FirstChunk))
+			i--
+			dAtA[i] = 0x10
+		}
+		if len(x.Name) > 0 {
+			i -= len(x.Name)
+			copy(dAtA[i:], x.Name)
+			i = runtime.EncodeVarint(dAtA, i, uint64(len(x.Name)))
+			i--
+			dAtA[i] = 0xa
 		}
 		if input.Buf != nil {
 			input.Buf = append(input.Buf, dAtA...)
@@ -1034,7 +1682,7 @@ func (x *fastReflection_Metadata) ProtoMethods() *protoiface.Methods {
 		}, nil
 	}
 	unmarshal := func(input protoiface.UnmarshalInput) (protoiface.UnmarshalOutput, error) {
-		x := input.Message.Interface().(*Metadata)
+		x := input.Message.Interface().(*SnapshotStoreManifest)
 		if x == nil {
 			return protoiface.UnmarshalOutput{
 				NoUnkeyedLiterals: input.NoUnkeyedLiterals,
@@ -1066,15 +1714,85 @@ func (x *fastReflection_Metadata) ProtoMethods() *protoiface.Methods {
 			fieldNum := int32(wire >> 3)
 			wireType := int(wire & 0x7)
 			if wireType == 4 {
-				return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: Metadata: wiretype end group for non-group")
+				return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: SnapshotStoreManifest: wiretype end group for non-group")
 			}
 			if fieldNum <= 0 {
-				return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: Metadata: illegal tag %d (wire type %d)", fieldNum, wire)
+				return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: SnapshotStoreManifest: illegal tag %d (wire type %d)", fieldNum, wire)
 			}
 			switch fieldNum {
 			case 1:
 				if wireType != 2 {
-					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: wrong wireType = %d for field ChunkHashes", wireType)
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				}
+				var stringLen uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrIntOverflow
+					}
+					if iNdEx >= l {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					stringLen |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				intStringLen := int(stringLen)
+				if intStringLen < 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrInvalidLength
+				}
+				postIndex := iNdEx + intStringLen
+				if postIndex < 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrInvalidLength
+				}
+				if postIndex > l {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+				}
+				x.Name = string(dAtA[iNdEx:postIndex])
+				iNdEx = postIndex
+			case 2:
+				if wireType != 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: wrong wireType = %d for field FirstChunk", wireType)
+				}
+				x.FirstChunk = 0
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrIntOverflow
+					}
+					if iNdEx >= l {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					x.FirstChunk |= uint32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+			case 3:
+				if wireType != 0 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: wrong wireType = %d for field Chunks", wireType)
+				}
+				x.Chunks = 0
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, runtime.ErrIntOverflow
+					}
+					if iNdEx >= l {
+						return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					x.Chunks |= uint32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+			case 4:
+				if wireType != 2 {
+					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
 				}
 				var byteLen int
 				for shift := uint(0); ; shift += 7 {
@@ -1101,8 +1819,10 @@ func (x *fastReflection_Metadata) ProtoMethods() *protoiface.Methods {
 				if postIndex > l {
 					return protoiface.UnmarshalOutput{NoUnkeyedLiterals: input.NoUnkeyedLiterals, Flags: input.Flags}, io.ErrUnexpectedEOF
 				}
-				x.ChunkHashes = append(x.ChunkHashes, make([]byte, postIndex-iNdEx))
-				copy(x.ChunkHashes[len(x.ChunkHashes)-1], dAtA[iNdEx:postIndex])
+				x.Hash = append(x.Hash[:0], dAtA[iNdEx:postIndex]...)
+				if x.Hash == nil {
+					x.Hash = []byte{}
+				}
 				iNdEx = postIndex
 			default:
 				iNdEx = preIndex
@@ -1165,7 +1885,7 @@ func (x *SnapshotItem) ProtoReflect() protoreflect.Message {
 }
 
 func (x *SnapshotItem) slowProtoReflect() protoreflect.Message {
-	mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[2]
+	mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1945,7 +2665,7 @@ func (x *SnapshotStoreItem) ProtoReflect() protoreflect.Message {
 }
 
 func (x *SnapshotStoreItem) slowProtoReflect() protoreflect.Message {
-	mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[3]
+	mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2371,7 +3091,7 @@ func (x *SnapshotIAVLItem) ProtoReflect() protoreflect.Message {
 }
 
 func (x *SnapshotIAVLItem) slowProtoReflect() protoreflect.Message {
-	mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[4]
+	mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2951,7 +3671,7 @@ func (x *SnapshotExtensionMeta) ProtoReflect() protoreflect.Message {
 }
 
 func (x *SnapshotExtensionMeta) slowProtoReflect() protoreflect.Message {
-	mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[5]
+	mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3417,7 +4137,7 @@ func (x *SnapshotExtensionPayload) ProtoReflect() protoreflect.Message {
 }
 
 func (x *SnapshotExtensionPayload) slowProtoReflect() protoreflect.Message {
-	mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[6]
+	mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3907,6 +4627,9 @@ type Metadata struct {
 	unknownFields protoimpl.UnknownFields
 
 	ChunkHashes [][]byte `protobuf:"bytes,1,rep,name=chunk_hashes,json=chunkHashes,proto3" json:"chunk_hashes,omitempty"` // SHA-256 chunk hashes
+	// stores lists the chunks of every store, it's only set by the snapshots using
+	// the format 4, in which the items of every store are written to their own chunks.
+	Stores []*SnapshotStoreManifest `protobuf:"bytes,2,rep,name=stores,proto3" json:"stores,omitempty"`
 }
 
 func (x *Metadata) Reset() {
@@ -3936,6 +4659,73 @@ func (x *Metadata) GetChunkHashes() [][]byte {
 	return nil
 }
 
+func (x *Metadata) GetStores() []*SnapshotStoreManifest {
+	if x != nil {
+		return x.Stores
+	}
+	return nil
+}
+
+// SnapshotStoreManifest describes the chunks containing the items of a store.
+type SnapshotStoreManifest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name       string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	FirstChunk uint32 `protobuf:"varint,2,opt,name=first_chunk,json=firstChunk,proto3" json:"first_chunk,omitempty"`
+	Chunks     uint32 `protobuf:"varint,3,opt,name=chunks,proto3" json:"chunks,omitempty"`
+	Hash       []byte `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"` // SHA-256 hash of the chunk hashes of the store
+}
+
+func (x *SnapshotStoreManifest) Reset() {
+	*x = SnapshotStoreManifest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SnapshotStoreManifest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotStoreManifest) ProtoMessage() {}
+
+// Deprecated: Use SnapshotStoreManifest.ProtoReflect.Descriptor instead.
+func (*SnapshotStoreManifest) Descriptor() ([]byte, []int) {
+	return file_cosmos_store_snapshots_v2_snapshot_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SnapshotStoreManifest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SnapshotStoreManifest) GetFirstChunk() uint32 {
+	if x != nil {
+		return x.FirstChunk
+	}
+	return 0
+}
+
+func (x *SnapshotStoreManifest) GetChunks() uint32 {
+	if x != nil {
+		return x.Chunks
+	}
+	return 0
+}
+
+func (x *SnapshotStoreManifest) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
 // SnapshotItem is an item contained in a rootmulti.Store snapshot.
 type SnapshotItem struct {
 	state         protoimpl.MessageState
@@ -3956,7 +4746,7 @@ type SnapshotItem struct {
 func (x *SnapshotItem) Reset() {
 	*x = SnapshotItem{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[2]
+		mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -3970,7 +4760,7 @@ func (*SnapshotItem) ProtoMessage() {}
 
 // Deprecated: Use SnapshotItem.ProtoReflect.Descriptor instead.
 func (*SnapshotItem) Descriptor() ([]byte, []int) {
-	return file_cosmos_store_snapshots_v2_snapshot_proto_rawDescGZIP(), []int{2}
+	return file_cosmos_store_snapshots_v2_snapshot_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *SnapshotItem) GetItem() isSnapshotItem_Item {
@@ -4048,7 +4838,7 @@ type SnapshotStoreItem struct {
 func (x *SnapshotStoreItem) Reset() {
 	*x = SnapshotStoreItem{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[3]
+		mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4062,7 +4852,7 @@ func (*SnapshotStoreItem) ProtoMessage() {}
 
 // Deprecated: Use SnapshotStoreItem.ProtoReflect.Descriptor instead.
 func (*SnapshotStoreItem) Descriptor() ([]byte, []int) {
-	return file_cosmos_store_snapshots_v2_snapshot_proto_rawDescGZIP(), []int{3}
+	return file_cosmos_store_snapshots_v2_snapshot_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *SnapshotStoreItem) GetName() string {
@@ -4089,7 +4879,7 @@ type SnapshotIAVLItem struct {
 func (x *SnapshotIAVLItem) Reset() {
 	*x = SnapshotIAVLItem{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[4]
+		mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4103,7 +4893,7 @@ func (*SnapshotIAVLItem) ProtoMessage() {}
 
 // Deprecated: Use SnapshotIAVLItem.ProtoReflect.Descriptor instead.
 func (*SnapshotIAVLItem) Descriptor() ([]byte, []int) {
-	return file_cosmos_store_snapshots_v2_snapshot_proto_rawDescGZIP(), []int{4}
+	return file_cosmos_store_snapshots_v2_snapshot_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *SnapshotIAVLItem) GetKey() []byte {
@@ -4147,7 +4937,7 @@ type SnapshotExtensionMeta struct {
 func (x *SnapshotExtensionMeta) Reset() {
 	*x = SnapshotExtensionMeta{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[5]
+		mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4161,7 +4951,7 @@ func (*SnapshotExtensionMeta) ProtoMessage() {}
 
 // Deprecated: Use SnapshotExtensionMeta.ProtoReflect.Descriptor instead.
 func (*SnapshotExtensionMeta) Descriptor() ([]byte, []int) {
-	return file_cosmos_store_snapshots_v2_snapshot_proto_rawDescGZIP(), []int{5}
+	return file_cosmos_store_snapshots_v2_snapshot_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *SnapshotExtensionMeta) GetName() string {
@@ -4190,7 +4980,7 @@ type SnapshotExtensionPayload struct {
 func (x *SnapshotExtensionPayload) Reset() {
 	*x = SnapshotExtensionPayload{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[6]
+		mi := &file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -4204,7 +4994,7 @@ func (*SnapshotExtensionPayload) ProtoMessage() {}
 
 // Deprecated: Use SnapshotExtensionPayload.ProtoReflect.Descriptor instead.
 func (*SnapshotExtensionPayload) Descriptor() ([]byte, []int) {
-	return file_cosmos_store_snapshots_v2_snapshot_proto_rawDescGZIP(), []int{6}
+	return file_cosmos_store_snapshots_v2_snapshot_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *SnapshotExtensionPayload) GetPayload() []byte {
@@ -4235,71 +5025,83 @@ var file_cosmos_store_snapshots_v2_snapshot_proto_rawDesc = []byte{
 	0x0b, 0x32, 0x23, 0x2e, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x65,
 	0x2e, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x2e, 0x76, 0x32, 0x2e, 0x4d, 0x65,
 	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x42, 0x04, 0xc8, 0xde, 0x1f, 0x00, 0x52, 0x08, 0x6d, 0x65,
-	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0x2d, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0x77, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
 	0x74, 0x61, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68,
 	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0b, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x48,
-	0x61, 0x73, 0x68, 0x65, 0x73, 0x22, 0xf4, 0x02, 0x0a, 0x0c, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
-	0x6f, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x44, 0x0a, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2e, 0x73,
+	0x61, 0x73, 0x68, 0x65, 0x73, 0x12, 0x48, 0x0a, 0x06, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2e, 0x73,
 	0x74, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x2e, 0x76,
-	0x32, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x49,
-	0x74, 0x65, 0x6d, 0x48, 0x00, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x4b, 0x0a, 0x04,
-	0x69, 0x61, 0x76, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x63, 0x6f, 0x73,
-	0x6d, 0x6f, 0x73, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68,
-	0x6f, 0x74, 0x73, 0x2e, 0x76, 0x32, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x49,
-	0x41, 0x56, 0x4c, 0x49, 0x74, 0x65, 0x6d, 0x42, 0x08, 0xe2, 0xde, 0x1f, 0x04, 0x49, 0x41, 0x56,
-	0x4c, 0x48, 0x00, 0x52, 0x04, 0x69, 0x61, 0x76, 0x6c, 0x12, 0x50, 0x0a, 0x09, 0x65, 0x78, 0x74,
-	0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x63,
-	0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x6e, 0x61, 0x70,
-	0x73, 0x68, 0x6f, 0x74, 0x73, 0x2e, 0x76, 0x32, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
-	0x74, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x48, 0x00,
-	0x52, 0x09, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x62, 0x0a, 0x11, 0x65,
-	0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x33, 0x2e, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2e,
-	0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x2e,
-	0x76, 0x32, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x45, 0x78, 0x74, 0x65, 0x6e,
-	0x73, 0x69, 0x6f, 0x6e, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x48, 0x00, 0x52, 0x10, 0x65,
-	0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x3a,
-	0x13, 0xd2, 0xb4, 0x2d, 0x0f, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2d, 0x73, 0x64, 0x6b, 0x20,
-	0x30, 0x2e, 0x34, 0x36, 0x42, 0x06, 0x0a, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x22, 0x3c, 0x0a, 0x11,
-	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x49, 0x74, 0x65,
-	0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x3a, 0x13, 0xd2, 0xb4, 0x2d, 0x0f, 0x63, 0x6f, 0x73, 0x6d, 0x6f,
-	0x73, 0x2d, 0x73, 0x64, 0x6b, 0x20, 0x30, 0x2e, 0x34, 0x36, 0x22, 0x81, 0x01, 0x0a, 0x10, 0x53,
-	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x49, 0x41, 0x56, 0x4c, 0x49, 0x74, 0x65, 0x6d, 0x12,
-	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65,
-	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
-	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
-	0x6e, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x3a, 0x13, 0xd2, 0xb4, 0x2d, 0x0f, 0x63,
-	0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2d, 0x73, 0x64, 0x6b, 0x20, 0x30, 0x2e, 0x34, 0x36, 0x22, 0x58,
-	0x0a, 0x15, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73,
-	0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66,
-	0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x66, 0x6f, 0x72,
-	0x6d, 0x61, 0x74, 0x3a, 0x13, 0xd2, 0xb4, 0x2d, 0x0f, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2d,
-	0x73, 0x64, 0x6b, 0x20, 0x30, 0x2e, 0x34, 0x36, 0x22, 0x49, 0x0a, 0x18, 0x53, 0x6e, 0x61, 0x70,
-	0x73, 0x68, 0x6f, 0x74, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x61, 0x79,
-	0x6c, 0x6f, 0x61, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x3a, 0x13,
-	0xd2, 0xb4, 0x2d, 0x0f, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2d, 0x73, 0x64, 0x6b, 0x20, 0x30,
-	0x2e, 0x34, 0x36, 0x42, 0xed, 0x01, 0x0a, 0x1d, 0x63, 0x6f, 0x6d, 0x2e, 0x63, 0x6f, 0x73, 0x6d,
+	0x32, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x4d,
+	0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x52, 0x06, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x73, 0x22,
+	0x78, 0x0a, 0x15, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x53, 0x74, 0x6f, 0x72, 0x65,
+	0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1f, 0x0a, 0x0b,
+	0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x16, 0x0a,
+	0x06, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x63,
+	0x68, 0x75, 0x6e, 0x6b, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x22, 0xf4, 0x02, 0x0a, 0x0c, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x44, 0x0a, 0x05, 0x73, 0x74,
+	0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x63, 0x6f, 0x73, 0x6d,
 	0x6f, 0x73, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
-	0x74, 0x73, 0x2e, 0x76, 0x32, 0x42, 0x0d, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x50,
-	0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x36, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x73, 0x64,
-	0x6b, 0x2e, 0x69, 0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2f,
-	0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x2f,
-	0x76, 0x32, 0x3b, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x76, 0x32, 0xa2, 0x02,
-	0x03, 0x43, 0x53, 0x53, 0xaa, 0x02, 0x19, 0x43, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2e, 0x53, 0x74,
-	0x6f, 0x72, 0x65, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x2e, 0x56, 0x32,
-	0xca, 0x02, 0x19, 0x43, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x5c, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x5c,
-	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x5c, 0x56, 0x32, 0xe2, 0x02, 0x25, 0x43,
-	0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x5c, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x5c, 0x53, 0x6e, 0x61, 0x70,
-	0x73, 0x68, 0x6f, 0x74, 0x73, 0x5c, 0x56, 0x32, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61,
-	0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x1c, 0x43, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x3a, 0x3a, 0x53,
-	0x74, 0x6f, 0x72, 0x65, 0x3a, 0x3a, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x3a,
-	0x3a, 0x56, 0x32, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x73, 0x2e, 0x76, 0x32, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x53, 0x74,
+	0x6f, 0x72, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x48, 0x00, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x12, 0x4b, 0x0a, 0x04, 0x69, 0x61, 0x76, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2b,
+	0x2e, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x2e, 0x76, 0x32, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x49, 0x41, 0x56, 0x4c, 0x49, 0x74, 0x65, 0x6d, 0x42, 0x08, 0xe2, 0xde, 0x1f,
+	0x04, 0x49, 0x41, 0x56, 0x4c, 0x48, 0x00, 0x52, 0x04, 0x69, 0x61, 0x76, 0x6c, 0x12, 0x50, 0x0a,
+	0x09, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x30, 0x2e, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e,
+	0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x2e, 0x76, 0x32, 0x2e, 0x53, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x65,
+	0x74, 0x61, 0x48, 0x00, 0x52, 0x09, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x62, 0x0a, 0x11, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x61, 0x79,
+	0x6c, 0x6f, 0x61, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x33, 0x2e, 0x63, 0x6f, 0x73,
+	0x6d, 0x6f, 0x73, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x73, 0x2e, 0x76, 0x32, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x45,
+	0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x48,
+	0x00, 0x52, 0x10, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x61, 0x79, 0x6c,
+	0x6f, 0x61, 0x64, 0x3a, 0x13, 0xd2, 0xb4, 0x2d, 0x0f, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2d,
+	0x73, 0x64, 0x6b, 0x20, 0x30, 0x2e, 0x34, 0x36, 0x42, 0x06, 0x0a, 0x04, 0x69, 0x74, 0x65, 0x6d,
+	0x22, 0x3c, 0x0a, 0x11, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x53, 0x74, 0x6f, 0x72,
+	0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x3a, 0x13, 0xd2, 0xb4, 0x2d, 0x0f, 0x63,
+	0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2d, 0x73, 0x64, 0x6b, 0x20, 0x30, 0x2e, 0x34, 0x36, 0x22, 0x81,
+	0x01, 0x0a, 0x10, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x49, 0x41, 0x56, 0x4c, 0x49,
+	0x74, 0x65, 0x6d, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x3a, 0x13, 0xd2,
+	0xb4, 0x2d, 0x0f, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2d, 0x73, 0x64, 0x6b, 0x20, 0x30, 0x2e,
+	0x34, 0x36, 0x22, 0x58, 0x0a, 0x15, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x45, 0x78,
+	0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x3a, 0x13, 0xd2, 0xb4, 0x2d, 0x0f, 0x63, 0x6f, 0x73,
+	0x6d, 0x6f, 0x73, 0x2d, 0x73, 0x64, 0x6b, 0x20, 0x30, 0x2e, 0x34, 0x36, 0x22, 0x49, 0x0a, 0x18,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f,
+	0x6e, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c,
+	0x6f, 0x61, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f,
+	0x61, 0x64, 0x3a, 0x13, 0xd2, 0xb4, 0x2d, 0x0f, 0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2d, 0x73,
+	0x64, 0x6b, 0x20, 0x30, 0x2e, 0x34, 0x36, 0x42, 0xed, 0x01, 0x0a, 0x1d, 0x63, 0x6f, 0x6d, 0x2e,
+	0x63, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x2e, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x73, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x2e, 0x76, 0x32, 0x42, 0x0d, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x36, 0x63, 0x6f, 0x73, 0x6d,
+	0x6f, 0x73, 0x73, 0x64, 0x6b, 0x2e, 0x69, 0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x63, 0x6f, 0x73,
+	0x6d, 0x6f, 0x73, 0x2f, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x73, 0x2f, 0x76, 0x32, 0x3b, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73,
+	0x76, 0x32, 0xa2, 0x02, 0x03, 0x43, 0x53, 0x53, 0xaa, 0x02, 0x19, 0x43, 0x6f, 0x73, 0x6d, 0x6f,
+	0x73, 0x2e, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x2e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x73, 0x2e, 0x56, 0x32, 0xca, 0x02, 0x19, 0x43, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x5c, 0x53, 0x74,
+	0x6f, 0x72, 0x65, 0x5c, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x5c, 0x56, 0x32,
+	0xe2, 0x02, 0x25, 0x43, 0x6f, 0x73, 0x6d, 0x6f, 0x73, 0x5c, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x5c,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x5c, 0x56, 0x32, 0x5c, 0x47, 0x50, 0x42,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x1c, 0x43, 0x6f, 0x73, 0x6d, 0x6f,
+	0x73, 0x3a, 0x3a, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x3a, 0x3a, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x73, 0x3a, 0x3a, 0x56, 0x32, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -4314,27 +5116,29 @@ func file_cosmos_store_snapshots_v2_snapshot_proto_rawDescGZIP() []byte {
 	return file_cosmos_store_snapshots_v2_snapshot_proto_rawDescData
 }
 
-var file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_cosmos_store_snapshots_v2_snapshot_proto_goTypes = []interface{}{
 	(*Snapshot)(nil),                 // 0: cosmos.store.snapshots.v2.Snapshot
 	(*Metadata)(nil),                 // 1: cosmos.store.snapshots.v2.Metadata
-	(*SnapshotItem)(nil),             // 2: cosmos.store.snapshots.v2.SnapshotItem
-	(*SnapshotStoreItem)(nil),        // 3: cosmos.store.snapshots.v2.SnapshotStoreItem
-	(*SnapshotIAVLItem)(nil),         // 4: cosmos.store.snapshots.v2.SnapshotIAVLItem
-	(*SnapshotExtensionMeta)(nil),    // 5: cosmos.store.snapshots.v2.SnapshotExtensionMeta
-	(*SnapshotExtensionPayload)(nil), // 6: cosmos.store.snapshots.v2.SnapshotExtensionPayload
+	(*SnapshotStoreManifest)(nil),    // 2: cosmos.store.snapshots.v2.SnapshotStoreManifest
+	(*SnapshotItem)(nil),             // 3: cosmos.store.snapshots.v2.SnapshotItem
+	(*SnapshotStoreItem)(nil),        // 4: cosmos.store.snapshots.v2.SnapshotStoreItem
+	(*SnapshotIAVLItem)(nil),         // 5: cosmos.store.snapshots.v2.SnapshotIAVLItem
+	(*SnapshotExtensionMeta)(nil),    // 6: cosmos.store.snapshots.v2.SnapshotExtensionMeta
+	(*SnapshotExtensionPayload)(nil), // 7: cosmos.store.snapshots.v2.SnapshotExtensionPayload
 }
 var file_cosmos_store_snapshots_v2_snapshot_proto_depIdxs = []int32{
 	1, // 0: cosmos.store.snapshots.v2.Snapshot.metadata:type_name -> cosmos.store.snapshots.v2.Metadata
-	3, // 1: cosmos.store.snapshots.v2.SnapshotItem.store:type_name -> cosmos.store.snapshots.v2.SnapshotStoreItem
-	4, // 2: cosmos.store.snapshots.v2.SnapshotItem.iavl:type_name -> cosmos.store.snapshots.v2.SnapshotIAVLItem
-	5, // 3: cosmos.store.snapshots.v2.SnapshotItem.extension:type_name -> cosmos.store.snapshots.v2.SnapshotExtensionMeta
-	6, // 4: cosmos.store.snapshots.v2.SnapshotItem.extension_payload:type_name -> cosmos.store.snapshots.v2.SnapshotExtensionPayload
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	2, // 1: cosmos.store.snapshots.v2.Metadata.stores:type_name -> cosmos.store.snapshots.v2.SnapshotStoreManifest
+	4, // 2: cosmos.store.snapshots.v2.SnapshotItem.store:type_name -> cosmos.store.snapshots.v2.SnapshotStoreItem
+	5, // 3: cosmos.store.snapshots.v2.SnapshotItem.iavl:type_name -> cosmos.store.snapshots.v2.SnapshotIAVLItem
+	6, // 4: cosmos.store.snapshots.v2.SnapshotItem.extension:type_name -> cosmos.store.snapshots.v2.SnapshotExtensionMeta
+	7, // 5: cosmos.store.snapshots.v2.SnapshotItem.extension_payload:type_name -> cosmos.store.snapshots.v2.SnapshotExtensionPayload
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_cosmos_store_snapshots_v2_snapshot_proto_init() }
@@ -4368,7 +5172,7 @@ func file_cosmos_store_snapshots_v2_snapshot_proto_init() {
 			}
 		}
 		file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SnapshotItem); i {
+			switch v := v.(*SnapshotStoreManifest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4380,7 +5184,7 @@ func file_cosmos_store_snapshots_v2_snapshot_proto_init() {
 			}
 		}
 		file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SnapshotStoreItem); i {
+			switch v := v.(*SnapshotItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4392,7 +5196,7 @@ func file_cosmos_store_snapshots_v2_snapshot_proto_init() {
 			}
 		}
 		file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SnapshotIAVLItem); i {
+			switch v := v.(*SnapshotStoreItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4404,7 +5208,7 @@ func file_cosmos_store_snapshots_v2_snapshot_proto_init() {
 			}
 		}
 		file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SnapshotExtensionMeta); i {
+			switch v := v.(*SnapshotIAVLItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4416,6 +5220,18 @@ func file_cosmos_store_snapshots_v2_snapshot_proto_init() {
 			}
 		}
 		file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SnapshotExtensionMeta); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SnapshotExtensionPayload); i {
 			case 0:
 				return &v.state
@@ -4428,7 +5244,7 @@ func file_cosmos_store_snapshots_v2_snapshot_proto_init() {
 			}
 		}
 	}
-	file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[2].OneofWrappers = []interface{}{
+	file_cosmos_store_snapshots_v2_snapshot_proto_msgTypes[3].OneofWrappers = []interface{}{
 		(*SnapshotItem_Store)(nil),
 		(*SnapshotItem_Iavl)(nil),
 		(*SnapshotItem_Extension)(nil),
@@ -4440,7 +5256,7 @@ func file_cosmos_store_snapshots_v2_snapshot_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_cosmos_store_snapshots_v2_snapshot_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
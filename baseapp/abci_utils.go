@@ -262,7 +262,9 @@ func (h *DefaultProposalHandler) SetTxSelector(ts TxSelector) {
 // 2) Are valid (i.e. pass runTx, AnteHandler only).
 //
 // Enumeration is halted once RequestPrepareProposal.MaxBytes of transactions is
-// reached or the mempool is exhausted.
+// reached or the mempool is exhausted. The lanes of a LanedMempool are enumerated
+// by priority, the transactions of a lane being capped to its share of the
+// block bytes and gas.
 //
 // Note:
 //
@@ -310,87 +312,119 @@ func (h *DefaultProposalHandler) PrepareProposalHandler() sdk.PrepareProposalHan
 			return &abci.PrepareProposalResponse{Txs: h.txSelector.SelectedTxs(ctx)}, nil
 		}
 
+		// a mempool without lanes is a single lane without limit
+		lanes := []mempool.Lane{{Mempool: h.mempool}}
+		if lanedMempool, ok := h.mempool.(*mempool.LanedMempool); ok {
+			lanes = lanedMempool.Lanes()
+		}
+
 		selectedTxsSignersSeqs := make(map[string]uint64)
 		var (
 			resError        error
 			selectedTxsNums int
+			selectedTxBytes uint64
+			selectedTxGas   uint64
 			invalidTxs      []sdk.Tx // invalid txs to be removed out of the loop to avoid dead lock
 		)
-		h.mempool.SelectBy(ctx, decodedTxs, func(memTx sdk.Tx) bool {
-			unorderedTx, ok := memTx.(sdk.TxWithUnordered)
-			isUnordered := ok && unorderedTx.GetUnordered()
-			txSignersSeqs := make(map[string]uint64)
-
-			// if the tx is unordered, we don't need to check the sequence, we just add it
-			if !isUnordered {
-				signerData, err := h.signerExtAdapter.GetSigners(memTx)
-				if err != nil {
-					// propagate the error to the caller
-					resError = err
-					return false
-				}
+		for _, lane := range lanes {
+			// the txs of a lane are selected within its share of the block space,
+			// on top of the txs selected from the previous lanes
+			laneMaxTxBytes := min(uint64(req.MaxTxBytes), selectedTxBytes+lane.BlockSpaceLimit(uint64(req.MaxTxBytes)))
+			laneMaxBlockGas := maxBlockGas
+			if maxBlockGas > 0 {
+				laneMaxBlockGas = min(maxBlockGas, selectedTxGas+lane.BlockSpaceLimit(maxBlockGas))
+			}
 
-				// If the signers aren't in selectedTxsSignersSeqs then we haven't seen them before
-				// so we add them and continue given that we don't need to check the sequence.
-				shouldAdd := true
-				for _, signer := range signerData {
-					signerKey := string(signer.Signer)
-					seq, ok := selectedTxsSignersSeqs[signerKey]
-					if !ok {
-						txSignersSeqs[signerKey] = signer.Sequence
-						continue
-					}
+			lane.Mempool.SelectBy(ctx, decodedTxs, func(memTx sdk.Tx) bool {
+				unorderedTx, ok := memTx.(sdk.TxWithUnordered)
+				isUnordered := ok && unorderedTx.GetUnordered()
+				txSignersSeqs := make(map[string]uint64)
 
-					// If we have seen this signer before in this block, we must make
-					// sure that the current sequence is seq+1; otherwise is invalid
-					// and we skip it.
-					if seq+1 != signer.Sequence {
-						shouldAdd = false
-						break
+				// if the tx is unordered, we don't need to check the sequence, we just add it
+				if !isUnordered {
+					signerData, err := h.signerExtAdapter.GetSigners(memTx)
+					if err != nil {
+						// propagate the error to the caller
+						resError = err
+						return false
 					}
-					txSignersSeqs[signerKey] = signer.Sequence
-				}
-				if !shouldAdd {
-					return true
-				}
-			}
 
-			// NOTE: Since transaction verification was already executed in CheckTx,
-			// which calls mempool.Insert, in theory everything in the pool should be
-			// valid. But some mempool implementations may insert invalid txs, so we
-			// check again.
-			txBz, err := h.txVerifier.PrepareProposalVerifyTx(memTx)
-			if err != nil {
-				invalidTxs = append(invalidTxs, memTx)
-			} else {
-				stop := h.txSelector.SelectTxForProposal(ctx, uint64(req.MaxTxBytes), maxBlockGas, memTx, txBz)
-				if stop {
-					return false
+					// If the signers aren't in selectedTxsSignersSeqs then we haven't seen them before
+					// so we add them and continue given that we don't need to check the sequence.
+					shouldAdd := true
+					for _, signer := range signerData {
+						signerKey := string(signer.Signer)
+						seq, ok := selectedTxsSignersSeqs[signerKey]
+						if !ok {
+							txSignersSeqs[signerKey] = signer.Sequence
+							continue
+						}
+
+						// If we have seen this signer before in this block, we must make
+						// sure that the current sequence is seq+1; otherwise is invalid
+						// and we skip it.
+						if seq+1 != signer.Sequence {
+							shouldAdd = false
+							break
+						}
+						txSignersSeqs[signerKey] = signer.Sequence
+					}
+					if !shouldAdd {
+						return true
+					}
 				}
 
-				txsLen := len(h.txSelector.SelectedTxs(ctx))
-				// If the tx is unordered, we don't need to update the sender sequence.
-				if !isUnordered {
-					for sender, seq := range txSignersSeqs {
-						// If txsLen != selectedTxsNums is true, it means that we've
-						// added a new tx to the selected txs, so we need to update
-						// the sequence of the sender.
-						if txsLen != selectedTxsNums {
-							selectedTxsSignersSeqs[sender] = seq
-						} else if _, ok := selectedTxsSignersSeqs[sender]; !ok {
-							// The transaction hasn't been added but it passed the
-							// verification, so we know that the sequence is correct.
-							// So we set this sender's sequence to seq-1, in order
-							// to avoid unnecessary calls to PrepareProposalVerifyTx.
-							selectedTxsSignersSeqs[sender] = seq - 1
+				// NOTE: Since transaction verification was already executed in CheckTx,
+				// which calls mempool.Insert, in theory everything in the pool should be
+				// valid. But some mempool implementations may insert invalid txs, so we
+				// check again.
+				txBz, err := h.txVerifier.PrepareProposalVerifyTx(memTx)
+				if err != nil {
+					invalidTxs = append(invalidTxs, memTx)
+				} else {
+					stop := h.txSelector.SelectTxForProposal(ctx, laneMaxTxBytes, laneMaxBlockGas, memTx, txBz)
+
+					txsLen := len(h.txSelector.SelectedTxs(ctx))
+					if txsLen != selectedTxsNums {
+						selectedTxBytes += uint64(cmttypes.ComputeProtoSizeForTxs([]cmttypes.Tx{txBz}))
+						if gasTx, ok := memTx.(GasTx); ok {
+							selectedTxGas += gasTx.GetGas()
 						}
 					}
+					// If the tx is unordered, we don't need to update the sender sequence.
+					if !isUnordered {
+						for sender, seq := range txSignersSeqs {
+							// If txsLen != selectedTxsNums is true, it means that we've
+							// added a new tx to the selected txs, so we need to update
+							// the sequence of the sender.
+							if txsLen != selectedTxsNums {
+								selectedTxsSignersSeqs[sender] = seq
+							} else if _, ok := selectedTxsSignersSeqs[sender]; !ok {
+								// The transaction hasn't been added but it passed the
+								// verification, so we know that the sequence is correct.
+								// So we set this sender's sequence to seq-1, in order
+								// to avoid unnecessary calls to PrepareProposalVerifyTx.
+								selectedTxsSignersSeqs[sender] = seq - 1
+							}
+						}
+					}
+					selectedTxsNums = txsLen
+
+					// the lane is full, the selection goes on with the next lane
+					if stop {
+						return false
+					}
 				}
-				selectedTxsNums = txsLen
-			}
 
-			return true
-		})
+				return true
+			})
+			if resError != nil {
+				break
+			}
+			if selectedTxBytes >= uint64(req.MaxTxBytes) || (maxBlockGas > 0 && selectedTxGas >= maxBlockGas) {
+				break
+			}
+		}
 
 		if resError != nil {
 			return nil, resError
@@ -413,6 +447,9 @@ func (h *DefaultProposalHandler) PrepareProposalHandler() sdk.PrepareProposalHan
 // 1. The transaction bytes must decode to a valid transaction.
 // 2. The transaction must be valid (i.e. pass runTx, AnteHandler only)
 //
+// With a LanedMempool, the transactions must also be ordered by lane priority
+// and the transactions of a lane must fit its share of the block bytes and gas.
+//
 // If any transaction fails to pass either condition, the proposal is rejected.
 // Note that step (2) is identical to the validation step performed in
 // DefaultPrepareProposal. It is very important that the same validation logic
@@ -426,27 +463,48 @@ func (h *DefaultProposalHandler) ProcessProposalHandler() sdk.ProcessProposalHan
 		return NoOpProcessProposal()
 	}
 
+	// the txs of a laned mempool must be ordered by lane, within their lane share
+	lanedMempool, isLaned := h.mempool.(*mempool.LanedMempool)
+	var lanes []mempool.Lane
+	if isLaned {
+		lanes = lanedMempool.Lanes()
+	}
+
 	return func(ctx sdk.Context, req *abci.ProcessProposalRequest) (*abci.ProcessProposalResponse, error) {
 		var totalTxGas uint64
 
-		var maxBlockGas int64
+		var maxBlockGas, maxBlockBytes int64
 		if b := ctx.ConsensusParams().Block; b != nil { //nolint:staticcheck // ignore linting error
 			maxBlockGas = b.MaxGas
+			maxBlockBytes = b.MaxBytes
 		}
 
+		var usage laneUsage
 		for _, txBytes := range req.Txs {
 			tx, err := h.txVerifier.ProcessProposalVerifyTx(txBytes)
 			if err != nil {
 				return &abci.ProcessProposalResponse{Status: abci.PROCESS_PROPOSAL_STATUS_REJECT}, nil
 			}
 
+			var txGas uint64
+			if gasTx, ok := tx.(GasTx); ok {
+				txGas = gasTx.GetGas()
+			}
+
 			if maxBlockGas > 0 {
-				gasTx, ok := tx.(GasTx)
-				if ok {
-					totalTxGas += gasTx.GetGas()
+				totalTxGas += txGas
+				if totalTxGas > uint64(maxBlockGas) {
+					return &abci.ProcessProposalResponse{Status: abci.PROCESS_PROPOSAL_STATUS_REJECT}, nil
 				}
+			}
 
-				if totalTxGas > uint64(maxBlockGas) {
+			if isLaned {
+				laneIndex, err := lanedMempool.LaneIndex(tx)
+				if err != nil {
+					return &abci.ProcessProposalResponse{Status: abci.PROCESS_PROPOSAL_STATUS_REJECT}, nil
+				}
+				txSize := uint64(cmttypes.ComputeProtoSizeForTxs([]cmttypes.Tx{txBytes}))
+				if !usage.add(laneIndex, lanes[laneIndex], txSize, txGas, maxBlockBytes, maxBlockGas) {
 					return &abci.ProcessProposalResponse{Status: abci.PROCESS_PROPOSAL_STATUS_REJECT}, nil
 				}
 			}
@@ -456,6 +514,32 @@ func (h *DefaultProposalHandler) ProcessProposalHandler() sdk.ProcessProposalHan
 	}
 }
 
+// laneUsage tracks the block space used by the lanes of the txs of a proposal.
+type laneUsage struct {
+	lane         int
+	txBytes, gas uint64
+	started      bool
+}
+
+// add adds a tx of the given lane, and reports whether the txs are ordered by
+// lane and within the share of the block space of their lane.
+func (u *laneUsage) add(laneIndex int, lane mempool.Lane, txBytes, txGas uint64, maxBlockBytes, maxBlockGas int64) bool {
+	switch {
+	case !u.started || laneIndex > u.lane:
+		*u = laneUsage{lane: laneIndex, started: true}
+	case laneIndex < u.lane:
+		// the txs of a lane with a higher priority must come first
+		return false
+	}
+
+	u.txBytes += txBytes
+	u.gas += txGas
+	if maxBlockBytes > 0 && u.txBytes > lane.BlockSpaceLimit(uint64(maxBlockBytes)) {
+		return false
+	}
+	return maxBlockGas <= 0 || u.gas <= lane.BlockSpaceLimit(uint64(maxBlockGas))
+}
+
 // NoOpPrepareProposal defines a no-op PrepareProposal handler. It will always
 // return the transactions sent by the client's request.
 func NoOpPrepareProposal() sdk.PrepareProposalHandler {
@@ -2,6 +2,7 @@ package baseapp_test
 
 import (
 	"bytes"
+	"fmt"
 	"sort"
 	"testing"
 	"time"
@@ -714,6 +715,89 @@ func (s *ABCIUtilsTestSuite) TestDefaultProposalHandler_PriorityNonceMempoolTxSe
 	}
 }
 
+func (s *ABCIUtilsTestSuite) TestDefaultProposalHandler_LanedMempool() {
+	cdc := codectestutil.CodecOptions{}.NewCodec()
+	baseapptestutil.RegisterInterfaces(cdc.InterfaceRegistry())
+	signingCtx := cdc.InterfaceRegistry().SigningContext()
+	txConfig := authtx.NewTxConfig(cdc, signingCtx.AddressCodec(), signingCtx.ValidatorAddressCodec(), authtx.DefaultSignModes)
+
+	type testTx struct {
+		tx       sdk.Tx
+		priority int64
+		bz       []byte
+	}
+
+	// relayer txs pay less than the spam txs of the default lane
+	var relayerTxs, spamTxs []testTx
+	for i := range 3 {
+		secret := []byte(fmt.Sprintf("relayer%d", i))
+		relayerTxs = append(relayerTxs, testTx{tx: buildMsg(s.T(), txConfig, signingCtx.AddressCodec(), []byte(fmt.Sprintf("r%d", i)), [][]byte{secret}, []uint64{1}, false), priority: 1})
+	}
+	for i := range 5 {
+		secret := []byte(fmt.Sprintf("spam%d", i))
+		spamTxs = append(spamTxs, testTx{tx: buildMsg(s.T(), txConfig, signingCtx.AddressCodec(), []byte(fmt.Sprintf("s%d", i)), [][]byte{secret}, []uint64{1}, false), priority: 100})
+	}
+	isRelayerTx := func(tx sdk.Tx) bool {
+		return bytes.HasPrefix(tx.GetMsgs()[0].(*baseapptestutil.MsgKeyValue).Value, []byte("r"))
+	}
+
+	ctrl := gomock.NewController(s.T())
+	app := mock.NewMockProposalTxVerifier(ctrl)
+	newPriorityMempool := func() mempool.Mempool {
+		return mempool.NewPriorityMempool(mempool.PriorityNonceMempoolConfig[int64]{
+			TxPriority:      mempool.NewDefaultTxPriority(),
+			SignerExtractor: mempool.NewDefaultSignerExtractionAdapter(),
+		})
+	}
+	mp, err := mempool.NewLanedMempool(
+		mempool.Lane{Name: "default", Mempool: newPriorityMempool()},
+		mempool.Lane{Name: "relayer", Mempool: newPriorityMempool(), Match: isRelayerTx, MaxBlockSpace: 40, Priority: 10},
+	)
+	s.Require().NoError(err)
+
+	req := &abci.PrepareProposalRequest{}
+	var txSize int64
+	for _, v := range append(spamTxs, relayerTxs...) {
+		v.bz, err = txConfig.TxEncoder()(v.tx)
+		s.Require().NoError(err)
+		txSize = cmttypes.ComputeProtoSizeForTxs([]cmttypes.Tx{v.bz})
+		app.EXPECT().TxDecode(v.bz).Return(v.tx, nil).AnyTimes()
+		app.EXPECT().PrepareProposalVerifyTx(v.tx).Return(v.bz, nil).AnyTimes()
+		app.EXPECT().ProcessProposalVerifyTx(v.bz).Return(v.tx, nil).AnyTimes()
+		s.Require().NoError(mp.Insert(s.ctx.WithPriority(v.priority), v.tx))
+		req.Txs = append(req.Txs, v.bz)
+	}
+	s.Require().Equal(8, mp.CountTx())
+
+	// the relayer lane gets 2 txs within its 40% of the block, the default lane the rest
+	req.MaxTxBytes = 5*txSize + txSize/2
+	ctx := s.ctx.WithConsensusParams(cmtproto.ConsensusParams{Block: &cmtproto.BlockParams{MaxBytes: req.MaxTxBytes}})
+	ph := baseapp.NewDefaultProposalHandler(mp, app)
+	resp, err := ph.PrepareProposalHandler()(ctx, req)
+	s.Require().NoError(err)
+	s.Require().Len(resp.Txs, 5)
+	for i, bz := range resp.Txs {
+		tx, err := txConfig.TxDecoder()(bz)
+		s.Require().NoError(err)
+		s.Require().Equal(i < 2, isRelayerTx(tx), "tx %d", i)
+	}
+
+	processProposal := ph.ProcessProposalHandler()
+	res, err := processProposal(ctx, &abci.ProcessProposalRequest{Txs: resp.Txs})
+	s.Require().NoError(err)
+	s.Require().Equal(abci.PROCESS_PROPOSAL_STATUS_ACCEPT, res.Status)
+
+	// the txs of the relayer lane must come first
+	res, err = processProposal(ctx, &abci.ProcessProposalRequest{Txs: append(resp.Txs[2:], resp.Txs[:2]...)})
+	s.Require().NoError(err)
+	s.Require().Equal(abci.PROCESS_PROPOSAL_STATUS_REJECT, res.Status)
+
+	// the relayer lane can't exceed its share of the block
+	res, err = processProposal(ctx, &abci.ProcessProposalRequest{Txs: req.Txs[5:]})
+	s.Require().NoError(err)
+	s.Require().Equal(abci.PROCESS_PROPOSAL_STATUS_REJECT, res.Status)
+}
+
 func marshalDelimitedFn(msg proto.Message) ([]byte, error) {
 	var buf bytes.Buffer
 	if err := protoio.NewDelimitedWriter(&buf).WriteMsg(msg); err != nil {
@@ -0,0 +1,152 @@
+package indexes
+
+import (
+	"context"
+	"errors"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/collections/codec"
+)
+
+// Range defines an index which can be queried by ranges of reference keys. Like Multi,
+// it maps a field of value to its primary key without enforcing uniqueness, but it also
+// answers queries like "all primary keys whose reference key falls in [a, b)" in ascending
+// or descending order and with cursor based pagination.
+// Typical reference keys are amounts or times, e.g. validators by tokens or proposals by end time.
+// Reference keys are ordered by their byte representation, so the reference key codec must
+// preserve the order of the keys, like collections.Uint64Key or sdk.TimeKey do.
+type Range[ReferenceKey, PrimaryKey, Value any] struct {
+	*Multi[ReferenceKey, PrimaryKey, Value]
+}
+
+// NewRange instantiates a new Range instance given a schema,
+// a Prefix, the humanized name for the index, the reference key key codec
+// and the primary key key codec. The getRefKeyFunc is a function that
+// given the primary key and value returns the referencing key.
+func NewRange[ReferenceKey, PrimaryKey, Value any](
+	schema *collections.SchemaBuilder,
+	prefix collections.Prefix,
+	name string,
+	refCodec codec.KeyCodec[ReferenceKey],
+	pkCodec codec.KeyCodec[PrimaryKey],
+	getRefKeyFunc func(pk PrimaryKey, value Value) (ReferenceKey, error),
+	options ...func(*multiOptions),
+) *Range[ReferenceKey, PrimaryKey, Value] {
+	return &Range[ReferenceKey, PrimaryKey, Value]{
+		Multi: NewMulti(schema, prefix, name, refCodec, pkCodec, getRefKeyFunc, options...),
+	}
+}
+
+// MatchRange returns a MultiIterator containing all the primary keys whose reference key
+// is within the provided ValueRange. A nil ValueRange iterates over the whole index.
+func (r *Range[ReferenceKey, PrimaryKey, Value]) MatchRange(ctx context.Context, rng *ValueRange[ReferenceKey, PrimaryKey]) (MultiIterator[ReferenceKey, PrimaryKey], error) {
+	if rng == nil {
+		return r.Iterate(ctx, nil)
+	}
+	return r.Iterate(ctx, rng)
+}
+
+// Between returns a MultiIterator containing all the primary keys whose reference key
+// is bigger or equal to start and smaller than end, in ascending order.
+func (r *Range[ReferenceKey, PrimaryKey, Value]) Between(ctx context.Context, start, end ReferenceKey) (MultiIterator[ReferenceKey, PrimaryKey], error) {
+	return r.MatchRange(ctx, NewValueRange[ReferenceKey, PrimaryKey]().StartInclusive(start).EndExclusive(end))
+}
+
+// Page returns at most limit entries of the index within the provided ValueRange.
+// If there are more entries, the returned cursor is the last returned entry and can be passed
+// to ValueRange.After to fetch the next page, otherwise the returned cursor is nil.
+func (r *Range[ReferenceKey, PrimaryKey, Value]) Page(
+	ctx context.Context,
+	rng *ValueRange[ReferenceKey, PrimaryKey],
+	limit int,
+) (entries []collections.Pair[ReferenceKey, PrimaryKey], cursor *collections.Pair[ReferenceKey, PrimaryKey], err error) {
+	if limit <= 0 {
+		return nil, nil, errors.New("collections: page limit must be positive")
+	}
+
+	iter, err := r.MatchRange(ctx, rng)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer iter.Close()
+
+	for ; iter.Valid(); iter.Next() {
+		if len(entries) == limit {
+			last := entries[len(entries)-1]
+			return entries, &last, nil
+		}
+
+		entry, err := iter.FullKey()
+		if err != nil {
+			return nil, nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil, nil
+}
+
+// NewValueRange creates a new ValueRange which includes all the reference keys.
+func NewValueRange[ReferenceKey, PrimaryKey any]() *ValueRange[ReferenceKey, PrimaryKey] {
+	return &ValueRange[ReferenceKey, PrimaryKey]{}
+}
+
+// ValueRange defines a range of reference keys of a Range index.
+// It implements the collections.Ranger API for the keys of the index.
+type ValueRange[ReferenceKey, PrimaryKey any] struct {
+	start  *collections.RangeKey[collections.Pair[ReferenceKey, PrimaryKey]]
+	end    *collections.RangeKey[collections.Pair[ReferenceKey, PrimaryKey]]
+	cursor *collections.Pair[ReferenceKey, PrimaryKey]
+	order  collections.Order
+}
+
+// StartInclusive makes the range contain only reference keys which are bigger or equal to the provided start.
+func (v *ValueRange[ReferenceKey, PrimaryKey]) StartInclusive(start ReferenceKey) *ValueRange[ReferenceKey, PrimaryKey] {
+	v.start = collections.RangeKeyExact(collections.PairPrefix[ReferenceKey, PrimaryKey](start))
+	return v
+}
+
+// StartExclusive makes the range contain only reference keys which are bigger than the provided start.
+func (v *ValueRange[ReferenceKey, PrimaryKey]) StartExclusive(start ReferenceKey) *ValueRange[ReferenceKey, PrimaryKey] {
+	v.start = collections.RangeKeyPrefixEnd(collections.PairPrefix[ReferenceKey, PrimaryKey](start))
+	return v
+}
+
+// EndInclusive makes the range contain only reference keys which are smaller or equal to the provided end.
+func (v *ValueRange[ReferenceKey, PrimaryKey]) EndInclusive(end ReferenceKey) *ValueRange[ReferenceKey, PrimaryKey] {
+	v.end = collections.RangeKeyPrefixEnd(collections.PairPrefix[ReferenceKey, PrimaryKey](end))
+	return v
+}
+
+// EndExclusive makes the range contain only reference keys which are smaller than the provided end.
+func (v *ValueRange[ReferenceKey, PrimaryKey]) EndExclusive(end ReferenceKey) *ValueRange[ReferenceKey, PrimaryKey] {
+	v.end = collections.RangeKeyExact(collections.PairPrefix[ReferenceKey, PrimaryKey](end))
+	return v
+}
+
+// Descending makes the range yield the entries from the biggest reference key to the smallest.
+func (v *ValueRange[ReferenceKey, PrimaryKey]) Descending() *ValueRange[ReferenceKey, PrimaryKey] {
+	v.order = collections.OrderDescending
+	return v
+}
+
+// After makes the range resume after the provided cursor, which is an entry previously
+// returned by an iteration over the same range, i.e. the cursor returned by Range.Page.
+func (v *ValueRange[ReferenceKey, PrimaryKey]) After(cursor collections.Pair[ReferenceKey, PrimaryKey]) *ValueRange[ReferenceKey, PrimaryKey] {
+	v.cursor = &cursor
+	return v
+}
+
+// RangeValues implements collections.Ranger.
+func (v *ValueRange[ReferenceKey, PrimaryKey]) RangeValues() (start, end *collections.RangeKey[collections.Pair[ReferenceKey, PrimaryKey]], order collections.Order, err error) {
+	start, end = v.start, v.end
+	if v.cursor != nil {
+		// the cursor is within the range, so it replaces the bound from which the iteration starts
+		if v.order == collections.OrderDescending {
+			end = collections.RangeKeyExact(*v.cursor)
+		} else {
+			start = collections.RangeKeyNext(*v.cursor)
+		}
+	}
+	return start, end, v.order, nil
+}
@@ -0,0 +1,127 @@
+package indexes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/collections"
+)
+
+type tokensIndex struct {
+	Tokens *Range[uint64, string, uint64]
+}
+
+func (t tokensIndex) IndexesList() []collections.Index[string, uint64] {
+	return []collections.Index[string, uint64]{t.Tokens}
+}
+
+func TestRangeIndex(t *testing.T) {
+	sk, ctx := deps()
+	sb := collections.NewSchemaBuilder(sk)
+
+	// validators by tokens
+	indexedMap := collections.NewIndexedMap(
+		sb,
+		collections.NewPrefix("validators"), "validators",
+		collections.StringKey,
+		collections.Uint64Value,
+		tokensIndex{
+			Tokens: NewRange(sb, collections.NewPrefix("tokens_index"), "tokens_index", collections.Uint64Key, collections.StringKey, func(_ string, tokens uint64) (uint64, error) {
+				return tokens, nil
+			}),
+		},
+	)
+
+	for val, tokens := range map[string]uint64{"a": 10, "b": 20, "c": 20, "d": 30, "e": 40, "f": 1000} {
+		require.NoError(t, indexedMap.Set(ctx, val, tokens))
+	}
+	idx := indexedMap.Indexes.Tokens
+
+	primaryKeys := func(rng *ValueRange[uint64, string]) []string {
+		t.Helper()
+		iter, err := idx.MatchRange(ctx, rng)
+		require.NoError(t, err)
+		pks, err := iter.PrimaryKeys()
+		require.NoError(t, err)
+		return pks
+	}
+
+	// [20, 40)
+	iter, err := idx.Between(ctx, 20, 40)
+	require.NoError(t, err)
+	pks, err := iter.PrimaryKeys()
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "c", "d"}, pks)
+
+	require.Equal(t, []string{"a", "b", "c", "d", "e", "f"}, primaryKeys(nil))
+	require.Equal(t, []string{"d", "e"}, primaryKeys(NewValueRange[uint64, string]().StartExclusive(20).EndInclusive(40)))
+	require.Equal(t, []string{"e", "d", "c", "b"}, primaryKeys(NewValueRange[uint64, string]().StartInclusive(11).EndExclusive(1000).Descending()))
+	require.Equal(t, []string{"a", "b", "c"}, primaryKeys(NewValueRange[uint64, string]().EndExclusive(30)))
+	require.Equal(t, []string{"f"}, primaryKeys(NewValueRange[uint64, string]().StartInclusive(41)))
+	require.Empty(t, primaryKeys(NewValueRange[uint64, string]().StartInclusive(41).EndExclusive(1000)))
+
+	// values can be collected from the indexed map
+	iter, err = idx.MatchRange(ctx, NewValueRange[uint64, string]().StartInclusive(30))
+	require.NoError(t, err)
+	values, err := CollectValues(ctx, indexedMap, iter)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{30, 40, 1000}, values)
+
+	// updates move the reference
+	require.NoError(t, indexedMap.Set(ctx, "a", 35))
+	require.Equal(t, []string{"d", "a", "e"}, primaryKeys(NewValueRange[uint64, string]().StartInclusive(30).EndExclusive(1000)))
+	require.NoError(t, indexedMap.Remove(ctx, "d"))
+	require.Equal(t, []string{"a", "e"}, primaryKeys(NewValueRange[uint64, string]().StartInclusive(30).EndExclusive(1000)))
+}
+
+func TestRangeIndexPage(t *testing.T) {
+	sk, ctx := deps()
+	sb := collections.NewSchemaBuilder(sk)
+
+	idx := NewRange(sb, collections.NewPrefix("tokens_index"), "tokens_index", collections.Uint64Key, collections.StringKey, func(_ string, tokens uint64) (uint64, error) {
+		return tokens, nil
+	})
+	for val, tokens := range map[string]uint64{"a": 10, "b": 20, "c": 20, "d": 20, "e": 30, "f": 40} {
+		require.NoError(t, idx.Reference(ctx, val, tokens, func() (uint64, error) { return 0, collections.ErrNotFound }))
+	}
+
+	paginate := func(newRange func() *ValueRange[uint64, string], limit int) (pages [][]string) {
+		t.Helper()
+		var cursor *collections.Pair[uint64, string]
+		for {
+			rng := newRange()
+			if cursor != nil {
+				rng.After(*cursor)
+			}
+			entries, next, err := idx.Page(ctx, rng, limit)
+			require.NoError(t, err)
+
+			var page []string
+			for _, entry := range entries {
+				page = append(page, entry.K2())
+			}
+			pages = append(pages, page)
+
+			if next == nil {
+				return pages
+			}
+			cursor = next
+		}
+	}
+
+	require.Equal(t, [][]string{{"b", "c"}, {"d", "e"}}, paginate(func() *ValueRange[uint64, string] {
+		return NewValueRange[uint64, string]().StartInclusive(20).EndExclusive(40)
+	}, 2))
+
+	require.Equal(t, [][]string{{"f", "e", "d"}, {"c", "b", "a"}}, paginate(func() *ValueRange[uint64, string] {
+		return NewValueRange[uint64, string]().Descending()
+	}, 3))
+
+	require.Equal(t, [][]string{{"a", "b", "c", "d", "e", "f"}}, paginate(func() *ValueRange[uint64, string] {
+		return nil
+	}, 10))
+
+	_, _, err := idx.Page(ctx, nil, 0)
+	require.Error(t, err)
+}
@@ -0,0 +1,130 @@
+package collections
+
+import (
+	"context"
+
+	"cosmossdk.io/collections/codec"
+)
+
+const (
+	PriorityQueueElementsNameSuffix   = "_elements"
+	PriorityQueueSequenceNameSuffix   = "_sequence"
+	PriorityQueueElementsPrefixSuffix = 0x0
+	PriorityQueueSequencePrefixSuffix = 0x1
+)
+
+// NewPriorityQueue creates a new PriorityQueue instance. Elements are ordered by their priority,
+// whose ordering is defined by the bytes representation of the priority codec, e.g. sdk.TimeKey
+// orders elements by time and Uint64Key by number.
+// Since PriorityQueue relies on two collections, it will register two state objects on the
+// schema builder. The elements are a map keyed by the priority and the insertion sequence of the
+// element whose prefix is the provided prefix with a suffix which equals to
+// PriorityQueueElementsPrefixSuffix, the name is also suffixed with PriorityQueueElementsNameSuffix.
+// The second is the insertion sequence whose prefix is the provided prefix with a suffix which
+// equals to PriorityQueueSequencePrefixSuffix, the name is also suffixed with PriorityQueueSequenceNameSuffix.
+func NewPriorityQueue[P, T any](
+	sb *SchemaBuilder,
+	prefix Prefix,
+	name string,
+	priorityCodec codec.KeyCodec[P],
+	vc codec.ValueCodec[T],
+) PriorityQueue[P, T] {
+	return PriorityQueue[P, T]{
+		sequence: NewSequence(sb, append(prefix, PriorityQueueSequencePrefixSuffix), name+PriorityQueueSequenceNameSuffix),
+		elements: NewMap(
+			sb,
+			append(prefix, PriorityQueueElementsPrefixSuffix),
+			name+PriorityQueueElementsNameSuffix,
+			NamedPairKeyCodec("priority", priorityCodec, "sequence", Uint64Key),
+			vc,
+		),
+	}
+}
+
+// PriorityQueue is a queue sitting on top of a KVStore whose elements are popped in the order of their
+// priority, from the smallest to the biggest. Elements with the same priority are popped in the order
+// in which they were pushed. A typical use case is a queue of elements which mature at a given time,
+// like unbonding delegations or proposals ending their voting period.
+// It relies on a Map[Pair[P, uint64], T] keyed by the priority and the insertion sequence of the
+// element, and a Sequence.
+type PriorityQueue[P, T any] struct {
+	sequence Sequence
+	elements Map[Pair[P, uint64], T]
+}
+
+// Push adds an element with the provided priority to the PriorityQueue.
+func (q PriorityQueue[P, T]) Push(ctx context.Context, priority P, elem T) error {
+	seq, err := q.sequence.Next(ctx)
+	if err != nil {
+		return err
+	}
+	return q.elements.Set(ctx, Join(priority, seq), elem)
+}
+
+// Peek returns the element with the smallest priority and its priority without removing it.
+// Returns ErrEmptyQueue if the PriorityQueue is empty.
+func (q PriorityQueue[P, T]) Peek(ctx context.Context) (priority P, elem T, err error) {
+	kv, err := q.head(ctx)
+	if err != nil {
+		return priority, elem, err
+	}
+	return kv.Key.K1(), kv.Value, nil
+}
+
+// Pop removes the element with the smallest priority and returns it along with its priority.
+// Returns ErrEmptyQueue if the PriorityQueue is empty.
+func (q PriorityQueue[P, T]) Pop(ctx context.Context) (priority P, elem T, err error) {
+	kv, err := q.head(ctx)
+	if err != nil {
+		return priority, elem, err
+	}
+	err = q.elements.Remove(ctx, kv.Key)
+	if err != nil {
+		return priority, elem, err
+	}
+	return kv.Key.K1(), kv.Value, nil
+}
+
+// PopUntil removes all the elements whose priority is smaller or equal to the provided priority
+// and returns them, along with their priorities, in the order in which they would have been popped.
+func (q PriorityQueue[P, T]) PopUntil(ctx context.Context, priority P) ([]KeyValue[P, T], error) {
+	iter, err := q.elements.Iterate(ctx, NewPrefixUntilPairRange[P, uint64](priority))
+	if err != nil {
+		return nil, err
+	}
+	kvs, err := iter.KeyValues()
+	if err != nil {
+		return nil, err
+	}
+
+	popped := make([]KeyValue[P, T], 0, len(kvs))
+	for _, kv := range kvs {
+		err = q.elements.Remove(ctx, kv.Key)
+		if err != nil {
+			return nil, err
+		}
+		popped = append(popped, KeyValue[P, T]{Key: kv.Key.K1(), Value: kv.Value})
+	}
+	return popped, nil
+}
+
+// Walk walks over the elements of the PriorityQueue in the order in which they would be popped.
+func (q PriorityQueue[P, T]) Walk(ctx context.Context, walkFn func(priority P, elem T) (stop bool, err error)) error {
+	return q.elements.Walk(ctx, nil, func(key Pair[P, uint64], elem T) (bool, error) {
+		return walkFn(key.K1(), elem)
+	})
+}
+
+// head returns the first element of the PriorityQueue or ErrEmptyQueue if the PriorityQueue is empty.
+func (q PriorityQueue[P, T]) head(ctx context.Context) (kv KeyValue[Pair[P, uint64], T], err error) {
+	iter, err := q.elements.Iterate(ctx, nil)
+	if err != nil {
+		return kv, err
+	}
+	defer iter.Close()
+
+	if !iter.Valid() {
+		return kv, ErrEmptyQueue
+	}
+	return iter.KeyValue()
+}
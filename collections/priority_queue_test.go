@@ -0,0 +1,104 @@
+package collections
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/schema"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	sk, ctx := deps()
+	schemaBuilder := NewSchemaBuilder(sk)
+	queue := NewPriorityQueue(schemaBuilder, NewPrefix(0), "queue", Uint64Key, StringValue)
+	_, err := schemaBuilder.Build()
+	require.NoError(t, err)
+
+	_, _, err = queue.Peek(ctx)
+	require.ErrorIs(t, err, ErrEmptyQueue)
+	_, _, err = queue.Pop(ctx)
+	require.ErrorIs(t, err, ErrEmptyQueue)
+
+	// elements with the same priority are popped in insertion order
+	require.NoError(t, queue.Push(ctx, 30, "a"))
+	require.NoError(t, queue.Push(ctx, 10, "b"))
+	require.NoError(t, queue.Push(ctx, 20, "c"))
+	require.NoError(t, queue.Push(ctx, 10, "d"))
+	require.NoError(t, queue.Push(ctx, 40, "e"))
+
+	priority, elem, err := queue.Peek(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), priority)
+	require.Equal(t, "b", elem)
+
+	priority, elem, err = queue.Pop(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), priority)
+	require.Equal(t, "b", elem)
+
+	// pop all the elements with priority <= 30
+	popped, err := queue.PopUntil(ctx, 30)
+	require.NoError(t, err)
+	require.Equal(t, []KeyValue[uint64, string]{{Key: 10, Value: "d"}, {Key: 20, Value: "c"}, {Key: 30, Value: "a"}}, popped)
+
+	popped, err = queue.PopUntil(ctx, 39)
+	require.NoError(t, err)
+	require.Empty(t, popped)
+
+	require.NoError(t, queue.Push(ctx, 35, "f"))
+	var elems []string
+	require.NoError(t, queue.Walk(ctx, func(_ uint64, elem string) (bool, error) {
+		elems = append(elems, elem)
+		return false, nil
+	}))
+	require.Equal(t, []string{"f", "e"}, elems)
+}
+
+func TestPriorityQueueGenesisAndSchema(t *testing.T) {
+	sk, ctx := deps()
+	schemaBuilder := NewSchemaBuilder(sk)
+	queue := NewPriorityQueue(schemaBuilder, NewPrefix(0), "queue", Uint64Key, StringValue)
+	collSchema, err := schemaBuilder.Build()
+	require.NoError(t, err)
+
+	require.NoError(t, queue.Push(ctx, 20, "a"))
+	require.NoError(t, queue.Push(ctx, 10, "b"))
+
+	genesis := map[string]*bufCloser{}
+	require.NoError(t, collSchema.ExportGenesis(ctx, func(field string) (io.WriteCloser, error) {
+		w := newBufCloser(t, "")
+		genesis[field] = w
+		return w, nil
+	}))
+	require.Equal(t, `[{"key":["10","1"],"value":"b"},{"key":["20","0"],"value":"a"}]`, genesis["queue_elements"].String())
+	require.Equal(t, `[{"key":"item","value":"2"}]`, genesis["queue_sequence"].String())
+
+	sk, ctx = deps()
+	schemaBuilder = NewSchemaBuilder(sk)
+	queue = NewPriorityQueue(schemaBuilder, NewPrefix(0), "queue", Uint64Key, StringValue)
+	collSchema, err = schemaBuilder.Build()
+	require.NoError(t, err)
+	require.NoError(t, collSchema.InitGenesis(ctx, func(field string) (io.ReadCloser, error) {
+		return newBufCloser(t, genesis[field].String()), nil
+	}))
+
+	// the sequence is restored so that new elements are ordered after existing ones with the same priority
+	require.NoError(t, queue.Push(ctx, 10, "c"))
+	popped, err := queue.PopUntil(ctx, 20)
+	require.NoError(t, err)
+	require.Equal(t, []KeyValue[uint64, string]{{Key: 10, Value: "b"}, {Key: 10, Value: "c"}, {Key: 20, Value: "a"}}, popped)
+
+	// the elements are exposed to indexers with named key fields
+	moduleCodec, err := collSchema.ModuleCodec(IndexingOptions{})
+	require.NoError(t, err)
+	objType, found := moduleCodec.Schema.LookupStateObjectType("queue_elements")
+	require.True(t, found)
+	require.Equal(t, []schema.Field{
+		{Name: "priority", Kind: schema.Uint64Kind},
+		{Name: "sequence", Kind: schema.Uint64Kind},
+	}, objType.KeyFields)
+	_, found = moduleCodec.Schema.LookupStateObjectType("queue_sequence")
+	require.True(t, found)
+}
@@ -0,0 +1,341 @@
+package collections
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+
+	"cosmossdk.io/collections/codec"
+)
+
+// DefaultQueryLimit is the number of entries returned in a page when no limit is set on a Query.
+const DefaultQueryLimit uint64 = 100
+
+// ErrInvalidCursor is returned when a Query is resumed with a cursor which is malformed
+// or which was not returned by the same query.
+var ErrInvalidCursor = errors.New("collections: invalid query cursor")
+
+// queryCursorVersion is the version of the cursor format, it is the first byte of every cursor.
+const queryCursorVersion byte = 1
+
+// QueryCollection defines the API of a collection which can be queried with a Query.
+// It is implemented by Map and IndexedMap.
+type QueryCollection[K, V any] interface {
+	// Iterate provides an Iterator over the collection within the provided range.
+	Iterate(ctx context.Context, ranger Ranger[K]) (Iterator[K, V], error)
+	// Get returns the value of the provided key.
+	Get(ctx context.Context, key K) (V, error)
+	// KeyCodec returns the KeyCodec of the collection.
+	KeyCodec() codec.KeyCodec[K]
+}
+
+// QueryIndex defines the API of an IndexedMap index which can be queried with a Query.
+// It is implemented by the indexes.Multi and indexes.Range indexes, whose entries are composed of
+// the reference key and the primary key.
+type QueryIndex[ReferenceKey, PrimaryKey any] interface {
+	// Walk calls walkFunc with the reference key and the primary key of every entry of the index
+	// within the provided range.
+	Walk(ctx context.Context, ranger Ranger[Pair[ReferenceKey, PrimaryKey]], walkFunc func(ReferenceKey, PrimaryKey) (stop bool, err error)) error
+	// KeyCodec returns the KeyCodec of the index entries.
+	KeyCodec() codec.KeyCodec[Pair[ReferenceKey, PrimaryKey]]
+}
+
+// QueryPage is a page of results of a Query.
+type QueryPage[K, V any] struct {
+	// Items are the key value pairs of the page.
+	Items []KeyValue[K, V]
+	// NextCursor is an opaque token which resumes the query after the last item of the page
+	// when passed to Query.Page. It is nil if there are no more results.
+	NextCursor []byte
+}
+
+// Query is a typed query over a Map or an IndexedMap which returns results in pages.
+// Results can be restricted to a range of keys, or a range of entries of an index, filtered,
+// and returned in reverse order.
+//
+// Pages are resumed with opaque cursors which point to the key of the last returned
+// result rather than to an offset. This keeps pagination stable under concurrent writes:
+// results which are added or removed before the cursor never cause results after it to be
+// skipped or returned twice, and results added after the cursor are included in later pages.
+// Cursors are bound to the range and the order of the query which returned them.
+//
+// In gRPC handlers the cursor can be read from and written to the key and next_key fields of
+// the cosmos.base.query.v1beta1 PageRequest and PageResponse messages. Counting the total number
+// of results is intentionally not supported as it requires iterating over all of them.
+type Query[K, V any] struct {
+	source  querySource[K, V]
+	filter  func(key K, value V) (include bool, err error)
+	reverse bool
+	limit   uint64
+}
+
+// NewQuery creates a Query over the provided Map or IndexedMap which returns the entries within
+// the provided range. A nil ranger queries all the entries. Prefix queries can be built using
+// Range.Prefix or NewPrefixedPairRange.
+func NewQuery[K, V any, C QueryCollection[K, V]](coll C, ranger Ranger[K]) *Query[K, V] {
+	return &Query[K, V]{
+		source: mapQuerySource[K, V]{coll: coll, ranger: ranger},
+		limit:  DefaultQueryLimit,
+	}
+}
+
+// NewIndexQuery creates a Query which returns the entries of the provided IndexedMap whose index entries
+// are within the provided range, in the order of the index. A nil ranger queries all the index entries.
+// Ranges of reference keys can be built using NewPrefixedPairRange or indexes.NewValueRange.
+func NewIndexQuery[ReferenceKey, PrimaryKey, V any, C QueryCollection[PrimaryKey, V]](
+	coll C,
+	index QueryIndex[ReferenceKey, PrimaryKey],
+	ranger Ranger[Pair[ReferenceKey, PrimaryKey]],
+) *Query[PrimaryKey, V] {
+	return &Query[PrimaryKey, V]{
+		source: indexQuerySource[ReferenceKey, PrimaryKey, V]{coll: coll, index: index, ranger: ranger},
+		limit:  DefaultQueryLimit,
+	}
+}
+
+// Filter makes the query return only the entries for which the provided predicate returns true.
+// Entries which are filtered out don't count towards the limit.
+func (q *Query[K, V]) Filter(predicate func(key K, value V) (include bool, err error)) *Query[K, V] {
+	q.filter = predicate
+	return q
+}
+
+// Reverse reverses the order of the results.
+func (q *Query[K, V]) Reverse(reverse bool) *Query[K, V] {
+	q.reverse = reverse
+	return q
+}
+
+// Limit sets the maximum number of results of a page. A zero limit resets the limit to DefaultQueryLimit.
+func (q *Query[K, V]) Limit(limit uint64) *Query[K, V] {
+	if limit == 0 {
+		limit = DefaultQueryLimit
+	}
+	q.limit = limit
+	return q
+}
+
+// Page returns the page of results which starts after the provided cursor.
+// A nil or empty cursor returns the first page.
+func (q *Query[K, V]) Page(ctx context.Context, cursor []byte) (QueryPage[K, V], error) {
+	fingerprint, err := q.source.fingerprint(q.reverse)
+	if err != nil {
+		return QueryPage[K, V]{}, err
+	}
+
+	var after []byte
+	if len(cursor) != 0 {
+		if len(cursor) < 9 || cursor[0] != queryCursorVersion || binary.BigEndian.Uint64(cursor[1:9]) != fingerprint {
+			return QueryPage[K, V]{}, ErrInvalidCursor
+		}
+		after = cursor[9:]
+	}
+
+	var (
+		page    QueryPage[K, V]
+		lastKey []byte
+	)
+	err = q.source.walk(ctx, after, q.reverse, func(entryKey []byte, key K, value V) (bool, error) {
+		if q.filter != nil {
+			include, err := q.filter(key, value)
+			if err != nil || !include {
+				return false, err
+			}
+		}
+
+		// there are more results than fit in the page, so the next page starts after the last result
+		if uint64(len(page.Items)) == q.limit {
+			page.NextCursor = make([]byte, 9, 9+len(lastKey))
+			page.NextCursor[0] = queryCursorVersion
+			binary.BigEndian.PutUint64(page.NextCursor[1:9], fingerprint)
+			page.NextCursor = append(page.NextCursor, lastKey...)
+			return true, nil
+		}
+
+		page.Items = append(page.Items, KeyValue[K, V]{Key: key, Value: value})
+		lastKey = entryKey
+		return false, nil
+	})
+	if errors.Is(err, ErrInvalidIterator) {
+		// the cursor is beyond the end of the range
+		return QueryPage[K, V]{}, nil
+	}
+	if err != nil {
+		return QueryPage[K, V]{}, err
+	}
+	return page, nil
+}
+
+// querySource is the source of the entries of a Query.
+type querySource[K, V any] interface {
+	// walk calls fn with the encoded entry key, the primary key and the value of every entry after the
+	// entry with the provided encoded key, or of every entry if after is nil.
+	walk(ctx context.Context, after []byte, reverse bool, fn func(entryKey []byte, key K, value V) (stop bool, err error)) error
+	// fingerprint returns a hash of the range and order of the source which is used to bind cursors to the source.
+	fingerprint(reverse bool) (uint64, error)
+}
+
+type mapQuerySource[K, V any] struct {
+	coll   QueryCollection[K, V]
+	ranger Ranger[K]
+}
+
+func (m mapQuerySource[K, V]) walk(ctx context.Context, after []byte, reverse bool, fn func(entryKey []byte, key K, value V) (bool, error)) error {
+	kc := m.coll.KeyCodec()
+	ranger, err := newCursorRanger(kc, m.ranger, after, reverse)
+	if err != nil {
+		return err
+	}
+
+	iter, err := m.coll.Iterate(ctx, ranger)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	for ; iter.Valid(); iter.Next() {
+		kv, err := iter.KeyValue()
+		if err != nil {
+			return err
+		}
+
+		entryKey, err := EncodeKeyWithPrefix(nil, kc, kv.Key)
+		if err != nil {
+			return err
+		}
+
+		stop, err := fn(entryKey, kv.Key, kv.Value)
+		if err != nil || stop {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m mapQuerySource[K, V]) fingerprint(reverse bool) (uint64, error) {
+	return rangeFingerprint(0, m.coll.KeyCodec(), m.ranger, reverse)
+}
+
+type indexQuerySource[ReferenceKey, PrimaryKey, V any] struct {
+	coll   QueryCollection[PrimaryKey, V]
+	index  QueryIndex[ReferenceKey, PrimaryKey]
+	ranger Ranger[Pair[ReferenceKey, PrimaryKey]]
+}
+
+func (i indexQuerySource[ReferenceKey, PrimaryKey, V]) walk(ctx context.Context, after []byte, reverse bool, fn func(entryKey []byte, key PrimaryKey, value V) (bool, error)) error {
+	kc := i.index.KeyCodec()
+	ranger, err := newCursorRanger(kc, i.ranger, after, reverse)
+	if err != nil {
+		return err
+	}
+
+	return i.index.Walk(ctx, ranger, func(refKey ReferenceKey, pk PrimaryKey) (bool, error) {
+		value, err := i.coll.Get(ctx, pk)
+		if err != nil {
+			return true, err
+		}
+
+		entryKey, err := EncodeKeyWithPrefix(nil, kc, Join(refKey, pk))
+		if err != nil {
+			return true, err
+		}
+
+		return fn(entryKey, pk, value)
+	})
+}
+
+func (i indexQuerySource[ReferenceKey, PrimaryKey, V]) fingerprint(reverse bool) (uint64, error) {
+	return rangeFingerprint(1, i.index.KeyCodec(), i.ranger, reverse)
+}
+
+// cursorRanger is a Ranger which resumes the iteration of another Ranger after a cursor key.
+type cursorRanger[K any] struct {
+	ranger  Ranger[K]
+	after   *K
+	reverse bool
+}
+
+// newCursorRanger decodes the cursor key and returns a Ranger which iterates over the range
+// after the cursor key in the provided order.
+func newCursorRanger[K any](kc codec.KeyCodec[K], ranger Ranger[K], after []byte, reverse bool) (cursorRanger[K], error) {
+	r := cursorRanger[K]{ranger: ranger, reverse: reverse}
+	if after == nil {
+		return r, nil
+	}
+
+	n, key, err := kc.Decode(after)
+	if err != nil || n != len(after) {
+		return cursorRanger[K]{}, ErrInvalidCursor
+	}
+	r.after = &key
+	return r, nil
+}
+
+func (c cursorRanger[K]) RangeValues() (start, end *RangeKey[K], order Order, err error) {
+	if c.ranger != nil {
+		start, end, order, err = c.ranger.RangeValues()
+		if err != nil {
+			return nil, nil, 0, err
+		}
+	}
+
+	if c.reverse {
+		order = reverseOrder(order)
+	}
+
+	// the cursor was returned by a query with the same range, so it is within the range
+	// and replaces the bound from which the iteration starts
+	if c.after != nil {
+		if order == OrderDescending {
+			end = RangeKeyExact(*c.after)
+		} else {
+			start = RangeKeyNext(*c.after)
+		}
+	}
+	return start, end, order, nil
+}
+
+func reverseOrder(order Order) Order {
+	if order == OrderDescending {
+		return OrderAscending
+	}
+	return OrderDescending
+}
+
+// rangeFingerprint hashes the kind of the query source and the bounds and order of the range.
+func rangeFingerprint[K any](sourceKind byte, kc codec.KeyCodec[K], ranger Ranger[K], reverse bool) (uint64, error) {
+	var (
+		start, end *RangeKey[K]
+		order      Order
+		err        error
+	)
+	if ranger != nil {
+		start, end, order, err = ranger.RangeValues()
+		if err != nil {
+			return 0, err
+		}
+	}
+	if reverse {
+		order = reverseOrder(order)
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte{sourceKind, byte(order)})
+	for _, bound := range []*RangeKey[K]{start, end} {
+		if bound == nil {
+			_, _ = h.Write([]byte{0xff})
+			continue
+		}
+
+		bz, err := EncodeKeyWithPrefix(nil, kc, bound.key)
+		if err != nil {
+			return 0, fmt.Errorf("%w: query range: %w", ErrEncoding, err)
+		}
+		_, _ = h.Write([]byte{byte(bound.kind)})
+		_, _ = h.Write(binary.AppendUvarint(nil, uint64(len(bz))))
+		_, _ = h.Write(bz)
+	}
+	return h.Sum64(), nil
+}
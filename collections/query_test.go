@@ -0,0 +1,158 @@
+package collections_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/collections/indexes"
+	"cosmossdk.io/core/testing"
+)
+
+func TestQuery(t *testing.T) {
+	ctx := coretesting.Context()
+	sb := collections.NewSchemaBuilder(coretesting.KVStoreService(ctx, "test"))
+	m := collections.NewMap(sb, collections.NewPrefix(1), "balances", collections.PairKeyCodec(collections.StringKey, collections.StringKey), collections.Uint64Value)
+
+	for _, kv := range []collections.KeyValue[collections.Pair[string, string], uint64]{
+		{Key: collections.Join("alice", "atom"), Value: 1},
+		{Key: collections.Join("alice", "osmo"), Value: 2},
+		{Key: collections.Join("alice", "stake"), Value: 3},
+		{Key: collections.Join("bob", "atom"), Value: 4},
+		{Key: collections.Join("bob", "stake"), Value: 5},
+		{Key: collections.Join("carol", "atom"), Value: 6},
+	} {
+		require.NoError(t, m.Set(ctx, kv.Key, kv.Value))
+	}
+
+	values := func(items []collections.KeyValue[collections.Pair[string, string], uint64]) []uint64 {
+		var values []uint64
+		for _, item := range items {
+			values = append(values, item.Value)
+		}
+		return values
+	}
+
+	collectPages := func(q *collections.Query[collections.Pair[string, string], uint64]) [][]uint64 {
+		t.Helper()
+		var (
+			pages  [][]uint64
+			cursor []byte
+		)
+		for {
+			page, err := q.Page(ctx, cursor)
+			require.NoError(t, err)
+			pages = append(pages, values(page.Items))
+			if page.NextCursor == nil {
+				return pages
+			}
+			cursor = page.NextCursor
+		}
+	}
+
+	// all entries
+	q := collections.NewQuery(m, nil).Limit(4)
+	require.Equal(t, [][]uint64{{1, 2, 3, 4}, {5, 6}}, collectPages(q))
+
+	// an exact multiple of the limit doesn't return an empty page
+	require.Equal(t, [][]uint64{{1, 2, 3}, {4, 5, 6}}, collectPages(q.Limit(3)))
+
+	// prefix and reverse
+	q = collections.NewQuery(m, collections.NewPrefixedPairRange[string, string]("alice")).Limit(2).Reverse(true)
+	require.Equal(t, [][]uint64{{3, 2}, {1}}, collectPages(q))
+
+	// range
+	rng := new(collections.Range[collections.Pair[string, string]]).
+		StartExclusive(collections.Join("alice", "osmo")).
+		EndInclusive(collections.Join("bob", "stake"))
+	q = collections.NewQuery(m, rng).Limit(2)
+	require.Equal(t, [][]uint64{{3, 4}, {5}}, collectPages(q))
+
+	// filter
+	q = collections.NewQuery(m, nil).Limit(2).Filter(func(key collections.Pair[string, string], _ uint64) (bool, error) {
+		return key.K2() == "atom", nil
+	})
+	require.Equal(t, [][]uint64{{1, 4}, {6}}, collectPages(q))
+
+	// writes between pages don't cause results to be skipped or repeated
+	q = collections.NewQuery(m, nil).Limit(2)
+	page, err := q.Page(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1, 2}, values(page.Items))
+	require.NoError(t, m.Remove(ctx, collections.Join("alice", "osmo")))
+	require.NoError(t, m.Set(ctx, collections.Join("alice", "btc"), 7))
+	require.NoError(t, m.Set(ctx, collections.Join("bob", "btc"), 8))
+	page, err = q.Page(ctx, page.NextCursor)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3, 4}, values(page.Items))
+	page, err = q.Page(ctx, page.NextCursor)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{8, 5}, values(page.Items))
+
+	// cursors are bound to the query which returned them
+	_, err = collections.NewQuery(m, nil).Reverse(true).Page(ctx, page.NextCursor)
+	require.ErrorIs(t, err, collections.ErrInvalidCursor)
+	_, err = collections.NewQuery(m, collections.NewPrefixedPairRange[string, string]("bob")).Page(ctx, page.NextCursor)
+	require.ErrorIs(t, err, collections.ErrInvalidCursor)
+	_, err = q.Page(ctx, []byte{1, 2, 3})
+	require.ErrorIs(t, err, collections.ErrInvalidCursor)
+	corrupted := append([]byte{}, page.NextCursor...)
+	corrupted[1] ^= 0xff
+	_, err = q.Page(ctx, corrupted)
+	require.ErrorIs(t, err, collections.ErrInvalidCursor)
+}
+
+type tokensIndexes struct {
+	Tokens *indexes.Range[uint64, string, uint64]
+}
+
+func (t tokensIndexes) IndexesList() []collections.Index[string, uint64] {
+	return []collections.Index[string, uint64]{t.Tokens}
+}
+
+func TestIndexQuery(t *testing.T) {
+	ctx := coretesting.Context()
+	sb := collections.NewSchemaBuilder(coretesting.KVStoreService(ctx, "test"))
+	m := collections.NewIndexedMap(sb, collections.NewPrefix(1), "validators", collections.StringKey, collections.Uint64Value, tokensIndexes{
+		Tokens: indexes.NewRange(sb, collections.NewPrefix(2), "validators_by_tokens", collections.Uint64Key, collections.StringKey, func(_ string, tokens uint64) (uint64, error) {
+			return tokens, nil
+		}),
+	})
+
+	for val, tokens := range map[string]uint64{"a": 50, "b": 10, "c": 40, "d": 20, "e": 30} {
+		require.NoError(t, m.Set(ctx, val, tokens))
+	}
+
+	keys := func(items []collections.KeyValue[string, uint64]) []string {
+		var keys []string
+		for _, item := range items {
+			keys = append(keys, item.Key)
+		}
+		return keys
+	}
+
+	// validators with at least 20 tokens, by descending tokens
+	q := collections.NewIndexQuery(m, m.Indexes.Tokens, indexes.NewValueRange[uint64, string]().StartInclusive(20).Descending()).Limit(2)
+	page, err := q.Page(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "c"}, keys(page.Items))
+	require.Equal(t, uint64(50), page.Items[0].Value)
+
+	page, err = q.Page(ctx, page.NextCursor)
+	require.NoError(t, err)
+	require.Equal(t, []string{"e", "d"}, keys(page.Items))
+	require.Nil(t, page.NextCursor)
+
+	// reversing the index order
+	q = collections.NewIndexQuery(m, m.Indexes.Tokens, nil).Reverse(true).Limit(10)
+	page, err = q.Page(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "c", "e", "d", "b"}, keys(page.Items))
+
+	// map cursors can't be used with index queries
+	mapPage, err := collections.NewQuery(m, nil).Limit(1).Page(ctx, nil)
+	require.NoError(t, err)
+	_, err = q.Page(ctx, mapPage.NextCursor)
+	require.ErrorIs(t, err, collections.ErrInvalidCursor)
+}
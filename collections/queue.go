@@ -0,0 +1,147 @@
+package collections
+
+import (
+	"context"
+	"errors"
+
+	"cosmossdk.io/collections/codec"
+)
+
+// ErrEmptyQueue is returned when trying to peek or pop an element from an empty Queue or PriorityQueue.
+var ErrEmptyQueue = errors.New("collections: queue is empty")
+
+const (
+	QueueElementsNameSuffix   = "_elements"
+	QueueHeadNameSuffix       = "_head"
+	QueueTailNameSuffix       = "_tail"
+	QueueElementsPrefixSuffix = 0x0
+	QueueHeadPrefixSuffix     = 0x1
+	QueueTailPrefixSuffix     = 0x2
+)
+
+// NewQueue creates a new Queue instance. Since Queue relies on three collections, one for the elements
+// and two for the positions of the head and the tail of the queue, it will register three state objects
+// on the schema builder.
+// The elements are a map whose prefix is the provided prefix with a suffix which equals to
+// QueueElementsPrefixSuffix, the name is also suffixed with QueueElementsNameSuffix.
+// The head and the tail are items whose prefixes are the provided prefix suffixed with QueueHeadPrefixSuffix
+// and QueueTailPrefixSuffix, the names are also suffixed with QueueHeadNameSuffix and QueueTailNameSuffix.
+func NewQueue[T any](sb *SchemaBuilder, prefix Prefix, name string, vc codec.ValueCodec[T]) Queue[T] {
+	return Queue[T]{
+		head:     NewSequence(sb, append(prefix, QueueHeadPrefixSuffix), name+QueueHeadNameSuffix),
+		tail:     NewSequence(sb, append(prefix, QueueTailPrefixSuffix), name+QueueTailNameSuffix),
+		elements: NewMap(sb, append(prefix, QueueElementsPrefixSuffix), name+QueueElementsNameSuffix, Uint64Key, vc),
+	}
+}
+
+// Queue is a FIFO queue sitting on top of a KVStore. Elements are pushed to the tail of the queue
+// and popped from its head, both in constant time.
+// It relies on three collections, one for the elements which is a Map[uint64, T] keyed by the
+// position of the element, and two sequences for the positions of the head and the tail.
+type Queue[T any] struct {
+	head     Sequence
+	tail     Sequence
+	elements Map[uint64, T]
+}
+
+// Push adds an element to the tail of the Queue.
+func (q Queue[T]) Push(ctx context.Context, elem T) error {
+	tail, err := q.tail.Next(ctx)
+	if err != nil {
+		return err
+	}
+	return q.elements.Set(ctx, tail, elem)
+}
+
+// Peek returns the element at the head of the Queue without removing it.
+// Returns ErrEmptyQueue if the Queue is empty.
+func (q Queue[T]) Peek(ctx context.Context) (elem T, err error) {
+	head, err := q.headIfNotEmpty(ctx)
+	if err != nil {
+		return elem, err
+	}
+	return q.elements.Get(ctx, head)
+}
+
+// Pop removes the element at the head of the Queue and returns it.
+// Returns ErrEmptyQueue if the Queue is empty.
+func (q Queue[T]) Pop(ctx context.Context) (elem T, err error) {
+	head, err := q.headIfNotEmpty(ctx)
+	if err != nil {
+		return elem, err
+	}
+	elem, err = q.elements.Get(ctx, head)
+	if err != nil {
+		return elem, err
+	}
+	err = q.elements.Remove(ctx, head)
+	if err != nil {
+		return elem, err
+	}
+	return elem, q.head.Set(ctx, head+1)
+}
+
+// PopUntil pops elements from the head of the Queue until the stop function returns true
+// for the element at the head, or until the Queue is empty, and returns the popped elements.
+// The element for which stop returned true is not popped.
+func (q Queue[T]) PopUntil(ctx context.Context, stop func(elem T) (bool, error)) (elems []T, err error) {
+	for {
+		elem, err := q.Peek(ctx)
+		if errors.Is(err, ErrEmptyQueue) {
+			return elems, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		done, err := stop(elem)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return elems, nil
+		}
+
+		_, err = q.Pop(ctx)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, elem)
+	}
+}
+
+// Len returns the number of elements in the Queue.
+func (q Queue[T]) Len(ctx context.Context) (uint64, error) {
+	head, err := q.head.Peek(ctx)
+	if err != nil {
+		return 0, err
+	}
+	tail, err := q.tail.Peek(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return tail - head, nil
+}
+
+// Walk walks over the elements of the Queue from the head to the tail.
+// The walk function is called with the position of the element in the queue, which
+// is stable across pushes and pops, and the element itself.
+func (q Queue[T]) Walk(ctx context.Context, walkFn func(position uint64, elem T) (stop bool, err error)) error {
+	return q.elements.Walk(ctx, nil, walkFn)
+}
+
+// headIfNotEmpty returns the position of the head of the Queue or ErrEmptyQueue if the Queue is empty.
+func (q Queue[T]) headIfNotEmpty(ctx context.Context) (uint64, error) {
+	head, err := q.head.Peek(ctx)
+	if err != nil {
+		return 0, err
+	}
+	tail, err := q.tail.Peek(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if head == tail {
+		return 0, ErrEmptyQueue
+	}
+	return head, nil
+}
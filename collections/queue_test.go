@@ -0,0 +1,105 @@
+package collections
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue(t *testing.T) {
+	sk, ctx := deps()
+	schemaBuilder := NewSchemaBuilder(sk)
+	queue := NewQueue(schemaBuilder, NewPrefix(0), "queue", StringValue)
+	_, err := schemaBuilder.Build()
+	require.NoError(t, err)
+
+	// peek and pop when empty should error with an empty queue error
+	_, err = queue.Peek(ctx)
+	require.ErrorIs(t, err, ErrEmptyQueue)
+	_, err = queue.Pop(ctx)
+	require.ErrorIs(t, err, ErrEmptyQueue)
+
+	for _, elem := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, queue.Push(ctx, elem))
+	}
+
+	length, err := queue.Len(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), length)
+
+	// peek doesn't remove the head
+	elem, err := queue.Peek(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "a", elem)
+
+	elem, err = queue.Pop(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "a", elem)
+
+	// pop until c
+	elems, err := queue.PopUntil(ctx, func(elem string) (bool, error) { return elem == "c", nil })
+	require.NoError(t, err)
+	require.Equal(t, []string{"b"}, elems)
+
+	// push after pops and walk
+	require.NoError(t, queue.Push(ctx, "e"))
+	var positions []uint64
+	elems = nil
+	require.NoError(t, queue.Walk(ctx, func(position uint64, elem string) (bool, error) {
+		positions = append(positions, position)
+		elems = append(elems, elem)
+		return false, nil
+	}))
+	require.Equal(t, []uint64{2, 3, 4}, positions)
+	require.Equal(t, []string{"c", "d", "e"}, elems)
+
+	// pop until empty
+	elems, err = queue.PopUntil(ctx, func(string) (bool, error) { return false, nil })
+	require.NoError(t, err)
+	require.Equal(t, []string{"c", "d", "e"}, elems)
+
+	length, err = queue.Len(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), length)
+	_, err = queue.Pop(ctx)
+	require.ErrorIs(t, err, ErrEmptyQueue)
+}
+
+func TestQueueGenesis(t *testing.T) {
+	sk, ctx := deps()
+	schemaBuilder := NewSchemaBuilder(sk)
+	queue := NewQueue(schemaBuilder, NewPrefix(0), "queue", StringValue)
+	schema, err := schemaBuilder.Build()
+	require.NoError(t, err)
+
+	for _, elem := range []string{"a", "b", "c"} {
+		require.NoError(t, queue.Push(ctx, elem))
+	}
+	_, err = queue.Pop(ctx)
+	require.NoError(t, err)
+
+	genesis := map[string]*bufCloser{}
+	require.NoError(t, schema.ExportGenesis(ctx, func(field string) (io.WriteCloser, error) {
+		w := newBufCloser(t, "")
+		genesis[field] = w
+		return w, nil
+	}))
+	require.Equal(t, `[{"key":"1","value":"b"},{"key":"2","value":"c"}]`, genesis["queue_elements"].String())
+	require.Equal(t, `[{"key":"item","value":"1"}]`, genesis["queue_head"].String())
+	require.Equal(t, `[{"key":"item","value":"3"}]`, genesis["queue_tail"].String())
+
+	// import into a new store
+	sk, ctx = deps()
+	schemaBuilder = NewSchemaBuilder(sk)
+	queue = NewQueue(schemaBuilder, NewPrefix(0), "queue", StringValue)
+	schema, err = schemaBuilder.Build()
+	require.NoError(t, err)
+	require.NoError(t, schema.InitGenesis(ctx, func(field string) (io.ReadCloser, error) {
+		return newBufCloser(t, genesis[field].String()), nil
+	}))
+
+	elems, err := queue.PopUntil(ctx, func(string) (bool, error) { return false, nil })
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "c"}, elems)
+}
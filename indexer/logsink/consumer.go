@@ -0,0 +1,181 @@
+package logsink
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"cosmossdk.io/schema"
+)
+
+const consumersDir = "consumers"
+
+// Record is a packet read from the log along with its offset.
+type Record struct {
+	// Offset is the offset of the frame in the log.
+	Offset uint64
+
+	Packet
+}
+
+// Consumer reads packets from a file log and tracks its position in the log with a named offset which is
+// persisted in the log directory. Consumers only see complete blocks, i.e. packets which are followed by a
+// commit packet, so a consumer which commits its offset after processing each commit packet will process
+// every block exactly once.
+//
+// A consumer can read a log which is being written to by a FileLog in another go routine or process.
+type Consumer struct {
+	dir  string
+	name string
+
+	// base and pos are the segment and byte position of the frame at offset
+	base   uint64
+	pos    int64
+	offset uint64
+
+	// pending are the records of the current block which have been read from the log but not returned by Next
+	pending []Record
+}
+
+// OpenConsumer opens the consumer with the given name for the file log in dir. The consumer starts
+// reading at its last committed offset or at the beginning of the log if it has never committed an offset.
+func OpenConsumer(dir, name string) (*Consumer, error) {
+	if !schema.ValidateName(name) {
+		return nil, fmt.Errorf("invalid consumer name %q", name)
+	}
+
+	c := &Consumer{dir: dir, name: name}
+	bz, err := ioutil.ReadFile(c.offsetPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, c.Seek(0)
+		}
+		return nil, err
+	}
+
+	offset, err := strconv.ParseUint(strings.TrimSpace(string(bz)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid offset for consumer %q: %v", name, err) //nolint:errorlint // we support go 1.12, so no error wrapping
+	}
+
+	return c, c.Seek(offset)
+}
+
+// Next returns the next record in the log. It returns io.EOF if there is no complete block to be read.
+// Next can be called again after io.EOF is returned to check for new data.
+func (c *Consumer) Next() (Record, error) {
+	if len(c.pending) == 0 {
+		err := c.readBlock()
+		if err != nil {
+			return Record{}, err
+		}
+	}
+
+	rec := c.pending[0]
+	c.pending = c.pending[1:]
+	return rec, nil
+}
+
+// readBlock reads the frames up to and including the next commit frame into pending.
+func (c *Consumer) readBlock() error {
+	var frames [][]byte
+	pos, err := readSegmentFrames(c.dir, c.base, c.pos, func(frame []byte) bool {
+		frames = append(frames, frame)
+		return PacketType(frame[frameHeaderSize+1]) != CommitPacket
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(frames) == 0 {
+		// segments are only rolled over after a commit frame, so once the current segment is exhausted
+		// the next block starts in the segment beginning at the current offset
+		if c.offset != c.base && segmentExists(c.dir, c.offset) {
+			c.base, c.pos = c.offset, 0
+			return c.readBlock()
+		}
+		return io.EOF
+	}
+
+	if PacketType(frames[len(frames)-1][frameHeaderSize+1]) != CommitPacket {
+		// the block hasn't been committed yet
+		return io.EOF
+	}
+
+	records := make([]Record, len(frames))
+	for i, frame := range frames {
+		packet, err := DecodeFrame(frame)
+		if err != nil {
+			return fmt.Errorf("failed to decode frame at offset %d: %v", c.offset+uint64(i), err) //nolint:errorlint // we support go 1.12, so no error wrapping
+		}
+		records[i] = Record{Offset: c.offset + uint64(i), Packet: packet}
+	}
+
+	c.pending = records
+	c.pos = pos
+	c.offset += uint64(len(records))
+	return nil
+}
+
+// Offset returns the offset of the next record which will be returned by Next.
+func (c *Consumer) Offset() uint64 {
+	return c.offset - uint64(len(c.pending))
+}
+
+// Seek moves the consumer to the given offset. It does not change the committed offset.
+func (c *Consumer) Seek(offset uint64) error {
+	bases, err := listSegments(c.dir)
+	if err != nil {
+		return err
+	}
+
+	c.base, c.pos, c.offset, c.pending = 0, 0, 0, nil
+	for i := len(bases) - 1; i >= 0; i-- {
+		if bases[i] <= offset {
+			c.base = bases[i]
+			break
+		}
+	}
+	c.offset = c.base
+	if offset == c.offset {
+		return nil
+	}
+
+	c.pos, err = readSegmentFrames(c.dir, c.base, 0, func(frame []byte) bool {
+		c.offset++
+		return c.offset < offset
+	})
+	if err != nil {
+		return err
+	}
+
+	if c.offset != offset {
+		return fmt.Errorf("offset %d is beyond the end of the log", offset)
+	}
+	return nil
+}
+
+// Commit persists the offset of the next record which will be returned by Next as the offset of the consumer.
+func (c *Consumer) Commit() error {
+	err := os.MkdirAll(filepath.Join(c.dir, consumersDir), 0755)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file and rename it so that the offset is updated atomically
+	tmp := c.offsetPath() + ".tmp"
+	err = ioutil.WriteFile(tmp, []byte(strconv.FormatUint(c.Offset(), 10)), 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, c.offsetPath())
+}
+
+func (c *Consumer) offsetPath() string {
+	return filepath.Join(c.dir, consumersDir, c.name+".offset")
+}
@@ -0,0 +1,349 @@
+package logsink
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FileTransport is the transport type of the local segmented file log.
+	FileTransport = "file"
+
+	// DefaultSegmentSize is the default size after which a new segment file is started.
+	DefaultSegmentSize int64 = 64 << 20
+
+	segmentSuffix = ".log"
+)
+
+// FileLogOptions are the options for a FileLog.
+type FileLogOptions struct {
+	// SegmentSize is the size in bytes after which a new segment file is started. Segments are only rolled
+	// after a commit frame, so segments may grow larger than this. It defaults to DefaultSegmentSize.
+	SegmentSize int64
+}
+
+// FileLog is a Transport which appends frames to segment files in a local directory. Each frame in the log
+// has an offset which is its position in the log starting from 0. Segment files are named after the offset of
+// their first frame and are rolled over after a commit frame once they exceed the configured segment size,
+// so every segment except the last ends with a commit frame.
+//
+// Frames which follow the last commit frame are incomplete blocks. They are truncated when the log is opened
+// and are never returned to consumers. Opening the log fails if a corrupted frame precedes a commit frame.
+type FileLog struct {
+	dir  string
+	opts FileLogOptions
+
+	file        *os.File
+	writer      *bufio.Writer
+	segmentSize int64
+	nextOffset  uint64
+
+	lastCommittedHeight uint64
+}
+
+var _ Transport = (*FileLog)(nil)
+
+// OpenFileLog opens or creates the file log in dir and truncates any frames following the last commit frame.
+func OpenFileLog(dir string, opts FileLogOptions) (*FileLog, error) {
+	if dir == "" {
+		return nil, errors.New("missing log directory")
+	}
+	if opts.SegmentSize <= 0 {
+		opts.SegmentSize = DefaultSegmentSize
+	}
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	bases, err := listSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &FileLog{dir: dir, opts: opts}
+	if len(bases) == 0 {
+		return l, l.openSegment(0, 0)
+	}
+
+	// only the last segment can contain frames following the last commit frame
+	base := bases[len(bases)-1]
+	committedSize, numFrames, height, err := scanCommitted(segmentPath(dir, base))
+	if err != nil {
+		return nil, err
+	}
+
+	if height != 0 {
+		l.lastCommittedHeight = height
+	} else if len(bases) > 1 {
+		// the last segment was started after the last commit frame of the previous segment
+		_, _, l.lastCommittedHeight, err = scanCommitted(segmentPath(dir, bases[len(bases)-2]))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = os.Truncate(segmentPath(dir, base), committedSize)
+	if err != nil {
+		return nil, err
+	}
+
+	l.nextOffset = base + numFrames
+	return l, l.openSegment(base, committedSize)
+}
+
+// scanCommitted returns the size and number of frames of the segment up to and including its last commit frame
+// and the height of that commit frame.
+//
+// A torn write can only damage the frames following the last commit frame, which are truncated by OpenFileLog.
+// If a frame which can't be read or fails verification is followed by a valid commit frame, committed data
+// was corrupted and an error is returned instead.
+func scanCommitted(path string) (size int64, numFrames, height uint64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var pos int64
+	var n uint64
+	for {
+		frame, err := ReadFrame(r)
+		if err == io.EOF { //nolint:errorlint // we support go 1.12, so no error wrapping
+			return size, numFrames, height, nil
+		}
+
+		var packet Packet
+		if err == nil {
+			err = verifyFrame(frame)
+		}
+		if err == nil && PacketType(frame[frameHeaderSize+1]) == CommitPacket {
+			packet, err = DecodeFrame(frame)
+		}
+		if err != nil {
+			committed, findErr := findCommitFrame(f, pos+1)
+			if findErr != nil {
+				return 0, 0, 0, findErr
+			}
+			if committed {
+				return 0, 0, 0, fmt.Errorf("corrupted frame at byte %d of segment %s is followed by committed frames: %w", pos, path, err)
+			}
+			return size, numFrames, height, nil
+		}
+
+		pos += int64(len(frame))
+		n++
+		if PacketType(frame[frameHeaderSize+1]) == CommitPacket {
+			size, numFrames, height = pos, n, packet.BlockHeight
+		}
+	}
+}
+
+// findCommitFrame reports whether a valid commit frame starts at any byte position of the file from pos onwards.
+// It is used to tell a torn write at the end of a segment from a corrupted frame in the middle of it, after which
+// frame boundaries are unknown.
+func findCommitFrame(f *os.File, pos int64) (bool, error) {
+	_, err := f.Seek(pos, io.SeekStart)
+	if err != nil {
+		return false, err
+	}
+
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return false, err
+	}
+
+	for i := 0; i+frameHeaderSize+2+frameTrailerSize <= len(data); i++ {
+		bodySize := binary.BigEndian.Uint32(data[i:])
+		if bodySize < 2 || bodySize > maxFrameBodySize {
+			continue
+		}
+
+		end := uint64(i) + frameHeaderSize + uint64(bodySize) + frameTrailerSize
+		if end > uint64(len(data)) {
+			continue
+		}
+
+		frame := data[i:end]
+		if frame[frameHeaderSize] != FormatVersion || PacketType(frame[frameHeaderSize+1]) != CommitPacket {
+			continue
+		}
+
+		if _, err := DecodeFrame(frame); err == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// openSegment opens the segment starting at offset base for appending. size is the current size of the segment.
+func (l *FileLog) openSegment(base uint64, size int64) error {
+	f, err := os.OpenFile(segmentPath(l.dir, base), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	l.file = f
+	l.writer = bufio.NewWriter(f)
+	l.segmentSize = size
+	return nil
+}
+
+// Publish implements Transport.
+func (l *FileLog) Publish(frame []byte) error {
+	if len(frame) < frameHeaderSize+2+frameTrailerSize {
+		return ErrIncompleteFrame
+	}
+
+	// commit frames are decoded to track the last committed height
+	var commit *Packet
+	if PacketType(frame[frameHeaderSize+1]) == CommitPacket {
+		packet, err := DecodeFrame(frame)
+		if err != nil {
+			return err
+		}
+		commit = &packet
+	}
+
+	_, err := l.writer.Write(frame)
+	if err != nil {
+		return err
+	}
+
+	l.segmentSize += int64(len(frame))
+	l.nextOffset++
+	if commit != nil {
+		l.lastCommittedHeight = commit.BlockHeight
+	}
+	return nil
+}
+
+// Flush implements Transport. It syncs the current segment to disk and starts a new segment if the current
+// one has reached the segment size.
+func (l *FileLog) Flush() error {
+	err := l.writer.Flush()
+	if err != nil {
+		return err
+	}
+
+	err = l.file.Sync()
+	if err != nil {
+		return err
+	}
+
+	if l.segmentSize < l.opts.SegmentSize {
+		return nil
+	}
+
+	err = l.file.Close()
+	if err != nil {
+		return err
+	}
+
+	return l.openSegment(l.nextOffset, 0)
+}
+
+// LastCommittedHeight implements Transport.
+func (l *FileLog) LastCommittedHeight() (uint64, error) {
+	return l.lastCommittedHeight, nil
+}
+
+// NextOffset returns the offset of the next frame which will be appended to the log.
+func (l *FileLog) NextOffset() uint64 {
+	return l.nextOffset
+}
+
+// Close implements Transport.
+func (l *FileLog) Close() error {
+	err := l.writer.Flush()
+	if err != nil {
+		return err
+	}
+
+	err = l.file.Sync()
+	if err != nil {
+		return err
+	}
+
+	return l.file.Close()
+}
+
+func segmentPath(dir string, base uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", base, segmentSuffix))
+}
+
+// listSegments returns the base offsets of the segments in dir in ascending order.
+func listSegments(dir string) ([]uint64, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var bases []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+
+		base, err := strconv.ParseUint(strings.TrimSuffix(name, segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		bases = append(bases, base)
+	}
+
+	sort.Slice(bases, func(i, j int) bool { return bases[i] < bases[j] })
+	return bases, nil
+}
+
+func segmentExists(dir string, base uint64) bool {
+	_, err := os.Stat(segmentPath(dir, base))
+	return err == nil
+}
+
+// readSegmentFrames calls fn with each complete frame in the segment starting at base, beginning at byte
+// position pos, until fn returns false or the end of the written data is reached. It returns the position
+// following the last frame passed to fn. A segment which doesn't exist yet is treated as empty.
+func readSegmentFrames(dir string, base uint64, pos int64, fn func(frame []byte) bool) (int64, error) {
+	f, err := os.Open(segmentPath(dir, base))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return pos, nil
+		}
+		return pos, err
+	}
+	defer f.Close()
+
+	_, err = f.Seek(pos, io.SeekStart)
+	if err != nil {
+		return pos, err
+	}
+
+	r := bufio.NewReader(f)
+	for {
+		frame, err := ReadFrame(r)
+		if err != nil {
+			if err == io.EOF || err == ErrIncompleteFrame { //nolint:errorlint // we support go 1.12, so no error wrapping
+				return pos, nil
+			}
+			return pos, err
+		}
+
+		pos += int64(len(frame))
+		if !fn(frame) {
+			return pos, nil
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package logsink
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"cosmossdk.io/schema/appdata"
+)
+
+// publishBlock publishes n+1 start block frames for the height followed by a commit frame if commit is true.
+func publishBlock(t *testing.T, l *FileLog, height uint64, n int, commit bool) {
+	t.Helper()
+	enc := newPacketEncoder()
+	frames := [][]byte{}
+	for i := 0; i <= n; i++ {
+		frame, err := enc.encodeStartBlock(appdata.StartBlockData{Height: height})
+		if err != nil {
+			t.Fatal(err)
+		}
+		frames = append(frames, frame)
+	}
+	if commit {
+		frames = append(frames, enc.encodeCommit(height))
+	}
+
+	for _, frame := range frames {
+		if err := l.Publish(frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if commit {
+		if err := l.Flush(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// readAll reads all available records from the consumer.
+func readAll(t *testing.T, c *Consumer) []Record {
+	t.Helper()
+	var records []Record
+	for {
+		rec, err := c.Next()
+		if err == io.EOF { //nolint:errorlint // we support go 1.12, so no error wrapping
+			return records
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, rec)
+	}
+}
+
+func TestFileLog(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := OpenFileLog(dir, FileLogOptions{SegmentSize: 32})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publishBlock(t, l, 1, 1, true)
+	publishBlock(t, l, 2, 0, true)
+
+	segments, err := listSegments(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) < 2 {
+		t.Fatalf("expected segments to be rolled over, got %v", segments)
+	}
+
+	c, err := OpenConsumer(dir, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// frames of an uncommitted block are not visible to consumers
+	publishBlock(t, l, 3, 0, false)
+	if err := l.writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	records := readAll(t, c)
+	if len(records) != 5 {
+		t.Fatalf("expected 5 records, got %d", len(records))
+	}
+	for i, rec := range records {
+		if rec.Offset != uint64(i) {
+			t.Fatalf("expected offset %d, got %d", i, rec.Offset)
+		}
+	}
+	if records[2].Type != CommitPacket || records[2].BlockHeight != 1 || records[4].BlockHeight != 2 {
+		t.Fatalf("unexpected records %+v", records)
+	}
+
+	if err := c.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// reopening truncates the uncommitted block
+	l, err = OpenFileLog(dir, FileLogOptions{SegmentSize: 32})
+	if err != nil {
+		t.Fatal(err)
+	}
+	height, err := l.LastCommittedHeight()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height != 2 || l.NextOffset() != 5 {
+		t.Fatalf("expected last committed height 2 and next offset 5, got %d and %d", height, l.NextOffset())
+	}
+
+	publishBlock(t, l, 3, 1, true)
+
+	// a consumer resumes from its committed offset
+	c, err = OpenConsumer(dir, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Offset() != 5 {
+		t.Fatalf("expected offset 5, got %d", c.Offset())
+	}
+	records = readAll(t, c)
+	if len(records) != 3 || records[0].Offset != 5 || records[2].BlockHeight != 3 {
+		t.Fatalf("unexpected records %+v", records)
+	}
+
+	// seeking into the middle of a segment
+	if err := c.Seek(1); err != nil {
+		t.Fatal(err)
+	}
+	records = readAll(t, c)
+	if len(records) != 7 || records[0].Offset != 1 {
+		t.Fatalf("unexpected records %+v", records)
+	}
+
+	if err := c.Seek(100); err == nil {
+		t.Fatal("expected error seeking beyond the end of the log")
+	}
+
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileLogTornWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := OpenFileLog(dir, FileLogOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	publishBlock(t, l, 1, 0, true)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate a crash in the middle of writing a frame
+	f, err := os.OpenFile(segmentPath(dir, 0), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frame := newPacketEncoder().encodeCommit(2)
+	if _, err := f.Write(frame[:len(frame)-2]); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err = OpenFileLog(dir, FileLogOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	height, _ := l.LastCommittedHeight()
+	if height != 1 || l.NextOffset() != 2 {
+		t.Fatalf("expected last committed height 1 and next offset 2, got %d and %d", height, l.NextOffset())
+	}
+
+	publishBlock(t, l, 2, 0, true)
+	c, err := OpenConsumer(dir, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := readAll(t, c)
+	if len(records) != 4 || records[3].BlockHeight != 2 {
+		t.Fatalf("unexpected records %+v", records)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileLogCorruption(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := OpenFileLog(dir, FileLogOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	publishBlock(t, l, 1, 0, true)
+	publishBlock(t, l, 2, 0, true)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	bz, err := ioutil.ReadFile(segmentPath(dir, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// damaging a frame which is followed by a commit frame is an error rather than a torn write
+	corrupted := append([]byte(nil), bz...)
+	corrupted[frameHeaderSize+4] ^= 0xff
+	if err := ioutil.WriteFile(segmentPath(dir, 0), corrupted, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenFileLog(dir, FileLogOptions{}); err == nil {
+		t.Fatal("expected error opening a log with a corrupted committed frame")
+	}
+
+	// garbage following the last commit frame is truncated
+	tail := append(append([]byte(nil), bz...), 0xde, 0xad, 0xbe, 0xef, 0x01, 0x02)
+	if err := ioutil.WriteFile(segmentPath(dir, 0), tail, 0644); err != nil {
+		t.Fatal(err)
+	}
+	l, err = OpenFileLog(dir, FileLogOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	height, _ := l.LastCommittedHeight()
+	if height != 2 || l.NextOffset() != 4 {
+		t.Fatalf("expected last committed height 2 and next offset 4, got %d and %d", height, l.NextOffset())
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,171 @@
+package logsink
+
+import (
+	"context"
+	"fmt"
+
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+	"cosmossdk.io/schema/logutil"
+	"cosmossdk.io/schema/view"
+)
+
+type Config struct {
+	// Transport is the type of transport to publish packets with. It defaults to "file".
+	// Other transports can be registered with RegisterTransport.
+	Transport string `json:"transport"`
+
+	// Dir is the directory of the file log.
+	Dir string `json:"dir"`
+
+	// SegmentSize is the size in bytes after which the file log starts a new segment file.
+	SegmentSize int64 `json:"segment_size"`
+
+	// Options are custom options for transports registered with RegisterTransport.
+	Options map[string]interface{} `json:"options"`
+}
+
+type indexerImpl struct {
+	transport Transport
+	encoder   *packetEncoder
+	logger    logutil.Logger
+
+	// lastCommittedHeight is the height of the last block which was committed to the log
+	lastCommittedHeight uint64
+	// height is the height of the current block
+	height uint64
+	// skipBlock is true when the current block was already committed to the log
+	skipBlock bool
+}
+
+func init() {
+	indexer.Register("logsink", indexer.Initializer{
+		InitFunc:   startIndexer,
+		ConfigType: Config{},
+	})
+}
+
+func startIndexer(params indexer.InitParams) (indexer.InitResult, error) {
+	config, ok := params.Config.Config.(Config)
+	if !ok {
+		return indexer.InitResult{}, fmt.Errorf("invalid config type, expected %T got %T", Config{}, params.Config.Config)
+	}
+
+	ctx := params.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	transportType := config.Transport
+	if transportType == "" {
+		transportType = FileTransport
+	}
+
+	factory, ok := transportRegistry[transportType]
+	if !ok {
+		return indexer.InitResult{}, fmt.Errorf("transport %q not found", transportType)
+	}
+
+	transport, err := factory(config)
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	lastCommittedHeight, err := transport.LastCommittedHeight()
+	if err != nil {
+		return indexer.InitResult{}, err
+	}
+
+	idx := &indexerImpl{
+		transport:           transport,
+		encoder:             newPacketEncoder(),
+		logger:              params.Logger,
+		lastCommittedHeight: lastCommittedHeight,
+	}
+
+	go func() {
+		<-ctx.Done()
+		err := transport.Close()
+		if err != nil && idx.logger != nil {
+			idx.logger.Error("failed to close log sink transport", "error", err)
+		}
+	}()
+
+	return indexer.InitResult{
+		Listener: idx.listener(),
+		View:     idx,
+	}, nil
+}
+
+var _ view.AppData = (*indexerImpl)(nil)
+
+// BlockNum implements view.AppData. It returns the height of the last block which was committed to the log
+// so that the indexer manager can catch up the log with any blocks which are missing.
+func (i *indexerImpl) BlockNum() (uint64, error) {
+	return i.lastCommittedHeight, nil
+}
+
+// AppState implements view.AppData. The log sink doesn't provide a view of app state.
+func (i *indexerImpl) AppState() view.AppState {
+	return nil
+}
+
+// listener returns the listener which publishes module initialization, start block, object update and commit
+// packets to the transport. Packets for blocks which were already committed to the log are skipped so that
+// every block is published exactly once even if the app replays blocks after a restart.
+func (i *indexerImpl) listener() appdata.Listener {
+	return appdata.Listener{
+		InitializeModuleData: func(data appdata.ModuleInitializationData) error {
+			frame, err := i.encoder.encodeModuleInitialization(data)
+			if err != nil {
+				return err
+			}
+			return i.transport.Publish(frame)
+		},
+		StartBlock: func(data appdata.StartBlockData) error {
+			i.height = data.Height
+			i.skipBlock = data.Height <= i.lastCommittedHeight
+			if i.skipBlock {
+				return nil
+			}
+
+			frame, err := i.encoder.encodeStartBlock(data)
+			if err != nil {
+				return err
+			}
+			return i.transport.Publish(frame)
+		},
+		OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+			if i.skipBlock {
+				return nil
+			}
+
+			frame, err := i.encoder.encodeObjectUpdate(data)
+			if err != nil {
+				return err
+			}
+			return i.transport.Publish(frame)
+		},
+		Commit: func(data appdata.CommitData) (func() error, error) {
+			if i.skipBlock {
+				i.skipBlock = false
+				return nil, nil
+			}
+
+			err := i.transport.Publish(i.encoder.encodeCommit(i.height))
+			if err != nil {
+				return nil, err
+			}
+
+			err = i.transport.Flush()
+			if err != nil {
+				return nil, err
+			}
+
+			if i.height > i.lastCommittedHeight {
+				i.lastCommittedHeight = i.height
+			}
+			return nil, nil
+		},
+	}
+}
@@ -0,0 +1,173 @@
+package logsink
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+)
+
+func TestIndexer(t *testing.T) {
+	dir := t.TempDir()
+
+	start := func(ctx context.Context) indexer.InitResult {
+		t.Helper()
+		res, err := startIndexer(indexer.InitParams{
+			Config:  indexer.Config{Config: Config{Dir: dir}},
+			Context: ctx,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	send := func(listener appdata.Listener, packets ...appdata.Packet) {
+		t.Helper()
+		for _, packet := range packets {
+			if err := listener.SendPacket(packet); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	update := func(value int32) appdata.ObjectUpdateData {
+		return appdata.ObjectUpdateData{
+			ModuleName: "test",
+			Updates:    []schema.StateObjectUpdate{{TypeName: "singleton", Value: value}},
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	res := start(ctx)
+	blockNum, err := res.View.BlockNum()
+	if err != nil || blockNum != 0 {
+		t.Fatalf("expected block number 0, got %d, %v", blockNum, err)
+	}
+
+	send(res.Listener,
+		appdata.ModuleInitializationData{ModuleName: "test", Schema: testModuleSchema},
+		appdata.StartBlockData{Height: 1},
+		update(1),
+		appdata.CommitData{},
+		appdata.StartBlockData{Height: 2},
+		update(2),
+		appdata.CommitData{},
+	)
+	blockNum, _ = res.View.BlockNum()
+	if blockNum != 2 {
+		t.Fatalf("expected block number 2, got %d", blockNum)
+	}
+
+	// data which isn't part of the packet stream is ignored
+	send(res.Listener, appdata.TxData{BlockNumber: 3}, appdata.EventData{})
+	cancel()
+
+	// after a restart, blocks which were already committed are skipped
+	res = start(context.Background())
+	blockNum, _ = res.View.BlockNum()
+	if blockNum != 2 {
+		t.Fatalf("expected block number 2, got %d", blockNum)
+	}
+	send(res.Listener,
+		appdata.ModuleInitializationData{ModuleName: "test", Schema: testModuleSchema},
+		appdata.StartBlockData{Height: 2},
+		update(2),
+		appdata.CommitData{},
+		appdata.StartBlockData{Height: 3},
+		update(3),
+		appdata.CommitData{},
+	)
+
+	c, err := OpenConsumer(dir, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []PacketType
+	var values []interface{}
+	var heights []uint64
+	for _, rec := range readAll(t, c) {
+		types = append(types, rec.Type)
+		switch data := rec.Data.(type) {
+		case appdata.ObjectUpdateData:
+			values = append(values, data.Updates[0].Value)
+		case appdata.CommitData:
+			heights = append(heights, rec.BlockHeight)
+		}
+	}
+
+	expectedTypes := []PacketType{
+		ModuleInitializationPacket, StartBlockPacket, ObjectUpdatePacket, CommitPacket,
+		StartBlockPacket, ObjectUpdatePacket, CommitPacket,
+		ModuleInitializationPacket, StartBlockPacket, ObjectUpdatePacket, CommitPacket,
+	}
+	if !reflect.DeepEqual(expectedTypes, types) {
+		t.Fatalf("expected packet types %v, got %v", expectedTypes, types)
+	}
+	if !reflect.DeepEqual([]interface{}{int32(1), int32(2), int32(3)}, values) {
+		t.Fatalf("unexpected values %v", values)
+	}
+	if !reflect.DeepEqual([]uint64{1, 2, 3}, heights) {
+		t.Fatalf("unexpected commit heights %v", heights)
+	}
+}
+
+type memTransport struct {
+	frames    [][]byte
+	published int
+}
+
+func (m *memTransport) Publish(frame []byte) error {
+	m.published++
+	m.frames = append(m.frames, frame)
+	return nil
+}
+
+func (m *memTransport) Flush() error { return nil }
+
+func (m *memTransport) LastCommittedHeight() (uint64, error) { return 0, nil }
+
+func (m *memTransport) Close() error { return nil }
+
+func TestIndexerCustomTransport(t *testing.T) {
+	transport := &memTransport{}
+	RegisterTransport("mem", func(cfg Config) (Transport, error) {
+		if cfg.Options["topic"] != "state" {
+			t.Fatalf("expected topic option, got %v", cfg.Options)
+		}
+		return transport, nil
+	})
+
+	res, err := startIndexer(indexer.InitParams{
+		Config: indexer.Config{Config: Config{Transport: "mem", Options: map[string]interface{}{"topic": "state"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = res.Listener.SendPacket(appdata.StartBlockData{Height: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transport.published != 1 {
+		t.Fatalf("expected 1 published frame, got %d", transport.published)
+	}
+
+	packet, err := DecodeFrame(transport.frames[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if packet.Type != StartBlockPacket || packet.BlockHeight != 1 {
+		t.Fatalf("unexpected packet %+v", packet)
+	}
+
+	_, err = startIndexer(indexer.InitParams{
+		Config: indexer.Config{Config: Config{Transport: "unknown"}},
+	})
+	if err == nil {
+		t.Fatal("expected error for unknown transport")
+	}
+}
@@ -0,0 +1,481 @@
+package logsink
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+)
+
+// FormatVersion is the version of the binary packet format written by this package.
+const FormatVersion byte = 1
+
+// PacketType identifies the type of packet stored in a frame.
+type PacketType byte
+
+const (
+	// ModuleInitializationPacket frames contain an appdata.ModuleInitializationData packet.
+	ModuleInitializationPacket PacketType = iota + 1
+
+	// StartBlockPacket frames contain an appdata.StartBlockData packet.
+	StartBlockPacket
+
+	// ObjectUpdatePacket frames contain an appdata.ObjectUpdateData packet.
+	ObjectUpdatePacket
+
+	// CommitPacket frames contain an appdata.CommitData packet.
+	CommitPacket
+)
+
+const (
+	// frameHeaderSize is the size of the length prefix of a frame.
+	frameHeaderSize = 4
+	// frameTrailerSize is the size of the checksum at the end of a frame.
+	frameTrailerSize = 4
+	// maxFrameBodySize is the maximum size of a frame body which we accept when reading.
+	maxFrameBodySize = 1 << 30
+
+	updateFlagDelete       byte = 1
+	updateFlagValueUpdates byte = 2
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Packet is a decoded packet along with the block height it belongs to.
+type Packet struct {
+	// Type is the type of the packet.
+	Type PacketType
+
+	// BlockHeight is the height of the block for StartBlock and Commit packets and zero otherwise.
+	BlockHeight uint64
+
+	// Data is the decoded packet which can be sent to an appdata.Listener.
+	Data appdata.Packet
+}
+
+// packetEncoder encodes appdata packets into frames. It tracks module schemas from module initialization
+// packets so that object updates can be encoded according to the schema of their object type.
+type packetEncoder struct {
+	schemas map[string]schema.ModuleSchema
+}
+
+func newPacketEncoder() *packetEncoder {
+	return &packetEncoder{schemas: map[string]schema.ModuleSchema{}}
+}
+
+func (p *packetEncoder) encodeModuleInitialization(data appdata.ModuleInitializationData) ([]byte, error) {
+	schemaJSON, err := json.Marshal(data.Schema)
+	if err != nil {
+		return nil, err
+	}
+	p.schemas[data.ModuleName] = data.Schema
+
+	e := newFrameEncoder(ModuleInitializationPacket)
+	e.writeString(data.ModuleName)
+	e.writeBytes(schemaJSON)
+	return e.finish(), nil
+}
+
+func (p *packetEncoder) encodeStartBlock(data appdata.StartBlockData) ([]byte, error) {
+	e := newFrameEncoder(StartBlockPacket)
+	e.writeUvarint(data.Height)
+
+	var header []byte
+	if data.HeaderBytes != nil {
+		var err error
+		header, err = data.HeaderBytes()
+		if err != nil {
+			return nil, err
+		}
+	}
+	e.writeBytes(header)
+
+	var headerJSON json.RawMessage
+	if data.HeaderJSON != nil {
+		var err error
+		headerJSON, err = data.HeaderJSON()
+		if err != nil {
+			return nil, err
+		}
+	}
+	e.writeBytes(headerJSON)
+
+	return e.finish(), nil
+}
+
+func (p *packetEncoder) encodeObjectUpdate(data appdata.ObjectUpdateData) ([]byte, error) {
+	modSchema, ok := p.schemas[data.ModuleName]
+	if !ok {
+		return nil, fmt.Errorf("module %s not initialized", data.ModuleName)
+	}
+
+	e := newFrameEncoder(ObjectUpdatePacket)
+	e.writeString(data.ModuleName)
+	e.writeUvarint(uint64(len(data.Updates)))
+	for _, update := range data.Updates {
+		objType, ok := modSchema.LookupStateObjectType(update.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("object type %s not found in module %s", update.TypeName, data.ModuleName)
+		}
+
+		err := e.writeObjectUpdate(objType, update)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode update of object type %s in module %s: %v", update.TypeName, data.ModuleName, err) //nolint:errorlint // we support go 1.12, so no error wrapping
+		}
+	}
+	return e.finish(), nil
+}
+
+func (p *packetEncoder) encodeCommit(height uint64) []byte {
+	e := newFrameEncoder(CommitPacket)
+	e.writeUvarint(height)
+	return e.finish()
+}
+
+// writeObjectUpdate writes the object update. Keys and values are written as a count followed by the
+// values so that they can be decoded without the schema. ValueUpdates are written as a count followed by
+// pairs of field names and values.
+func (e *encoder) writeObjectUpdate(objType schema.StateObjectType, update schema.StateObjectUpdate) error {
+	var flags byte
+	valueUpdates, isValueUpdates := update.Value.(schema.ValueUpdates)
+	if update.Delete {
+		flags |= updateFlagDelete
+	} else if isValueUpdates {
+		flags |= updateFlagValueUpdates
+	}
+
+	e.writeString(update.TypeName)
+	e.writeByte(flags)
+
+	err := e.writeValues(objType.KeyFields, update.Key)
+	if err != nil || update.Delete {
+		return err
+	}
+
+	if !isValueUpdates {
+		return e.writeValues(objType.ValueFields, update.Value)
+	}
+
+	fields := make(map[string]schema.Field, len(objType.ValueFields))
+	for _, field := range objType.ValueFields {
+		fields[field.Name] = field
+	}
+
+	var names []string
+	var values []interface{}
+	err = valueUpdates.Iterate(func(col string, value interface{}) bool {
+		names = append(names, col)
+		values = append(values, value)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	e.writeUvarint(uint64(len(names)))
+	for i, name := range names {
+		field, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("unknown value field %q", name)
+		}
+		e.writeString(name)
+		err = e.writeValue(field, values[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeValues writes the values of fields. value is expected to be a single value if there is one field
+// or a slice of values otherwise.
+func (e *encoder) writeValues(fields []schema.Field, value interface{}) error {
+	switch len(fields) {
+	case 0:
+		e.writeUvarint(0)
+		return nil
+	case 1:
+		e.writeUvarint(1)
+		return e.writeValue(fields[0], value)
+	}
+
+	values, ok := value.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected []interface{} for %d fields, got %T", len(fields), value)
+	}
+	if len(values) != len(fields) {
+		return fmt.Errorf("expected %d values, got %d", len(fields), len(values))
+	}
+
+	e.writeUvarint(uint64(len(values)))
+	for i, field := range fields {
+		err := e.writeValue(field, values[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newFrameEncoder returns an encoder for a frame of the given packet type with space reserved for the
+// length prefix and the version and packet type already written.
+func newFrameEncoder(typ PacketType) *encoder {
+	e := &encoder{buf: make([]byte, frameHeaderSize, 64)}
+	e.writeByte(FormatVersion)
+	e.writeByte(byte(typ))
+	return e
+}
+
+// finish fills in the length prefix of the frame and appends the checksum of the frame body.
+func (e *encoder) finish() []byte {
+	body := e.buf[frameHeaderSize:]
+	binary.BigEndian.PutUint32(e.buf[:frameHeaderSize], uint32(len(body)))
+	var crc [frameTrailerSize]byte
+	binary.BigEndian.PutUint32(crc[:], crc32.Checksum(body, crcTable))
+	return append(e.buf, crc[:]...)
+}
+
+// ErrIncompleteFrame is returned when reading a frame which was only partially written.
+var ErrIncompleteFrame = errors.New("incomplete frame")
+
+// ReadFrame reads the next frame from r. io.EOF is returned if r is at the end of its data and
+// ErrIncompleteFrame if r ends in the middle of a frame.
+// Each frame consists of a 4-byte big endian length of the frame body, the frame body and a 4-byte big
+// endian CRC-32C checksum of the frame body. The frame body starts with the format version and packet type.
+func ReadFrame(r io.Reader) ([]byte, error) {
+	var header [frameHeaderSize]byte
+	n, err := io.ReadFull(r, header[:])
+	if err != nil {
+		if n == 0 && err == io.EOF { //nolint:errorlint // we support go 1.12, so no error wrapping
+			return nil, io.EOF
+		}
+		return nil, ErrIncompleteFrame
+	}
+
+	bodySize := binary.BigEndian.Uint32(header[:])
+	if bodySize < 2 || bodySize > maxFrameBodySize {
+		return nil, fmt.Errorf("invalid frame body size %d", bodySize)
+	}
+
+	frame := make([]byte, frameHeaderSize+int(bodySize)+frameTrailerSize)
+	copy(frame, header[:])
+	_, err = io.ReadFull(r, frame[frameHeaderSize:])
+	if err != nil {
+		return nil, ErrIncompleteFrame
+	}
+	return frame, nil
+}
+
+// verifyFrame checks that the length prefix of a frame matches its size and that the checksum matches its body.
+func verifyFrame(frame []byte) error {
+	if len(frame) < frameHeaderSize+2+frameTrailerSize {
+		return ErrIncompleteFrame
+	}
+
+	bodySize := binary.BigEndian.Uint32(frame[:frameHeaderSize])
+	if uint64(len(frame)) != uint64(frameHeaderSize)+uint64(bodySize)+frameTrailerSize {
+		return ErrIncompleteFrame
+	}
+
+	body := frame[frameHeaderSize : frameHeaderSize+int(bodySize)]
+	crc := binary.BigEndian.Uint32(frame[frameHeaderSize+int(bodySize):])
+	if crc32.Checksum(body, crcTable) != crc {
+		return errors.New("frame checksum mismatch")
+	}
+	return nil
+}
+
+// DecodeFrame verifies and decodes a frame written by the log sink.
+func DecodeFrame(frame []byte) (Packet, error) {
+	err := verifyFrame(frame)
+	if err != nil {
+		return Packet{}, err
+	}
+
+	body := frame[frameHeaderSize : len(frame)-frameTrailerSize]
+	if body[0] != FormatVersion {
+		return Packet{}, fmt.Errorf("unsupported format version %d", body[0])
+	}
+
+	typ := PacketType(body[1])
+	d := &decoder{buf: body[2:]}
+	var packet Packet
+	switch typ {
+	case ModuleInitializationPacket:
+		packet, err = d.readModuleInitialization()
+	case StartBlockPacket:
+		packet, err = d.readStartBlock()
+	case ObjectUpdatePacket:
+		packet, err = d.readObjectUpdate()
+	case CommitPacket:
+		var height uint64
+		height, err = d.readUvarint()
+		packet = Packet{BlockHeight: height, Data: appdata.CommitData{}}
+	default:
+		return Packet{}, fmt.Errorf("unknown packet type %d", typ)
+	}
+	if err != nil {
+		return Packet{}, fmt.Errorf("failed to decode packet of type %d: %v", typ, err) //nolint:errorlint // we support go 1.12, so no error wrapping
+	}
+	if len(d.buf) != 0 {
+		return Packet{}, fmt.Errorf("unexpected %d trailing bytes in packet of type %d", len(d.buf), typ)
+	}
+
+	packet.Type = typ
+	return packet, nil
+}
+
+func (d *decoder) readModuleInitialization() (Packet, error) {
+	moduleName, err := d.readString()
+	if err != nil {
+		return Packet{}, err
+	}
+
+	schemaJSON, err := d.readBytes()
+	if err != nil {
+		return Packet{}, err
+	}
+
+	var modSchema schema.ModuleSchema
+	err = json.Unmarshal(schemaJSON, &modSchema)
+	if err != nil {
+		return Packet{}, err
+	}
+
+	return Packet{Data: appdata.ModuleInitializationData{
+		ModuleName: moduleName,
+		Schema:     modSchema,
+	}}, nil
+}
+
+func (d *decoder) readStartBlock() (Packet, error) {
+	height, err := d.readUvarint()
+	if err != nil {
+		return Packet{}, err
+	}
+
+	header, err := d.readBytes()
+	if err != nil {
+		return Packet{}, err
+	}
+
+	headerJSON, err := d.readBytes()
+	if err != nil {
+		return Packet{}, err
+	}
+
+	data := appdata.StartBlockData{Height: height}
+	if len(header) > 0 {
+		data.HeaderBytes = func() ([]byte, error) { return header, nil }
+	}
+	if len(headerJSON) > 0 {
+		data.HeaderJSON = func() (json.RawMessage, error) { return headerJSON, nil }
+	}
+	return Packet{BlockHeight: height, Data: data}, nil
+}
+
+func (d *decoder) readObjectUpdate() (Packet, error) {
+	moduleName, err := d.readString()
+	if err != nil {
+		return Packet{}, err
+	}
+
+	n, err := d.readUvarint()
+	if err != nil {
+		return Packet{}, err
+	}
+	if n > uint64(len(d.buf)) {
+		return Packet{}, errUnexpectedEnd
+	}
+
+	updates := make([]schema.StateObjectUpdate, n)
+	for i := range updates {
+		updates[i], err = d.readStateObjectUpdate()
+		if err != nil {
+			return Packet{}, err
+		}
+	}
+
+	return Packet{Data: appdata.ObjectUpdateData{
+		ModuleName: moduleName,
+		Updates:    updates,
+	}}, nil
+}
+
+func (d *decoder) readStateObjectUpdate() (schema.StateObjectUpdate, error) {
+	typeName, err := d.readString()
+	if err != nil {
+		return schema.StateObjectUpdate{}, err
+	}
+
+	flags, err := d.readByte()
+	if err != nil {
+		return schema.StateObjectUpdate{}, err
+	}
+
+	update := schema.StateObjectUpdate{
+		TypeName: typeName,
+		Delete:   flags&updateFlagDelete != 0,
+	}
+
+	update.Key, err = d.readValues()
+	if err != nil || update.Delete {
+		return update, err
+	}
+
+	if flags&updateFlagValueUpdates == 0 {
+		update.Value, err = d.readValues()
+		return update, err
+	}
+
+	n, err := d.readUvarint()
+	if err != nil {
+		return update, err
+	}
+
+	valueUpdates := schema.MapValueUpdates{}
+	for i := uint64(0); i < n; i++ {
+		name, err := d.readString()
+		if err != nil {
+			return update, err
+		}
+
+		valueUpdates[name], err = d.readValue()
+		if err != nil {
+			return update, err
+		}
+	}
+	update.Value = valueUpdates
+	return update, nil
+}
+
+// readValues reads values written by writeValues.
+func (d *decoder) readValues() (interface{}, error) {
+	n, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case n == 0:
+		return nil, nil
+	case n == 1:
+		return d.readValue()
+	case n > uint64(len(d.buf)):
+		return nil, errUnexpectedEnd
+	}
+
+	values := make([]interface{}, n)
+	for i := range values {
+		values[i], err = d.readValue()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,186 @@
+package logsink
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+)
+
+var testModuleSchema = schema.MustCompileModuleSchema(
+	schema.EnumType{
+		Name:   "status",
+		Values: []schema.EnumValueDefinition{{Name: "active", Value: 0}, {Name: "inactive", Value: 1}},
+	},
+	schema.StateObjectType{
+		Name: "all_kinds",
+		KeyFields: []schema.Field{
+			{Name: "id", Kind: schema.Uint64Kind},
+			{Name: "addr", Kind: schema.AddressKind},
+		},
+		ValueFields: []schema.Field{
+			{Name: "str", Kind: schema.StringKind},
+			{Name: "bz", Kind: schema.BytesKind},
+			{Name: "i8", Kind: schema.Int8Kind},
+			{Name: "u8", Kind: schema.Uint8Kind},
+			{Name: "i16", Kind: schema.Int16Kind},
+			{Name: "u16", Kind: schema.Uint16Kind},
+			{Name: "i32", Kind: schema.Int32Kind},
+			{Name: "u32", Kind: schema.Uint32Kind},
+			{Name: "i64", Kind: schema.Int64Kind},
+			{Name: "integer", Kind: schema.IntegerKind},
+			{Name: "decimal", Kind: schema.DecimalKind},
+			{Name: "b", Kind: schema.BoolKind},
+			{Name: "time", Kind: schema.TimeKind},
+			{Name: "duration", Kind: schema.DurationKind},
+			{Name: "f32", Kind: schema.Float32Kind},
+			{Name: "f64", Kind: schema.Float64Kind},
+			{Name: "status", Kind: schema.EnumKind, ReferencedType: "status"},
+			{Name: "json", Kind: schema.JSONKind},
+			{Name: "nullable", Kind: schema.StringKind, Nullable: true},
+		},
+	},
+	schema.StateObjectType{
+		Name:        "singleton",
+		ValueFields: []schema.Field{{Name: "value", Kind: schema.Int32Kind}},
+	},
+	schema.StateObjectType{
+		Name:      "set",
+		KeyFields: []schema.Field{{Name: "key", Kind: schema.StringKind}},
+	},
+)
+
+func TestPacketRoundTrip(t *testing.T) {
+	enc := newPacketEncoder()
+
+	roundTrip := func(frame []byte, err error) Packet {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		packet, err := DecodeFrame(frame)
+		if err != nil {
+			t.Fatal(err)
+		}
+		readFrame, err := ReadFrame(bytes.NewReader(frame))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(frame, readFrame) {
+			t.Fatalf("expected ReadFrame to return the frame")
+		}
+		return packet
+	}
+
+	packet := roundTrip(enc.encodeModuleInitialization(appdata.ModuleInitializationData{ModuleName: "test", Schema: testModuleSchema}))
+	if packet.Type != ModuleInitializationPacket {
+		t.Fatalf("unexpected packet type %d", packet.Type)
+	}
+	initData := packet.Data.(appdata.ModuleInitializationData)
+	if initData.ModuleName != "test" {
+		t.Fatalf("unexpected module name %s", initData.ModuleName)
+	}
+	expectedSchema, _ := json.Marshal(testModuleSchema)
+	actualSchema, _ := json.Marshal(initData.Schema)
+	if !bytes.Equal(expectedSchema, actualSchema) {
+		t.Fatalf("expected schema %s, got %s", expectedSchema, actualSchema)
+	}
+
+	packet = roundTrip(enc.encodeStartBlock(appdata.StartBlockData{
+		Height:      7,
+		HeaderBytes: func() ([]byte, error) { return []byte("header"), nil },
+	}))
+	if packet.Type != StartBlockPacket || packet.BlockHeight != 7 {
+		t.Fatalf("unexpected packet %+v", packet)
+	}
+	header, _ := packet.Data.(appdata.StartBlockData).HeaderBytes()
+	if string(header) != "header" || packet.Data.(appdata.StartBlockData).HeaderJSON != nil {
+		t.Fatalf("unexpected header %s", header)
+	}
+
+	updates := []schema.StateObjectUpdate{
+		{
+			TypeName: "all_kinds",
+			Key:      []interface{}{uint64(1), []byte{0x01, 0x02}},
+			Value: []interface{}{
+				"str", []byte("bz"), int8(-8), uint8(8), int16(-16), uint16(16), int32(-32), uint32(32), int64(-64),
+				"-123", "1.5", true, time.Unix(1, 2), time.Second, float32(1.5), 2.5, "inactive",
+				json.RawMessage(`{"a":1}`), nil,
+			},
+		},
+		{
+			TypeName: "all_kinds",
+			Key:      []interface{}{uint64(2), []byte{0x03}},
+			Value:    schema.MapValueUpdates{"str": "updated", "nullable": "x"},
+		},
+		{
+			TypeName: "all_kinds",
+			Key:      []interface{}{uint64(3), []byte{0x04}},
+			Delete:   true,
+		},
+		{TypeName: "singleton", Value: int32(5)},
+		{TypeName: "set", Key: "member"},
+	}
+	packet = roundTrip(enc.encodeObjectUpdate(appdata.ObjectUpdateData{ModuleName: "test", Updates: updates}))
+	if packet.Type != ObjectUpdatePacket {
+		t.Fatalf("unexpected packet type %d", packet.Type)
+	}
+	expected := appdata.ObjectUpdateData{ModuleName: "test", Updates: updates}
+	if !reflect.DeepEqual(expected, packet.Data) {
+		t.Fatalf("expected %+v, got %+v", expected, packet.Data)
+	}
+
+	packet = roundTrip(enc.encodeCommit(7), nil)
+	if packet.Type != CommitPacket || packet.BlockHeight != 7 {
+		t.Fatalf("unexpected packet %+v", packet)
+	}
+}
+
+func TestPacketEncodeErrors(t *testing.T) {
+	enc := newPacketEncoder()
+
+	_, err := enc.encodeObjectUpdate(appdata.ObjectUpdateData{ModuleName: "test"})
+	if err == nil {
+		t.Fatal("expected error for uninitialized module")
+	}
+
+	_, err = enc.encodeModuleInitialization(appdata.ModuleInitializationData{ModuleName: "test", Schema: testModuleSchema})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, update := range []schema.StateObjectUpdate{
+		{TypeName: "unknown"},
+		{TypeName: "singleton", Value: "wrong type"},
+		{TypeName: "singleton", Value: nil},
+		{TypeName: "all_kinds", Key: uint64(1)},
+		{TypeName: "singleton", Value: schema.MapValueUpdates{"unknown": int32(1)}},
+	} {
+		_, err = enc.encodeObjectUpdate(appdata.ObjectUpdateData{ModuleName: "test", Updates: []schema.StateObjectUpdate{update}})
+		if err == nil {
+			t.Fatalf("expected error for update %+v", update)
+		}
+	}
+}
+
+func TestDecodeFrameErrors(t *testing.T) {
+	frame := newPacketEncoder().encodeCommit(1)
+
+	corrupted := append([]byte(nil), frame...)
+	corrupted[6] ^= 0xff
+	if _, err := DecodeFrame(corrupted); err == nil {
+		t.Fatal("expected checksum error")
+	}
+
+	if _, err := DecodeFrame(frame[:len(frame)-1]); err != ErrIncompleteFrame { //nolint:errorlint // we support go 1.12, so no error wrapping
+		t.Fatalf("expected ErrIncompleteFrame, got %v", err)
+	}
+
+	if _, err := ReadFrame(bytes.NewReader(frame[:len(frame)-1])); err != ErrIncompleteFrame { //nolint:errorlint // we support go 1.12, so no error wrapping
+		t.Fatalf("expected ErrIncompleteFrame, got %v", err)
+	}
+}
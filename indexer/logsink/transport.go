@@ -0,0 +1,45 @@
+package logsink
+
+import "fmt"
+
+// Transport publishes frames to a durable, ordered log. Frames are published in order from a single
+// go routine. A Kafka or NATS JetStream client can implement this interface to publish the packet
+// stream to a broker instead of the local file log.
+type Transport interface {
+	// Publish appends the frame to the log. Frames may be buffered until Flush is called.
+	Publish(frame []byte) error
+
+	// Flush blocks until all published frames have been durably stored. It is called after
+	// every commit frame.
+	Flush() error
+
+	// LastCommittedHeight returns the block height of the last commit frame which was durably stored
+	// or 0 if there is none. Any frames which were published after that commit frame but not flushed must
+	// be discarded by the transport or ignored by consumers because the indexer will publish them again.
+	LastCommittedHeight() (uint64, error)
+
+	// Close flushes and closes the transport.
+	Close() error
+}
+
+// TransportFactory creates a transport from the log sink configuration.
+type TransportFactory func(cfg Config) (Transport, error)
+
+var transportRegistry = map[string]TransportFactory{
+	FileTransport: func(cfg Config) (Transport, error) {
+		return OpenFileLog(cfg.Dir, FileLogOptions{SegmentSize: cfg.SegmentSize})
+	},
+}
+
+// RegisterTransport registers a transport type which can be selected with Config.Transport.
+func RegisterTransport(transportType string, factory TransportFactory) {
+	if _, ok := transportRegistry[transportType]; ok {
+		panic(fmt.Sprintf("transport %s already registered", transportType))
+	}
+
+	if factory == nil {
+		panic(fmt.Sprintf("transport %s has no factory", transportType))
+	}
+
+	transportRegistry[transportType] = factory
+}
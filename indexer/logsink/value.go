@@ -0,0 +1,237 @@
+package logsink
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"cosmossdk.io/schema"
+)
+
+// encoder appends binary encoded data to a byte slice.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) writeByte(b byte) {
+	e.buf = append(e.buf, b)
+}
+
+func (e *encoder) writeUvarint(x uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], x)
+	e.buf = append(e.buf, tmp[:n]...)
+}
+
+func (e *encoder) writeVarint(x int64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(tmp[:], x)
+	e.buf = append(e.buf, tmp[:n]...)
+}
+
+func (e *encoder) writeFixed64(x uint64) {
+	var tmp [8]byte
+	binary.BigEndian.PutUint64(tmp[:], x)
+	e.buf = append(e.buf, tmp[:]...)
+}
+
+func (e *encoder) writeBytes(bz []byte) {
+	e.writeUvarint(uint64(len(bz)))
+	e.buf = append(e.buf, bz...)
+}
+
+func (e *encoder) writeString(s string) {
+	e.writeUvarint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// writeValue writes a value of the field's kind prefixed with the kind so that it can be decoded without the
+// schema. A nil value is written as InvalidKind.
+func (e *encoder) writeValue(field schema.Field, value interface{}) error {
+	if value == nil {
+		if !field.Nullable {
+			return fmt.Errorf("field %q is not nullable", field.Name)
+		}
+		e.writeByte(byte(schema.InvalidKind))
+		return nil
+	}
+
+	err := field.Kind.ValidateValueType(value)
+	if err != nil {
+		return fmt.Errorf("invalid value for field %q: %v", field.Name, err) //nolint:errorlint // we support go 1.12, so no error wrapping
+	}
+
+	e.writeByte(byte(field.Kind))
+	switch field.Kind {
+	case schema.StringKind, schema.IntegerKind, schema.DecimalKind, schema.EnumKind:
+		e.writeString(value.(string))
+	case schema.BytesKind, schema.AddressKind:
+		e.writeBytes(value.([]byte))
+	case schema.JSONKind:
+		e.writeBytes(value.(json.RawMessage))
+	case schema.Int8Kind:
+		e.writeVarint(int64(value.(int8)))
+	case schema.Int16Kind:
+		e.writeVarint(int64(value.(int16)))
+	case schema.Int32Kind:
+		e.writeVarint(int64(value.(int32)))
+	case schema.Int64Kind:
+		e.writeVarint(value.(int64))
+	case schema.Uint8Kind:
+		e.writeUvarint(uint64(value.(uint8)))
+	case schema.Uint16Kind:
+		e.writeUvarint(uint64(value.(uint16)))
+	case schema.Uint32Kind:
+		e.writeUvarint(uint64(value.(uint32)))
+	case schema.Uint64Kind:
+		e.writeUvarint(value.(uint64))
+	case schema.BoolKind:
+		if value.(bool) {
+			e.writeByte(1)
+		} else {
+			e.writeByte(0)
+		}
+	case schema.Float32Kind:
+		e.writeFixed64(uint64(math.Float32bits(value.(float32))))
+	case schema.Float64Kind:
+		e.writeFixed64(math.Float64bits(value.(float64)))
+	case schema.TimeKind:
+		e.writeVarint(value.(time.Time).UnixNano())
+	case schema.DurationKind:
+		e.writeVarint(int64(value.(time.Duration)))
+	default:
+		return fmt.Errorf("unsupported kind %s for field %q", field.Kind, field.Name)
+	}
+	return nil
+}
+
+var errUnexpectedEnd = errors.New("unexpected end of data")
+
+// decoder reads binary encoded data written by encoder.
+type decoder struct {
+	buf []byte
+}
+
+func (d *decoder) readByte() (byte, error) {
+	if len(d.buf) == 0 {
+		return 0, errUnexpectedEnd
+	}
+	b := d.buf[0]
+	d.buf = d.buf[1:]
+	return b, nil
+}
+
+func (d *decoder) readUvarint() (uint64, error) {
+	x, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		return 0, errors.New("invalid uvarint")
+	}
+	d.buf = d.buf[n:]
+	return x, nil
+}
+
+func (d *decoder) readVarint() (int64, error) {
+	x, n := binary.Varint(d.buf)
+	if n <= 0 {
+		return 0, errors.New("invalid varint")
+	}
+	d.buf = d.buf[n:]
+	return x, nil
+}
+
+func (d *decoder) readFixed64() (uint64, error) {
+	if len(d.buf) < 8 {
+		return 0, errUnexpectedEnd
+	}
+	x := binary.BigEndian.Uint64(d.buf)
+	d.buf = d.buf[8:]
+	return x, nil
+}
+
+func (d *decoder) readBytes() ([]byte, error) {
+	n, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(d.buf)) < n {
+		return nil, errUnexpectedEnd
+	}
+	bz := make([]byte, n)
+	copy(bz, d.buf[:n])
+	d.buf = d.buf[n:]
+	return bz, nil
+}
+
+func (d *decoder) readString() (string, error) {
+	bz, err := d.readBytes()
+	return string(bz), err
+}
+
+// readValue reads a value written by writeValue and returns it as the Go type of its kind.
+func (d *decoder) readValue() (interface{}, error) {
+	b, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	kind := schema.Kind(b)
+	switch kind {
+	case schema.InvalidKind:
+		return nil, nil
+	case schema.StringKind, schema.IntegerKind, schema.DecimalKind, schema.EnumKind:
+		return d.readString()
+	case schema.BytesKind, schema.AddressKind:
+		return d.readBytes()
+	case schema.JSONKind:
+		bz, err := d.readBytes()
+		return json.RawMessage(bz), err
+	case schema.Int8Kind, schema.Int16Kind, schema.Int32Kind, schema.Int64Kind, schema.TimeKind, schema.DurationKind:
+		x, err := d.readVarint()
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case schema.Int8Kind:
+			return int8(x), nil
+		case schema.Int16Kind:
+			return int16(x), nil
+		case schema.Int32Kind:
+			return int32(x), nil
+		case schema.TimeKind:
+			return time.Unix(0, x), nil
+		case schema.DurationKind:
+			return time.Duration(x), nil
+		default:
+			return x, nil
+		}
+	case schema.Float32Kind:
+		x, err := d.readFixed64()
+		return math.Float32frombits(uint32(x)), err
+	case schema.Float64Kind:
+		x, err := d.readFixed64()
+		return math.Float64frombits(x), err
+	case schema.Uint8Kind, schema.Uint16Kind, schema.Uint32Kind, schema.Uint64Kind:
+		x, err := d.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case schema.Uint8Kind:
+			return uint8(x), nil
+		case schema.Uint16Kind:
+			return uint16(x), nil
+		case schema.Uint32Kind:
+			return uint32(x), nil
+		default:
+			return x, nil
+		}
+	case schema.BoolKind:
+		b, err := d.readByte()
+		return b != 0, err
+	default:
+		return nil, fmt.Errorf("unsupported kind %d", b)
+	}
+}
@@ -6,6 +6,12 @@ CREATE OR REPLACE FUNCTION nanos_to_timestamptz(nanos bigint) RETURNS timestampt
     SELECT to_timestamp(nanos / 1000000000) + (nanos / 1000000000) * INTERVAL '1 microsecond'
 $$ LANGUAGE SQL IMMUTABLE;
 
+CREATE TABLE IF NOT EXISTS module_schema
+(
+    module_name TEXT  NOT NULL PRIMARY KEY,
+    schema      JSONB NOT NULL
+);
+
 CREATE TABLE IF NOT EXISTS block
 (
     number BIGINT NOT NULL PRIMARY KEY,
@@ -18,9 +24,21 @@ CREATE TABLE IF NOT EXISTS tx
     block_number   BIGINT NOT NULL REFERENCES block (number),
     index_in_block BIGINT NOT NULL,
     data           JSONB NULL,
-    bytes          BYTEA NULL 
+    bytes          BYTEA NULL,
+    hash           BYTEA NULL,
+    signers        TEXT[] NULL,
+    msg_type_urls  TEXT[] NULL
 );
 
+-- add columns which were introduced after the tx table was first created
+ALTER TABLE tx ADD COLUMN IF NOT EXISTS hash BYTEA NULL;
+ALTER TABLE tx ADD COLUMN IF NOT EXISTS signers TEXT[] NULL;
+ALTER TABLE tx ADD COLUMN IF NOT EXISTS msg_type_urls TEXT[] NULL;
+
+CREATE INDEX IF NOT EXISTS tx_hash_idx ON tx (hash);
+CREATE INDEX IF NOT EXISTS tx_signers_idx ON tx USING GIN (signers);
+CREATE INDEX IF NOT EXISTS tx_msg_type_urls_idx ON tx USING GIN (msg_type_urls);
+
 CREATE TABLE IF NOT EXISTS event
 (
     id           BIGSERIAL PRIMARY KEY,
@@ -15,6 +15,18 @@ func (tm *objectIndexer) createTable(ctx context.Context, conn dbConn) error {
 		return err
 	}
 
+	if tm.options.retainHistory {
+		_, err = fmt.Fprintf(buf, "\n")
+		if err != nil {
+			return err
+		}
+
+		err = tm.createHistoryTableSql(buf)
+		if err != nil {
+			return err
+		}
+	}
+
 	sqlStr := buf.String()
 	if tm.options.logger != nil {
 		tm.options.logger.Debug("Creating table", "table", tm.tableName(), "sql", sqlStr)
@@ -29,27 +41,10 @@ func (tm *objectIndexer) createTableSql(writer io.Writer) error {
 	if err != nil {
 		return err
 	}
-	isSingleton := false
-	if len(tm.typ.KeyFields) == 0 {
-		isSingleton = true
-		_, err = fmt.Fprintf(writer, "_id INTEGER NOT NULL CHECK (_id = 1),\n\t")
-		if err != nil {
-			return err
-		}
-	} else {
-		for _, field := range tm.typ.KeyFields {
-			err = tm.createColumnDefinition(writer, field)
-			if err != nil {
-				return err
-			}
-		}
-	}
 
-	for _, field := range tm.typ.ValueFields {
-		err = tm.createColumnDefinition(writer, field)
-		if err != nil {
-			return err
-		}
+	pKeys, err := tm.createKeyValueColumnDefinitions(writer)
+	if err != nil {
+		return err
 	}
 
 	// add _deleted column when we have RetainDeletions set and enabled
@@ -60,20 +55,6 @@ func (tm *objectIndexer) createTableSql(writer io.Writer) error {
 		}
 	}
 
-	var pKeys []string
-	if !isSingleton {
-		for _, field := range tm.typ.KeyFields {
-			name, err := tm.updatableColumnName(field)
-			if err != nil {
-				return err
-			}
-
-			pKeys = append(pKeys, name)
-		}
-	} else {
-		pKeys = []string{"_id"}
-	}
-
 	_, err = fmt.Fprintf(writer, "PRIMARY KEY (%s)", strings.Join(pKeys, ", "))
 	if err != nil {
 		return err
@@ -94,3 +75,39 @@ func (tm *objectIndexer) createTableSql(writer io.Writer) error {
 
 	return nil
 }
+
+// createKeyValueColumnDefinitions writes the column definitions for the key and value fields of the object type
+// and returns the names of the primary key columns.
+func (tm *objectIndexer) createKeyValueColumnDefinitions(writer io.Writer) ([]string, error) {
+	var pKeys []string
+	if len(tm.typ.KeyFields) == 0 {
+		_, err := fmt.Fprintf(writer, "_id INTEGER NOT NULL CHECK (_id = 1),\n\t")
+		if err != nil {
+			return nil, err
+		}
+		pKeys = []string{"_id"}
+	} else {
+		for _, field := range tm.typ.KeyFields {
+			err := tm.createColumnDefinition(writer, field)
+			if err != nil {
+				return nil, err
+			}
+
+			name, err := tm.updatableColumnName(field)
+			if err != nil {
+				return nil, err
+			}
+
+			pKeys = append(pKeys, name)
+		}
+	}
+
+	for _, field := range tm.typ.ValueFields {
+		err := tm.createColumnDefinition(writer, field)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pKeys, nil
+}
@@ -93,3 +93,46 @@ func exampleCreateTableOpt(objectType schema.StateObjectType, noRetainDelete boo
 		panic(err)
 	}
 }
+
+func Example_objectIndexer_createHistoryTableSql_vote() {
+	tm := newObjectIndexer("test", testdata.VoteObject, options{
+		logger:        logutil.NoopLogger{},
+		retainHistory: true,
+	})
+	err := tm.createHistoryTableSql(os.Stdout)
+	if err != nil {
+		panic(err)
+	}
+	// Output:
+	// CREATE TABLE IF NOT EXISTS "test_vote_history" (
+	// 	"proposal" BIGINT NOT NULL,
+	// 	"address" TEXT NOT NULL,
+	// 	"vote" "test_vote_type" NOT NULL,
+	// 	_valid_from BIGINT NOT NULL,
+	// 	_valid_to BIGINT NULL,
+	// 	PRIMARY KEY ("proposal", "address", _valid_from)
+	// );
+	// GRANT SELECT ON TABLE "test_vote_history" TO PUBLIC;
+}
+
+func Example_objectIndexer_createHistoryTableSql_singleton() {
+	tm := newObjectIndexer("test", testdata.SingletonObject, options{
+		logger:        logutil.NoopLogger{},
+		retainHistory: true,
+	})
+	err := tm.createHistoryTableSql(os.Stdout)
+	if err != nil {
+		panic(err)
+	}
+	// Output:
+	// CREATE TABLE IF NOT EXISTS "test_singleton_history" (
+	// 	_id INTEGER NOT NULL CHECK (_id = 1),
+	// 	"foo" TEXT NOT NULL,
+	// 	"bar" INTEGER NULL,
+	// 	"an_enum" "test_my_enum" NOT NULL,
+	// 	_valid_from BIGINT NOT NULL,
+	// 	_valid_to BIGINT NULL,
+	// 	PRIMARY KEY (_id, _valid_from)
+	// );
+	// GRANT SELECT ON TABLE "test_singleton_history" TO PUBLIC;
+}
@@ -20,8 +20,7 @@ func (m *moduleIndexer) createEnumType(ctx context.Context, conn dbConn, enum sc
 			return fmt.Errorf("failed to check if enum type %q exists: %v", typeName, err) //nolint:errorlint // using %v for go 1.12 compat
 		}
 	} else {
-		// the enum type already exists
-		// TODO: add a check to ensure the existing enum type matches the expected values, and update it if necessary?
+		// the enum type already exists, values which were added to it are migrated by initializeSchema
 		return nil
 	}
 
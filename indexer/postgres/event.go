@@ -0,0 +1,261 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+)
+
+// eventIndexer manages the typed table for an event type.
+type eventIndexer struct {
+	typ schema.EventType
+
+	// obj is used to generate column definitions and names for the event fields.
+	obj *objectIndexer
+}
+
+// newEventIndexer creates a new eventIndexer for the given event type.
+func newEventIndexer(typ schema.EventType, options options) *eventIndexer {
+	// all event columns are nullable because events may be emitted by older or newer versions
+	// of a module which have a different set of fields
+	fields := make([]schema.Field, len(typ.Fields))
+	for i, field := range typ.Fields {
+		field.Nullable = true
+		fields[i] = field
+	}
+
+	return &eventIndexer{
+		typ: typ,
+		obj: newObjectIndexer("event", schema.StateObjectType{
+			Name:        strings.ReplaceAll(typ.Name, ".", "_"),
+			ValueFields: fields,
+		}, options),
+	}
+}
+
+// tableName returns the name of the typed table for the event type which is the fully-qualified
+// event type name with dots replaced by underscores and prefixed with event_, shortened by identifier
+// if it is too long.
+func (e *eventIndexer) tableName() string {
+	return identifier(e.obj.tableName())
+}
+
+// maxIdentifierLength is the maximum length of a Postgres identifier. Longer identifiers are silently
+// truncated by Postgres.
+const maxIdentifierLength = 63
+
+// identifier returns name if it fits in a Postgres identifier, and otherwise a prefix of name followed by
+// a hash of the full name so that distinct long names don't collide once truncated.
+func identifier(name string) string {
+	if len(name) <= maxIdentifierLength {
+		return name
+	}
+
+	hash := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(hash[:4])
+	return name[:maxIdentifierLength-len(suffix)-1] + "_" + suffix
+}
+
+// createTable creates the typed table for the event type.
+func (e *eventIndexer) createTable(ctx context.Context, conn dbConn) error {
+	buf := new(strings.Builder)
+	err := e.createTableSql(buf)
+	if err != nil {
+		return err
+	}
+
+	sqlStr := buf.String()
+	if e.obj.options.logger != nil {
+		e.obj.options.logger.Debug("Creating event table", "table", e.tableName(), "sql", sqlStr)
+	}
+	_, err = conn.ExecContext(ctx, sqlStr)
+	return err
+}
+
+// createTableSql generates a CREATE TABLE statement for the typed event table along with indexes on
+// the block number and on all address fields.
+func (e *eventIndexer) createTableSql(writer io.Writer) error {
+	_, err := fmt.Fprintf(writer, "CREATE TABLE IF NOT EXISTS %q (\n\t", e.tableName())
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, "event_id BIGINT NOT NULL REFERENCES event (id),\n\tblock_number BIGINT NOT NULL REFERENCES block (number),\n\ttx_index BIGINT NOT NULL,\n\tmsg_index BIGINT NOT NULL,\n\t")
+	if err != nil {
+		return err
+	}
+
+	for _, field := range e.obj.typ.ValueFields {
+		err = e.obj.createColumnDefinition(writer, field)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(writer, "PRIMARY KEY (event_id)\n);\n")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, "CREATE INDEX IF NOT EXISTS %q ON %q (block_number);\n",
+		identifier(fmt.Sprintf("%s_block_number_idx", e.tableName())), e.tableName())
+	if err != nil {
+		return err
+	}
+
+	for _, field := range e.obj.typ.ValueFields {
+		if field.Kind != schema.AddressKind {
+			continue
+		}
+
+		_, err = fmt.Fprintf(writer, "CREATE INDEX IF NOT EXISTS %q ON %q (%q);\n",
+			identifier(fmt.Sprintf("%s_%s_idx", e.tableName(), field.Name)), e.tableName(), field.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(writer, "GRANT SELECT ON TABLE %q TO PUBLIC;", e.tableName())
+	return err
+}
+
+// insert inserts the event with the provided id in the generic event table into the typed event table.
+// data is the JSON representation of the event.
+func (e *eventIndexer) insert(ctx context.Context, conn dbConn, eventID int64, event appdata.Event, data json.RawMessage) error {
+	buf := new(strings.Builder)
+	params, err := e.insertSqlAndParams(buf, eventID, event, data)
+	if err != nil {
+		return err
+	}
+
+	sqlStr := buf.String()
+	if e.obj.options.logger != nil {
+		e.obj.options.logger.Debug("Insert event", "sql", sqlStr, "params", params)
+	}
+	_, err = conn.ExecContext(ctx, sqlStr, params...)
+	return err
+}
+
+// insertSqlAndParams generates an INSERT statement and binding parameters for the event.
+func (e *eventIndexer) insertSqlAndParams(w io.Writer, eventID int64, event appdata.Event, data json.RawMessage) ([]interface{}, error) {
+	var values map[string]json.RawMessage
+	err := json.Unmarshal(data, &values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode event %s: %w", e.typ.Name, err)
+	}
+
+	cols := []string{"event_id", "block_number", "tx_index", "msg_index"}
+	params := []interface{}{eventID, event.BlockNumber, event.TxIndex, event.MsgIndex}
+	for _, field := range e.obj.typ.ValueFields {
+		param, err := eventFieldParam(field, values[field.Name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode field %q of event %s: %w", field.Name, e.typ.Name, err)
+		}
+
+		col, err := e.obj.updatableColumnName(field)
+		if err != nil {
+			return nil, err
+		}
+
+		cols = append(cols, col)
+		params = append(params, param)
+	}
+
+	paramBindings := make([]string, 0, len(cols))
+	for i := 1; i <= len(cols); i++ {
+		paramBindings = append(paramBindings, fmt.Sprintf("$%d", i))
+	}
+
+	_, err = fmt.Fprintf(w, "INSERT INTO %q (%s) VALUES (%s);", e.tableName(),
+		strings.Join(cols, ", "),
+		strings.Join(paramBindings, ", "),
+	)
+	return params, err
+}
+
+// attributesJSON returns the JSON object of the fields of a typed event from its attributes. The attributes
+// of typed events hold the JSON representation of each field, as emitted by the typed event helpers of the
+// SDK; attribute values which are not valid JSON are treated as strings.
+func attributesJSON(attrs []appdata.EventAttribute) (json.RawMessage, error) {
+	values := make(map[string]json.RawMessage, len(attrs))
+	for _, attr := range attrs {
+		if json.Valid([]byte(attr.Value)) {
+			values[attr.Key] = json.RawMessage(attr.Value)
+			continue
+		}
+
+		value, err := json.Marshal(attr.Value)
+		if err != nil {
+			return nil, err
+		}
+		values[attr.Key] = value
+	}
+
+	return json.Marshal(values)
+}
+
+// eventFieldParam converts the JSON value of an event field to a binding parameter for its column.
+// Values are expected to be in the protobuf JSON format, so 64-bit integers may be quoted, bytes are base64
+// encoded, times are RFC 3339 strings and durations are strings with an "s" suffix.
+// Address fields are stored as they appear in the event because they are already encoded as strings.
+func eventFieldParam(field schema.Field, raw json.RawMessage) (interface{}, error) {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	switch field.Kind {
+	case schema.JSONKind:
+		return raw, nil
+	case schema.BoolKind:
+		var b bool
+		err := json.Unmarshal(raw, &b)
+		return b, err
+	}
+
+	// all other kinds are represented as either JSON strings or numbers, so we work with the unquoted text
+	str := string(raw)
+	if raw[0] == '"' {
+		err := json.Unmarshal(raw, &str)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch field.Kind {
+	case schema.StringKind, schema.AddressKind, schema.IntegerKind, schema.DecimalKind, schema.Uint64Kind:
+		return str, nil
+	case schema.Int8Kind, schema.Int16Kind, schema.Int32Kind, schema.Int64Kind:
+		return strconv.ParseInt(str, 10, 64)
+	case schema.Uint8Kind, schema.Uint16Kind, schema.Uint32Kind:
+		return strconv.ParseUint(str, 10, 32)
+	case schema.Float32Kind, schema.Float64Kind:
+		return strconv.ParseFloat(str, 64)
+	case schema.BytesKind:
+		return base64.StdEncoding.DecodeString(str)
+	case schema.TimeKind:
+		t, err := time.Parse(time.RFC3339Nano, str)
+		if err != nil {
+			return nil, err
+		}
+		return t.UnixNano(), nil
+	case schema.DurationKind:
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return nil, err
+		}
+		return int64(d), nil
+	default:
+		return nil, fmt.Errorf("unsupported event field kind %s", field.Kind)
+	}
+}
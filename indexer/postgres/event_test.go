@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/logutil"
+)
+
+var exampleEventType = schema.EventType{
+	Name: "cosmos.staking.v1beta1.EventDelegate",
+	Fields: []schema.Field{
+		{Name: "delegator", Kind: schema.AddressKind},
+		{Name: "validator", Kind: schema.AddressKind},
+		{Name: "amount", Kind: schema.Uint64Kind},
+		{Name: "time", Kind: schema.TimeKind},
+		{Name: "coins", Kind: schema.JSONKind},
+	},
+}
+
+func Example_eventIndexer_createTableSql() {
+	ei := newEventIndexer(exampleEventType, options{logger: logutil.NoopLogger{}})
+	err := ei.createTableSql(os.Stdout)
+	if err != nil {
+		panic(err)
+	}
+	// Output:
+	// CREATE TABLE IF NOT EXISTS "event_cosmos_staking_v1beta1_EventDelegate" (
+	// 	event_id BIGINT NOT NULL REFERENCES event (id),
+	// 	block_number BIGINT NOT NULL REFERENCES block (number),
+	// 	tx_index BIGINT NOT NULL,
+	// 	msg_index BIGINT NOT NULL,
+	// 	"delegator" TEXT NULL,
+	// 	"validator" TEXT NULL,
+	// 	"amount" NUMERIC NULL,
+	// 	"time" TIMESTAMPTZ GENERATED ALWAYS AS (nanos_to_timestamptz("time_nanos")) STORED,
+	// 	"time_nanos" BIGINT NULL,
+	// 	"coins" JSONB NULL,
+	// 	PRIMARY KEY (event_id)
+	// );
+	// CREATE INDEX IF NOT EXISTS "event_cosmos_staking_v1beta1_EventDelegate_block_number_idx" ON "event_cosmos_staking_v1beta1_EventDelegate" (block_number);
+	// CREATE INDEX IF NOT EXISTS "event_cosmos_staking_v1beta1_EventDelegate_delegator_idx" ON "event_cosmos_staking_v1beta1_EventDelegate" ("delegator");
+	// CREATE INDEX IF NOT EXISTS "event_cosmos_staking_v1beta1_EventDelegate_validator_idx" ON "event_cosmos_staking_v1beta1_EventDelegate" ("validator");
+	// GRANT SELECT ON TABLE "event_cosmos_staking_v1beta1_EventDelegate" TO PUBLIC;
+}
+
+func Example_eventIndexer_insertSqlAndParams() {
+	ei := newEventIndexer(exampleEventType, options{logger: logutil.NoopLogger{}})
+	buf := new(strings.Builder)
+	params, err := ei.insertSqlAndParams(buf, 7, appdata.Event{BlockNumber: 2, TxIndex: 1, MsgIndex: 1},
+		json.RawMessage(`{"delegator":"cosmos1abc","validator":"cosmosvaloper1def","amount":"18446744073709551615","time":"2024-01-02T03:04:05.000000006Z","coins":[{"denom":"foo","amount":"1"}]}`))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(buf.String())
+	fmt.Printf("%v\n", params[:8])
+	fmt.Println(string(params[8].(json.RawMessage)))
+	// Output:
+	// INSERT INTO "event_cosmos_staking_v1beta1_EventDelegate" (event_id, block_number, tx_index, msg_index, "delegator", "validator", "amount", "time_nanos", "coins") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+	// [7 2 1 1 cosmos1abc cosmosvaloper1def 18446744073709551615 1704164645000000006]
+	// [{"denom":"foo","amount":"1"}]
+}
+
+func Example_eventFieldParam() {
+	examples := []struct {
+		kind schema.Kind
+		json string
+	}{
+		{schema.Int64Kind, `"-12"`},
+		{schema.Int32Kind, `5`},
+		{schema.Uint32Kind, `6`},
+		{schema.BoolKind, `true`},
+		{schema.BytesKind, `"AQI="`},
+		{schema.DurationKind, `"1.5s"`},
+		{schema.StringKind, `null`},
+		{schema.Float64Kind, `1.25`},
+	}
+	for _, ex := range examples {
+		param, err := eventFieldParam(schema.Field{Name: "a", Kind: ex.kind}, json.RawMessage(ex.json))
+		fmt.Println(param, err)
+	}
+	// Output:
+	// -12 <nil>
+	// 5 <nil>
+	// 6 <nil>
+	// true <nil>
+	// [1 2] <nil>
+	// 1500000000 <nil>
+	// <nil> <nil>
+	// 1.25 <nil>
+}
+
+func Example_identifier() {
+	fmt.Println(identifier("event_cosmos_staking_v1beta1_EventDelegate"))
+	fmt.Println(identifier("event_ibc_applications_interchain_accounts_controller_v1_EventRegisterInterchainAccount"))
+	fmt.Println(identifier("event_ibc_applications_interchain_accounts_controller_v1_EventRegisterInterchainAccountAddress"))
+	// Output:
+	// event_cosmos_staking_v1beta1_EventDelegate
+	// event_ibc_applications_interchain_accounts_controller__24bea5ee
+	// event_ibc_applications_interchain_accounts_controller__e56c0782
+}
+
+func Example_attributesJSON() {
+	ei := newEventIndexer(exampleEventType, options{logger: logutil.NoopLogger{}})
+	data, err := attributesJSON([]appdata.EventAttribute{
+		{Key: "delegator", Value: `"cosmos1abc"`},
+		{Key: "validator", Value: "cosmosvaloper1def"},
+		{Key: "amount", Value: `"12"`},
+		{Key: "coins", Value: `[{"denom":"foo","amount":"1"}]`},
+		{Key: "msg_index", Value: "0"},
+	})
+	if err != nil {
+		panic(err)
+	}
+	params, err := ei.insertSqlAndParams(new(strings.Builder), 7, appdata.Event{BlockNumber: 2}, data)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%v\n", params[:8])
+	fmt.Println(string(params[8].(json.RawMessage)))
+	// Output:
+	// [7 2 0 0 cosmos1abc cosmosvaloper1def 12 <nil>]
+	// [{"denom":"foo","amount":"1"}]
+}
@@ -0,0 +1,256 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"cosmossdk.io/schema"
+)
+
+// historyTableName returns the name of the history table for the object type scoped to its module.
+func (tm *objectIndexer) historyTableName() string {
+	return fmt.Sprintf("%s_%s_history", tm.moduleName, tm.typ.Name)
+}
+
+// createHistoryTableSql generates a CREATE TABLE statement for the history table of the object type.
+// The history table has the same key and value columns as the object table plus _valid_from and _valid_to
+// columns which indicate the range of block heights [_valid_from, _valid_to) in which a row was the current value.
+// A NULL _valid_to means that the row is still the current value.
+func (tm *objectIndexer) createHistoryTableSql(writer io.Writer) error {
+	_, err := fmt.Fprintf(writer, "CREATE TABLE IF NOT EXISTS %q (\n\t", tm.historyTableName())
+	if err != nil {
+		return err
+	}
+
+	pKeys, err := tm.createKeyValueColumnDefinitions(writer)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, "_valid_from BIGINT NOT NULL,\n\t_valid_to BIGINT NULL,\n\t")
+	if err != nil {
+		return err
+	}
+
+	pKeys = append(pKeys, "_valid_from")
+	_, err = fmt.Fprintf(writer, "PRIMARY KEY (%s)", strings.Join(pKeys, ", "))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, "\n);\n")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, "GRANT SELECT ON TABLE %q TO PUBLIC;", tm.historyTableName())
+	return err
+}
+
+// recordHistory records the update which was just applied to the object table at the given block height in the
+// history table. For inserts and updates, the full current value is read back from the object table so that
+// partial value updates are recorded correctly.
+func (tm *objectIndexer) recordHistory(ctx context.Context, conn dbConn, height uint64, update schema.StateObjectUpdate) error {
+	// rows which were already written at the same height are replaced rather than closed
+	// so that only the last value in a block is retained
+	err := tm.execHistorySql(ctx, conn, func(w io.Writer) ([]interface{}, error) {
+		return tm.deleteHistorySqlAndParams(w, update.Key, height)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = tm.execHistorySql(ctx, conn, func(w io.Writer) ([]interface{}, error) {
+		return tm.closeHistorySqlAndParams(w, update.Key, height)
+	})
+	if err != nil || update.Delete {
+		return err
+	}
+
+	cur, found, err := tm.get(ctx, conn, update.Key)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("object %v of type %s not found after update", update.Key, tm.typ.Name)
+	}
+
+	return tm.execHistorySql(ctx, conn, func(w io.Writer) ([]interface{}, error) {
+		return tm.insertHistorySqlAndParams(w, cur.Key, cur.Value, height)
+	})
+}
+
+// execHistorySql executes the statement generated by gen.
+func (tm *objectIndexer) execHistorySql(ctx context.Context, conn dbConn, gen func(w io.Writer) ([]interface{}, error)) error {
+	buf := new(strings.Builder)
+	params, err := gen(buf)
+	if err != nil {
+		return err
+	}
+
+	sqlStr := buf.String()
+	if tm.options.logger != nil {
+		tm.options.logger.Debug("Record history", "sql", sqlStr, "params", params)
+	}
+	_, err = conn.ExecContext(ctx, sqlStr, params...)
+	return err
+}
+
+// deleteHistorySqlAndParams generates a DELETE statement for the history row of the provided key which
+// became valid at the given height.
+func (tm *objectIndexer) deleteHistorySqlAndParams(w io.Writer, key interface{}, height uint64) ([]interface{}, error) {
+	_, err := fmt.Fprintf(w, "DELETE FROM %q", tm.historyTableName())
+	if err != nil {
+		return nil, err
+	}
+
+	paramIdx, params, err := tm.whereSqlAndParams(w, key, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = fmt.Fprintf(w, " AND _valid_from = $%d;", paramIdx)
+	return append(params, height), err
+}
+
+// closeHistorySqlAndParams generates an UPDATE statement which ends the validity of the current history row
+// for the provided key at the given height.
+func (tm *objectIndexer) closeHistorySqlAndParams(w io.Writer, key interface{}, height uint64) ([]interface{}, error) {
+	_, err := fmt.Fprintf(w, "UPDATE %q SET _valid_to = $1", tm.historyTableName())
+	if err != nil {
+		return nil, err
+	}
+
+	_, keyParams, err := tm.whereSqlAndParams(w, key, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = fmt.Fprintf(w, " AND _valid_to IS NULL;")
+	return append([]interface{}{height}, keyParams...), err
+}
+
+// insertHistorySqlAndParams generates an INSERT statement for a new history row valid from the given height.
+func (tm *objectIndexer) insertHistorySqlAndParams(w io.Writer, key, value interface{}, height uint64) ([]interface{}, error) {
+	keyParams, keyCols, err := tm.bindKeyParams(key)
+	if err != nil {
+		return nil, err
+	}
+
+	valueParams, valueCols, err := tm.bindValueParams(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var allParams []interface{}
+	allParams = append(allParams, keyParams...)
+	allParams = append(allParams, valueParams...)
+	allParams = append(allParams, height)
+
+	allCols := make([]string, 0, len(keyCols)+len(valueCols)+1)
+	allCols = append(allCols, keyCols...)
+	allCols = append(allCols, valueCols...)
+	allCols = append(allCols, "_valid_from")
+
+	var paramBindings []string
+	for i := 1; i <= len(allCols); i++ {
+		paramBindings = append(paramBindings, fmt.Sprintf("$%d", i))
+	}
+
+	_, err = fmt.Fprintf(w, "INSERT INTO %q (%s) VALUES (%s);", tm.historyTableName(),
+		strings.Join(allCols, ", "),
+		strings.Join(paramBindings, ", "),
+	)
+	return allParams, err
+}
+
+// historySelectClause generates a SELECT clause for the key and value columns of the history table.
+func (tm *objectIndexer) historySelectClause(w io.Writer) error {
+	cols, err := tm.selectColumns(false)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "SELECT %s FROM %q", strings.Join(cols, ", "), tm.historyTableName())
+	return err
+}
+
+// historyHeightCondition returns the condition which selects the history rows which were the current value
+// at the height bound to the parameter paramIdx.
+func historyHeightCondition(paramIdx int) string {
+	return fmt.Sprintf("_valid_from <= $%d AND (_valid_to IS NULL OR _valid_to > $%d)", paramIdx, paramIdx)
+}
+
+// getAtHeight returns the value of the object with the provided key at the given height.
+func (tm *objectIndexer) getAtHeight(ctx context.Context, conn dbConn, key interface{}, height uint64) (schema.StateObjectUpdate, bool, error) {
+	buf := new(strings.Builder)
+	err := tm.historySelectClause(buf)
+	if err != nil {
+		return schema.StateObjectUpdate{}, false, err
+	}
+
+	paramIdx, params, err := tm.whereSqlAndParams(buf, key, 1)
+	if err != nil {
+		return schema.StateObjectUpdate{}, false, err
+	}
+
+	_, err = fmt.Fprintf(buf, " AND %s;", historyHeightCondition(paramIdx))
+	if err != nil {
+		return schema.StateObjectUpdate{}, false, err
+	}
+	params = append(params, height)
+
+	sqlStr := buf.String()
+	if tm.options.logger != nil {
+		tm.options.logger.Debug("Get at height", "sql", sqlStr, "params", params)
+	}
+
+	row := conn.QueryRowContext(ctx, sqlStr, params...)
+	return tm.readRowColumns(row, false)
+}
+
+// selectAllAtHeightSql generates a SELECT statement for all objects which existed at the height bound to
+// the first parameter.
+func (tm *objectIndexer) selectAllAtHeightSql(w io.Writer) error {
+	err := tm.historySelectClause(w)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, " WHERE %s;", historyHeightCondition(1))
+	return err
+}
+
+// selectAfterAtHeightSqlAndParams generates a SELECT statement for the objects which existed at the given height
+// with keys greater than after in ascending key order. If after is nil, all objects which existed at the height
+// are selected.
+func (tm *objectIndexer) selectAfterAtHeightSqlAndParams(w io.Writer, after interface{}, height uint64) ([]interface{}, error) {
+	err := tm.historySelectClause(w)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = fmt.Fprintf(w, " WHERE %s", historyHeightCondition(1))
+	if err != nil {
+		return nil, err
+	}
+
+	keyParams, err := tm.keysetSqlAndParams(w, after, " AND", 2)
+	if err != nil {
+		return nil, err
+	}
+	return append([]interface{}{height}, keyParams...), nil
+}
+
+// countAtHeight returns the number of objects which existed at the given height.
+func (tm *objectIndexer) countAtHeight(ctx context.Context, conn dbConn, height uint64) (int, error) {
+	sqlStr := fmt.Sprintf("SELECT COUNT(*) FROM %q WHERE %s;", tm.historyTableName(), historyHeightCondition(1))
+	if tm.options.logger != nil {
+		tm.options.logger.Debug("Count at height", "sql", sqlStr, "height", height)
+	}
+	var count int
+	err := conn.QueryRowContext(ctx, sqlStr, height).Scan(&count)
+	return count, err
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sync"
 
 	"cosmossdk.io/schema/indexer"
 	"cosmossdk.io/schema/logutil"
@@ -19,6 +20,16 @@ type Config struct {
 
 	// DisableRetainDeletions disables the retain deletions functionality even if it is set in an object type schema.
 	DisableRetainDeletions bool `json:"disable_retain_deletions"`
+
+	// RetainHistory enables history mode. In history mode, every update to a state object is also written
+	// to a history table named after the object table with a _history suffix which records the range of block
+	// heights in which each value was current. This allows querying the value of state at any height
+	// after history mode was enabled.
+	RetainHistory bool `json:"retain_history"`
+
+	// DisableTypedEvents disables writing typed events to their own tables. By default, a table with a column
+	// for each field is created for every event type known to the app in addition to the generic event table.
+	DisableTypedEvents bool `json:"disable_typed_events"`
 }
 
 type indexerImpl struct {
@@ -27,7 +38,14 @@ type indexerImpl struct {
 	tx      *sql.Tx
 	opts    options
 	modules map[string]*moduleIndexer
+	events  map[string]*eventIndexer
 	logger  logutil.Logger
+
+	// modulesMu guards modules which is written by the listener and read by views.
+	modulesMu sync.RWMutex
+
+	// blockNum is the height of the block currently being indexed.
+	blockNum uint64
 }
 
 func init() {
@@ -76,16 +94,30 @@ func startIndexer(params indexer.InitParams) (indexer.InitResult, error) {
 	moduleIndexers := map[string]*moduleIndexer{}
 	opts := options{
 		disableRetainDeletions: config.DisableRetainDeletions,
+		retainHistory:          config.RetainHistory,
 		logger:                 params.Logger,
 		addressCodec:           params.AddressCodec,
 	}
 
+	eventIndexers := map[string]*eventIndexer{}
+	if !config.DisableTypedEvents {
+		for _, eventType := range params.EventTypes {
+			ei := newEventIndexer(eventType, opts)
+			err = ei.createTable(ctx, tx)
+			if err != nil {
+				return indexer.InitResult{}, fmt.Errorf("failed to create table for event %s: %w", eventType.Name, err)
+			}
+			eventIndexers[eventType.Name] = ei
+		}
+	}
+
 	idx := &indexerImpl{
 		ctx:     ctx,
 		db:      db,
 		tx:      tx,
 		opts:    opts,
 		modules: moduleIndexers,
+		events:  eventIndexers,
 		logger:  params.Logger,
 	}
 
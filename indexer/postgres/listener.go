@@ -18,11 +18,15 @@ func (i *indexerImpl) listener() appdata.Listener {
 			}
 
 			mm := newModuleIndexer(moduleName, modSchema, i.opts)
+			i.modulesMu.Lock()
 			i.modules[moduleName] = mm
+			i.modulesMu.Unlock()
 
-			return mm.initializeSchema(i.ctx, i.tx)
+			return mm.initializeSchema(i.ctx, i.tx, i.db)
 		},
 		StartBlock: func(data appdata.StartBlockData) error {
+			i.blockNum = data.Height
+
 			var (
 				headerBz []byte
 				err      error
@@ -70,23 +74,35 @@ func (i *indexerImpl) listener() appdata.Listener {
 				if err != nil {
 					return err
 				}
+
+				if i.opts.retainHistory {
+					err = tm.recordHistory(i.ctx, i.tx, i.blockNum, update)
+					if err != nil {
+						return err
+					}
+				}
 			}
 			return nil
 		},
 		Commit: func(data appdata.CommitData) (func() error, error) {
-			err := i.tx.Commit()
-			if err != nil {
-				return nil, err
-			}
-
-			i.tx, err = i.db.BeginTx(i.ctx, nil)
-			return nil, err
+			return nil, i.commitTx()
 		},
 		OnTx:    txListener(i),
 		OnEvent: eventListener(i),
 	}
 }
 
+// commitTx commits the current transaction and begins a new one.
+func (i *indexerImpl) commitTx() error {
+	err := i.tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	i.tx, err = i.db.BeginTx(i.ctx, nil)
+	return err
+}
+
 func txListener(i *indexerImpl) func(data appdata.TxData) error {
 	return func(td appdata.TxData) error {
 		var bz []byte
@@ -107,8 +123,42 @@ func txListener(i *indexerImpl) func(data appdata.TxData) error {
 			}
 		}
 
-		_, err := i.tx.Exec("INSERT INTO tx (block_number, index_in_block, data, bytes) VALUES ($1, $2, $3, $4)",
-			td.BlockNumber, td.TxIndex, jsonData, bz)
+		var hash []byte
+		if td.Hash != nil {
+			var err error
+			hash, err = td.Hash()
+			if err != nil {
+				return err
+			}
+		}
+
+		var signers []string
+		if td.Signers != nil {
+			signerBzs, err := td.Signers()
+			if err != nil {
+				return err
+			}
+
+			for _, signerBz := range signerBzs {
+				signer, err := i.opts.addressCodec.BytesToString(signerBz)
+				if err != nil {
+					return fmt.Errorf("failed to encode signer address: %w", err)
+				}
+				signers = append(signers, signer)
+			}
+		}
+
+		var msgTypeURLs []string
+		if td.MsgTypeURLs != nil {
+			var err error
+			msgTypeURLs, err = td.MsgTypeURLs()
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := i.tx.Exec("INSERT INTO tx (block_number, index_in_block, data, bytes, hash, signers, msg_type_urls) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+			td.BlockNumber, td.TxIndex, jsonData, bz, hash, signers, msgTypeURLs)
 
 		return err
 	}
@@ -117,7 +167,7 @@ func txListener(i *indexerImpl) func(data appdata.TxData) error {
 func eventListener(i *indexerImpl) func(data appdata.EventData) error {
 	return func(data appdata.EventData) error {
 		for _, e := range data.Events {
-			var jsonData json.RawMessage
+			var jsonData, typedData json.RawMessage
 
 			if e.Data != nil {
 				var err error
@@ -125,6 +175,7 @@ func eventListener(i *indexerImpl) func(data appdata.EventData) error {
 				if err != nil {
 					return fmt.Errorf("failed to get event data: %w", err)
 				}
+				typedData = jsonData
 			} else if e.Attributes != nil {
 				attrs, err := e.Attributes()
 				if err != nil {
@@ -140,13 +191,35 @@ func eventListener(i *indexerImpl) func(data appdata.EventData) error {
 				if err != nil {
 					return fmt.Errorf("failed to marshal event attributes: %w", err)
 				}
+
+				typedData, err = attributesJSON(attrs)
+				if err != nil {
+					return fmt.Errorf("failed to marshal event attributes: %w", err)
+				}
 			}
 
-			_, err := i.tx.Exec("INSERT INTO event (block_number, block_stage, tx_index, msg_index, event_index, type, data) VALUES ($1, $2, $3, $4, $5, $6, $7)",
-				e.BlockNumber, e.BlockStage, e.TxIndex, e.MsgIndex, e.EventIndex, e.Type, jsonData)
+			const insertEventSql = "INSERT INTO event (block_number, block_stage, tx_index, msg_index, event_index, type, data) VALUES ($1, $2, $3, $4, $5, $6, $7)"
+
+			ei, ok := i.events[e.Type]
+			if !ok || typedData == nil {
+				_, err := i.tx.Exec(insertEventSql, e.BlockNumber, e.BlockStage, e.TxIndex, e.MsgIndex, e.EventIndex, e.Type, jsonData)
+				if err != nil {
+					return fmt.Errorf("failed to index event: %w", err)
+				}
+				continue
+			}
+
+			var eventID int64
+			err := i.tx.QueryRow(insertEventSql+" RETURNING id",
+				e.BlockNumber, e.BlockStage, e.TxIndex, e.MsgIndex, e.EventIndex, e.Type, jsonData).Scan(&eventID)
 			if err != nil {
 				return fmt.Errorf("failed to index event: %w", err)
 			}
+
+			err = ei.insert(i.ctx, i.tx, eventID, e, typedData)
+			if err != nil {
+				return fmt.Errorf("failed to index typed event: %w", err)
+			}
 		}
 		return nil
 	}
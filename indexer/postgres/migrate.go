@@ -0,0 +1,416 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/diff"
+)
+
+// loadSchema loads the module schema which was last indexed for the module from the module_schema table.
+func (m *moduleIndexer) loadSchema(ctx context.Context, conn dbConn) (schema.ModuleSchema, bool, error) {
+	var bz []byte
+	row := conn.QueryRowContext(ctx, "SELECT schema FROM module_schema WHERE module_name = $1", m.moduleName)
+	if err := row.Scan(&bz); err != nil {
+		if err == sql.ErrNoRows { //nolint:errorlint // using == for go 1.12 compat
+			return schema.ModuleSchema{}, false, nil
+		}
+		return schema.ModuleSchema{}, false, fmt.Errorf("failed to load schema for module %s: %v", m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+
+	var modSchema schema.ModuleSchema
+	if err := json.Unmarshal(bz, &modSchema); err != nil {
+		return schema.ModuleSchema{}, false, fmt.Errorf("failed to decode schema for module %s: %v", m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
+	}
+
+	return modSchema, true, nil
+}
+
+// saveSchema saves the module schema to the module_schema table so that it can be compared with the next schema
+// the module is initialized with.
+func (m *moduleIndexer) saveSchema(ctx context.Context, conn dbConn) error {
+	bz, err := json.Marshal(m.schema)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.ExecContext(ctx,
+		"INSERT INTO module_schema (module_name, schema) VALUES ($1, $2) ON CONFLICT (module_name) DO UPDATE SET schema = EXCLUDED.schema",
+		m.moduleName, bz)
+	return err
+}
+
+// addEnumValues adds the values which were added to the enum type to the existing enum type in the database.
+// Values added with ALTER TYPE can't be used before the transaction in which they were added is committed.
+func (m *moduleIndexer) addEnumValues(ctx context.Context, conn dbConn, enumDiff diff.EnumTypeDiff) error {
+	if len(enumDiff.AddedValues) == 0 {
+		return nil
+	}
+
+	buf := new(strings.Builder)
+	err := addEnumValuesSql(buf, m.moduleName, enumDiff)
+	if err != nil {
+		return err
+	}
+
+	sqlStr := buf.String()
+	if m.options.logger != nil {
+		m.options.logger.Debug("Adding enum values", "sql", sqlStr)
+	}
+	_, err = conn.ExecContext(ctx, sqlStr)
+	return err
+}
+
+// addEnumValuesSql generates ALTER TYPE statements for the values which were added to the enum type.
+func addEnumValuesSql(writer io.Writer, moduleName string, enumDiff diff.EnumTypeDiff) error {
+	for i, value := range enumDiff.AddedValues {
+		if i > 0 {
+			_, err := fmt.Fprintf(writer, "\n")
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := fmt.Fprintf(writer, "ALTER TYPE %q ADD VALUE IF NOT EXISTS '%s';", enumTypeName(moduleName, enumDiff.Name), value.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumns adds columns for the value fields which were added to the object type to the object table and,
+// in history mode, to the history table.
+func (tm *objectIndexer) addColumns(ctx context.Context, conn dbConn, objDiff diff.StateObjectTypeDiff) error {
+	if len(objDiff.ValueFieldsDiff.Added) == 0 {
+		return nil
+	}
+
+	buf := new(strings.Builder)
+	err := tm.addColumnsSql(buf, tm.tableName(), objDiff.ValueFieldsDiff.Added)
+	if err != nil {
+		return err
+	}
+
+	if tm.options.retainHistory {
+		_, err = fmt.Fprintf(buf, "\n")
+		if err != nil {
+			return err
+		}
+
+		err = tm.addColumnsSql(buf, tm.historyTableName(), objDiff.ValueFieldsDiff.Added)
+		if err != nil {
+			return err
+		}
+	}
+
+	sqlStr := buf.String()
+	if tm.options.logger != nil {
+		tm.options.logger.Debug("Adding columns", "table", tm.tableName(), "sql", sqlStr)
+	}
+	_, err = conn.ExecContext(ctx, sqlStr)
+	return err
+}
+
+// addColumnsSql generates ALTER TABLE statements which add columns for the fields to the table.
+func (tm *objectIndexer) addColumnsSql(writer io.Writer, tableName string, fields []schema.Field) error {
+	for i, field := range fields {
+		if i > 0 {
+			_, err := fmt.Fprintf(writer, "\n")
+			if err != nil {
+				return err
+			}
+		}
+
+		defs, err := tm.addColumnDefinitions(field)
+		if err != nil {
+			return err
+		}
+
+		for j, def := range defs {
+			if j > 0 {
+				_, err = fmt.Fprintf(writer, "\n")
+				if err != nil {
+					return err
+				}
+			}
+
+			_, err = fmt.Fprintf(writer, "ALTER TABLE %q ADD COLUMN IF NOT EXISTS %s;", tableName, def)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addColumnDefinitions returns the definitions of the columns for the field in the order in which they
+// must be added to an existing table.
+func (tm *objectIndexer) addColumnDefinitions(field schema.Field) ([]string, error) {
+	buf := new(strings.Builder)
+	err := tm.createColumnDefinition(buf, field)
+	if err != nil {
+		return nil, err
+	}
+
+	defs := strings.Split(strings.TrimSuffix(buf.String(), ",\n\t"), ",\n\t")
+	if field.Kind == schema.TimeKind && len(defs) == 2 {
+		// the generated timestamp column is defined before the _nanos column it references,
+		// but the _nanos column must be added first
+		defs[0], defs[1] = defs[1], defs[0]
+	}
+	return defs, nil
+}
+
+// incompatibleChanges returns descriptions of the changes in the module schema diff which can't be migrated
+// automatically.
+func incompatibleChanges(d diff.ModuleSchemaDiff) []string {
+	var changes []string
+	for _, obj := range d.RemovedStateObjectTypes {
+		changes = append(changes, fmt.Sprintf("object type %q was removed", obj.Name))
+	}
+
+	for _, objDiff := range d.ChangedStateObjectTypes {
+		if !objDiff.KeyFieldsDiff.Empty() {
+			changes = append(changes, fmt.Sprintf("key fields of object type %q were changed", objDiff.Name))
+		}
+
+		fieldsDiff := objDiff.ValueFieldsDiff
+		for _, field := range fieldsDiff.Added {
+			if !field.Nullable {
+				changes = append(changes, fmt.Sprintf("non-nullable value field %q was added to object type %q", field.Name, objDiff.Name))
+			}
+		}
+		for _, field := range fieldsDiff.Changed {
+			changes = append(changes, fmt.Sprintf("value field %q of object type %q was changed", field.Name, objDiff.Name))
+		}
+		for _, field := range fieldsDiff.Removed {
+			changes = append(changes, fmt.Sprintf("value field %q was removed from object type %q", field.Name, objDiff.Name))
+		}
+		if fieldsDiff.OrderChanged() {
+			changes = append(changes, fmt.Sprintf("value fields of object type %q were reordered", objDiff.Name))
+		}
+	}
+
+	for _, enum := range d.RemovedEnumTypes {
+		changes = append(changes, fmt.Sprintf("enum type %q was removed", enum.Name))
+	}
+
+	for _, enumDiff := range d.ChangedEnumTypes {
+		for _, value := range enumDiff.RemovedValues {
+			changes = append(changes, fmt.Sprintf("value %q was removed from enum type %q", value.Name, enumDiff.Name))
+		}
+		for _, value := range enumDiff.ChangedValues {
+			changes = append(changes, fmt.Sprintf("value %q of enum type %q was changed from %d to %d", value.Name, enumDiff.Name, value.OldValue, value.NewValue))
+		}
+		if enumDiff.KindChanged() {
+			changes = append(changes, fmt.Sprintf("numeric kind of enum type %q was changed from %s to %s", enumDiff.Name, enumDiff.OldNumericKind, enumDiff.NewNumericKind))
+		}
+	}
+
+	return changes
+}
+
+// introspectSchemaDiff compares the module schema with the tables and enum types which exist in the database
+// for the module. It is used when no schema was saved for the module, i.e. because it was indexed by a version
+// of the indexer which didn't save module schemas, so that existing tables are migrated or refused in the same
+// way as when comparing saved schemas. Object and enum types which don't exist in the database yet are ignored.
+func (m *moduleIndexer) introspectSchemaDiff(ctx context.Context, conn dbConn) (diff.ModuleSchemaDiff, error) {
+	var (
+		schemaDiff diff.ModuleSchemaDiff
+		err        error
+	)
+
+	m.schema.EnumTypes(func(enumType schema.EnumType) bool {
+		var labels []string
+		labels, err = queryStrings(ctx, conn,
+			"SELECT e.enumlabel FROM pg_type t JOIN pg_enum e ON e.enumtypid = t.oid WHERE t.typname = $1 ORDER BY e.enumsortorder",
+			enumTypeName(m.moduleName, enumType.Name))
+		if err != nil {
+			err = fmt.Errorf("failed to introspect enum type %s in module %s: %v", enumType.Name, m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
+			return false
+		}
+
+		if len(labels) != 0 {
+			enumDiff := compareEnumValues(enumType, labels)
+			if len(enumDiff.AddedValues) != 0 || len(enumDiff.RemovedValues) != 0 {
+				schemaDiff.ChangedEnumTypes = append(schemaDiff.ChangedEnumTypes, enumDiff)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return diff.ModuleSchemaDiff{}, err
+	}
+
+	m.schema.StateObjectTypes(func(typ schema.StateObjectType) bool {
+		tm := newObjectIndexer(m.moduleName, typ, m.options)
+		var cols map[string]columnInfo
+		cols, err = tm.introspectColumns(ctx, conn)
+		if err != nil {
+			err = fmt.Errorf("failed to introspect table for %s in module %s: %v", typ.Name, m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
+			return false
+		}
+
+		if len(cols) != 0 {
+			objDiff := tm.compareColumns(cols)
+			if !objDiff.KeyFieldsDiff.Empty() || !objDiff.ValueFieldsDiff.Empty() {
+				schemaDiff.ChangedStateObjectTypes = append(schemaDiff.ChangedStateObjectTypes, objDiff)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return diff.ModuleSchemaDiff{}, err
+	}
+
+	return schemaDiff, nil
+}
+
+// compareEnumValues compares the values of the enum type with the labels of the existing enum type in the database.
+// Postgres enum types don't store numeric values, so only added and removed values can be detected.
+func compareEnumValues(enumType schema.EnumType, labels []string) diff.EnumTypeDiff {
+	existing := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		existing[label] = true
+	}
+
+	enumDiff := diff.EnumTypeDiff{Name: enumType.Name}
+	defined := make(map[string]bool, len(enumType.Values))
+	for _, value := range enumType.Values {
+		defined[value.Name] = true
+		if !existing[value.Name] {
+			enumDiff.AddedValues = append(enumDiff.AddedValues, value)
+		}
+	}
+
+	for _, label := range labels {
+		if !defined[label] {
+			enumDiff.RemovedValues = append(enumDiff.RemovedValues, schema.EnumValueDefinition{Name: label})
+		}
+	}
+
+	return enumDiff
+}
+
+// columnInfo is the type and nullability of an existing column.
+type columnInfo struct {
+	// typ is the lower case data type of the column or the name of the type for enum types.
+	typ      string
+	nullable bool
+}
+
+// introspectColumns returns the columns of the object table by name. It returns an empty map if the table
+// doesn't exist.
+func (tm *objectIndexer) introspectColumns(ctx context.Context, conn dbConn) (map[string]columnInfo, error) {
+	rows, err := conn.QueryContext(ctx,
+		"SELECT column_name, data_type, udt_name, is_nullable FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1",
+		tm.tableName())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols := map[string]columnInfo{}
+	for rows.Next() {
+		var name, dataType, udtName, isNullable string
+		err = rows.Scan(&name, &dataType, &udtName, &isNullable)
+		if err != nil {
+			return nil, err
+		}
+
+		typ := strings.ToLower(dataType)
+		if dataType == "USER-DEFINED" {
+			typ = udtName
+		}
+		cols[name] = columnInfo{typ: typ, nullable: isNullable == "YES"}
+	}
+
+	return cols, rows.Err()
+}
+
+// compareColumns compares the fields of the object type with the existing columns of its table. Fields which
+// don't have a column are reported as added, fields whose column has a different type or nullability as changed
+// and columns which don't belong to any field as removed fields.
+func (tm *objectIndexer) compareColumns(cols map[string]columnInfo) diff.StateObjectTypeDiff {
+	known := map[string]bool{"_id": true, "_deleted": true}
+	compare := func(fields []schema.Field, fieldsDiff *diff.FieldsDiff) {
+		for _, field := range fields {
+			name, typ := field.Name, tm.columnType(field)
+			if field.Kind == schema.TimeKind {
+				// the generated timestamp column is derived from the _nanos column which stores the value
+				known[name] = true
+				name = fmt.Sprintf("%s_nanos", name)
+			}
+			known[name] = true
+
+			col, ok := cols[name]
+			switch {
+			case !ok:
+				fieldsDiff.Added = append(fieldsDiff.Added, field)
+			case col.typ != typ || col.nullable != field.Nullable:
+				fieldsDiff.Changed = append(fieldsDiff.Changed, diff.FieldDiff{
+					Name:        field.Name,
+					NewKind:     field.Kind,
+					OldNullable: col.nullable,
+					NewNullable: field.Nullable,
+				})
+			}
+		}
+	}
+
+	objDiff := diff.StateObjectTypeDiff{Name: tm.typ.Name}
+	compare(tm.typ.KeyFields, &objDiff.KeyFieldsDiff)
+	compare(tm.typ.ValueFields, &objDiff.ValueFieldsDiff)
+
+	var removed []string
+	for name := range cols {
+		if !known[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		objDiff.ValueFieldsDiff.Removed = append(objDiff.ValueFieldsDiff.Removed, schema.Field{Name: name})
+	}
+
+	return objDiff
+}
+
+// columnType returns the type of the column which stores the field as reported by information_schema.columns.
+func (tm *objectIndexer) columnType(field schema.Field) string {
+	switch field.Kind {
+	case schema.EnumKind:
+		return enumTypeName(tm.moduleName, field.ReferencedType)
+	case schema.TimeKind:
+		return "bigint"
+	default:
+		return strings.ToLower(simpleColumnType(field.Kind))
+	}
+}
+
+// queryStrings returns the values of the single text column selected by the query.
+func queryStrings(ctx context.Context, conn dbConn, query string, args ...interface{}) ([]string, error) {
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []string
+	for rows.Next() {
+		var s string
+		err = rows.Scan(&s)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+	return res, rows.Err()
+}
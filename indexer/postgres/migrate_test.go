@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"fmt"
+	"os"
+
+	"cosmossdk.io/indexer/postgres/internal/testdata"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/diff"
+	"cosmossdk.io/schema/logutil"
+)
+
+func Example_addEnumValuesSql() {
+	newEnum := testdata.MyEnum
+	newEnum.Values = append(newEnum.Values,
+		schema.EnumValueDefinition{Name: "d", Value: 4},
+		schema.EnumValueDefinition{Name: "e", Value: 5},
+	)
+	enumDiff := diff.CompareModuleSchemas(
+		schema.MustCompileModuleSchema(testdata.MyEnum),
+		schema.MustCompileModuleSchema(newEnum),
+	).ChangedEnumTypes[0]
+
+	err := addEnumValuesSql(os.Stdout, "test", enumDiff)
+	if err != nil {
+		panic(err)
+	}
+	// Output:
+	// ALTER TYPE "test_my_enum" ADD VALUE IF NOT EXISTS 'd';
+	// ALTER TYPE "test_my_enum" ADD VALUE IF NOT EXISTS 'e';
+}
+
+func Example_objectIndexer_addColumnsSql() {
+	tm := newObjectIndexer("test", testdata.SingletonObject, options{
+		logger: logutil.NoopLogger{},
+	})
+	err := tm.addColumnsSql(os.Stdout, tm.tableName(), []schema.Field{
+		{Name: "baz", Kind: schema.StringKind, Nullable: true},
+		{Name: "at", Kind: schema.TimeKind, Nullable: true},
+		{Name: "other_enum", Kind: schema.EnumKind, ReferencedType: testdata.VoteType.Name, Nullable: true},
+	})
+	if err != nil {
+		panic(err)
+	}
+	// Output:
+	// ALTER TABLE "test_singleton" ADD COLUMN IF NOT EXISTS "baz" TEXT NULL;
+	// ALTER TABLE "test_singleton" ADD COLUMN IF NOT EXISTS "at_nanos" BIGINT NULL;
+	// ALTER TABLE "test_singleton" ADD COLUMN IF NOT EXISTS "at" TIMESTAMPTZ GENERATED ALWAYS AS (nanos_to_timestamptz("at_nanos")) STORED;
+	// ALTER TABLE "test_singleton" ADD COLUMN IF NOT EXISTS "other_enum" "test_vote_type" NULL;
+}
+
+func Example_incompatibleChanges() {
+	newSingleton := testdata.SingletonObject
+	newSingleton.ValueFields = []schema.Field{
+		{Name: "foo", Kind: schema.Int32Kind},
+		{Name: "an_enum", Kind: schema.EnumKind, ReferencedType: testdata.MyEnum.Name},
+		{Name: "qux", Kind: schema.StringKind},
+	}
+
+	newEnum := testdata.MyEnum
+	newEnum.Values = []schema.EnumValueDefinition{
+		{Name: "a", Value: 1},
+		{Name: "b", Value: 4},
+	}
+
+	schemaDiff := diff.CompareModuleSchemas(
+		testdata.ExampleSchema,
+		schema.MustCompileModuleSchema(testdata.AllKindsObject, newSingleton, newEnum, testdata.VoteType),
+	)
+	for _, change := range incompatibleChanges(schemaDiff) {
+		fmt.Println(change)
+	}
+	// Output:
+	// object type "vote" was removed
+	// non-nullable value field "qux" was added to object type "singleton"
+	// value field "foo" of object type "singleton" was changed
+	// value field "bar" was removed from object type "singleton"
+	// value fields of object type "singleton" were reordered
+	// value "c" was removed from enum type "my_enum"
+	// value "b" of enum type "my_enum" was changed from 2 to 4
+}
+
+func Example_compareEnumValues() {
+	enumDiff := compareEnumValues(testdata.MyEnum, []string{"a", "c", "z"})
+	fmt.Println(enumDiff.AddedValues)
+	fmt.Println(enumDiff.RemovedValues)
+	// Output:
+	// [{b 2}]
+	// [{z 0}]
+}
+
+func Example_objectIndexer_compareColumns() {
+	tm := newObjectIndexer("test", testdata.SingletonObject, options{
+		logger: logutil.NoopLogger{},
+	})
+	objDiff := tm.compareColumns(map[string]columnInfo{
+		"_id":     {typ: "integer"},
+		"foo":     {typ: "text"},
+		"an_enum": {typ: "test_my_enum", nullable: true},
+		"old":     {typ: "bigint", nullable: true},
+	})
+	fmt.Println(objDiff.KeyFieldsDiff.Empty(), objDiff.ValueFieldsDiff.Added[0].Name)
+	for _, change := range incompatibleChanges(diff.ModuleSchemaDiff{ChangedStateObjectTypes: []diff.StateObjectTypeDiff{objDiff}}) {
+		fmt.Println(change)
+	}
+	// Output:
+	// true bar
+	// value field "an_enum" of object type "singleton" was changed
+	// value field "old" was removed from object type "singleton"
+}
@@ -3,8 +3,10 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/diff"
 )
 
 // moduleIndexer manages the tables for a module.
@@ -27,10 +29,43 @@ func newModuleIndexer(moduleName string, modSchema schema.ModuleSchema, options
 	}
 }
 
-// initializeSchema creates tables for all object types in the module schema and creates enum types.
-func (m *moduleIndexer) initializeSchema(ctx context.Context, conn dbConn) error {
+// initializeSchema creates tables for all object types in the module schema and creates enum types using conn.
+// If the module was indexed before with a different schema, compatible changes are migrated automatically and
+// an error is returned if there are changes which can't be migrated. The previous schema is the one saved in the
+// module_schema table or, if none was saved, the one of the existing tables and enum types.
+//
+// Values which were added to existing enum types can't be used in the transaction which adds them, so they are
+// added with db outside of the transaction of conn. Adding enum values is idempotent, so this is safe if the
+// transaction is rolled back.
+func (m *moduleIndexer) initializeSchema(ctx context.Context, conn, db dbConn) error {
+	oldSchema, found, err := m.loadSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	var schemaDiff diff.ModuleSchemaDiff
+	if found {
+		schemaDiff = diff.CompareModuleSchemas(oldSchema, m.schema)
+	} else {
+		schemaDiff, err = m.introspectSchemaDiff(ctx, conn)
+		if err != nil {
+			return err
+		}
+	}
+	if !schemaDiff.HasCompatibleChanges() {
+		return fmt.Errorf("module %s has schema changes which can't be migrated automatically, its tables must be dropped and reindexed: %s",
+			m.moduleName, strings.Join(incompatibleChanges(schemaDiff), "; "))
+	}
+
+	// add values to existing enum types
+	for _, enumDiff := range schemaDiff.ChangedEnumTypes {
+		err = m.addEnumValues(ctx, db, enumDiff)
+		if err != nil {
+			return fmt.Errorf("failed to add values to enum type %s in module %s: %v", enumDiff.Name, m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
+		}
+	}
+
 	// create enum types
-	var err error
 	m.schema.EnumTypes(func(enumType schema.EnumType) bool {
 		err = m.createEnumType(ctx, conn, enumType)
 		return err == nil
@@ -49,6 +84,17 @@ func (m *moduleIndexer) initializeSchema(ctx context.Context, conn dbConn) error
 		}
 		return err == nil
 	})
+	if err != nil {
+		return err
+	}
+
+	// add columns for new value fields to existing tables
+	for _, objDiff := range schemaDiff.ChangedStateObjectTypes {
+		err = m.tables[objDiff.Name].addColumns(ctx, conn, objDiff)
+		if err != nil {
+			return fmt.Errorf("failed to add columns to table for %s in module %s: %v", objDiff.Name, m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
+		}
+	}
 
-	return err
+	return m.saveSchema(ctx, conn)
 }
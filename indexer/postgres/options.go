@@ -10,6 +10,9 @@ type options struct {
 	// disableRetainDeletions disables retain deletions functionality even on object types that have it set.
 	disableRetainDeletions bool
 
+	// retainHistory enables writing every update to a history table keyed by block height.
+	retainHistory bool
+
 	// logger is the logger for the indexer to use. It may be nil.
 	logger logutil.Logger
 
@@ -96,6 +96,17 @@ func (tm *objectIndexer) selectAllSql(w io.Writer) error {
 	return err
 }
 
+// selectAfterSqlAndParams generates a SELECT statement for the objects with keys greater than after in ascending
+// key order. If after is nil, all objects are selected.
+func (tm *objectIndexer) selectAfterSqlAndParams(w io.Writer, after interface{}) ([]interface{}, error) {
+	err := tm.selectAllClause(w)
+	if err != nil {
+		return nil, err
+	}
+
+	return tm.keysetSqlAndParams(w, after, " WHERE", 1)
+}
+
 func (tm *objectIndexer) getSqlAndParams(w io.Writer, key interface{}) ([]interface{}, error) {
 	err := tm.selectAllClause(w)
 	if err != nil {
@@ -117,12 +128,27 @@ func (tm *objectIndexer) getSqlAndParams(w io.Writer, key interface{}) ([]interf
 }
 
 func (tm *objectIndexer) selectAllClause(w io.Writer) error {
-	allFields := make([]string, 0, len(tm.typ.KeyFields)+len(tm.typ.ValueFields))
+	allFields, err := tm.selectColumns(!tm.options.disableRetainDeletions && tm.typ.RetainDeletions)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "SELECT %s FROM %q", strings.Join(allFields, ", "), tm.tableName())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// selectColumns returns the key and value columns to select, optionally including the _deleted column.
+func (tm *objectIndexer) selectColumns(withDeleted bool) ([]string, error) {
+	allFields := make([]string, 0, len(tm.typ.KeyFields)+len(tm.typ.ValueFields)+1)
 
 	for _, field := range tm.typ.KeyFields {
 		colName, err := tm.updatableColumnName(field)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		allFields = append(allFields, colName)
 	}
@@ -130,24 +156,24 @@ func (tm *objectIndexer) selectAllClause(w io.Writer) error {
 	for _, field := range tm.typ.ValueFields {
 		colName, err := tm.updatableColumnName(field)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		allFields = append(allFields, colName)
 	}
 
-	if !tm.options.disableRetainDeletions && tm.typ.RetainDeletions {
+	if withDeleted {
 		allFields = append(allFields, "_deleted")
 	}
 
-	_, err := fmt.Fprintf(w, "SELECT %s FROM %q", strings.Join(allFields, ", "), tm.tableName())
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return allFields, nil
 }
 
 func (tm *objectIndexer) readRow(row interface{ Scan(...interface{}) error }) (schema.StateObjectUpdate, bool, error) {
+	return tm.readRowColumns(row, !tm.options.disableRetainDeletions && tm.typ.RetainDeletions)
+}
+
+// readRowColumns reads a row selected with the columns returned by selectColumns.
+func (tm *objectIndexer) readRowColumns(row interface{ Scan(...interface{}) error }, withDeleted bool) (schema.StateObjectUpdate, bool, error) {
 	var res []interface{}
 	for _, f := range tm.typ.KeyFields {
 		res = append(res, tm.colBindValue(f))
@@ -157,7 +183,7 @@ func (tm *objectIndexer) readRow(row interface{ Scan(...interface{}) error }) (s
 		res = append(res, tm.colBindValue(f))
 	}
 
-	if !tm.options.disableRetainDeletions && tm.typ.RetainDeletions {
+	if withDeleted {
 		res = append(res, new(bool))
 	}
 
@@ -205,7 +231,7 @@ func (tm *objectIndexer) readRow(row interface{ Scan(...interface{}) error }) (s
 		Value:    value,
 	}
 
-	if !tm.options.disableRetainDeletions && tm.typ.RetainDeletions {
+	if withDeleted {
 		deleted := res[0].(*bool)
 		if *deleted {
 			update.Delete = true
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"cosmossdk.io/indexer/postgres/internal/testdata"
+	"cosmossdk.io/schema/addressutil"
+	"cosmossdk.io/schema/logutil"
+)
+
+func Example_objectIndexer_selectAfterSqlAndParams() {
+	tm := newObjectIndexer("test", testdata.VoteObject, options{
+		logger:       logutil.NoopLogger{},
+		addressCodec: addressutil.HexAddressCodec{},
+	})
+
+	for _, after := range []interface{}{nil, []interface{}{int64(1), []byte{0xab}}} {
+		buf := new(strings.Builder)
+		params, err := tm.selectAfterSqlAndParams(buf, after)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(buf.String())
+		fmt.Println(params)
+	}
+	// Output:
+	// SELECT "proposal", "address", "vote", _deleted FROM "test_vote" ORDER BY "proposal", "address";
+	// []
+	// SELECT "proposal", "address", "vote", _deleted FROM "test_vote" WHERE ("proposal", "address") > ($1, $2) ORDER BY "proposal", "address";
+	// [1 0xab]
+}
+
+func Example_objectIndexer_selectAfterAtHeightSqlAndParams() {
+	tm := newObjectIndexer("test", testdata.SingletonObject, options{
+		logger:        logutil.NoopLogger{},
+		retainHistory: true,
+	})
+
+	buf := new(strings.Builder)
+	params, err := tm.selectAfterAtHeightSqlAndParams(buf, nil, 7)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(buf.String())
+	fmt.Println(params)
+	// Output:
+	// SELECT "foo", "bar", "an_enum" FROM "test_singleton_history" WHERE _valid_from <= $1 AND (_valid_to IS NULL OR _valid_to > $1) ORDER BY _id;
+	// [7]
+}
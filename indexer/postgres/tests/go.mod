@@ -32,3 +32,8 @@ require (
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	pgregory.net/rapid v1.1.0 // indirect
 )
+
+replace (
+	cosmossdk.io/indexer/postgres => ../.
+	cosmossdk.io/schema => ../../../schema
+)
@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/indexer/postgres"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/addressutil"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+)
+
+func TestMigrateSchema(t *testing.T) {
+	ctx, dbUrl := startPostgres(t)
+
+	status := schema.EnumType{
+		Name:   "status",
+		Values: []schema.EnumValueDefinition{{Name: "active", Value: 1}, {Name: "inactive", Value: 2}},
+	}
+	account := schema.StateObjectType{
+		Name:        "account",
+		KeyFields:   []schema.Field{{Name: "id", Kind: schema.Uint32Kind}},
+		ValueFields: []schema.Field{{Name: "status", Kind: schema.EnumKind, ReferencedType: "status"}},
+	}
+
+	start := func(modSchema schema.ModuleSchema) (appdata.Listener, func(), error) {
+		t.Helper()
+		ctx, cancel := context.WithCancel(ctx)
+		res, err := indexer.StartIndexing(indexer.IndexingOptions{
+			Config: indexer.IndexingConfig{
+				Target: map[string]indexer.Config{
+					"postgres": {
+						Type:   "postgres",
+						Config: postgres.Config{DatabaseURL: dbUrl, RetainHistory: true},
+					},
+				},
+			},
+			Context:      ctx,
+			AddressCodec: addressutil.HexAddressCodec{},
+		})
+		require.NoError(t, err)
+
+		err = res.Listener.InitializeModuleData(appdata.ModuleInitializationData{
+			ModuleName: "test",
+			Schema:     modSchema,
+		})
+		return res.Listener, cancel, err
+	}
+
+	indexBlock := func(listener appdata.Listener, height uint64, updates ...schema.StateObjectUpdate) {
+		t.Helper()
+		require.NoError(t, listener.StartBlock(appdata.StartBlockData{Height: height}))
+		require.NoError(t, listener.OnObjectUpdate(appdata.ObjectUpdateData{ModuleName: "test", Updates: updates}))
+		_, err := listener.Commit(appdata.CommitData{})
+		require.NoError(t, err)
+	}
+
+	listener, stop, err := start(schema.MustCompileModuleSchema(status, account))
+	require.NoError(t, err)
+	indexBlock(listener, 1, schema.StateObjectUpdate{TypeName: "account", Key: uint32(1), Value: "active"})
+	stop()
+
+	// add an enum value, nullable value fields and a new object type
+	status.Values = append(status.Values, schema.EnumValueDefinition{Name: "frozen", Value: 3})
+	account.ValueFields = append(account.ValueFields,
+		schema.Field{Name: "memo", Kind: schema.StringKind, Nullable: true},
+		schema.Field{Name: "updated", Kind: schema.TimeKind, Nullable: true},
+	)
+	params := schema.StateObjectType{
+		Name:        "params",
+		ValueFields: []schema.Field{{Name: "max_accounts", Kind: schema.Uint32Kind}},
+	}
+	listener, stop, err = start(schema.MustCompileModuleSchema(status, account, params))
+	require.NoError(t, err)
+	updated := time.Unix(100, 0)
+	indexBlock(listener, 2,
+		schema.StateObjectUpdate{TypeName: "account", Key: uint32(2), Value: []interface{}{"frozen", "hello", updated}},
+		schema.StateObjectUpdate{TypeName: "params", Value: uint32(10)},
+	)
+	stop()
+
+	db, err := sql.Open("pgx", dbUrl)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var (
+		accountStatus string
+		memo          sql.NullString
+		updatedNanos  sql.NullInt64
+	)
+	row := db.QueryRowContext(ctx, `SELECT "status", "memo", "updated_nanos" FROM "test_account" WHERE "id" = 2`)
+	require.NoError(t, row.Scan(&accountStatus, &memo, &updatedNanos))
+	require.Equal(t, "frozen", accountStatus)
+	require.Equal(t, "hello", memo.String)
+	require.Equal(t, updated.UnixNano(), updatedNanos.Int64)
+
+	row = db.QueryRowContext(ctx, `SELECT "memo" FROM "test_account_history" WHERE "id" = 1`)
+	require.NoError(t, row.Scan(&memo))
+	require.False(t, memo.Valid)
+
+	// removing a value field can't be migrated
+	account.ValueFields = account.ValueFields[:2]
+	_, stop, err = start(schema.MustCompileModuleSchema(status, account, params))
+	require.ErrorContains(t, err, `value field "updated" was removed from object type "account"`)
+	stop()
+
+	// without a saved schema, the schema is compared with the existing tables and enum types
+	_, err = db.ExecContext(ctx, `DELETE FROM module_schema WHERE module_name = 'test'`)
+	require.NoError(t, err)
+	status.Values = append(status.Values, schema.EnumValueDefinition{Name: "closed", Value: 4})
+	account.ValueFields = append(account.ValueFields,
+		schema.Field{Name: "updated", Kind: schema.TimeKind, Nullable: true},
+		schema.Field{Name: "note", Kind: schema.StringKind, Nullable: true},
+	)
+	listener, stop, err = start(schema.MustCompileModuleSchema(status, account, params))
+	require.NoError(t, err)
+	indexBlock(listener, 3,
+		schema.StateObjectUpdate{TypeName: "account", Key: uint32(3), Value: []interface{}{"closed", nil, nil, "note"}},
+	)
+	stop()
+
+	var note sql.NullString
+	row = db.QueryRowContext(ctx, `SELECT "status", "note" FROM "test_account" WHERE "id" = 3`)
+	require.NoError(t, row.Scan(&accountStatus, &note))
+	require.Equal(t, "closed", accountStatus)
+	require.Equal(t, "note", note.String)
+
+	_, err = db.ExecContext(ctx, `DELETE FROM module_schema WHERE module_name = 'test'`)
+	require.NoError(t, err)
+	account.ValueFields = []schema.Field{account.ValueFields[0], account.ValueFields[2], account.ValueFields[3]}
+	_, stop, err = start(schema.MustCompileModuleSchema(status, account, params))
+	require.ErrorContains(t, err, `value field "memo" was removed from object type "account"`)
+	stop()
+}
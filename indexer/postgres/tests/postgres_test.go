@@ -16,6 +16,7 @@ import (
 	indexertesting "cosmossdk.io/schema/testing"
 	"cosmossdk.io/schema/testing/appdatasim"
 	"cosmossdk.io/schema/testing/statesim"
+	"cosmossdk.io/schema/view"
 )
 
 func TestPostgresIndexer(t *testing.T) {
@@ -30,24 +31,7 @@ func TestPostgresIndexer(t *testing.T) {
 func testPostgresIndexer(t *testing.T, retainDeletions bool) {
 	t.Helper()
 
-	tempDir := t.TempDir()
-
-	dbPort := freeport.GetOne(t)
-	pgConfig := embeddedpostgres.DefaultConfig().
-		Port(uint32(dbPort)).
-		DataPath(tempDir)
-
-	dbUrl := pgConfig.GetConnectionURL()
-	pg := embeddedpostgres.NewDatabase(pgConfig)
-	require.NoError(t, pg.Start())
-
-	ctx, cancel := context.WithCancel(context.Background())
-
-	t.Cleanup(func() {
-		cancel()
-		require.NoError(t, pg.Stop())
-	})
-
+	ctx, dbUrl := startPostgres(t)
 	debugLog := &strings.Builder{}
 
 	res, err := indexer.StartIndexing(indexer.IndexingOptions{
@@ -103,3 +87,103 @@ func testPostgresIndexer(t *testing.T, retainDeletions bool) {
 		debugLog.Reset()
 	}
 }
+
+func TestPostgresIndexer_history(t *testing.T) {
+	ctx, dbUrl := startPostgres(t)
+
+	debugLog := &strings.Builder{}
+
+	res, err := indexer.StartIndexing(indexer.IndexingOptions{
+		Config: indexer.IndexingConfig{
+			Target: map[string]indexer.Config{
+				"postgres": {
+					Type: "postgres",
+					Config: postgres.Config{
+						DatabaseURL:            dbUrl,
+						DisableRetainDeletions: true,
+						RetainHistory:          true,
+					},
+				},
+			},
+		},
+		Context:      ctx,
+		Logger:       &prettyLogger{debugLog},
+		AddressCodec: addressutil.HexAddressCodec{},
+	})
+	require.NoError(t, err)
+
+	sim, err := appdatasim.NewSimulator(appdatasim.Options{
+		Listener:  res.Listener,
+		AppSchema: indexertesting.ExampleAppSchema,
+	})
+	require.NoError(t, err)
+
+	pgIndexerView, ok := res.IndexerInfos["postgres"].View.(postgres.HistoricalView)
+	require.True(t, ok)
+
+	blockDataGen := sim.BlockDataGenN(10, 100)
+	numBlocks := 50
+	if testing.Short() {
+		numBlocks = 10
+	}
+	var blocks []appdatasim.BlockData
+	for i := 0; i < numBlocks; i++ {
+		blockData := blockDataGen.Example(i)
+		blocks = append(blocks, blockData)
+		require.NoError(t, sim.ProcessBlockData(blockData), debugLog.String())
+		debugLog.Reset()
+	}
+
+	// replay the same blocks into a fresh simulator and compare the state at each height
+	// to the historical state in the indexer
+	expectedSim, err := appdatasim.NewSimulator(appdatasim.Options{
+		AppSchema: indexertesting.ExampleAppSchema,
+	})
+	require.NoError(t, err)
+
+	for _, blockData := range blocks {
+		require.NoError(t, expectedSim.ProcessBlockData(blockData))
+
+		height, err := expectedSim.BlockNum()
+		require.NoError(t, err)
+
+		state, err := pgIndexerView.AppStateAt(height)
+		require.NoError(t, err)
+
+		require.Empty(t, appdatasim.DiffAppData(expectedSim, historicalAppData{state, height}), "height %d", height)
+	}
+}
+
+// historicalAppData is a view.AppData for the app state at a fixed height.
+type historicalAppData struct {
+	state  view.AppState
+	height uint64
+}
+
+func (h historicalAppData) AppState() view.AppState { return h.state }
+
+func (h historicalAppData) BlockNum() (uint64, error) { return h.height, nil }
+
+func startPostgres(t *testing.T) (context.Context, string) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+
+	dbPort := freeport.GetOne(t)
+	pgConfig := embeddedpostgres.DefaultConfig().
+		Port(uint32(dbPort)).
+		DataPath(tempDir)
+
+	dbUrl := pgConfig.GetConnectionURL()
+	pg := embeddedpostgres.NewDatabase(pgConfig)
+	require.NoError(t, pg.Start())
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, pg.Stop())
+	})
+
+	return ctx, dbUrl
+}
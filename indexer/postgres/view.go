@@ -3,21 +3,39 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"strings"
 
 	"cosmossdk.io/schema"
 	"cosmossdk.io/schema/view"
 )
 
-var _ view.AppData = &indexerImpl{}
+// HistoricalView is the view.AppData implementation of the postgres indexer which can additionally
+// return the app state at a past block height when history mode is enabled.
+type HistoricalView interface {
+	view.AppData
+
+	// AppStateAt returns the app state as it was at the end of the block with the provided height.
+	// An error is returned if history mode is not enabled.
+	AppStateAt(height uint64) (view.AppState, error)
+}
+
+var _ HistoricalView = &indexerImpl{}
 
 func (i *indexerImpl) AppState() view.AppState {
 	return i
 }
 
+func (i *indexerImpl) AppStateAt(height uint64) (view.AppState, error) {
+	if !i.opts.retainHistory {
+		return nil, errors.New("history mode is not enabled")
+	}
+	return &historicalAppState{indexerImpl: i, height: height}, nil
+}
+
 func (i *indexerImpl) BlockNum() (uint64, error) {
 	var blockNum int64
-	err := i.tx.QueryRow("SELECT coalesce(max(number), 0) FROM block").Scan(&blockNum)
+	err := i.db.QueryRowContext(i.ctx, "SELECT coalesce(max(number), 0) FROM block").Scan(&blockNum)
 	if err != nil {
 		return 0, err
 	}
@@ -26,36 +44,76 @@ func (i *indexerImpl) BlockNum() (uint64, error) {
 
 type moduleView struct {
 	moduleIndexer
-	ctx  context.Context
+	ctx context.Context
+	// conn is the connection pool rather than the listener's transaction, which is replaced on every commit,
+	// so that views only read committed data and can be used concurrently with the listener.
 	conn dbConn
+
+	// height is the block height to read state at from the history tables or nil to read the latest state.
+	height *uint64
 }
 
 func (i *indexerImpl) GetModule(moduleName string) (view.ModuleState, error) {
+	return i.getModule(moduleName, nil)
+}
+
+func (i *indexerImpl) Modules(f func(modState view.ModuleState, err error) bool) {
+	i.iterateModules(f, nil)
+}
+
+func (i *indexerImpl) NumModules() (int, error) {
+	i.modulesMu.RLock()
+	defer i.modulesMu.RUnlock()
+	return len(i.modules), nil
+}
+
+func (i *indexerImpl) getModule(moduleName string, height *uint64) (view.ModuleState, error) {
+	i.modulesMu.RLock()
 	mod, ok := i.modules[moduleName]
+	i.modulesMu.RUnlock()
 	if !ok {
 		return nil, nil
 	}
 	return &moduleView{
 		moduleIndexer: *mod,
 		ctx:           i.ctx,
-		conn:          i.tx,
+		conn:          i.db,
+		height:        height,
 	}, nil
 }
 
-func (i *indexerImpl) Modules(f func(modState view.ModuleState, err error) bool) {
+func (i *indexerImpl) iterateModules(f func(modState view.ModuleState, err error) bool, height *uint64) {
+	i.modulesMu.RLock()
+	mods := make([]*moduleIndexer, 0, len(i.modules))
 	for _, mod := range i.modules {
+		mods = append(mods, mod)
+	}
+	i.modulesMu.RUnlock()
+
+	for _, mod := range mods {
 		if !f(&moduleView{
 			moduleIndexer: *mod,
 			ctx:           i.ctx,
-			conn:          i.tx,
+			conn:          i.db,
+			height:        height,
 		}, nil) {
 			return
 		}
 	}
 }
 
-func (i *indexerImpl) NumModules() (int, error) {
-	return len(i.modules), nil
+// historicalAppState is a view.AppState which reads state at a past block height from the history tables.
+type historicalAppState struct {
+	*indexerImpl
+	height uint64
+}
+
+func (h *historicalAppState) GetModule(moduleName string) (view.ModuleState, error) {
+	return h.getModule(moduleName, &h.height)
+}
+
+func (h *historicalAppState) Modules(f func(modState view.ModuleState, err error) bool) {
+	h.iterateModules(f, &h.height)
 }
 
 func (m *moduleView) ModuleName() string {
@@ -75,6 +133,7 @@ func (m *moduleView) GetObjectCollection(objectType string) (view.ObjectCollecti
 		objectIndexer: *obj,
 		ctx:           m.ctx,
 		conn:          m.conn,
+		height:        m.height,
 	}, nil
 }
 
@@ -84,6 +143,7 @@ func (m *moduleView) ObjectCollections(f func(value view.ObjectCollection, err e
 			objectIndexer: *obj,
 			ctx:           m.ctx,
 			conn:          m.conn,
+			height:        m.height,
 		}, nil) {
 			return
 		}
@@ -98,6 +158,9 @@ type objectView struct {
 	objectIndexer
 	ctx  context.Context
 	conn dbConn
+
+	// height is the block height to read state at from the history table or nil to read the latest state.
+	height *uint64
 }
 
 func (tm *objectView) ObjectType() schema.StateObjectType {
@@ -105,47 +168,94 @@ func (tm *objectView) ObjectType() schema.StateObjectType {
 }
 
 func (tm *objectView) GetObject(key interface{}) (update schema.StateObjectUpdate, found bool, err error) {
+	if tm.height != nil {
+		return tm.getAtHeight(tm.ctx, tm.conn, key, *tm.height)
+	}
 	return tm.get(tm.ctx, tm.conn, key)
 }
 
 func (tm *objectView) AllState(f func(schema.StateObjectUpdate, error) bool) {
 	buf := new(strings.Builder)
-	err := tm.selectAllSql(buf)
+	var params []interface{}
+	var err error
+	if tm.height != nil {
+		err = tm.selectAllAtHeightSql(buf)
+		params = append(params, *tm.height)
+	} else {
+		err = tm.selectAllSql(buf)
+	}
 	if err != nil {
-		panic(err)
+		f(schema.StateObjectUpdate{}, err)
+		return
 	}
 
-	sqlStr := buf.String()
+	tm.iterate(buf.String(), params, f)
+}
+
+// StateAfter iterates over the state of the collection in ascending key order starting after the object with
+// the provided key, or at the first object if key is nil. It allows paginating over large collections without
+// scanning the objects of previous pages.
+func (tm *objectView) StateAfter(key interface{}, f func(schema.StateObjectUpdate, error) bool) {
+	buf := new(strings.Builder)
+	var params []interface{}
+	var err error
+	if tm.height != nil {
+		params, err = tm.selectAfterAtHeightSqlAndParams(buf, key, *tm.height)
+	} else {
+		params, err = tm.selectAfterSqlAndParams(buf, key)
+	}
+	if err != nil {
+		f(schema.StateObjectUpdate{}, err)
+		return
+	}
+
+	tm.iterate(buf.String(), params, f)
+}
+
+// iterate runs the select query and calls f with each row read as an object update. Errors are passed to f.
+func (tm *objectView) iterate(sqlStr string, params []interface{}, f func(schema.StateObjectUpdate, error) bool) {
 	if tm.options.logger != nil {
-		tm.options.logger.Debug("Select", "sql", sqlStr)
+		tm.options.logger.Debug("Select", "sql", sqlStr, "params", params)
 	}
 
-	rows, err := tm.conn.QueryContext(tm.ctx, sqlStr)
+	rows, err := tm.conn.QueryContext(tm.ctx, sqlStr, params...)
 	if err != nil {
-		panic(err)
+		f(schema.StateObjectUpdate{}, err)
+		return
 	}
-	defer func(rows *sql.Rows) {
-		err := rows.Close()
-		if err != nil {
-			panic(err)
-		}
-	}(rows)
+	defer rows.Close()
 
 	for rows.Next() {
-		update, found, err := tm.readRow(rows)
+		update, found, err := tm.readViewRow(rows)
 		if err == nil && !found {
 			err = sql.ErrNoRows
 		}
-		if !f(update, err) {
+		if !f(update, err) || err != nil {
 			return
 		}
 	}
+
+	if err := rows.Err(); err != nil {
+		f(schema.StateObjectUpdate{}, err)
+	}
 }
 
 func (tm *objectView) Len() (int, error) {
+	if tm.height != nil {
+		return tm.countAtHeight(tm.ctx, tm.conn, *tm.height)
+	}
+
 	n, err := tm.count(tm.ctx, tm.conn)
 	if err != nil {
 		return 0, err
 	}
 	return n, nil
 }
+
+// readViewRow reads a row selected from either the object table or the history table.
+func (tm *objectView) readViewRow(row interface{ Scan(...interface{}) error }) (schema.StateObjectUpdate, bool, error) {
+	if tm.height != nil {
+		return tm.readRowColumns(row, false)
+	}
+	return tm.readRow(row)
+}
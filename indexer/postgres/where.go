@@ -3,6 +3,7 @@ package postgres
 import (
 	"fmt"
 	"io"
+	"strings"
 )
 
 // whereSqlAndParams generates a WHERE clause for the provided key and returns the parameters.
@@ -58,3 +59,42 @@ func (tm *objectIndexer) whereSql(w io.Writer, params []interface{}, cols []stri
 
 	return endParamIdx, resParams, nil
 }
+
+// keysetSqlAndParams generates a condition selecting the rows with keys greater than after, joined to the preceding
+// clause with conjunction, followed by an ORDER BY clause on the key columns and returns the parameters. If after is
+// nil, only the ORDER BY clause is generated.
+func (tm *objectIndexer) keysetSqlAndParams(w io.Writer, after interface{}, conjunction string, startParamIdx int) ([]interface{}, error) {
+	keyCols := []string{"_id"}
+	if len(tm.typ.KeyFields) > 0 {
+		keyCols = make([]string, 0, len(tm.typ.KeyFields))
+		for _, field := range tm.typ.KeyFields {
+			colName, err := tm.updatableColumnName(field)
+			if err != nil {
+				return nil, err
+			}
+			keyCols = append(keyCols, colName)
+		}
+	}
+
+	var params []interface{}
+	if after != nil {
+		var err error
+		params, _, err = tm.bindKeyParams(after)
+		if err != nil {
+			return nil, err
+		}
+
+		bindings := make([]string, len(params))
+		for i := range params {
+			bindings[i] = fmt.Sprintf("$%d", startParamIdx+i)
+		}
+
+		_, err = fmt.Fprintf(w, "%s (%s) > (%s)", conjunction, strings.Join(keyCols, ", "), strings.Join(bindings, ", "))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, " ORDER BY %s;", strings.Join(keyCols, ", "))
+	return params, err
+}
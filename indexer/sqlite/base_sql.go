@@ -0,0 +1,31 @@
+package sqlite
+
+// baseSQL is the base SQL that is always included in the schema.
+const baseSQL = `
+CREATE TABLE IF NOT EXISTS block
+(
+    number INTEGER NOT NULL PRIMARY KEY,
+    header TEXT    NULL
+);
+
+CREATE TABLE IF NOT EXISTS tx
+(
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    block_number   INTEGER NOT NULL REFERENCES block (number),
+    index_in_block INTEGER NOT NULL,
+    data           TEXT    NULL,
+    bytes          BLOB    NULL
+);
+
+CREATE TABLE IF NOT EXISTS event
+(
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    block_number INTEGER NOT NULL REFERENCES block (number),
+    block_stage  INTEGER NOT NULL,
+    tx_index     INTEGER NOT NULL,
+    msg_index    INTEGER NOT NULL,
+    event_index  INTEGER NOT NULL,
+    type         TEXT    NULL,
+    data         TEXT    NULL
+);
+`
@@ -0,0 +1,43 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"cosmossdk.io/schema"
+)
+
+// moduleIndexer manages the tables for a module.
+type moduleIndexer struct {
+	moduleName string
+	schema     schema.ModuleSchema
+	tables     map[string]*objectIndexer
+	options    options
+}
+
+// newModuleIndexer creates a new moduleIndexer for the given module schema.
+func newModuleIndexer(moduleName string, modSchema schema.ModuleSchema, options options) *moduleIndexer {
+	return &moduleIndexer{
+		moduleName: moduleName,
+		schema:     modSchema,
+		tables:     map[string]*objectIndexer{},
+		options:    options,
+	}
+}
+
+// initializeSchema creates tables for all object types in the module schema.
+// SQLite has no enum types so enum values are enforced with CHECK constraints on each column instead.
+func (m *moduleIndexer) initializeSchema(ctx context.Context, conn dbConn) error {
+	var err error
+	m.schema.StateObjectTypes(func(typ schema.StateObjectType) bool {
+		tm := newObjectIndexer(m.moduleName, typ, m.schema, m.options)
+		m.tables[typ.Name] = tm
+		err = tm.createTable(ctx, conn)
+		if err != nil {
+			err = fmt.Errorf("failed to create table for %s in module %s: %v", typ.Name, m.moduleName, err) //nolint:errorlint // using %v for go 1.12 compat
+		}
+		return err == nil
+	})
+
+	return err
+}
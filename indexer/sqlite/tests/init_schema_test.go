@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gotest.tools/v3/golden"
+
+	"cosmossdk.io/indexer/sqlite"
+	"cosmossdk.io/indexer/sqlite/internal/testdata"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/indexer"
+)
+
+func TestInitSchema(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		testInitSchema(t, false, "init_schema.txt")
+	})
+
+	t.Run("retain deletions disabled", func(t *testing.T) {
+		testInitSchema(t, true, "init_schema_no_retain_delete.txt")
+	})
+}
+
+func testInitSchema(t *testing.T, disableRetainDeletions bool, goldenFileName string) {
+	t.Helper()
+
+	buf := &strings.Builder{}
+	res, err := indexer.StartIndexing(indexer.IndexingOptions{
+		Config: indexer.IndexingConfig{
+			Target: map[string]indexer.Config{
+				"sqlite": {
+					Type: "sqlite",
+					Config: sqlite.Config{
+						DatabaseURL:            createTestDB(t),
+						DisableRetainDeletions: disableRetainDeletions,
+					},
+				},
+			},
+		},
+		Context: context.Background(),
+		Logger:  prettyLogger{buf},
+	})
+	require.NoError(t, err)
+	listener := res.Listener
+
+	require.NotNil(t, listener.InitializeModuleData)
+	require.NoError(t, listener.InitializeModuleData(appdata.ModuleInitializationData{
+		ModuleName: "test",
+		Schema:     testdata.ExampleSchema,
+	}))
+
+	require.NotNil(t, listener.Commit)
+	cb, err := listener.Commit(appdata.CommitData{})
+	require.NoError(t, err)
+	if cb != nil {
+		require.NoError(t, cb())
+	}
+
+	golden.Assert(t, buf.String(), goldenFileName)
+}
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite" // this is where we get our sqlite database driver from
+
+	"cosmossdk.io/indexer/sqlite"
+	"cosmossdk.io/schema/addressutil"
+	"cosmossdk.io/schema/indexer"
+	indexertesting "cosmossdk.io/schema/testing"
+	"cosmossdk.io/schema/testing/appdatasim"
+	"cosmossdk.io/schema/testing/statesim"
+)
+
+func TestSQLiteIndexer(t *testing.T) {
+	t.Run("RetainDeletions", func(t *testing.T) {
+		testSQLiteIndexer(t, true)
+	})
+	t.Run("NoRetainDeletions", func(t *testing.T) {
+		testSQLiteIndexer(t, false)
+	})
+}
+
+func testSQLiteIndexer(t *testing.T, retainDeletions bool) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	debugLog := &strings.Builder{}
+
+	res, err := indexer.StartIndexing(indexer.IndexingOptions{
+		Config: indexer.IndexingConfig{
+			Target: map[string]indexer.Config{
+				"sqlite": {
+					Type: "sqlite",
+					Config: sqlite.Config{
+						DatabaseURL:            createTestDB(t),
+						DisableRetainDeletions: !retainDeletions,
+					},
+				},
+			},
+		},
+		Context:      ctx,
+		Logger:       &prettyLogger{debugLog},
+		AddressCodec: addressutil.HexAddressCodec{},
+	})
+	require.NoError(t, err)
+
+	sim, err := appdatasim.NewSimulator(appdatasim.Options{
+		Listener:  res.Listener,
+		AppSchema: indexertesting.ExampleAppSchema,
+		StateSimOptions: statesim.Options{
+			CanRetainDeletions: retainDeletions,
+		},
+	})
+	require.NoError(t, err)
+
+	sqliteIndexerView := res.IndexerInfos["sqlite"].View
+	require.NotNil(t, sqliteIndexerView)
+
+	blockDataGen := sim.BlockDataGenN(10, 100)
+	numBlocks := 200
+	if testing.Short() {
+		numBlocks = 10
+	}
+	for i := 0; i < numBlocks; i++ {
+		// using Example generates a deterministic data set based
+		// on a seed so that regression tests can be created OR rapid.Check can
+		// be used for fully random property-based testing
+		blockData := blockDataGen.Example(i)
+
+		// process the generated block data with the simulator which will also
+		// send it to the indexer
+		require.NoError(t, sim.ProcessBlockData(blockData), debugLog.String())
+
+		// compare the expected state in the simulator to the actual state in the indexer and expect the diff to be empty
+		require.Empty(t, appdatasim.DiffAppData(sim, sqliteIndexerView), debugLog.String())
+
+		// reset the debug log after each successful block so that it doesn't get too long when debugging
+		debugLog.Reset()
+	}
+}
+
+func createTestDB(t *testing.T) (dataSourceName string) {
+	t.Helper()
+	return "file:" + filepath.Join(t.TempDir(), "indexer.db")
+}
@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"fmt"
+	"io"
+)
+
+// whereSqlAndParams generates a WHERE clause for the provided key and returns the parameters.
+func (tm *objectIndexer) whereSqlAndParams(w io.Writer, key interface{}) (keyParams []interface{}, err error) {
+	var keyCols []string
+	keyParams, keyCols, err = tm.bindKeyParams(key)
+	if err != nil {
+		return
+	}
+
+	keyParams, err = tm.whereSql(w, keyParams, keyCols)
+	return
+}
+
+// whereSql generates a WHERE clause for the provided columns and returns the parameters.
+// SQLite binds parameters positionally, so the returned parameters are in the same order
+// as their ? placeholders.
+func (tm *objectIndexer) whereSql(w io.Writer, params []interface{}, cols []string) (resParams []interface{}, err error) {
+	_, err = fmt.Fprintf(w, " WHERE ")
+	if err != nil {
+		return nil, err
+	}
+
+	for i, col := range cols {
+		if i > 0 {
+			_, err = fmt.Fprintf(w, " AND ")
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = fmt.Fprintf(w, "%s ", col)
+		if err != nil {
+			return nil, err
+		}
+
+		if params[i] == nil {
+			_, err = fmt.Fprintf(w, "IS NULL")
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			_, err = fmt.Fprintf(w, "= ?")
+			if err != nil {
+				return nil, err
+			}
+
+			resParams = append(resParams, params[i])
+		}
+	}
+
+	return resParams, nil
+}
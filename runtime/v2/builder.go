@@ -31,6 +31,8 @@ type AppBuilder[T transaction.Tx] struct {
 	txValidator func(ctx context.Context, tx T) error
 	postTxExec  func(ctx context.Context, tx T, success bool) error
 	preblocker  func(ctx context.Context, txs []T, mmPreblocker func() error) error
+
+	parallelWorkers int // parallelWorkers is the number of workers executing the txs of a block in parallel.
 }
 
 // RegisterModules registers the provided modules with the module manager.
@@ -84,6 +86,12 @@ func (a *AppBuilder[T]) Build(opts ...AppBuilderOption[T]) (*App[T], error) {
 		}
 	}
 
+	if a.parallelWorkers > 1 {
+		if err := a.app.moduleManager.validateParallelExecution(); err != nil {
+			return nil, err
+		}
+	}
+
 	var err error
 	a.app.db, err = a.storeBuilder.Build(a.app.logger, a.storeConfig)
 	if err != nil {
@@ -118,6 +126,7 @@ func (a *AppBuilder[T]) Build(opts ...AppBuilderOption[T]) (*App[T], error) {
 		valUpdate,
 		a.postTxExec,
 		a.branch,
+		stf.WithParallelExecution(a.parallelWorkers),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create STF: %w", err)
@@ -232,6 +241,15 @@ func AppBuilderWithPostTxExec[T transaction.Tx](
 	}
 }
 
+// AppBuilderWithParallelExecution sets the number of workers executing the txs of
+// a block in parallel, see stf.WithParallelExecution. It overrides the
+// server.parallel-execution-workers option of the global config.
+func AppBuilderWithParallelExecution[T transaction.Tx](workers int) AppBuilderOption[T] {
+	return func(a *AppBuilder[T]) {
+		a.parallelWorkers = workers
+	}
+}
+
 // AppBuilderWithPreblocker sets logic that will be executed before each block.
 // mmPreblocker can be used to call module manager's preblocker, so that it can be
 // called before or after depending on the app's logic.
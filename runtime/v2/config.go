@@ -3,6 +3,8 @@ package runtime
 import (
 	"strings"
 
+	"github.com/spf13/cast"
+
 	"cosmossdk.io/core/server"
 	"cosmossdk.io/depinject"
 )
@@ -59,6 +61,17 @@ func ProvideModuleConfigMaps(
 	return moduleConfigMaps
 }
 
+// parallelExecutionWorkers returns the number of workers executing the txs of a
+// block in parallel, set by the server.parallel-execution-workers option of the
+// server component.
+func parallelExecutionWorkers(globalConfig GlobalConfig) int {
+	serverConfig, ok := globalConfig["server"].(map[string]any)
+	if !ok {
+		return 0
+	}
+	return cast.ToInt(serverConfig["parallel-execution-workers"])
+}
+
 func ProvideModuleScopedConfigMap(
 	key depinject.ModuleKey,
 	moduleConfigs ModuleConfigMaps,
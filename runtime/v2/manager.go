@@ -601,6 +601,28 @@ func (m *MM[T]) validateConfig() error {
 	return nil
 }
 
+// validateParallelExecution checks that the configuration allows the txs of a
+// block to be executed in parallel. The fees are then credited to the fee
+// collector by the end blocker of x/bank, which must run before the end blockers
+// of the other modules, since they may use the fee collector.
+func (m *MM[T]) validateParallelExecution() error {
+	const bankName = "bank"
+	if _, ok := m.modules[bankName].(appmodulev2.HasEndBlocker); !ok {
+		return nil
+	}
+	for _, moduleName := range m.config.EndBlockers {
+		if moduleName == bankName {
+			return nil
+		}
+		_, hasEndBlock := m.modules[moduleName].(appmodulev2.HasEndBlocker)
+		_, hasABCIEndBlock := m.modules[moduleName].(hasABCIEndBlock)
+		if hasEndBlock || hasABCIEndBlock {
+			return fmt.Errorf("the %s end blocker must run first with the parallel execution, found %s before it", bankName, moduleName)
+		}
+	}
+	return nil
+}
+
 // assertNoForgottenModules checks that we didn't forget any modules in the *runtimev2.Module config.
 // `pass` is a closure which allows one to omit modules from `moduleNames`.
 // If you provide non-nil `pass` and it returns true, the module would not be subject of the assertion.
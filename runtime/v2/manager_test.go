@@ -0,0 +1,64 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	runtimev2 "cosmossdk.io/api/cosmos/app/runtime/v2"
+	appmodulev2 "cosmossdk.io/core/appmodule/v2"
+	"cosmossdk.io/core/transaction"
+)
+
+type mockModule struct{}
+
+func (mockModule) IsAppModule()        {}
+func (mockModule) IsOnePerModuleType() {}
+
+type mockEndBlockerModule struct{ mockModule }
+
+func (mockEndBlockerModule) EndBlock(context.Context) error { return nil }
+
+func TestValidateParallelExecution(t *testing.T) {
+	modules := map[string]appmodulev2.AppModule{
+		"bank":    mockEndBlockerModule{},
+		"gov":     mockEndBlockerModule{},
+		"genutil": mockModule{},
+	}
+
+	tests := []struct {
+		name        string
+		endBlockers []string
+		wantErr     bool
+	}{
+		{
+			name:        "bank first",
+			endBlockers: []string{"bank", "gov"},
+		},
+		{
+			name:        "bank after a module without end blocker",
+			endBlockers: []string{"genutil", "bank", "gov"},
+		},
+		{
+			name:        "bank after an end blocker",
+			endBlockers: []string{"gov", "bank"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mm := &MM[transaction.Tx]{
+				config:  &runtimev2.Module{EndBlockers: tt.endBlockers},
+				modules: modules,
+			}
+			err := mm.validateParallelExecution()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
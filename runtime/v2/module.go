@@ -120,6 +120,7 @@ func ProvideAppBuilder[T transaction.Tx](
 	amino registry.AminoRegistrar,
 	storeBuilder root.Builder,
 	storeConfig *root.Config,
+	globalConfig GlobalConfig,
 ) (
 	*AppBuilder[T],
 	*stf.MsgRouterBuilder,
@@ -146,7 +147,12 @@ func ProvideAppBuilder[T transaction.Tx](
 		queryHandlers:      map[string]appmodulev2.Handler{},
 		storeLoader:        DefaultStoreLoader,
 	}
-	appBuilder := &AppBuilder[T]{app: app, storeBuilder: storeBuilder, storeConfig: storeConfig}
+	appBuilder := &AppBuilder[T]{
+		app:             app,
+		storeBuilder:    storeBuilder,
+		storeConfig:     storeConfig,
+		parallelWorkers: parallelExecutionWorkers(globalConfig),
+	}
 
 	return appBuilder, msgRouterBuilder, appModule[T]{app}, protoFiles, protoTypes
 }
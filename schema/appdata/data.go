@@ -42,6 +42,17 @@ type TxData struct {
 
 	// JSON is the JSON representation of the transaction. It should generally be a JSON object.
 	JSON ToJSON
+
+	// Hash lazily returns the hash of the transaction. It may be nil if the source does not provide it.
+	Hash ToBytes
+
+	// Signers lazily returns the addresses of the transaction signers. It may be nil if the source does not
+	// provide it.
+	Signers func() ([][]byte, error)
+
+	// MsgTypeURLs lazily returns the type URLs of the messages in the transaction in the order that they
+	// appear in the transaction. It may be nil if the source does not provide it.
+	MsgTypeURLs func() ([]string, error)
 }
 
 // EventData represents event data that is passed to a listener when events are received.
@@ -0,0 +1,130 @@
+package decoding
+
+import (
+	"fmt"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+)
+
+// VersionedSyncSource is a SyncSource which retains historical versions of state and can therefore be used
+// to replay state changes block by block. It should generally be a wrapper around a versioned key-value store.
+// SyncSource.IterateAllKVPairs is expected to iterate over the latest version of state.
+type VersionedSyncSource interface {
+	SyncSource
+
+	// LatestVersion returns the latest committed version of state.
+	LatestVersion() (uint64, error)
+
+	// SyncSourceAt returns a SyncSource for the state at the given version. An error should be returned
+	// if the version is not retained.
+	SyncSourceAt(version uint64) (SyncSource, error)
+
+	// IterateChanges iterates over the key-value pairs for a given module that changed between version-1
+	// and version. Deleted keys should be passed with Remove set to true. An error should be returned if
+	// either version is not retained.
+	IterateChanges(moduleName string, version uint64, fn func(update schema.KVPairUpdate) error) error
+}
+
+// ReplayOptions are the options for Replay.
+type ReplayOptions struct {
+	ModuleFilter func(moduleName string) bool
+
+	// FromVersion is the first version to replay.
+	FromVersion uint64
+
+	// ToVersion is the last version to replay, inclusive.
+	ToVersion uint64
+
+	// FullSync indicates that the full state at FromVersion should be sent rather than only the changes
+	// made in FromVersion. It should be set when the listener has no prior state.
+	FullSync bool
+}
+
+// Replay replays historical state from the source to the listener using the resolver to decode data.
+// All modules are initialized first and then each version in [FromVersion, ToVersion] is sent as its own
+// block consisting of a StartBlock packet, the object updates for that version and a Commit packet.
+func Replay(listener appdata.Listener, source VersionedSyncSource, resolver DecoderResolver, opts ReplayOptions) error {
+	if opts.FromVersion == 0 || opts.FromVersion > opts.ToVersion {
+		return fmt.Errorf("invalid replay range [%d, %d]", opts.FromVersion, opts.ToVersion)
+	}
+
+	var moduleNames []string
+	codecs := map[string]schema.ModuleCodec{}
+	err := resolver.AllDecoders(func(moduleName string, cdc schema.ModuleCodec) error {
+		if opts.ModuleFilter != nil && !opts.ModuleFilter(moduleName) {
+			// ignore this module
+			return nil
+		}
+
+		if listener.InitializeModuleData != nil {
+			err := listener.InitializeModuleData(appdata.ModuleInitializationData{
+				ModuleName: moduleName,
+				Schema:     cdc.Schema,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		moduleNames = append(moduleNames, moduleName)
+		codecs[moduleName] = cdc
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for version := opts.FromVersion; version <= opts.ToVersion; version++ {
+		err = listener.SendPacket(appdata.StartBlockData{Height: version})
+		if err != nil {
+			return err
+		}
+
+		var syncSource SyncSource
+		if opts.FullSync && version == opts.FromVersion {
+			syncSource, err = source.SyncSourceAt(version)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, moduleName := range moduleNames {
+			cdc := codecs[moduleName]
+			if listener.OnObjectUpdate == nil || cdc.KVDecoder == nil {
+				continue
+			}
+
+			onUpdate := func(update schema.KVPairUpdate) error {
+				updates, err := cdc.KVDecoder(update)
+				if err != nil {
+					return err
+				}
+
+				if len(updates) == 0 {
+					return nil
+				}
+
+				return listener.OnObjectUpdate(appdata.ObjectUpdateData{ModuleName: moduleName, Updates: updates})
+			}
+
+			if syncSource != nil {
+				err = syncSource.IterateAllKVPairs(moduleName, func(key, value []byte) error {
+					return onUpdate(schema.KVPairUpdate{Key: key, Value: value})
+				})
+			} else {
+				err = source.IterateChanges(moduleName, version, onUpdate)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to replay module %s at version %d: %v", moduleName, version, err) //nolint:errorlint // we support go 1.12, so no error wrapping
+			}
+		}
+
+		err = listener.SendPacket(appdata.CommitData{})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,236 @@
+package decoding
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+)
+
+func TestReplay(t *testing.T) {
+	tl := newTestFixture(t)
+	vs := newTestVersionedStore(tl.multiStore)
+
+	tl.bankMod.Mint("bob", "foo", 100)
+	vs.commit() // version 1
+	err := tl.bankMod.Send("bob", "alice", "foo", 50)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	vs.commit() // version 2
+	tl.oneMod.SetValue("abc")
+	vs.commit() // version 3
+
+	var blocks []uint64
+	commits := 0
+	tl.StartBlock = func(data appdata.StartBlockData) error {
+		blocks = append(blocks, data.Height)
+		return nil
+	}
+	tl.Commit = func(data appdata.CommitData) (func() error, error) {
+		commits++
+		return nil, nil
+	}
+
+	err = Replay(tl.Listener, vs, tl.resolver, ReplayOptions{FromVersion: 2, ToVersion: 3})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	if !reflect.DeepEqual(blocks, []uint64{2, 3}) {
+		t.Fatalf("expected blocks [2 3], got %v", blocks)
+	}
+	if commits != 2 {
+		t.Fatalf("expected 2 commits, got %d", commits)
+	}
+
+	// only the changes made in version 2 should be replayed for bank
+	expectedBank := []schema.StateObjectUpdate{
+		{
+			TypeName: "balances",
+			Key:      []interface{}{"alice", "foo"},
+			Value:    uint64(50),
+		},
+		{
+			TypeName: "balances",
+			Key:      []interface{}{"bob", "foo"},
+			Value:    uint64(50),
+		},
+	}
+
+	if !reflect.DeepEqual(tl.bankUpdates, expectedBank) {
+		t.Fatalf("expected %v, got %v", expectedBank, tl.bankUpdates)
+	}
+
+	expectedOne := []schema.StateObjectUpdate{
+		{TypeName: "item", Value: "abc"},
+	}
+
+	if !reflect.DeepEqual(tl.oneValueUpdates, expectedOne) {
+		t.Fatalf("expected %v, got %v", expectedOne, tl.oneValueUpdates)
+	}
+}
+
+func TestReplay_fullSync(t *testing.T) {
+	tl := newTestFixture(t)
+	vs := newTestVersionedStore(tl.multiStore)
+
+	tl.bankMod.Mint("bob", "foo", 100)
+	vs.commit() // version 1
+	tl.bankMod.Mint("bob", "bar", 10)
+	vs.commit() // version 2
+	tl.bankMod.Mint("alice", "bar", 5)
+	vs.commit() // version 3
+
+	err := Replay(tl.Listener, vs, tl.resolver, ReplayOptions{FromVersion: 2, ToVersion: 3, FullSync: true})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	expected := []schema.StateObjectUpdate{
+		// full state at version 2
+		{
+			TypeName: "balances",
+			Key:      []interface{}{"bob", "bar"},
+			Value:    uint64(10),
+		},
+		{
+			TypeName: "balances",
+			Key:      []interface{}{"bob", "foo"},
+			Value:    uint64(100),
+		},
+		{
+			TypeName: "supply",
+			Key:      []interface{}{"bar"},
+			Value:    uint64(10),
+		},
+		{
+			TypeName: "supply",
+			Key:      []interface{}{"foo"},
+			Value:    uint64(100),
+		},
+		// changes in version 3
+		{
+			TypeName: "balances",
+			Key:      []interface{}{"alice", "bar"},
+			Value:    uint64(5),
+		},
+		{
+			TypeName: "supply",
+			Key:      []interface{}{"bar"},
+			Value:    uint64(15),
+		},
+	}
+
+	if !reflect.DeepEqual(tl.bankUpdates, expected) {
+		t.Fatalf("expected %v, got %v", expected, tl.bankUpdates)
+	}
+}
+
+func TestReplay_invalidRange(t *testing.T) {
+	tl := newTestFixture(t)
+	vs := newTestVersionedStore(tl.multiStore)
+
+	err := Replay(tl.Listener, vs, tl.resolver, ReplayOptions{FromVersion: 3, ToVersion: 2})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// testVersionedStore is a VersionedSyncSource which snapshots the state of a testMultiStore on every commit.
+type testVersionedStore struct {
+	*testMultiStore
+	versions []map[string]map[string][]byte
+}
+
+var _ VersionedSyncSource = &testVersionedStore{}
+
+func newTestVersionedStore(ms *testMultiStore) *testVersionedStore {
+	// version 0 is the empty state
+	return &testVersionedStore{
+		testMultiStore: ms,
+		versions:       []map[string]map[string][]byte{{}},
+	}
+}
+
+func (vs *testVersionedStore) commit() {
+	snapshot := map[string]map[string][]byte{}
+	for moduleName, s := range vs.stores {
+		kvs := map[string][]byte{}
+		for k, v := range s.store {
+			kvs[k] = v
+		}
+		snapshot[moduleName] = kvs
+	}
+	vs.versions = append(vs.versions, snapshot)
+}
+
+func (vs *testVersionedStore) LatestVersion() (uint64, error) {
+	return uint64(len(vs.versions) - 1), nil
+}
+
+func (vs *testVersionedStore) getVersion(version uint64) (map[string]map[string][]byte, error) {
+	if version >= uint64(len(vs.versions)) {
+		return nil, fmt.Errorf("version %d not found", version)
+	}
+	return vs.versions[version], nil
+}
+
+func (vs *testVersionedStore) SyncSourceAt(version uint64) (SyncSource, error) {
+	snapshot, err := vs.getVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	ms := newTestMultiStore()
+	for moduleName, kvs := range snapshot {
+		ms.stores[moduleName] = &testStore{modName: moduleName, store: kvs}
+	}
+	return ms, nil
+}
+
+func (vs *testVersionedStore) IterateChanges(moduleName string, version uint64, fn func(update schema.KVPairUpdate) error) error {
+	if version == 0 {
+		return fmt.Errorf("version 0 has no changes")
+	}
+	prev, err := vs.getVersion(version - 1)
+	if err != nil {
+		return err
+	}
+	cur, err := vs.getVersion(version)
+	if err != nil {
+		return err
+	}
+
+	keySet := map[string]bool{}
+	for k := range prev[moduleName] {
+		keySet[k] = true
+	}
+	for k := range cur[moduleName] {
+		keySet[k] = true
+	}
+	var keys []string
+	for k := range keySet {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		before, hadBefore := prev[moduleName][k]
+		after, hasAfter := cur[moduleName][k]
+		var err error
+		switch {
+		case !hasAfter:
+			err = fn(schema.KVPairUpdate{Key: []byte(k), Remove: true})
+		case !hadBefore || !bytes.Equal(before, after):
+			err = fn(schema.KVPairUpdate{Key: []byte(k), Value: after})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
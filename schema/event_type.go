@@ -0,0 +1,39 @@
+package schema
+
+import "fmt"
+
+// EventType describes the structure of a typed event, such as an ADR-032 typed protobuf event, so that
+// indexers can store events of this type with a column for each field rather than as opaque JSON.
+// Unlike StateObjectType and EnumType, event types are not scoped to a module schema because events are
+// identified by their fully-qualified type name.
+type EventType struct {
+	// Name is the fully-qualified name of the event type. It must conform to QualifiedNameFormat and
+	// it is matched against the Type of appdata.Event.
+	Name string `json:"name"`
+
+	// Fields are the fields of the event. Field names should match the keys of the event's JSON representation.
+	// EnumKind fields are not supported because event types don't belong to a type set; enum values should
+	// be represented as StringKind fields instead.
+	Fields []Field `json:"fields"`
+}
+
+// Validate validates the event type.
+func (e EventType) Validate() error {
+	if !ValidateQualifiedName(e.Name) {
+		return fmt.Errorf("invalid event type name %q", e.Name)
+	}
+
+	fieldNames := map[string]bool{}
+	for _, field := range e.Fields {
+		if err := field.Validate(EmptyTypeSet()); err != nil {
+			return fmt.Errorf("invalid field %q in event type %q: %v", field.Name, e.Name, err) //nolint:errorlint // false positive due to using go1.12
+		}
+
+		if fieldNames[field.Name] {
+			return fmt.Errorf("duplicate field name %q in event type %q", field.Name, e.Name)
+		}
+		fieldNames[field.Name] = true
+	}
+
+	return nil
+}
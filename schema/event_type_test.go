@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEventType_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		eventType   EventType
+		errContains string
+	}{
+		{
+			name: "valid",
+			eventType: EventType{
+				Name: "cosmos.bank.v1beta1.EventSend",
+				Fields: []Field{
+					{Name: "sender", Kind: AddressKind},
+					{Name: "amount", Kind: JSONKind, Nullable: true},
+				},
+			},
+		},
+		{
+			name:        "invalid name",
+			eventType:   EventType{Name: "cosmos..EventSend"},
+			errContains: "invalid event type name",
+		},
+		{
+			name: "invalid field",
+			eventType: EventType{
+				Name:   "EventSend",
+				Fields: []Field{{Name: "a", Kind: InvalidKind}},
+			},
+			errContains: "invalid field",
+		},
+		{
+			name: "enum field",
+			eventType: EventType{
+				Name:   "EventSend",
+				Fields: []Field{{Name: "a", Kind: EnumKind, ReferencedType: "foo"}},
+			},
+			errContains: "can't find enum type",
+		},
+		{
+			name: "duplicate field",
+			eventType: EventType{
+				Name:   "EventSend",
+				Fields: []Field{{Name: "a", Kind: StringKind}, {Name: "a", Kind: Int32Kind}},
+			},
+			errContains: "duplicate field name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.eventType.Validate()
+			if tt.errContains == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Fatalf("expected error containing %q, got: %v", tt.errContains, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package indexer
+
+import (
+	"fmt"
+
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/decoding"
+	"cosmossdk.io/schema/logutil"
+	"cosmossdk.io/schema/view"
+)
+
+// catchUp brings an indexer up to date with the sync source based on the block number reported by its view.
+// The returned listener should be used in place of the indexer's listener from then on because module
+// initialization has already been performed for all modules that were synced.
+func catchUp(opts IndexingOptions, cfg Config, listener appdata.Listener, view view.AppData, logger logutil.Logger) (appdata.Listener, error) {
+	blockNum, err := view.BlockNum()
+	if err != nil {
+		return appdata.Listener{}, err
+	}
+
+	initialized := map[string]bool{}
+	initializeModuleData := listener.InitializeModuleData
+	if initializeModuleData != nil {
+		listener.InitializeModuleData = func(data appdata.ModuleInitializationData) error {
+			initialized[data.ModuleName] = true
+			return initializeModuleData(data)
+		}
+	}
+
+	versioned, ok := opts.SyncSource.(decoding.VersionedSyncSource)
+	if !ok {
+		// without historical versions, we can only sync the current state into an empty indexer
+		if blockNum == 0 {
+			logger.Info("Syncing current state")
+			err = decoding.Sync(listener, opts.SyncSource, opts.Resolver, decoding.SyncOptions{})
+			if err != nil {
+				return appdata.Listener{}, err
+			}
+		}
+		return skipInitialized(listener, initializeModuleData, initialized), nil
+	}
+
+	latest, err := versioned.LatestVersion()
+	if err != nil {
+		return appdata.Listener{}, err
+	}
+
+	replayOpts := decoding.ReplayOptions{ToVersion: latest}
+	switch {
+	case blockNum > latest:
+		return appdata.Listener{}, fmt.Errorf("indexer block number %d is ahead of the latest version %d", blockNum, latest)
+	case blockNum == latest:
+		// already up to date
+		return skipInitialized(listener, initializeModuleData, initialized), nil
+	case blockNum == 0:
+		replayOpts.FromVersion = cfg.StartHeight
+		if replayOpts.FromVersion == 0 {
+			replayOpts.FromVersion = latest
+		}
+		if replayOpts.FromVersion > latest {
+			return appdata.Listener{}, fmt.Errorf("start height %d is ahead of the latest version %d", replayOpts.FromVersion, latest)
+		}
+		replayOpts.FullSync = true
+	default:
+		replayOpts.FromVersion = blockNum + 1
+	}
+
+	logger.Info("Replaying historical state", "from", replayOpts.FromVersion, "to", replayOpts.ToVersion, "full_sync", replayOpts.FullSync)
+	err = decoding.Replay(listener, versioned, opts.Resolver, replayOpts)
+	if err != nil {
+		return appdata.Listener{}, err
+	}
+
+	return skipInitialized(listener, initializeModuleData, initialized), nil
+}
+
+// skipInitialized returns a listener which forwards module initialization to initializeModuleData only for
+// modules which are not in the initialized set. This prevents modules which were initialized during
+// a catch-up sync from being initialized a second time when they are encountered in the data stream.
+func skipInitialized(listener appdata.Listener, initializeModuleData func(appdata.ModuleInitializationData) error, initialized map[string]bool) appdata.Listener {
+	if initializeModuleData == nil {
+		return listener
+	}
+
+	listener.InitializeModuleData = func(data appdata.ModuleInitializationData) error {
+		if initialized[data.ModuleName] {
+			return nil
+		}
+		return initializeModuleData(data)
+	}
+	return listener
+}
@@ -0,0 +1,172 @@
+package indexer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/appdata"
+	"cosmossdk.io/schema/decoding"
+	"cosmossdk.io/schema/logutil"
+	"cosmossdk.io/schema/view"
+)
+
+func TestCatchUp(t *testing.T) {
+	source := &testVersionedSource{values: []string{"", "a", "b", "c"}}
+	resolver := decoding.ModuleSetDecoderResolver(map[string]interface{}{"one": testValueModule{}})
+
+	tests := []struct {
+		name           string
+		blockNum       uint64
+		startHeight    uint64
+		expectedBlocks []uint64
+		expectedValues []interface{}
+		expectErr      bool
+	}{
+		{
+			name:           "new indexer at latest height",
+			blockNum:       0,
+			expectedBlocks: []uint64{3},
+			expectedValues: []interface{}{"c"},
+		},
+		{
+			name:           "new indexer at start height",
+			blockNum:       0,
+			startHeight:    1,
+			expectedBlocks: []uint64{1, 2, 3},
+			expectedValues: []interface{}{"a", "b", "c"},
+		},
+		{
+			name:           "indexer behind",
+			blockNum:       2,
+			expectedBlocks: []uint64{3},
+			expectedValues: []interface{}{"c"},
+		},
+		{
+			name:     "indexer up to date",
+			blockNum: 3,
+		},
+		{
+			name:      "indexer ahead",
+			blockNum:  4,
+			expectErr: true,
+		},
+		{
+			name:        "start height ahead",
+			blockNum:    0,
+			startHeight: 5,
+			expectErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var blocks []uint64
+			var values []interface{}
+			initCount := 0
+			listener := appdata.Listener{
+				InitializeModuleData: func(data appdata.ModuleInitializationData) error {
+					initCount++
+					return nil
+				},
+				StartBlock: func(data appdata.StartBlockData) error {
+					blocks = append(blocks, data.Height)
+					return nil
+				},
+				OnObjectUpdate: func(data appdata.ObjectUpdateData) error {
+					for _, update := range data.Updates {
+						values = append(values, update.Value)
+					}
+					return nil
+				},
+			}
+
+			res, err := catchUp(IndexingOptions{SyncSource: source, Resolver: resolver},
+				Config{StartHeight: tt.startHeight}, listener, testView{blockNum: tt.blockNum}, logutil.NoopLogger{})
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(blocks, tt.expectedBlocks) {
+				t.Fatalf("expected blocks %v, got %v", tt.expectedBlocks, blocks)
+			}
+			if !reflect.DeepEqual(values, tt.expectedValues) {
+				t.Fatalf("expected values %v, got %v", tt.expectedValues, values)
+			}
+
+			// modules initialized during catch-up should not be initialized again, so regardless of whether
+			// any versions were replayed, the module should be initialized exactly once
+			err = res.InitializeModuleData(appdata.ModuleInitializationData{ModuleName: "one"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if initCount != 1 {
+				t.Fatalf("expected 1 module initialization, got %d", initCount)
+			}
+		})
+	}
+}
+
+type testView struct {
+	blockNum uint64
+}
+
+func (v testView) BlockNum() (uint64, error) { return v.blockNum, nil }
+
+func (v testView) AppState() view.AppState { return nil }
+
+// testVersionedSource is a decoding.VersionedSyncSource for the testValueModule where each version
+// stores a single value.
+type testVersionedSource struct {
+	values []string
+}
+
+var _ decoding.VersionedSyncSource = &testVersionedSource{}
+
+func (s *testVersionedSource) IterateAllKVPairs(moduleName string, fn func(key, value []byte) error) error {
+	return fn([]byte("item"), []byte(s.values[len(s.values)-1]))
+}
+
+func (s *testVersionedSource) LatestVersion() (uint64, error) {
+	return uint64(len(s.values) - 1), nil
+}
+
+func (s *testVersionedSource) SyncSourceAt(version uint64) (decoding.SyncSource, error) {
+	if version >= uint64(len(s.values)) {
+		return nil, fmt.Errorf("version %d not found", version)
+	}
+	return &testVersionedSource{values: s.values[:version+1]}, nil
+}
+
+func (s *testVersionedSource) IterateChanges(moduleName string, version uint64, fn func(update schema.KVPairUpdate) error) error {
+	if version == 0 || version >= uint64(len(s.values)) {
+		return fmt.Errorf("version %d not found", version)
+	}
+	return fn(schema.KVPairUpdate{Key: []byte("item"), Value: []byte(s.values[version])})
+}
+
+type testValueModule struct{}
+
+func (testValueModule) ModuleCodec() (schema.ModuleCodec, error) {
+	modSchema, err := schema.CompileModuleSchema(schema.StateObjectType{
+		Name:        "item",
+		ValueFields: []schema.Field{{Name: "value", Kind: schema.StringKind}},
+	})
+	if err != nil {
+		return schema.ModuleCodec{}, err
+	}
+
+	return schema.ModuleCodec{
+		Schema: modSchema,
+		KVDecoder: func(update schema.KVPairUpdate) ([]schema.StateObjectUpdate, error) {
+			return []schema.StateObjectUpdate{{TypeName: "item", Value: string(update.Value)}}, nil
+		},
+	}, nil
+}
@@ -15,6 +15,11 @@ type Config struct {
 	// Config are the indexer specific config options specified by the user.
 	Config interface{} `mapstructure:"config" toml:"config" json:"config,omitempty" comment:"Indexer specific configuration options."`
 
+	// StartHeight is the height at which an indexer with no indexed data should start indexing. It is only
+	// used when the sync source retains historical versions and must be a retained height. If it is 0,
+	// indexing starts at the latest height.
+	StartHeight uint64 `mapstructure:"start_height" toml:"start_height" json:"start_height,omitempty" comment:"Height at which an indexer with no indexed data starts indexing. Defaults to the latest height."`
+
 	// Filter is the filter configuration for the indexer.
 	Filter *FilterConfig `mapstructure:"filter" toml:"filter" json:"filter,omitempty" comment:"Filter configuration for the indexer. Currently UNSUPPORTED!"`
 }
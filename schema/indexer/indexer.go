@@ -3,6 +3,7 @@ package indexer
 import (
 	"context"
 
+	"cosmossdk.io/schema"
 	"cosmossdk.io/schema/addressutil"
 	"cosmossdk.io/schema/appdata"
 	"cosmossdk.io/schema/logutil"
@@ -36,6 +37,10 @@ type InitParams struct {
 	// AddressCodec is the address codec that the indexer can use to encode and decode addresses. It is
 	// expected to be non-nil.
 	AddressCodec addressutil.AddressCodec
+
+	// EventTypes are the typed events known to the app which indexers can use to store events in a
+	// structured form. It may be empty.
+	EventTypes []schema.EventType
 }
 
 // InitResult is the indexer initialization result and includes the indexer's listener implementation.
@@ -49,7 +54,10 @@ type InitResult struct {
 	// starting block for the indexer. If the block number is 0, the indexer manager will attempt
 	// to perform a catch-up sync of state. Historical events will not be replayed, but an accurate
 	// representation of the current state at the height at which indexing began can be reproduced.
-	// If the block number is non-zero but does not match the current chain height, a runtime error
-	// will occur because this is an unsafe condition that indicates lost data.
+	// If the sync source retains historical versions (see decoding.VersionedSyncSource), the catch-up
+	// sync starts at Config.StartHeight and every following version is replayed as its own block,
+	// and an indexer whose block number is behind the current chain height will have the missing
+	// versions replayed. Otherwise, if the block number is non-zero but does not match the current
+	// chain height, a runtime error will occur because this is an unsafe condition that indicates lost data.
 	View view.AppData
 }
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"sync"
 
+	"cosmossdk.io/schema"
 	"cosmossdk.io/schema/addressutil"
 	"cosmossdk.io/schema/appdata"
 	"cosmossdk.io/schema/decoding"
@@ -27,6 +28,8 @@ type IndexingOptions struct {
 	// SyncSource is a representation of the current state of key-value data to be used in a catch-up sync.
 	// Catch-up syncs will be performed at initialization when necessary. SyncSource is optional but if
 	// it is omitted, indexers will only be able to start indexing state from genesis.
+	// If SyncSource also implements decoding.VersionedSyncSource, indexers which are behind the latest
+	// version will have the missing versions replayed and new indexers can start at any retained version.
 	SyncSource decoding.SyncSource
 
 	// Logger is the logger that indexers can use to write logs. It is optional.
@@ -42,6 +45,10 @@ type IndexingOptions struct {
 	// as hex strings.
 	AddressCodec addressutil.AddressCodec
 
+	// EventTypes are the typed events known to the app. They are passed to indexers so that they can store
+	// events of these types in a structured form. It is optional.
+	EventTypes []schema.EventType
+
 	// DoneWaitGroup is a wait group that all indexer manager go routines will wait on before returning when the context
 	// is done.
 	// It is optional.
@@ -98,6 +105,12 @@ func StartIndexing(opts IndexingOptions) (IndexingTarget, error) {
 		ctx = context.Background()
 	}
 
+	for _, eventType := range opts.EventTypes {
+		if err := eventType.Validate(); err != nil {
+			return IndexingTarget{}, err
+		}
+	}
+
 	listeners := make([]appdata.Listener, 0, len(cfg.Target))
 	indexerInfos := make(map[string]IndexerInfo, len(cfg.Target))
 
@@ -128,12 +141,19 @@ func StartIndexing(opts IndexingOptions) (IndexingTarget, error) {
 			Context:      ctx,
 			Logger:       childLogger,
 			AddressCodec: opts.AddressCodec,
+			EventTypes:   opts.EventTypes,
 		})
 		if err != nil {
 			return IndexingTarget{}, err
 		}
 
 		listener := initRes.Listener
+		if opts.SyncSource != nil && initRes.View != nil {
+			listener, err = catchUp(opts, targetCfg, listener, initRes.View, childLogger)
+			if err != nil {
+				return IndexingTarget{}, fmt.Errorf("failed to catch up indexer target %q: %v", targetName, err) //nolint:errorlint // we support go 1.12, so no error wrapping
+			}
+		}
 		listeners = append(listeners, listener)
 
 		indexerInfos[targetName] = IndexerInfo{
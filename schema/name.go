@@ -13,3 +13,15 @@ var nameRegex = regexp.MustCompile(NameFormat)
 func ValidateName(name string) bool {
 	return nameRegex.MatchString(name)
 }
+
+// QualifiedNameFormat is the regular expression that a qualified name must match.
+// A qualified name is one or more names conforming to NameFormat joined by dots,
+// such as the fully-qualified name of a protobuf message.
+const QualifiedNameFormat = `^[a-zA-Z_][a-zA-Z0-9_]{0,63}(\.[a-zA-Z_][a-zA-Z0-9_]{0,63})*$`
+
+var qualifiedNameRegex = regexp.MustCompile(QualifiedNameFormat)
+
+// ValidateQualifiedName checks if the given name is a valid qualified name conforming to QualifiedNameFormat.
+func ValidateQualifiedName(name string) bool {
+	return qualifiedNameRegex.MatchString(name)
+}
@@ -29,3 +29,27 @@ func TestValidateName(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateQualifiedName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"", false},
+		{"a", true},
+		{"cosmos.bank.v1beta1.EventSend", true},
+		{"a.", false},
+		{".a", false},
+		{"a..b", false},
+		{"a.0b", false},
+		{"a b.c", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if ValidateQualifiedName(test.name) != test.valid {
+				t.Errorf("expected %v for name %q", test.valid, test.name)
+			}
+		})
+	}
+}
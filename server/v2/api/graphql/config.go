@@ -0,0 +1,48 @@
+package graphql
+
+func DefaultConfig() *Config {
+	return &Config{
+		Enable:        false,
+		Address:       "localhost:8081",
+		IndexerTarget: "",
+		MaxLimit:      100,
+	}
+}
+
+type CfgOption func(*Config)
+
+// Config defines configuration for the GraphQL server.
+type Config struct {
+	// Enable defines if the GraphQL server should be enabled.
+	Enable bool `mapstructure:"enable" toml:"enable" comment:"Enable defines if the GraphQL server should be enabled. It requires an indexer target which exposes a view of the indexed data."`
+
+	// Address defines the address the GraphQL server binds to.
+	Address string `mapstructure:"address" toml:"address" comment:"Address defines the GraphQL server address to bind to."`
+
+	// IndexerTarget is the name of the indexer target to query.
+	IndexerTarget string `mapstructure:"indexer-target" toml:"indexer-target" comment:"IndexerTarget is the name of the indexer target to query. It can be left empty if only one indexer target exposes a view of the indexed data."`
+
+	// MaxLimit is the maximum number of objects that can be returned by a single query field.
+	MaxLimit int `mapstructure:"max-limit" toml:"max-limit" comment:"MaxLimit is the maximum number of objects that can be returned by a single query field. It is also the default limit."`
+}
+
+// OverwriteDefaultConfig overwrites the default config with the new config.
+func OverwriteDefaultConfig(newCfg *Config) CfgOption {
+	return func(cfg *Config) {
+		*cfg = *newCfg
+	}
+}
+
+// Disable the GraphQL server by default (default disabled).
+func Disable() CfgOption {
+	return func(cfg *Config) {
+		cfg.Enable = false
+	}
+}
+
+// Enable the GraphQL server by default (default disabled).
+func Enable() CfgOption {
+	return func(cfg *Config) {
+		cfg.Enable = true
+	}
+}
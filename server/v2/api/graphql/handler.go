@@ -0,0 +1,88 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	ContentTypeJSON = "application/json"
+	MaxBodySize     = 1 << 20 // 1 MB
+)
+
+// request is a GraphQL request as sent over HTTP.
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// NewHandler returns an HTTP handler which executes GraphQL queries against the schema.
+// Queries can be sent either as a JSON body with a POST request or as URL query parameters with a GET request.
+func NewHandler(schema graphql.Schema) http.Handler {
+	return &handler{schema: schema}
+}
+
+type handler struct {
+	schema graphql.Schema
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := parseRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res := graphql.Do(graphql.Params{
+		Schema:         h.schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        r.Context(),
+	})
+
+	w.Header().Set("Content-Type", ContentTypeJSON)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		http.Error(w, fmt.Sprintf("Error encoding response: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// parseRequest reads the GraphQL request from the HTTP request.
+func parseRequest(r *http.Request) (request, error) {
+	var req request
+	switch r.Method {
+	case http.MethodGet:
+		params := r.URL.Query()
+		req.Query = params.Get("query")
+		req.OperationName = params.Get("operationName")
+		if vars := params.Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				return req, fmt.Errorf("invalid variables: %w", err)
+			}
+		}
+	case http.MethodPost:
+		if contentType := r.Header.Get("Content-Type"); contentType != ContentTypeJSON {
+			return req, fmt.Errorf("unsupported content type, expected %s", ContentTypeJSON)
+		}
+
+		bz, err := io.ReadAll(io.LimitReader(r.Body, MaxBodySize))
+		if err != nil {
+			return req, err
+		}
+		if err := json.Unmarshal(bz, &req); err != nil {
+			return req, fmt.Errorf("invalid request body: %w", err)
+		}
+	default:
+		return req, fmt.Errorf("method not allowed")
+	}
+
+	if req.Query == "" {
+		return req, fmt.Errorf("query is required")
+	}
+	return req, nil
+}
@@ -0,0 +1,314 @@
+package graphql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/addressutil"
+	"cosmossdk.io/schema/view"
+)
+
+// KeysetObjectCollection is implemented by view.ObjectCollection's which can iterate over their objects in key
+// order starting after a key, such as the ones of the PostgreSQL indexer. Objects are returned in key order
+// and the after argument is supported only for collections which implement it.
+type KeysetObjectCollection interface {
+	view.ObjectCollection
+
+	// StateAfter iterates over the state of the collection in ascending key order starting after the object
+	// with the provided key, or at the first object if key is nil.
+	StateAfter(key interface{}, f func(schema.StateObjectUpdate, error) bool)
+}
+
+// objectResolver resolves the objects of a state object type from a module state view.
+type objectResolver struct {
+	objectType   schema.StateObjectType
+	addressCodec addressutil.AddressCodec
+	maxLimit     int
+}
+
+// resolve returns the objects of the state object type matching the where argument as a list of maps of field
+// names to GraphQL values. If all key fields are provided, the object is looked up directly, otherwise
+// objects are iterated over in key order starting after the after argument and filtered until limit objects match.
+func (r *objectResolver) resolve(p graphql.ResolveParams) (interface{}, error) {
+	modState, ok := p.Source.(view.ModuleState)
+	if !ok {
+		return nil, fmt.Errorf("unexpected source %T", p.Source)
+	}
+
+	limit := r.maxLimit
+	if l, ok := p.Args[limitArg].(int); ok {
+		if l < 0 || l > r.maxLimit {
+			return nil, fmt.Errorf("limit must be between 0 and %d", r.maxLimit)
+		}
+		limit = l
+	}
+
+	where := map[string]interface{}{}
+	if args, ok := p.Args[whereArg].(map[string]interface{}); ok {
+		for name, value := range args {
+			if value != nil {
+				where[name] = value
+			}
+		}
+	}
+
+	res := []map[string]interface{}{}
+	coll, err := modState.GetObjectCollection(r.objectType.Name)
+	if err != nil || coll == nil || limit == 0 {
+		return res, err
+	}
+
+	var after interface{}
+	if args, ok := p.Args[afterArg].(map[string]interface{}); ok {
+		after, err = r.decodeKey(args)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(where) == len(r.objectType.KeyFields) && after == nil {
+		key, err := r.decodeKey(where)
+		if err != nil {
+			return nil, err
+		}
+
+		update, found, err := coll.GetObject(key)
+		if err != nil || !found || update.Delete {
+			return res, err
+		}
+
+		obj, err := r.encodeObject(update)
+		if err != nil {
+			return nil, err
+		}
+		return append(res, obj), nil
+	}
+
+	iterate := coll.AllState
+	if keysetColl, ok := coll.(KeysetObjectCollection); ok {
+		iterate = func(f func(schema.StateObjectUpdate, error) bool) {
+			keysetColl.StateAfter(after, f)
+		}
+	} else if after != nil {
+		return nil, fmt.Errorf("object collection %s does not support the %s argument", r.objectType.Name, afterArg)
+	}
+
+	iterate(func(update schema.StateObjectUpdate, iterErr error) bool {
+		if iterErr != nil {
+			err = iterErr
+			return false
+		}
+
+		if update.Delete {
+			return true
+		}
+
+		var obj map[string]interface{}
+		obj, err = r.encodeObject(update)
+		if err != nil {
+			return false
+		}
+
+		for name, value := range where {
+			if obj[name] != value {
+				return true
+			}
+		}
+
+		res = append(res, obj)
+		return len(res) < limit
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// decodeKey converts the GraphQL values of the key fields to an object key.
+func (r *objectResolver) decodeKey(values map[string]interface{}) (interface{}, error) {
+	key := make([]interface{}, len(r.objectType.KeyFields))
+	for i, field := range r.objectType.KeyFields {
+		value, err := r.decodeValue(field, values[field.Name])
+		if err != nil {
+			return nil, err
+		}
+		key[i] = value
+	}
+
+	if len(key) == 1 {
+		return key[0], nil
+	}
+	return key, nil
+}
+
+// encodeObject converts the key and value of an object update to a map of field names to GraphQL values.
+func (r *objectResolver) encodeObject(update schema.StateObjectUpdate) (map[string]interface{}, error) {
+	obj := make(map[string]interface{}, len(r.objectType.KeyFields)+len(r.objectType.ValueFields))
+
+	err := r.encodeFields(obj, r.objectType.KeyFields, update.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	if valueUpdates, ok := update.Value.(schema.ValueUpdates); ok {
+		fields := make(map[string]schema.Field, len(r.objectType.ValueFields))
+		for _, field := range r.objectType.ValueFields {
+			fields[field.Name] = field
+		}
+
+		err = valueUpdates.Iterate(func(col string, value interface{}) bool {
+			obj[col], err = r.encodeValue(fields[col], value)
+			return err == nil
+		})
+		return obj, err
+	}
+
+	err = r.encodeFields(obj, r.objectType.ValueFields, update.Value)
+	return obj, err
+}
+
+// encodeFields converts the values of fields to GraphQL values and adds them to obj. value is expected
+// to be a single value if there is one field or a slice of values otherwise.
+func (r *objectResolver) encodeFields(obj map[string]interface{}, fields []schema.Field, value interface{}) error {
+	switch len(fields) {
+	case 0:
+		return nil
+	case 1:
+		encoded, err := r.encodeValue(fields[0], value)
+		if err != nil {
+			return err
+		}
+		obj[fields[0].Name] = encoded
+		return nil
+	}
+
+	values, ok := value.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected []interface{} for object %s, got %T", r.objectType.Name, value)
+	}
+	if len(values) != len(fields) {
+		return fmt.Errorf("expected %d values for object %s, got %d", len(fields), r.objectType.Name, len(values))
+	}
+
+	for i, field := range fields {
+		encoded, err := r.encodeValue(field, values[i])
+		if err != nil {
+			return err
+		}
+		obj[field.Name] = encoded
+	}
+	return nil
+}
+
+// encodeValue converts a field value to its GraphQL representation.
+func (r *objectResolver) encodeValue(field schema.Field, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch field.Kind {
+	case schema.StringKind, schema.EnumKind:
+		return value.(string), nil
+	case schema.IntegerKind, schema.DecimalKind:
+		return value.(string), nil
+	case schema.BytesKind:
+		return base64.StdEncoding.EncodeToString(value.([]byte)), nil
+	case schema.AddressKind:
+		return r.addressCodec.BytesToString(value.([]byte))
+	case schema.Int8Kind:
+		return int(value.(int8)), nil
+	case schema.Int16Kind:
+		return int(value.(int16)), nil
+	case schema.Int32Kind:
+		return int(value.(int32)), nil
+	case schema.Uint8Kind:
+		return int(value.(uint8)), nil
+	case schema.Uint16Kind:
+		return int(value.(uint16)), nil
+	case schema.Uint32Kind:
+		return strconv.FormatUint(uint64(value.(uint32)), 10), nil
+	case schema.Int64Kind:
+		return strconv.FormatInt(value.(int64), 10), nil
+	case schema.Uint64Kind:
+		return strconv.FormatUint(value.(uint64), 10), nil
+	case schema.Float32Kind:
+		return float64(value.(float32)), nil
+	case schema.Float64Kind:
+		return value.(float64), nil
+	case schema.BoolKind:
+		return value.(bool), nil
+	case schema.TimeKind:
+		return value.(time.Time).UTC().Format(time.RFC3339Nano), nil
+	case schema.DurationKind:
+		return value.(time.Duration).String(), nil
+	case schema.JSONKind:
+		return string(value.(json.RawMessage)), nil
+	default:
+		return nil, fmt.Errorf("unsupported kind %s for field %q", field.Kind, field.Name)
+	}
+}
+
+// decodeValue converts the GraphQL representation of a key field value to the field value.
+func (r *objectResolver) decodeValue(field schema.Field, value interface{}) (interface{}, error) {
+	res, err := decodeKeyValue(field.Kind, value, r.addressCodec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for field %q: %w", field.Name, err)
+	}
+	return res, nil
+}
+
+func decodeKeyValue(kind schema.Kind, value interface{}, addressCodec addressutil.AddressCodec) (interface{}, error) {
+	switch kind {
+	case schema.BoolKind:
+		return value.(bool), nil
+	case schema.Int8Kind:
+		return convertInt[int8](value.(int))
+	case schema.Int16Kind:
+		return convertInt[int16](value.(int))
+	case schema.Int32Kind:
+		return convertInt[int32](value.(int))
+	case schema.Uint8Kind:
+		return convertInt[uint8](value.(int))
+	case schema.Uint16Kind:
+		return convertInt[uint16](value.(int))
+	}
+
+	str := value.(string)
+	switch kind {
+	case schema.StringKind, schema.EnumKind, schema.IntegerKind, schema.DecimalKind:
+		return str, nil
+	case schema.BytesKind:
+		return base64.StdEncoding.DecodeString(str)
+	case schema.AddressKind:
+		return addressCodec.StringToBytes(str)
+	case schema.Uint32Kind:
+		v, err := strconv.ParseUint(str, 10, 32)
+		return uint32(v), err
+	case schema.Int64Kind:
+		return strconv.ParseInt(str, 10, 64)
+	case schema.Uint64Kind:
+		return strconv.ParseUint(str, 10, 64)
+	case schema.TimeKind:
+		return time.Parse(time.RFC3339Nano, str)
+	case schema.DurationKind:
+		return time.ParseDuration(str)
+	default:
+		return nil, fmt.Errorf("unsupported key kind %s", kind)
+	}
+}
+
+// convertInt converts a GraphQL integer to a narrower integer type, checking that it is in range.
+func convertInt[T int8 | int16 | int32 | uint8 | uint16](v int) (T, error) {
+	res := T(v)
+	if int(res) != v {
+		return 0, fmt.Errorf("%d is out of range", v)
+	}
+	return res, nil
+}
@@ -0,0 +1,256 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/addressutil"
+	"cosmossdk.io/schema/view"
+)
+
+const (
+	whereArg = "where"
+	limitArg = "limit"
+	afterArg = "after"
+)
+
+// schemaBuilder generates a GraphQL schema from module schemas and resolves queries against an indexer view.
+type schemaBuilder struct {
+	appData      view.AppData
+	addressCodec addressutil.AddressCodec
+	maxLimit     int
+}
+
+// NewSchema generates a GraphQL schema for the provided module schemas which resolves queries against appData.
+//
+// The root query type has a blockNum field and a field for each module. Each module type has a field for each of
+// its state object types which returns a list of objects. Object fields accept a where argument for filtering on
+// key fields and limit and after arguments for keyset pagination. maxLimit is both the default and the maximum limit.
+// Addresses are encoded using addressCodec.
+func NewSchema(
+	moduleSchemas map[string]schema.ModuleSchema,
+	appData view.AppData,
+	addressCodec addressutil.AddressCodec,
+	maxLimit int,
+) (graphql.Schema, error) {
+	if appData == nil {
+		return graphql.Schema{}, errors.New("app data view is required")
+	}
+	if addressCodec == nil {
+		return graphql.Schema{}, errors.New("address codec is required")
+	}
+	if maxLimit <= 0 {
+		return graphql.Schema{}, fmt.Errorf("invalid max limit %d", maxLimit)
+	}
+
+	b := &schemaBuilder{
+		appData:      appData,
+		addressCodec: addressCodec,
+		maxLimit:     maxLimit,
+	}
+
+	queryFields := graphql.Fields{
+		"blockNum": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The last block number persisted by the indexer.",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				blockNum, err := b.appData.BlockNum()
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprintf("%d", blockNum), nil
+			},
+		},
+	}
+
+	moduleNames := make([]string, 0, len(moduleSchemas))
+	for moduleName := range moduleSchemas {
+		moduleNames = append(moduleNames, moduleName)
+	}
+	sort.Strings(moduleNames)
+
+	for _, moduleName := range moduleNames {
+		if moduleName == "blockNum" {
+			return graphql.Schema{}, fmt.Errorf("module name %q clashes with a root query field", moduleName)
+		}
+
+		moduleField, err := b.moduleField(moduleName, moduleSchemas[moduleName])
+		if err != nil {
+			return graphql.Schema{}, fmt.Errorf("failed to generate GraphQL schema for module %q: %w", moduleName, err)
+		}
+		if moduleField != nil {
+			queryFields[moduleName] = moduleField
+		}
+	}
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Query",
+			Fields: queryFields,
+		}),
+	})
+}
+
+// moduleField generates the root query field for a module. It returns nil if the module has no state object types.
+func (b *schemaBuilder) moduleField(moduleName string, moduleSchema schema.ModuleSchema) (*graphql.Field, error) {
+	if !schema.ValidateName(moduleName) {
+		return nil, fmt.Errorf("invalid module name %q", moduleName)
+	}
+
+	enums := map[string]*graphql.Enum{}
+	moduleSchema.EnumTypes(func(enumType schema.EnumType) bool {
+		enums[enumType.Name] = enumGraphQLType(moduleName, enumType)
+		return true
+	})
+
+	var err error
+	fields := graphql.Fields{}
+	moduleSchema.StateObjectTypes(func(objectType schema.StateObjectType) bool {
+		var field *graphql.Field
+		field, err = b.objectField(moduleName, objectType, enums)
+		if err != nil {
+			err = fmt.Errorf("object type %q: %w", objectType.Name, err)
+			return false
+		}
+		fields[objectType.Name] = field
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return &graphql.Field{
+		Type: graphql.NewObject(graphql.ObjectConfig{
+			Name:   moduleName,
+			Fields: fields,
+		}),
+		Description: fmt.Sprintf("The state of the %s module.", moduleName),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			appState := b.appData.AppState()
+			if appState == nil {
+				return nil, nil
+			}
+
+			modState, err := appState.GetModule(moduleName)
+			if err != nil || modState == nil {
+				return nil, err
+			}
+			return modState, nil
+		},
+	}, nil
+}
+
+// objectField generates the module field which lists the objects of a state object type.
+func (b *schemaBuilder) objectField(moduleName string, objectType schema.StateObjectType, enums map[string]*graphql.Enum) (*graphql.Field, error) {
+	typeName := fmt.Sprintf("%s_%s", moduleName, objectType.Name)
+
+	fields := graphql.Fields{}
+	whereFields := graphql.InputObjectConfigFieldMap{}
+	keyFields := graphql.InputObjectConfigFieldMap{}
+	for _, field := range objectType.KeyFields {
+		typ, err := fieldGraphQLType(field, enums)
+		if err != nil {
+			return nil, err
+		}
+
+		fields[field.Name] = &graphql.Field{Type: graphql.NewNonNull(typ)}
+		whereFields[field.Name] = &graphql.InputObjectFieldConfig{Type: typ}
+		keyFields[field.Name] = &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(typ)}
+	}
+
+	for _, field := range objectType.ValueFields {
+		typ, err := fieldGraphQLType(field, enums)
+		if err != nil {
+			return nil, err
+		}
+
+		if !field.Nullable {
+			typ = graphql.NewNonNull(typ)
+		}
+		fields[field.Name] = &graphql.Field{Type: typ}
+	}
+
+	args := graphql.FieldConfigArgument{
+		limitArg: &graphql.ArgumentConfig{
+			Type:        graphql.Int,
+			Description: fmt.Sprintf("The maximum number of objects to return. It defaults to and can't exceed %d.", b.maxLimit),
+		},
+	}
+	if len(whereFields) > 0 {
+		args[whereArg] = &graphql.ArgumentConfig{
+			Type: graphql.NewInputObject(graphql.InputObjectConfig{
+				Name:   typeName + "_where",
+				Fields: whereFields,
+			}),
+			Description: "Filters objects by key fields. All provided key fields must match.",
+		}
+		args[afterArg] = &graphql.ArgumentConfig{
+			Type: graphql.NewInputObject(graphql.InputObjectConfig{
+				Name:   typeName + "_key",
+				Fields: keyFields,
+			}),
+			Description: "Returns the objects after the object with this key in key order. Pass the key of the last object of the previous page to get the next page.",
+		}
+	}
+
+	res := &objectResolver{
+		objectType:   objectType,
+		addressCodec: b.addressCodec,
+		maxLimit:     b.maxLimit,
+	}
+
+	return &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.NewObject(graphql.ObjectConfig{
+			Name:   typeName,
+			Fields: fields,
+		})))),
+		Args:    args,
+		Resolve: res.resolve,
+	}, nil
+}
+
+// enumGraphQLType generates the GraphQL enum type for an enum type scoped to its module.
+func enumGraphQLType(moduleName string, enumType schema.EnumType) *graphql.Enum {
+	values := graphql.EnumValueConfigMap{}
+	for _, value := range enumType.Values {
+		values[value.Name] = &graphql.EnumValueConfig{Value: value.Name}
+	}
+
+	return graphql.NewEnum(graphql.EnumConfig{
+		Name:   fmt.Sprintf("%s_%s", moduleName, enumType.Name),
+		Values: values,
+	})
+}
+
+// fieldGraphQLType returns the GraphQL type of a field. GraphQL integers are limited to 32-bit signed values,
+// so wider integer kinds are represented as strings along with other kinds which don't have a GraphQL equivalent.
+func fieldGraphQLType(field schema.Field, enums map[string]*graphql.Enum) (graphql.Output, error) {
+	switch field.Kind {
+	case schema.Int8Kind, schema.Int16Kind, schema.Int32Kind, schema.Uint8Kind, schema.Uint16Kind:
+		return graphql.Int, nil
+	case schema.Float32Kind, schema.Float64Kind:
+		return graphql.Float, nil
+	case schema.BoolKind:
+		return graphql.Boolean, nil
+	case schema.StringKind, schema.BytesKind, schema.AddressKind, schema.Uint32Kind, schema.Int64Kind,
+		schema.Uint64Kind, schema.IntegerKind, schema.DecimalKind, schema.TimeKind, schema.DurationKind,
+		schema.JSONKind:
+		return graphql.String, nil
+	case schema.EnumKind:
+		enum, ok := enums[field.ReferencedType]
+		if !ok {
+			return nil, fmt.Errorf("enum type %q of field %q not found", field.ReferencedType, field.Name)
+		}
+		return enum, nil
+	default:
+		return nil, fmt.Errorf("unsupported kind %s for field %q", field.Kind, field.Name)
+	}
+}
@@ -0,0 +1,283 @@
+package graphql
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/view"
+)
+
+var testModuleSchema = schema.MustCompileModuleSchema(
+	schema.EnumType{
+		Name:   "status",
+		Values: []schema.EnumValueDefinition{{Name: "active", Value: 0}, {Name: "inactive", Value: 1}},
+	},
+	schema.StateObjectType{
+		Name: "balances",
+		KeyFields: []schema.Field{
+			{Name: "address", Kind: schema.AddressKind},
+			{Name: "denom", Kind: schema.StringKind},
+		},
+		ValueFields: []schema.Field{
+			{Name: "amount", Kind: schema.IntegerKind},
+		},
+	},
+	schema.StateObjectType{
+		Name: "accounts",
+		KeyFields: []schema.Field{
+			{Name: "id", Kind: schema.Uint64Kind},
+		},
+		ValueFields: []schema.Field{
+			{Name: "status", Kind: schema.EnumKind, ReferencedType: "status"},
+			{Name: "sequence", Kind: schema.Int32Kind},
+			{Name: "memo", Kind: schema.StringKind, Nullable: true},
+		},
+	},
+)
+
+func TestSchema(t *testing.T) {
+	appData := &testAppData{
+		blockNum: 7,
+		modules: map[string]*testModuleState{
+			"bank": {
+				name:   "bank",
+				schema: testModuleSchema,
+				objects: map[string][]schema.StateObjectUpdate{
+					"balances": {
+						{Key: []interface{}{[]byte{0x01}, "atom"}, Value: "10"},
+						{Key: []interface{}{[]byte{0x01}, "stake"}, Value: "20"},
+						{Key: []interface{}{[]byte{0x02}, "atom"}, Value: "30"},
+						{Key: []interface{}{[]byte{0x03}, "atom"}, Delete: true},
+					},
+					"accounts": {
+						{Key: uint64(1), Value: []interface{}{"active", int32(3), nil}},
+						{Key: uint64(2), Value: schema.MapValueUpdates{"status": "inactive", "sequence": int32(0), "memo": "hi"}},
+					},
+				},
+			},
+		},
+	}
+
+	gqlSchema, err := NewSchema(map[string]schema.ModuleSchema{"bank": testModuleSchema}, appData, hexAddressCodec{}, 2)
+	require.NoError(t, err)
+
+	query := func(q string) string {
+		t.Helper()
+		res := graphql.Do(graphql.Params{Schema: gqlSchema, RequestString: q})
+		bz, err := json.Marshal(res)
+		require.NoError(t, err)
+		return string(bz)
+	}
+
+	require.JSONEq(t, `{"data":{"blockNum":"7"}}`, query(`{ blockNum }`))
+
+	// full key lookup
+	require.JSONEq(t,
+		`{"data":{"bank":{"balances":[{"address":"01","denom":"stake","amount":"20"}]}}}`,
+		query(`{ bank { balances(where: {address: "01", denom: "stake"}) { address denom amount } } }`),
+	)
+
+	// deleted objects are not returned
+	require.JSONEq(t,
+		`{"data":{"bank":{"balances":[]}}}`,
+		query(`{ bank { balances(where: {address: "03", denom: "atom"}) { amount } } }`),
+	)
+
+	// partial key filter
+	require.JSONEq(t,
+		`{"data":{"bank":{"balances":[{"address":"01","amount":"10"},{"address":"02","amount":"30"}]}}}`,
+		query(`{ bank { balances(where: {denom: "atom"}) { address amount } } }`),
+	)
+
+	// keyset pagination
+	require.JSONEq(t,
+		`{"data":{"bank":{"balances":[{"address":"01","denom":"stake"},{"address":"02","denom":"atom"}]}}}`,
+		query(`{ bank { balances(after: {address: "01", denom: "atom"}) { address denom } } }`),
+	)
+	require.JSONEq(t,
+		`{"data":{"bank":{"balances":[{"address":"02","amount":"30"}]}}}`,
+		query(`{ bank { balances(where: {denom: "atom"}, after: {address: "01", denom: "atom"}) { address amount } } }`),
+	)
+	require.JSONEq(t,
+		`{"data":{"bank":{"balances":[]}}}`,
+		query(`{ bank { balances(after: {address: "02", denom: "atom"}) { address } } }`),
+	)
+	require.Contains(t, query(`{ bank { balances(after: {address: "01"}) { address } } }`), "denom")
+	require.JSONEq(t,
+		`{"data":{"bank":{"balances":[{"amount":"10"}]}}}`,
+		query(`{ bank { balances(limit: 1) { amount } } }`),
+	)
+	require.Contains(t, query(`{ bank { balances(limit: 3) { amount } } }`), "limit must be between 0 and 2")
+
+	// enums, multiple value fields and value updates
+	require.JSONEq(t,
+		`{"data":{"bank":{"accounts":[{"id":"1","status":"active","sequence":3,"memo":null},{"id":"2","status":"inactive","sequence":0,"memo":"hi"}]}}}`,
+		query(`{ bank { accounts { id status sequence memo } } }`),
+	)
+	require.JSONEq(t,
+		`{"data":{"bank":{"accounts":[{"id":"2"}]}}}`,
+		query(`{ bank { accounts(where: {id: "2"}) { id } } }`),
+	)
+}
+
+func TestSchemaNoAppState(t *testing.T) {
+	gqlSchema, err := NewSchema(map[string]schema.ModuleSchema{"bank": testModuleSchema}, &testAppData{}, hexAddressCodec{}, 10)
+	require.NoError(t, err)
+
+	res := graphql.Do(graphql.Params{Schema: gqlSchema, RequestString: `{ bank { accounts { id } } }`})
+	require.Empty(t, res.Errors)
+	require.Equal(t, map[string]interface{}{"bank": nil}, res.Data)
+}
+
+type hexAddressCodec struct{}
+
+func (hexAddressCodec) StringToBytes(text string) ([]byte, error) {
+	return hex.DecodeString(text)
+}
+
+func (hexAddressCodec) BytesToString(bz []byte) (string, error) {
+	return hex.EncodeToString(bz), nil
+}
+
+type testAppData struct {
+	blockNum uint64
+	modules  map[string]*testModuleState
+}
+
+func (a *testAppData) BlockNum() (uint64, error) {
+	return a.blockNum, nil
+}
+
+func (a *testAppData) AppState() view.AppState {
+	if a.modules == nil {
+		return nil
+	}
+	return a
+}
+
+func (a *testAppData) GetModule(moduleName string) (view.ModuleState, error) {
+	mod, ok := a.modules[moduleName]
+	if !ok {
+		return nil, nil
+	}
+	return mod, nil
+}
+
+func (a *testAppData) Modules(f func(modState view.ModuleState, err error) bool) {
+	for _, mod := range a.modules {
+		if !f(mod, nil) {
+			return
+		}
+	}
+}
+
+func (a *testAppData) NumModules() (int, error) {
+	return len(a.modules), nil
+}
+
+type testModuleState struct {
+	name    string
+	schema  schema.ModuleSchema
+	objects map[string][]schema.StateObjectUpdate
+}
+
+func (m *testModuleState) ModuleName() string {
+	return m.name
+}
+
+func (m *testModuleState) ModuleSchema() schema.ModuleSchema {
+	return m.schema
+}
+
+func (m *testModuleState) GetObjectCollection(objectType string) (view.ObjectCollection, error) {
+	typ, ok := m.schema.LookupStateObjectType(objectType)
+	if !ok {
+		return nil, nil
+	}
+	return &testObjectCollection{typ: typ, updates: m.objects[objectType]}, nil
+}
+
+func (m *testModuleState) ObjectCollections(f func(value view.ObjectCollection, err error) bool) {
+	m.schema.StateObjectTypes(func(typ schema.StateObjectType) bool {
+		return f(&testObjectCollection{typ: typ, updates: m.objects[typ.Name]}, nil)
+	})
+}
+
+func (m *testModuleState) NumObjectCollections() (int, error) {
+	return len(m.objects), nil
+}
+
+type testObjectCollection struct {
+	typ     schema.StateObjectType
+	updates []schema.StateObjectUpdate
+}
+
+func (c *testObjectCollection) ObjectType() schema.StateObjectType {
+	return c.typ
+}
+
+func (c *testObjectCollection) GetObject(key interface{}) (schema.StateObjectUpdate, bool, error) {
+	keyStr, err := json.Marshal(key)
+	if err != nil {
+		return schema.StateObjectUpdate{}, false, err
+	}
+
+	for _, update := range c.updates {
+		updateKeyStr, err := json.Marshal(update.Key)
+		if err != nil {
+			return schema.StateObjectUpdate{}, false, err
+		}
+		if string(updateKeyStr) == string(keyStr) {
+			return update, true, nil
+		}
+	}
+	return schema.StateObjectUpdate{}, false, nil
+}
+
+func (c *testObjectCollection) AllState(f func(schema.StateObjectUpdate, error) bool) {
+	for _, update := range c.updates {
+		if !f(update, nil) {
+			return
+		}
+	}
+}
+
+// StateAfter iterates over the updates after the one with the provided key. It relies on the updates
+// of the test collections being in key order.
+func (c *testObjectCollection) StateAfter(key interface{}, f func(schema.StateObjectUpdate, error) bool) {
+	start := 0
+	if key != nil {
+		keyStr, err := json.Marshal(key)
+		if err != nil {
+			f(schema.StateObjectUpdate{}, err)
+			return
+		}
+
+		for i, update := range c.updates {
+			updateKeyStr, err := json.Marshal(update.Key)
+			if err != nil {
+				f(schema.StateObjectUpdate{}, err)
+				return
+			}
+			if string(updateKeyStr) == string(keyStr) {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	for _, update := range c.updates[start:] {
+		if !f(update, nil) {
+			return
+		}
+	}
+}
+
+func (c *testObjectCollection) Len() (int, error) {
+	return len(c.updates), nil
+}
@@ -0,0 +1,172 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"cosmossdk.io/core/server"
+	"cosmossdk.io/core/transaction"
+	"cosmossdk.io/log"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/addressutil"
+	"cosmossdk.io/schema/decoding"
+	"cosmossdk.io/schema/indexer"
+	"cosmossdk.io/schema/view"
+	serverv2 "cosmossdk.io/server/v2"
+)
+
+var (
+	_ serverv2.ServerComponent[transaction.Tx] = (*Server[transaction.Tx])(nil)
+	_ serverv2.HasConfig                       = (*Server[transaction.Tx])(nil)
+)
+
+const ServerName = "graphql"
+
+// Server serves a GraphQL API over the module state stored by an indexer target.
+// The GraphQL schema is generated from the module schemas of the app.
+type Server[T transaction.Tx] struct {
+	logger     log.Logger
+	config     *Config
+	cfgOptions []CfgOption
+
+	httpServer *http.Server
+}
+
+// New creates a new GraphQL server. The module schemas are retrieved from the decoder resolver and queries are
+// resolved against the view of the configured indexer target in indexerInfos.
+func New[T transaction.Tx](
+	logger log.Logger,
+	resolver decoding.DecoderResolver,
+	addressCodec addressutil.AddressCodec,
+	indexerInfos map[string]indexer.IndexerInfo,
+	cfg server.ConfigMap,
+	cfgOptions ...CfgOption,
+) (*Server[T], error) {
+	srv := &Server[T]{
+		logger:     logger.With(log.ModuleKey, ServerName),
+		cfgOptions: cfgOptions,
+	}
+
+	serverCfg := srv.Config().(*Config)
+	if len(cfg) > 0 {
+		if err := serverv2.UnmarshalSubConfig(cfg, srv.Name(), &serverCfg); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+		}
+	}
+	srv.config = serverCfg
+
+	if !srv.config.Enable {
+		return srv, nil
+	}
+
+	appData, err := selectView(indexerInfos, srv.config.IndexerTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	moduleSchemas := map[string]schema.ModuleSchema{}
+	err = resolver.AllDecoders(func(moduleName string, cdc schema.ModuleCodec) error {
+		moduleSchemas[moduleName] = cdc.Schema
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get module schemas: %w", err)
+	}
+
+	gqlSchema, err := NewSchema(moduleSchemas, appData, addressCodec, srv.config.MaxLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate GraphQL schema: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/graphql", NewHandler(gqlSchema))
+	srv.httpServer = &http.Server{
+		Addr:    srv.config.Address,
+		Handler: mux,
+	}
+	return srv, nil
+}
+
+// NewWithConfigOptions creates a new GraphQL server with the provided config options.
+// It is *not* a fully functional server (since it has been created without dependencies)
+// The returned server should only be used to get and set configuration.
+func NewWithConfigOptions[T transaction.Tx](opts ...CfgOption) *Server[T] {
+	return &Server[T]{
+		cfgOptions: opts,
+	}
+}
+
+// selectView returns the view of the indexer target with the given name. If no name is provided,
+// the view of the only indexer target which provides one is returned.
+func selectView(indexerInfos map[string]indexer.IndexerInfo, target string) (view.AppData, error) {
+	if target != "" {
+		info, ok := indexerInfos[target]
+		if !ok {
+			return nil, fmt.Errorf("indexer target %q not found", target)
+		}
+		if info.View == nil {
+			return nil, fmt.Errorf("indexer target %q doesn't provide a view", target)
+		}
+		return info.View, nil
+	}
+
+	var appData view.AppData
+	for _, info := range indexerInfos {
+		if info.View == nil {
+			continue
+		}
+		if appData != nil {
+			return nil, errors.New("multiple indexer targets provide a view, indexer-target must be set")
+		}
+		appData = info.View
+	}
+
+	if appData == nil {
+		return nil, errors.New("no indexer target provides a view")
+	}
+	return appData, nil
+}
+
+func (s *Server[T]) Name() string {
+	return ServerName
+}
+
+func (s *Server[T]) Start(ctx context.Context) error {
+	if !s.config.Enable {
+		s.logger.Info(fmt.Sprintf("%s server is disabled via config", s.Name()))
+		return nil
+	}
+
+	s.logger.Info("starting GraphQL server", "address", s.config.Address)
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.logger.Error("failed to start GraphQL server", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server[T]) Stop(ctx context.Context) error {
+	if !s.config.Enable {
+		return nil
+	}
+
+	s.logger.Info("stopping GraphQL server")
+	return s.httpServer.Shutdown(ctx)
+}
+
+func (s *Server[T]) Config() any {
+	if s.config == nil || s.config.Address == "" {
+		cfg := DefaultConfig()
+
+		for _, opt := range s.cfgOptions {
+			opt(cfg)
+		}
+
+		return cfg
+	}
+
+	return s.config
+}
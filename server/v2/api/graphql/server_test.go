@@ -0,0 +1,73 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/core/transaction"
+	"cosmossdk.io/schema/indexer"
+)
+
+func TestServerConfig(t *testing.T) {
+	testCases := []struct {
+		name           string
+		setupFunc      func() *Config
+		expectedConfig *Config
+	}{
+		{
+			name: "Default configuration, no custom configuration",
+			setupFunc: func() *Config {
+				s := &Server[transaction.Tx]{}
+				return s.Config().(*Config)
+			},
+			expectedConfig: DefaultConfig(),
+		},
+		{
+			name: "Custom configuration",
+			setupFunc: func() *Config {
+				s := NewWithConfigOptions[transaction.Tx](Enable(), func(config *Config) {
+					config.IndexerTarget = "postgres"
+				})
+				return s.Config().(*Config)
+			},
+			expectedConfig: &Config{
+				Enable:        true,
+				Address:       "localhost:8081",
+				IndexerTarget: "postgres",
+				MaxLimit:      100,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := tc.setupFunc()
+			require.Equal(t, tc.expectedConfig, config)
+		})
+	}
+}
+
+func TestSelectView(t *testing.T) {
+	appData := &testAppData{}
+
+	_, err := selectView(nil, "")
+	require.ErrorContains(t, err, "no indexer target provides a view")
+
+	res, err := selectView(map[string]indexer.IndexerInfo{"a": {}, "b": {View: appData}}, "")
+	require.NoError(t, err)
+	require.Equal(t, appData, res)
+
+	_, err = selectView(map[string]indexer.IndexerInfo{"a": {View: appData}, "b": {View: appData}}, "")
+	require.ErrorContains(t, err, "indexer-target must be set")
+
+	res, err = selectView(map[string]indexer.IndexerInfo{"a": {View: appData}, "b": {View: appData}}, "b")
+	require.NoError(t, err)
+	require.Equal(t, appData, res)
+
+	_, err = selectView(map[string]indexer.IndexerInfo{"a": {}}, "a")
+	require.ErrorContains(t, err, "doesn't provide a view")
+
+	_, err = selectView(map[string]indexer.IndexerInfo{"a": {}}, "c")
+	require.ErrorContains(t, err, "not found")
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"sync/atomic"
 
 	abci "github.com/cometbft/cometbft/abci/types"
@@ -53,6 +54,7 @@ type consensus[T transaction.Tx] struct {
 	snapshotManager  *snapshots.Manager
 	streamingManager streaming.Manager
 	mempool          mempool.Mempool[T]
+	mempoolJournal   *mempool.Journal
 	appCodecs        AppCodecs[T]
 
 	cfg               Config
@@ -71,6 +73,12 @@ type consensus[T transaction.Tx] struct {
 	extendVote             handlers.ExtendVoteHandler
 	checkTxHandler         handlers.CheckTxHandler[T]
 
+	// recheckMtx guards rechecking, set while the mempool is re-checked after a
+	// commit, and recheckPending, set when a commit happened meanwhile.
+	recheckMtx     sync.Mutex
+	rechecking     bool
+	recheckPending bool
+
 	// optimisticExec contains the context required for Optimistic Execution,
 	// including the goroutine handling.This is experimental and must be enabled
 	// by developers.
@@ -92,8 +100,19 @@ func (c *consensus[T]) CheckTx(ctx context.Context, req *abciproto.CheckTxReques
 		return nil, err
 	}
 
+	// the txs passing the check are inserted in the mempool, with the default
+	// handler as with a custom one
+	checkTx := func(ctx context.Context, tx T) (server.TxResult, error) {
+		resp, err := c.app.ValidateTx(ctx, tx)
+		if err != nil || resp.Error != nil {
+			return resp, err
+		}
+		resp.Error = c.insertMempoolTx(ctx, tx)
+		return resp, nil
+	}
+
 	if c.checkTxHandler == nil {
-		resp, err := c.app.ValidateTx(ctx, decodedTx)
+		resp, err := checkTx(ctx, decodedTx)
 		// we do not want to return a cometbft error, but a check tx response with the error
 		if err != nil && !errors.Is(err, resp.Error) {
 			return nil, err
@@ -128,7 +147,34 @@ func (c *consensus[T]) CheckTx(ctx context.Context, req *abciproto.CheckTxReques
 		return cometResp, nil
 	}
 
-	return c.checkTxHandler(c.app.ValidateTx)
+	return c.checkTxHandler(checkTx)
+}
+
+// setMempoolJournal sets the journal of the mempool txs. The txs removed by the
+// mempool, when it notifies them, are removed from the journal.
+func (c *consensus[T]) setMempoolJournal(journal *mempool.Journal) {
+	c.mempoolJournal = journal
+	if notifier, ok := c.mempool.(mempool.RemoveNotifier[T]); ok {
+		notifier.SetOnRemove(func(tx T) {
+			if err := journal.Remove(tx.Hash()); err != nil {
+				c.logger.Error("failed to remove tx from mempool journal", "err", err)
+			}
+		})
+	}
+}
+
+// insertMempoolTx inserts a checked tx in the mempool and journals it.
+func (c *consensus[T]) insertMempoolTx(ctx context.Context, tx T) error {
+	if err := c.mempool.Insert(ctx, tx); err != nil {
+		return err
+	}
+	if c.mempoolJournal != nil {
+		// a journal failure only loses the tx on restart
+		if err := c.mempoolJournal.Insert(tx.Hash(), tx.Bytes()); err != nil {
+			c.logger.Error("failed to journal mempool tx", "err", err)
+		}
+	}
+	return nil
 }
 
 // Info implements types.Application.
@@ -517,9 +563,14 @@ func (c *consensus[T]) FinalizeBlock(
 
 	// remove txs from the mempool
 	for _, tx := range decodedTxs {
-		if err = c.mempool.Remove(tx); err != nil {
+		if err = c.mempool.Remove(tx); err != nil && !errors.Is(err, mempool.ErrTxNotFound) {
 			return nil, fmt.Errorf("unable to remove tx: %w", err)
 		}
+		if c.mempoolJournal != nil {
+			if err := c.mempoolJournal.Remove(tx.Hash()); err != nil {
+				c.logger.Error("failed to remove tx from mempool journal", "err", err)
+			}
+		}
 	}
 
 	c.lastCommittedHeight.Store(req.Height)
@@ -591,6 +642,14 @@ func (c *consensus[T]) Commit(ctx context.Context, _ *abciproto.CommitRequest) (
 
 	c.snapshotManager.SnapshotIfApplicable(lastCommittedHeight)
 
+	c.recheckMempool(ctx)
+
+	if c.mempoolJournal != nil {
+		if _, err := c.mempoolJournal.Expire(); err != nil {
+			c.logger.Error("failed to expire mempool journal", "err", err)
+		}
+	}
+
 	cp, err := GetConsensusParams(ctx, c.app)
 	if err != nil {
 		return nil, err
@@ -601,6 +660,48 @@ func (c *consensus[T]) Commit(ctx context.Context, _ *abciproto.CommitRequest) (
 	}, nil
 }
 
+// recheckMempool re-checks the mempool txs against the committed state in the
+// background, so that Commit doesn't wait for the recheck. A recheck requested
+// while one is running runs once it is done, and only once however many
+// commits requested it.
+func (c *consensus[T]) recheckMempool(ctx context.Context) {
+	rechecker, ok := c.mempool.(mempool.Rechecker[T])
+	if !ok {
+		return
+	}
+
+	c.recheckMtx.Lock()
+	defer c.recheckMtx.Unlock()
+	if c.rechecking {
+		c.recheckPending = true
+		return
+	}
+	c.rechecking = true
+
+	ctx = context.WithoutCancel(ctx)
+	validate := func(ctx context.Context, tx T) error {
+		resp, err := c.app.ValidateTx(ctx, tx)
+		if err != nil {
+			return err
+		}
+		return resp.Error
+	}
+	go func() {
+		for {
+			rechecker.Recheck(ctx, validate)
+
+			c.recheckMtx.Lock()
+			if !c.recheckPending {
+				c.rechecking = false
+				c.recheckMtx.Unlock()
+				return
+			}
+			c.recheckPending = false
+			c.recheckMtx.Unlock()
+		}
+	}()
+}
+
 // Vote extensions
 
 // VerifyVoteExtension implements types.Application.
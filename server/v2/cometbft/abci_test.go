@@ -33,6 +33,7 @@ import (
 	"cosmossdk.io/server/v2/stf"
 	"cosmossdk.io/server/v2/stf/branch"
 	"cosmossdk.io/server/v2/stf/mock"
+	storedb "cosmossdk.io/store/v2/db"
 	consensustypes "cosmossdk.io/x/consensus/types"
 
 	"github.com/cosmos/cosmos-sdk/testutil/testdata"
@@ -343,6 +344,203 @@ func TestConsensus_CheckTx(t *testing.T) {
 	require.NotEqual(t, res.GasUsed, 0)
 }
 
+func TestConsensus_CheckTx_PriorityNonceMempool(t *testing.T) {
+	mp := mempool.NewPriorityNonceMempool(mempool.DefaultConfig(), func(_ context.Context, tx mock.Tx) (mempool.TxInfo, error) {
+		return mempool.TxInfo{Sender: string(tx.Sender), Fee: tx.GasLimit, GasLimit: 1}, nil
+	})
+	// the default config disables the mempool
+	require.NoError(t, mp.Insert(context.Background(), mockTx))
+	require.Zero(t, mp.CountTx())
+
+	cfg := mempool.DefaultConfig()
+	cfg.MaxTxs = 0
+	mp = mempool.NewPriorityNonceMempool(cfg, func(_ context.Context, tx mock.Tx) (mempool.TxInfo, error) {
+		return mempool.TxInfo{Sender: string(tx.Sender), Fee: tx.GasLimit, GasLimit: 1}, nil
+	})
+	c := setUpConsensus(t, 100_000, mp)
+
+	_, err := c.InitChain(context.Background(), &abciproto.InitChainRequest{
+		Time:          time.Now(),
+		ChainId:       "test",
+		InitialHeight: 1,
+	})
+	require.NoError(t, err)
+
+	_, err = c.FinalizeBlock(context.Background(), &abciproto.FinalizeBlockRequest{
+		Time:   time.Now(),
+		Height: 1,
+		Hash:   emptyHash[:],
+	})
+	require.NoError(t, err)
+
+	// the valid txs are inserted in the mempool
+	otherTx := mock.Tx{
+		Sender:   []byte("other"),
+		Msg:      &gogotypes.BoolValue{Value: true},
+		GasLimit: 100_000,
+	}
+	for _, tx := range []mock.Tx{mockTx, otherTx} {
+		res, err := c.CheckTx(context.Background(), &abciproto.CheckTxRequest{Tx: tx.Bytes()})
+		require.NoError(t, err)
+		require.Equal(t, uint32(0), res.Code)
+	}
+	require.Equal(t, 2, mp.CountTx())
+
+	// a tx failing to replace a mempool tx is rejected
+	res, err := c.CheckTx(context.Background(), &abciproto.CheckTxRequest{
+		Tx: mock.Tx{
+			Sender:   []byte("sender"),
+			Msg:      &gogotypes.BoolValue{Value: false},
+			GasLimit: 100_000,
+		}.Bytes(),
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, uint32(0), res.Code)
+	require.Equal(t, 2, mp.CountTx())
+
+	// the block txs are removed from the mempool, the txs of other nodes are ignored
+	_, err = c.FinalizeBlock(context.Background(), &abciproto.FinalizeBlockRequest{
+		Time:   time.Now(),
+		Height: 2,
+		Hash:   sum[:],
+		Txs:    [][]byte{mockTx.Bytes(), invalidMockTx.Bytes()},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, mp.CountTx())
+
+	// the remaining txs are still valid after the commit
+	_, err = c.Commit(context.Background(), &abciproto.CommitRequest{})
+	require.NoError(t, err)
+	waitRecheck(t, c)
+	require.Equal(t, 1, mp.CountTx())
+}
+
+func TestConsensus_CheckTx_Handler(t *testing.T) {
+	cfg := mempool.DefaultConfig()
+	cfg.MaxTxs = 0
+	mp := mempool.NewPriorityNonceMempool(cfg, func(_ context.Context, tx mock.Tx) (mempool.TxInfo, error) {
+		return mempool.TxInfo{Sender: string(tx.Sender), Fee: tx.GasLimit, GasLimit: 1}, nil
+	})
+	c := setUpConsensus(t, 100_000, mp)
+
+	_, err := c.InitChain(context.Background(), &abciproto.InitChainRequest{
+		Time:          time.Now(),
+		ChainId:       "test",
+		InitialHeight: 1,
+	})
+	require.NoError(t, err)
+
+	_, err = c.FinalizeBlock(context.Background(), &abciproto.FinalizeBlockRequest{
+		Time:   time.Now(),
+		Height: 1,
+		Hash:   emptyHash[:],
+	})
+	require.NoError(t, err)
+
+	// the txs passing the check of a custom handler are inserted in the mempool
+	c.checkTxHandler = func(validate func(context.Context, mock.Tx) (server.TxResult, error)) (*abciproto.CheckTxResponse, error) {
+		res, err := validate(context.Background(), mockTx)
+		if err != nil {
+			return nil, err
+		}
+		if res.Error != nil {
+			return &abciproto.CheckTxResponse{Code: 1}, nil
+		}
+		return &abciproto.CheckTxResponse{}, nil
+	}
+	res, err := c.CheckTx(context.Background(), &abciproto.CheckTxRequest{Tx: mockTx.Bytes()})
+	require.NoError(t, err)
+	require.Equal(t, uint32(0), res.Code)
+	require.Equal(t, []mock.Tx{mockTx}, selectMockTxs(mp))
+}
+
+// waitRecheck waits for the recheck of the mempool started by a commit.
+func waitRecheck(t *testing.T, c *consensus[mock.Tx]) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		c.recheckMtx.Lock()
+		defer c.recheckMtx.Unlock()
+		return !c.rechecking
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestConsensus_MempoolJournal(t *testing.T) {
+	cfg := mempool.DefaultConfig()
+	cfg.MaxTxs = 0
+	txInfo := func(_ context.Context, tx mock.Tx) (mempool.TxInfo, error) {
+		return mempool.TxInfo{Sender: string(tx.Sender), Fee: tx.GasLimit, GasLimit: 1}, nil
+	}
+	mp := mempool.NewPriorityNonceMempool(cfg, txInfo)
+	c := setUpConsensus(t, 100_000, mp)
+	journal := mempool.NewJournal(storedb.NewMemDB(), cfg.JournalTTL)
+	c.setMempoolJournal(journal)
+
+	_, err := c.InitChain(context.Background(), &abciproto.InitChainRequest{
+		Time:          time.Now(),
+		ChainId:       "test",
+		InitialHeight: 1,
+	})
+	require.NoError(t, err)
+
+	_, err = c.FinalizeBlock(context.Background(), &abciproto.FinalizeBlockRequest{
+		Time:   time.Now(),
+		Height: 1,
+		Hash:   emptyHash[:],
+	})
+	require.NoError(t, err)
+
+	// the txs inserted in the mempool are journaled
+	otherTx := mock.Tx{
+		Sender:   []byte("other"),
+		Msg:      &gogotypes.BoolValue{Value: true},
+		GasLimit: 100_000,
+	}
+	for _, tx := range []mock.Tx{mockTx, otherTx, invalidMockTx} {
+		_, err := c.CheckTx(context.Background(), &abciproto.CheckTxRequest{Tx: tx.Bytes()})
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, mp.CountTx())
+
+	// the block txs are removed from the journal
+	_, err = c.FinalizeBlock(context.Background(), &abciproto.FinalizeBlockRequest{
+		Time:   time.Now(),
+		Height: 2,
+		Hash:   sum[:],
+		Txs:    [][]byte{mockTx.Bytes()},
+	})
+	require.NoError(t, err)
+
+	// the journaled txs are replayed through CheckTx in a new mempool
+	mp = mempool.NewPriorityNonceMempool(cfg, txInfo)
+	c.mempool = mp
+	c.setMempoolJournal(journal)
+	replay := func() int {
+		replayed, err := journal.Replay(func(txBytes []byte) error {
+			res, err := c.CheckTx(context.Background(), &abciproto.CheckTxRequest{Tx: txBytes})
+			require.NoError(t, err)
+			require.Equal(t, uint32(0), res.Code)
+			return nil
+		})
+		require.NoError(t, err)
+		return replayed
+	}
+	require.Equal(t, 1, replay())
+	require.Equal(t, []mock.Tx{otherTx}, selectMockTxs(mp))
+
+	// the txs removed by the mempool are removed from the journal
+	mp.Recheck(context.Background(), func(context.Context, mock.Tx) error { return errors.New("invalid") })
+	require.Zero(t, mp.CountTx())
+	require.Zero(t, replay())
+}
+
+func selectMockTxs(mp mempool.Mempool[mock.Tx]) []mock.Tx {
+	var txs []mock.Tx
+	for it := mp.Select(context.Background(), nil); it != nil; it = it.Next() {
+		txs = append(txs, it.Tx())
+	}
+	return txs
+}
+
 func TestConsensus_ExtendVote(t *testing.T) {
 	c := setUpConsensus(t, 100_000, mempool.NoOpMempool[mock.Tx]{})
 
@@ -561,6 +759,79 @@ func TestConsensus_ProcessProposal_With_Handler(t *testing.T) {
 	require.Equal(t, res.Status, abciproto.PROCESS_PROPOSAL_STATUS_REJECT)
 }
 
+func TestConsensus_Proposal_LanedMempool(t *testing.T) {
+	cfg := mempool.DefaultConfig()
+	cfg.MaxTxs = 0
+	txInfo := func(_ context.Context, tx mock.Tx) (mempool.TxInfo, error) {
+		return mempool.TxInfo{Sender: string(tx.Sender), Fee: tx.GasLimit, GasLimit: 1}, nil
+	}
+	isRelayerTx := func(tx mock.Tx) bool { return strings.HasPrefix(string(tx.Sender), "relayer") }
+	mp, err := mempool.NewLanedMempool(
+		mempool.Lane[mock.Tx]{Name: "default", Mempool: mempool.NewPriorityNonceMempool(cfg, txInfo)},
+		mempool.Lane[mock.Tx]{
+			Name:          "relayer",
+			Mempool:       mempool.NewPriorityNonceMempool(cfg, txInfo),
+			Match:         isRelayerTx,
+			MaxBlockSpace: 40,
+			Priority:      10,
+		},
+	)
+	require.NoError(t, err)
+	c := setUpConsensus(t, 100_000, mp)
+	c.prepareProposalHandler = handlers.NewDefaultProposalHandler(c.mempool).PrepareHandler()
+	c.processProposalHandler = handlers.NewDefaultProposalHandler(c.mempool).ProcessHandler()
+	c.optimisticExec = oe.NewOptimisticExecution[mock.Tx](log.NewNopLogger(), func(context.Context, *abci.FinalizeBlockRequest) (*server.BlockResponse, store.WriterMap, []mock.Tx, error) {
+		return nil, nil, nil, errors.New("test error")
+	})
+
+	// the relayer lane can use 40% of the 300_000 max block gas
+	var relayerTxs, spamTxs [][]byte
+	for _, sender := range []string{"relayer1", "relayer2", "relayer3"} {
+		tx := mock.Tx{Sender: []byte(sender), Msg: &gogotypes.BoolValue{Value: true}, GasLimit: 50_000}
+		require.NoError(t, mp.Insert(context.Background(), tx))
+		relayerTxs = append(relayerTxs, tx.Bytes())
+	}
+	for _, sender := range []string{"spam1", "spam2", "spam3", "spam4"} {
+		tx := mock.Tx{Sender: []byte(sender), Msg: &gogotypes.BoolValue{Value: true}, GasLimit: 60_000}
+		require.NoError(t, mp.Insert(context.Background(), tx))
+		spamTxs = append(spamTxs, tx.Bytes())
+	}
+
+	res, err := c.PrepareProposal(context.Background(), &abciproto.PrepareProposalRequest{
+		Height:     1,
+		MaxTxBytes: 10_000,
+	})
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 5)
+	require.Equal(t, relayerTxs[:2], res.Txs[:2])
+	for _, tx := range res.Txs[2:] {
+		require.Contains(t, spamTxs, tx)
+	}
+
+	processRes, err := c.ProcessProposal(context.Background(), &abciproto.ProcessProposalRequest{
+		Height: 1,
+		Txs:    res.Txs,
+	})
+	require.NoError(t, err)
+	require.Equal(t, abciproto.PROCESS_PROPOSAL_STATUS_ACCEPT, processRes.Status)
+
+	// the txs of the relayer lane must come first
+	processRes, err = c.ProcessProposal(context.Background(), &abciproto.ProcessProposalRequest{
+		Height: 1,
+		Txs:    [][]byte{spamTxs[0], relayerTxs[0]},
+	})
+	require.NoError(t, err)
+	require.Equal(t, abciproto.PROCESS_PROPOSAL_STATUS_REJECT, processRes.Status)
+
+	// the txs of the relayer lane exceed its share of the block gas
+	processRes, err = c.ProcessProposal(context.Background(), &abciproto.ProcessProposalRequest{
+		Height: 1,
+		Txs:    relayerTxs,
+	})
+	require.NoError(t, err)
+	require.Equal(t, abciproto.PROCESS_PROPOSAL_STATUS_REJECT, processRes.Status)
+}
+
 func TestConsensus_Info(t *testing.T) {
 	c := setUpConsensus(t, 100_000, cometmock.MockMempool[mock.Tx]{})
 
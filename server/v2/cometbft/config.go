@@ -5,6 +5,7 @@ import (
 
 	"cosmossdk.io/schema/indexer"
 	"cosmossdk.io/server/v2/cometbft/mempool"
+	"cosmossdk.io/server/v2/streaming"
 )
 
 // Config is the configuration for the CometBFT application
@@ -27,6 +28,7 @@ func DefaultAppTomlConfig() *AppTomlConfig {
 			Target:            make(map[string]indexer.Config),
 			ChannelBufferSize: 1024,
 		},
+		StreamingSink:          streaming.DefaultSinkConfig(),
 		IndexABCIEvents:        make([]string, 0),
 		DisableIndexABCIEvents: false,
 		DisableABCIEvents:      false,
@@ -45,6 +47,7 @@ type AppTomlConfig struct {
 	// Sub configs
 	Mempool                mempool.Config         `mapstructure:"mempool" toml:"mempool" comment:"mempool defines the configuration for the SDK built-in app-side mempool implementations."`
 	Indexer                indexer.IndexingConfig `mapstructure:"indexer" toml:"indexer" comment:"indexer defines the configuration for the SDK built-in indexer implementation."`
+	StreamingSink          streaming.SinkConfig   `mapstructure:"streaming-sink" toml:"streaming-sink" comment:"streaming-sink defines the configuration for the SDK built-in streaming sink, which writes the changesets of every block to segment files."`
 	IndexABCIEvents        []string               `mapstructure:"index-abci-events" toml:"index-abci-events" comment:"index-abci-events defines the set of events in the form {eventType}.{attributeKey}, which informs CometBFT what to index. If empty, all events will be indexed."`
 	DisableIndexABCIEvents bool                   `mapstructure:"disable-index-abci-events" toml:"disable-index-abci-events" comment:"disable-index-abci-events disables the ABCI event indexing done by CometBFT. Useful when relying on the SDK indexer for event indexing, but still want events to be included in FinalizeBlockResponse."`
 	DisableABCIEvents      bool                   `mapstructure:"disable-abci-events" toml:"disable-abci-events" comment:"disable-abci-events disables all ABCI events. Useful when relying on the SDK indexer for event indexing."`
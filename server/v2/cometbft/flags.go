@@ -51,11 +51,16 @@ func prefix(f string) string {
 
 // Server flags
 var (
-	Standalone        = prefix("standalone")
-	FlagAddress       = prefix("address")
-	FlagTransport     = prefix("transport")
-	FlagHaltHeight    = prefix("halt-height")
-	FlagHaltTime      = prefix("halt-time")
-	FlagTrace         = prefix("trace")
-	FlagMempoolMaxTxs = prefix("mempool.max-txs")
+	Standalone                = prefix("standalone")
+	FlagAddress               = prefix("address")
+	FlagTransport             = prefix("transport")
+	FlagHaltHeight            = prefix("halt-height")
+	FlagHaltTime              = prefix("halt-time")
+	FlagTrace                 = prefix("trace")
+	FlagMempoolMaxTxs         = prefix("mempool.max-txs")
+	FlagMempoolTTL            = prefix("mempool.ttl")
+	FlagMempoolPriceBump      = prefix("mempool.price-bump")
+	FlagMempoolJournalDir     = prefix("mempool.journal-dir")
+	FlagMempoolJournalBackend = prefix("mempool.journal-backend")
+	FlagMempoolJournalTTL     = prefix("mempool.journal-ttl")
 )
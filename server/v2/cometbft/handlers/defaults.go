@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	abci "github.com/cometbft/cometbft/api/cometbft/abci/v1"
+	cmttypes "github.com/cometbft/cometbft/types"
 
 	"cosmossdk.io/core/server"
 	"cosmossdk.io/core/store"
@@ -69,28 +70,63 @@ func (h *DefaultProposalHandler[T]) PrepareHandler() PrepareHandler[T] {
 			return h.txSelector.SelectedTxs(ctx), nil
 		}
 
-		iterator := h.mempool.Select(ctx, txs)
-		for iterator != nil {
-			memTx := iterator.Tx()
+		// a mempool without lanes is a single lane without limit
+		lanes := []mempool.Lane[T]{{Mempool: h.mempool}}
+		if lanedMempool, ok := h.mempool.(*mempool.LanedMempool[T]); ok {
+			lanes = lanedMempool.Lanes()
+		}
 
-			// NOTE: Since transaction verification was already executed in CheckTx,
-			// which calls mempool.Insert, in theory everything in the pool should be
-			// valid. But some mempool implementations may insert invalid txs, so we
-			// check again.
-			_, err := app.ValidateTx(ctx, memTx)
-			if err != nil {
-				err := h.mempool.Remove(memTx)
-				if err != nil && !errors.Is(err, mempool.ErrTxNotFound) {
-					return nil, err
-				}
-			} else {
-				stop := h.txSelector.SelectTxForProposal(ctx, uint64(req.MaxTxBytes), maxBlockGas, memTx)
-				if stop {
-					break
+		var (
+			selectedTxsNums int
+			selectedTxBytes uint64
+			selectedTxGas   uint64
+		)
+		for _, lane := range lanes {
+			// the txs of a lane are selected within its share of the block space,
+			// on top of the txs selected from the previous lanes
+			laneMaxTxBytes := min(uint64(req.MaxTxBytes), selectedTxBytes+lane.BlockSpaceLimit(uint64(req.MaxTxBytes)))
+			laneMaxBlockGas := maxBlockGas
+			if maxBlockGas > 0 {
+				laneMaxBlockGas = min(maxBlockGas, selectedTxGas+lane.BlockSpaceLimit(maxBlockGas))
+			}
+
+			iterator := lane.Mempool.Select(ctx, txs)
+			for iterator != nil {
+				memTx := iterator.Tx()
+
+				// NOTE: Since transaction verification was already executed in CheckTx,
+				// which calls mempool.Insert, in theory everything in the pool should be
+				// valid. But some mempool implementations may insert invalid txs, so we
+				// check again.
+				_, err := app.ValidateTx(ctx, memTx)
+				if err != nil {
+					err := h.mempool.Remove(memTx)
+					if err != nil && !errors.Is(err, mempool.ErrTxNotFound) {
+						return nil, err
+					}
+				} else {
+					stop := h.txSelector.SelectTxForProposal(ctx, laneMaxTxBytes, laneMaxBlockGas, memTx)
+
+					if txsLen := len(h.txSelector.SelectedTxs(ctx)); txsLen != selectedTxsNums {
+						selectedTxsNums = txsLen
+						selectedTxBytes += uint64(cmttypes.ComputeProtoSizeForTxs([]cmttypes.Tx{memTx.Bytes()}))
+						if gasLimit, err := memTx.GetGasLimit(); err == nil {
+							selectedTxGas += gasLimit
+						}
+					}
+
+					// the lane is full, the selection goes on with the next lane
+					if stop {
+						break
+					}
 				}
+
+				iterator = iterator.Next()
 			}
 
-			iterator = iterator.Next()
+			if selectedTxBytes >= uint64(req.MaxTxBytes) || (maxBlockGas > 0 && selectedTxGas >= maxBlockGas) {
+				break
+			}
 		}
 
 		return h.txSelector.SelectedTxs(ctx), nil
@@ -116,9 +152,10 @@ func (h *DefaultProposalHandler[T]) ProcessHandler() ProcessHandler[T] {
 			return fmt.Errorf("unexpected consensus params response type; expected: %T, got: %T", &consensustypes.QueryParamsResponse{}, res)
 		}
 
-		var maxBlockGas uint64
+		var maxBlockGas, maxBlockBytes uint64
 		if b := paramsResp.GetParams().Block; b != nil {
 			maxBlockGas = uint64(b.MaxGas)
+			maxBlockBytes = uint64(b.MaxBytes)
 		}
 
 		// Decode request txs bytes
@@ -132,29 +169,82 @@ func (h *DefaultProposalHandler[T]) ProcessHandler() ProcessHandler[T] {
 			txs = append(txs, decTx)
 		}
 
-		var totalTxGas uint64
-		for _, tx := range txs {
+		// the txs of a laned mempool must be ordered by lane, within their lane share
+		lanedMempool, isLaned := h.mempool.(*mempool.LanedMempool[T])
+		var lanes []mempool.Lane[T]
+		if isLaned {
+			lanes = lanedMempool.Lanes()
+		}
+
+		var (
+			totalTxGas uint64
+			usage      laneUsage
+		)
+		for i, tx := range txs {
 			_, err := app.ValidateTx(ctx, tx)
 			if err != nil {
 				return fmt.Errorf("failed to validate tx: %w", err)
 			}
 
-			if maxBlockGas > 0 {
-				gaslimit, err := tx.GetGasLimit()
+			var gaslimit uint64
+			if maxBlockGas > 0 || isLaned {
+				gaslimit, err = tx.GetGasLimit()
 				if err != nil {
 					return errors.New("failed to get gas limit")
 				}
+			}
+
+			if maxBlockGas > 0 {
 				totalTxGas += gaslimit
 				if totalTxGas > maxBlockGas {
 					return fmt.Errorf("total tx gas %d exceeds max block gas %d", totalTxGas, maxBlockGas)
 				}
 			}
+
+			if isLaned {
+				laneIndex, err := lanedMempool.LaneIndex(tx)
+				if err != nil {
+					return fmt.Errorf("failed to get lane of tx %d: %w", i, err)
+				}
+				txSize := uint64(cmttypes.ComputeProtoSizeForTxs([]cmttypes.Tx{req.Txs[i]}))
+				if err := usage.add(laneIndex, lanes[laneIndex].BlockSpaceLimit, txSize, gaslimit, maxBlockBytes, maxBlockGas); err != nil {
+					return fmt.Errorf("tx %d of lane %s: %w", i, lanes[laneIndex].Name, err)
+				}
+			}
 		}
 
 		return nil
 	}
 }
 
+// laneUsage tracks the block space used by the lanes of the txs of a proposal.
+type laneUsage struct {
+	lane         int
+	txBytes, gas uint64
+	started      bool
+}
+
+// add adds a tx of the given lane, and returns an error if the txs are not
+// ordered by lane or exceed the share of the block space of their lane.
+func (u *laneUsage) add(laneIndex int, blockSpaceLimit func(uint64) uint64, txBytes, txGas, maxBlockBytes, maxBlockGas uint64) error {
+	switch {
+	case !u.started || laneIndex > u.lane:
+		*u = laneUsage{lane: laneIndex, started: true}
+	case laneIndex < u.lane:
+		return errors.New("the txs of a lane with a higher priority must come first")
+	}
+
+	u.txBytes += txBytes
+	u.gas += txGas
+	if maxBlockBytes > 0 && u.txBytes > blockSpaceLimit(maxBlockBytes) {
+		return fmt.Errorf("lane txs bytes %d exceed the lane share %d", u.txBytes, blockSpaceLimit(maxBlockBytes))
+	}
+	if maxBlockGas > 0 && u.gas > blockSpaceLimit(maxBlockGas) {
+		return fmt.Errorf("lane txs gas %d exceed the lane share %d", u.gas, blockSpaceLimit(maxBlockGas))
+	}
+	return nil
+}
+
 // decodeTxs decodes the txs bytes into a decoded txs
 // If there a fail decoding tx, remove from the list
 // Used for prepare proposal
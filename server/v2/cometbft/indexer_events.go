@@ -0,0 +1,139 @@
+package cometbft
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	cosmos_proto "github.com/cosmos/cosmos-proto"
+	gogoproto "github.com/cosmos/gogoproto/proto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"cosmossdk.io/schema"
+)
+
+// typedEventTypes returns the schema of every ADR-032 typed event registered in the protobuf registry.
+// Typed events are identified by convention as top-level messages whose name starts with "Event"
+// followed by an upper case letter, excluding the messages of the consensus engine and EventAttribute
+// messages which are the building blocks of untyped events.
+func typedEventTypes() []schema.EventType {
+	var eventTypes []schema.EventType
+	gogoproto.HybridResolver.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		pkg := string(fd.Package())
+		if strings.HasPrefix(pkg, "tendermint.") || strings.HasPrefix(pkg, "cometbft.") {
+			return true
+		}
+
+		msgs := fd.Messages()
+		for i := 0; i < msgs.Len(); i++ {
+			md := msgs.Get(i)
+			if isTypedEventName(string(md.Name())) {
+				eventTypes = append(eventTypes, eventTypeForMessage(md))
+			}
+		}
+		return true
+	})
+
+	sort.Slice(eventTypes, func(i, j int) bool {
+		return eventTypes[i].Name < eventTypes[j].Name
+	})
+
+	return eventTypes
+}
+
+func isTypedEventName(name string) bool {
+	const prefix = "Event"
+	if len(name) <= len(prefix) || !strings.HasPrefix(name, prefix) || name == "EventAttribute" {
+		return false
+	}
+
+	return unicode.IsUpper(rune(name[len(prefix)]))
+}
+
+// eventTypeForMessage derives the event type from the message descriptor. Field names are the original
+// protobuf field names because that is how typed events are marshaled to JSON.
+func eventTypeForMessage(md protoreflect.MessageDescriptor) schema.EventType {
+	fields := md.Fields()
+	eventType := schema.EventType{
+		Name:   string(md.FullName()),
+		Fields: make([]schema.Field, 0, fields.Len()),
+	}
+
+	for i := 0; i < fields.Len(); i++ {
+		eventType.Fields = append(eventType.Fields, eventField(fields.Get(i)))
+	}
+
+	return eventType
+}
+
+func eventField(f protoreflect.FieldDescriptor) schema.Field {
+	field := schema.Field{Name: string(f.Name())}
+	if f.IsMap() || f.IsList() {
+		field.Kind = schema.JSONKind
+		field.Nullable = true
+		return field
+	}
+
+	switch f.Kind() {
+	case protoreflect.BoolKind:
+		field.Kind = schema.BoolKind
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		field.Kind = schema.Int32Kind
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		field.Kind = schema.Int64Kind
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		field.Kind = schema.Uint32Kind
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		field.Kind = schema.Uint64Kind
+	case protoreflect.FloatKind:
+		field.Kind = schema.Float32Kind
+	case protoreflect.DoubleKind:
+		field.Kind = schema.Float64Kind
+	case protoreflect.StringKind:
+		field.Kind = scalarKind(f)
+	case protoreflect.BytesKind:
+		field.Kind = schema.BytesKind
+		field.Nullable = true
+	case protoreflect.EnumKind:
+		// enums are marshaled to JSON as their value names
+		field.Kind = schema.StringKind
+	case protoreflect.MessageKind:
+		field.Nullable = true
+		switch f.Message().FullName() {
+		case "google.protobuf.Timestamp":
+			field.Kind = schema.TimeKind
+		case "google.protobuf.Duration":
+			field.Kind = schema.DurationKind
+		default:
+			field.Kind = schema.JSONKind
+		}
+	default:
+		field.Kind = schema.JSONKind
+	}
+
+	if f.HasPresence() {
+		field.Nullable = true
+	}
+
+	return field
+}
+
+// scalarKind returns the kind for a string field based on its cosmos_proto.scalar annotation.
+func scalarKind(f protoreflect.FieldDescriptor) schema.Kind {
+	opts := f.Options()
+	if opts == nil || !proto.HasExtension(opts, cosmos_proto.E_Scalar) {
+		return schema.StringKind
+	}
+
+	switch proto.GetExtension(opts, cosmos_proto.E_Scalar).(string) {
+	case "cosmos.AddressString", "cosmos.ValidatorAddressString", "cosmos.ConsensusAddressString":
+		return schema.AddressKind
+	case "cosmos.Int":
+		return schema.IntegerKind
+	case "cosmos.Dec":
+		return schema.DecimalKind
+	default:
+		return schema.StringKind
+	}
+}
@@ -0,0 +1,72 @@
+package cometbft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+
+	"cosmossdk.io/schema"
+)
+
+func TestTypedEventTypes(t *testing.T) {
+	for _, et := range typedEventTypes() {
+		require.NoError(t, et.Validate())
+		require.NotEqual(t, "cosmos.streaming.v1.EventAttribute", et.Name)
+	}
+}
+
+func TestIsTypedEventName(t *testing.T) {
+	require.True(t, isTypedEventName("EventSend"))
+	require.False(t, isTypedEventName("Event"))
+	require.False(t, isTypedEventName("Eventually"))
+	require.False(t, isTypedEventName("EventAttribute"))
+	require.False(t, isTypedEventName("MsgSend"))
+}
+
+func TestEventTypeForMessage(t *testing.T) {
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       ptr("test/event.proto"),
+		Package:    ptr("test.v1"),
+		Syntax:     ptr("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name:  ptr("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{{Name: ptr("STATUS_UNSPECIFIED"), Number: ptr(int32(0))}},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: ptr("EventTest"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: ptr("sender"), Number: ptr(int32(1)), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+				{Name: ptr("amount"), Number: ptr(int32(2)), Type: descriptorpb.FieldDescriptorProto_TYPE_UINT64.Enum()},
+				{Name: ptr("time"), Number: ptr(int32(3)), Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: ptr(".google.protobuf.Timestamp")},
+				{Name: ptr("tags"), Number: ptr(int32(4)), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()},
+				{Name: ptr("status"), Number: ptr(int32(5)), Type: descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum(), TypeName: ptr(".test.v1.Status")},
+				{Name: ptr("data"), Number: ptr(int32(6)), Type: descriptorpb.FieldDescriptorProto_TYPE_BYTES.Enum()},
+			},
+		}},
+	}
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+
+	eventType := eventTypeForMessage(fd.Messages().ByName("EventTest"))
+	require.NoError(t, eventType.Validate())
+	require.Equal(t, schema.EventType{
+		Name: "test.v1.EventTest",
+		Fields: []schema.Field{
+			{Name: "sender", Kind: schema.StringKind},
+			{Name: "amount", Kind: schema.Uint64Kind},
+			{Name: "time", Kind: schema.TimeKind, Nullable: true},
+			{Name: "tags", Kind: schema.JSONKind, Nullable: true},
+			{Name: "status", Kind: schema.StringKind},
+			{Name: "data", Kind: schema.BytesKind, Nullable: true},
+		},
+	}, eventType)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,212 @@
+package cometbft
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	corestore "cosmossdk.io/core/store"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/decoding"
+	"cosmossdk.io/server/v2/cometbft/types"
+	storev2 "cosmossdk.io/store/v2"
+	"cosmossdk.io/store/v2/proof"
+)
+
+var _ decoding.VersionedSyncSource = (*indexerSyncSource)(nil)
+
+// indexerSyncSource is a decoding.VersionedSyncSource backed by the root store. It reads historical state
+// through the store's state readers and uses the stored commit info to skip stores which did not change
+// between two versions, which allows indexers to catch up from any retained version.
+type indexerSyncSource struct {
+	store    types.Store
+	resolver decoding.DecoderResolver
+}
+
+func newIndexerSyncSource(store types.Store, resolver decoding.DecoderResolver) *indexerSyncSource {
+	return &indexerSyncSource{store: store, resolver: resolver}
+}
+
+// IterateAllKVPairs implements decoding.SyncSource by iterating over the latest version of state.
+func (s *indexerSyncSource) IterateAllKVPairs(moduleName string, fn func(key, value []byte) error) error {
+	version, err := s.LatestVersion()
+	if err != nil {
+		return err
+	}
+
+	return s.iterateAt(moduleName, version, fn)
+}
+
+// LatestVersion implements decoding.VersionedSyncSource.
+func (s *indexerSyncSource) LatestVersion() (uint64, error) {
+	return s.store.GetLatestVersion()
+}
+
+// SyncSourceAt implements decoding.VersionedSyncSource.
+func (s *indexerSyncSource) SyncSourceAt(version uint64) (decoding.SyncSource, error) {
+	if _, err := s.commitInfo(version); err != nil {
+		return nil, err
+	}
+
+	return versionedSyncSource{source: s, version: version}, nil
+}
+
+// IterateChanges implements decoding.VersionedSyncSource. The changes are read from the state commitment
+// when it implements store.StateDiffer, which for iavl only reads the nodes written at the version, and by
+// merging iterators over the store at version-1 and version otherwise.
+func (s *indexerSyncSource) IterateChanges(moduleName string, version uint64, fn func(update schema.KVPairUpdate) error) error {
+	if version == 0 {
+		return errors.New("version 0 has no changes")
+	}
+
+	storeKey, err := s.resolver.EncodeModuleName(moduleName)
+	if err != nil {
+		return err
+	}
+
+	cur, err := s.commitInfo(version)
+	if err != nil {
+		return err
+	}
+	curID := findStoreCommitID(cur, storeKey)
+
+	var prevID *proof.CommitID
+	if version > 1 {
+		prev, err := s.commitInfo(version - 1)
+		if err != nil {
+			return err
+		}
+		prevID = findStoreCommitID(prev, storeKey)
+	}
+
+	if curID == nil && prevID == nil {
+		// the store doesn't exist in either version
+		return nil
+	}
+	if curID != nil && prevID != nil && bytes.Equal(curID.Hash, prevID.Hash) {
+		// the store root hash didn't change so neither did any of its keys
+		return nil
+	}
+
+	onChange := func(change storev2.KVChange) error {
+		if change.NewValue == nil {
+			return fn(schema.KVPairUpdate{Key: bytes.Clone(change.Key), Remove: true})
+		}
+		return fn(schema.KVPairUpdate{Key: bytes.Clone(change.Key), Value: bytes.Clone(change.NewValue)})
+	}
+
+	if differ, ok := s.store.GetStateCommitment().(storev2.StateDiffer); ok && curID != nil && prevID != nil {
+		return differ.DiffState(storeKey, version-1, version, nil, nil, onChange)
+	}
+
+	prevIter, err := s.iterator(storeKey, version-1, prevID != nil)
+	if err != nil {
+		return err
+	}
+	defer prevIter.Close()
+
+	curIter, err := s.iterator(storeKey, version, curID != nil)
+	if err != nil {
+		return err
+	}
+	defer curIter.Close()
+
+	return storev2.DiffIterators(prevIter, curIter, onChange)
+}
+
+// iterateAt iterates over all key-value pairs for the module at the given version.
+func (s *indexerSyncSource) iterateAt(moduleName string, version uint64, fn func(key, value []byte) error) error {
+	storeKey, err := s.resolver.EncodeModuleName(moduleName)
+	if err != nil {
+		return err
+	}
+
+	ci, err := s.commitInfo(version)
+	if err != nil {
+		return err
+	}
+
+	iter, err := s.iterator(storeKey, version, findStoreCommitID(ci, storeKey) != nil)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	for ; iter.Valid(); iter.Next() {
+		if err := fn(bytes.Clone(iter.Key()), bytes.Clone(iter.Value())); err != nil {
+			return err
+		}
+	}
+
+	return iter.Error()
+}
+
+// iterator returns an iterator over the whole store at the given version using the store's state reader.
+// If the store doesn't exist at that version, an empty iterator is returned.
+func (s *indexerSyncSource) iterator(storeKey []byte, version uint64, exists bool) (corestore.Iterator, error) {
+	if !exists {
+		return emptyIterator{}, nil
+	}
+
+	state, err := s.store.StateAt(version)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := state.GetReader(storeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return reader.Iterator(nil, nil)
+}
+
+// commitInfo returns the stored commit info for the version or an error if the version is not retained.
+func (s *indexerSyncSource) commitInfo(version uint64) (*proof.CommitInfo, error) {
+	sc := s.store.GetStateCommitment()
+	exists, err := sc.VersionExists(version)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("version %d is not retained", version)
+	}
+
+	return sc.GetCommitInfo(version)
+}
+
+// findStoreCommitID returns the commit ID for the store key in the commit info or nil if the store is not present.
+func findStoreCommitID(ci *proof.CommitInfo, storeKey []byte) *proof.CommitID {
+	if ci == nil {
+		return nil
+	}
+
+	for _, si := range ci.StoreInfos {
+		if si.Name == string(storeKey) {
+			return si.CommitId
+		}
+	}
+
+	return nil
+}
+
+// versionedSyncSource is a decoding.SyncSource for a single retained version of state.
+type versionedSyncSource struct {
+	source  *indexerSyncSource
+	version uint64
+}
+
+func (v versionedSyncSource) IterateAllKVPairs(moduleName string, fn func(key, value []byte) error) error {
+	return v.source.iterateAt(moduleName, v.version, fn)
+}
+
+// emptyIterator is a corestore.Iterator over no items, used for stores which don't exist at a version.
+type emptyIterator struct{}
+
+func (emptyIterator) Domain() (start, end []byte) { return nil, nil }
+func (emptyIterator) Valid() bool                 { return false }
+func (emptyIterator) Next()                       {}
+func (emptyIterator) Key() []byte                 { return nil }
+func (emptyIterator) Value() []byte               { return nil }
+func (emptyIterator) Error() error                { return nil }
+func (emptyIterator) Close() error                { return nil }
@@ -0,0 +1,96 @@
+package cometbft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/core/store"
+	"cosmossdk.io/log"
+	"cosmossdk.io/schema"
+	"cosmossdk.io/schema/decoding"
+	cometmock "cosmossdk.io/server/v2/cometbft/internal/mock"
+	storev2 "cosmossdk.io/store/v2"
+)
+
+func TestIndexerSyncSource(t *testing.T) {
+	ms := cometmock.NewMockStore(cometmock.NewMockCommiter(log.NewNopLogger(), "bank", "staking"))
+
+	commit := func(version uint64, fn func(cs *store.Changeset)) {
+		cs := store.NewChangeset(version)
+		fn(cs)
+		_, err := ms.Commit(cs)
+		require.NoError(t, err)
+	}
+
+	commit(1, func(cs *store.Changeset) {
+		cs.Add([]byte("bank"), []byte("a"), []byte("1"), false)
+		cs.Add([]byte("bank"), []byte("b"), []byte("2"), false)
+		cs.Add([]byte("staking"), []byte("x"), []byte("1"), false)
+	})
+	commit(2, func(cs *store.Changeset) {
+		cs.Add([]byte("bank"), []byte("a"), nil, true)
+		cs.Add([]byte("bank"), []byte("b"), []byte("3"), false)
+		cs.Add([]byte("bank"), []byte("c"), []byte("4"), false)
+	})
+
+	source := newIndexerSyncSource(ms, decoding.ModuleSetDecoderResolver(map[string]interface{}{"bank": nil, "staking": nil}))
+
+	latest, err := source.LatestVersion()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), latest)
+
+	// the changes are read from the iavl diff, and by iterating both versions
+	// when the state commitment can't diff versions
+	fallback := newIndexerSyncSource(
+		cometmock.NewMockStore(committerWithoutDiff{ms.Committer}),
+		decoding.ModuleSetDecoderResolver(map[string]interface{}{"bank": nil, "staking": nil}),
+	)
+	for _, source := range []*indexerSyncSource{source, fallback} {
+		collectChanges := func(moduleName string, version uint64) []schema.KVPairUpdate {
+			var updates []schema.KVPairUpdate
+			err := source.IterateChanges(moduleName, version, func(update schema.KVPairUpdate) error {
+				updates = append(updates, update)
+				return nil
+			})
+			require.NoError(t, err)
+			return updates
+		}
+
+		require.Equal(t, []schema.KVPairUpdate{
+			{Key: []byte("a"), Value: []byte("1")},
+			{Key: []byte("b"), Value: []byte("2")},
+		}, collectChanges("bank", 1))
+		require.Equal(t, []schema.KVPairUpdate{
+			{Key: []byte("a"), Remove: true},
+			{Key: []byte("b"), Value: []byte("3")},
+			{Key: []byte("c"), Value: []byte("4")},
+		}, collectChanges("bank", 2))
+		// staking didn't change in version 2
+		require.Empty(t, collectChanges("staking", 2))
+	}
+
+	collectAll := func(source decoding.SyncSource, moduleName string) map[string]string {
+		kvs := map[string]string{}
+		err := source.IterateAllKVPairs(moduleName, func(key, value []byte) error {
+			kvs[string(key)] = string(value)
+			return nil
+		})
+		require.NoError(t, err)
+		return kvs
+	}
+
+	v1, err := source.SyncSourceAt(1)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a": "1", "b": "2"}, collectAll(v1, "bank"))
+	require.Equal(t, map[string]string{"b": "3", "c": "4"}, collectAll(source, "bank"))
+
+	_, err = source.SyncSourceAt(3)
+	require.Error(t, err)
+	require.Error(t, source.IterateChanges("bank", 3, func(schema.KVPairUpdate) error { return nil }))
+}
+
+// committerWithoutDiff hides the store.StateDiffer implementation of a Committer.
+type committerWithoutDiff struct {
+	storev2.Committer
+}
@@ -71,6 +71,10 @@ func (s *MockStore) GetStateCommitment() storev2.Committer {
 	return s.Committer
 }
 
+func (s *MockStore) GetStateStorage() storev2.VersionedWriter {
+	return nil
+}
+
 func (s *MockStore) Query(storeKey []byte, version uint64, key []byte, prove bool) (storev2.QueryResult, error) {
 	state, err := s.StateAt(version)
 	if err != nil {
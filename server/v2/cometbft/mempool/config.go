@@ -6,11 +6,26 @@ var DefaultMaxTx = -1
 type Config struct {
 	// MaxTxs defines the maximum number of transactions that can be in the mempool.
 	MaxTxs int `mapstructure:"max-txs" toml:"max-txs" comment:"max-txs defines the maximum number of transactions that can be in the mempool. A value of 0 indicates an unbounded mempool, a negative value disables the app-side mempool."`
+	// TTL defines the number of seconds after which a transaction is evicted from the mempool.
+	TTL uint64 `mapstructure:"ttl" toml:"ttl" comment:"ttl defines the number of seconds after which a transaction is evicted from the mempool. A value of 0 disables the eviction by age."`
+	// PriceBump defines the minimum fee per gas increase, in percent, of a transaction replacing another one.
+	PriceBump uint64 `mapstructure:"price-bump" toml:"price-bump" comment:"price-bump defines the minimum fee per gas increase, in percent, of a transaction replacing a transaction of the same sender and nonce in the mempool."`
+	// JournalDir defines the directory of the on-disk journal of the mempool transactions.
+	JournalDir string `mapstructure:"journal-dir" toml:"journal-dir" comment:"journal-dir defines the directory, relative to the node home if not absolute, of the on-disk journal of the mempool transactions, which are replayed through CheckTx on startup. An empty value disables the journal."`
+	// JournalBackend defines the database backend of the journal.
+	JournalBackend string `mapstructure:"journal-backend" toml:"journal-backend" comment:"journal-backend defines the database backend of the journal (goleveldb, pebbledb)."`
+	// JournalTTL defines the number of seconds after which a journaled transaction expires.
+	JournalTTL uint64 `mapstructure:"journal-ttl" toml:"journal-ttl" comment:"journal-ttl defines the number of seconds after which a journaled transaction is removed from the journal and not replayed anymore. A value of 0 disables the expiry of the journal."`
 }
 
 // DefaultConfig returns a default configuration for the SDK built-in app-side mempool implementations.
 func DefaultConfig() Config {
 	return Config{
-		MaxTxs: DefaultMaxTx,
+		MaxTxs:         DefaultMaxTx,
+		TTL:            0,
+		PriceBump:      10,
+		JournalDir:     "",
+		JournalBackend: "goleveldb",
+		JournalTTL:     3600,
 	}
 }
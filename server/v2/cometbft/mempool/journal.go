@@ -0,0 +1,222 @@
+package mempool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	corestore "cosmossdk.io/core/store"
+)
+
+var (
+	// journalEntryPrefix prefixes the journal entries, keyed by insertion time
+	// and tx hash, whose value is the tx bytes.
+	journalEntryPrefix = []byte{0x00}
+	// journalIndexPrefix prefixes the insertion time of the journal entries,
+	// keyed by tx hash.
+	journalIndexPrefix = []byte{0x01}
+)
+
+// Journal is an on-disk journal of the transactions inserted in a mempool, so
+// that the pending transactions survive a node restart. The transactions are
+// journaled when inserted and removed from the journal when included in a block
+// or removed by the mempool, and replayed in their insertion order on startup.
+// The entries older than the ttl of the journal are removed by Expire and
+// skipped by Replay.
+type Journal struct {
+	mtx sync.Mutex
+	db  corestore.KVStoreWithBatch
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewJournal returns a new Journal storing its entries in db. The entries older
+// than ttl seconds are not replayed, a ttl of 0 disables the expiry.
+func NewJournal(db corestore.KVStoreWithBatch, ttl uint64) *Journal {
+	return &Journal{
+		db:  db,
+		ttl: time.Duration(ttl) * time.Second,
+		now: time.Now,
+	}
+}
+
+// Insert journals a transaction. Inserting a journaled transaction is a no-op,
+// its entry keeping its insertion time.
+func (j *Journal) Insert(hash [32]byte, txBytes []byte) error {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	indexKey := journalIndexKey(hash)
+	if ok, err := j.db.Has(indexKey); err != nil || ok {
+		return err
+	}
+
+	added := binary.BigEndian.AppendUint64(nil, uint64(j.now().UnixNano()))
+	batch := j.db.NewBatch()
+	defer batch.Close()
+	if err := batch.Set(journalEntryKey(added, hash), txBytes); err != nil {
+		return err
+	}
+	if err := batch.Set(indexKey, added); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// Remove removes a transaction from the journal. Removing a transaction which
+// is not journaled is a no-op.
+func (j *Journal) Remove(hash [32]byte) error {
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	indexKey := journalIndexKey(hash)
+	added, err := j.db.Get(indexKey)
+	if err != nil || added == nil {
+		return err
+	}
+
+	batch := j.db.NewBatch()
+	defer batch.Close()
+	if err := batch.Delete(journalEntryKey(added, hash)); err != nil {
+		return err
+	}
+	if err := batch.Delete(indexKey); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// Expire removes the entries older than the ttl of the journal. It returns the
+// number of removed entries.
+func (j *Journal) Expire() (int, error) {
+	if j.ttl == 0 {
+		return 0, nil
+	}
+	expiry := j.now().Add(-j.ttl).UnixNano()
+	if expiry <= 0 {
+		return 0, nil
+	}
+
+	j.mtx.Lock()
+	defer j.mtx.Unlock()
+
+	// the entries are ordered by insertion time
+	end := journalEntryKey(binary.BigEndian.AppendUint64(nil, uint64(expiry)), [32]byte{})
+	var keys [][]byte
+	err := func() error {
+		it, err := j.db.Iterator(journalEntryPrefix, end)
+		if err != nil {
+			return err
+		}
+		defer it.Close()
+
+		for ; it.Valid(); it.Next() {
+			keys = append(keys, bytes.Clone(it.Key()))
+		}
+		return it.Error()
+	}()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read mempool journal: %w", err)
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	batch := j.db.NewBatch()
+	defer batch.Close()
+	for _, key := range keys {
+		_, hash, err := parseJournalEntryKey(key)
+		if err != nil {
+			return 0, err
+		}
+		if err := batch.Delete(key); err != nil {
+			return 0, err
+		}
+		if err := batch.Delete(journalIndexKey(hash)); err != nil {
+			return 0, err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return 0, err
+	}
+	return len(keys), nil
+}
+
+// Replay calls replay on the journaled transactions in their insertion order.
+// The expired transactions, and the ones for which replay returns an error, are
+// removed from the journal. It returns the number of replayed transactions.
+func (j *Journal) Replay(replay func(txBytes []byte) error) (int, error) {
+	type entry struct {
+		key, txBytes []byte
+	}
+
+	j.mtx.Lock()
+	var entries []entry
+	err := func() error {
+		it, err := j.db.Iterator(journalEntryPrefix, journalIndexPrefix)
+		if err != nil {
+			return err
+		}
+		defer it.Close()
+
+		for ; it.Valid(); it.Next() {
+			entries = append(entries, entry{key: bytes.Clone(it.Key()), txBytes: bytes.Clone(it.Value())})
+		}
+		return it.Error()
+	}()
+	j.mtx.Unlock()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read mempool journal: %w", err)
+	}
+
+	var expiry time.Time
+	if j.ttl > 0 {
+		expiry = j.now().Add(-j.ttl)
+	}
+
+	replayed := 0
+	for _, e := range entries {
+		added, hash, err := parseJournalEntryKey(e.key)
+		if err != nil {
+			return replayed, err
+		}
+		if !added.Before(expiry) {
+			if err := replay(e.txBytes); err == nil {
+				replayed++
+				continue
+			}
+		}
+		if err := j.Remove(hash); err != nil {
+			return replayed, err
+		}
+	}
+	return replayed, nil
+}
+
+// Close closes the database of the journal.
+func (j *Journal) Close() error {
+	return j.db.Close()
+}
+
+func journalEntryKey(added []byte, hash [32]byte) []byte {
+	key := make([]byte, 0, len(journalEntryPrefix)+len(added)+len(hash))
+	key = append(key, journalEntryPrefix...)
+	key = append(key, added...)
+	return append(key, hash[:]...)
+}
+
+func parseJournalEntryKey(key []byte) (time.Time, [32]byte, error) {
+	var hash [32]byte
+	if len(key) != len(journalEntryPrefix)+8+len(hash) {
+		return time.Time{}, hash, fmt.Errorf("invalid mempool journal key %X", key)
+	}
+	key = key[len(journalEntryPrefix):]
+	copy(hash[:], key[8:])
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key[:8]))), hash, nil
+}
+
+func journalIndexKey(hash [32]byte) []byte {
+	return append(bytes.Clone(journalIndexPrefix), hash[:]...)
+}
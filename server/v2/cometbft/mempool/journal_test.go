@@ -0,0 +1,94 @@
+package mempool
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/store/v2/db"
+)
+
+func replayJournal(t *testing.T, j *Journal, fail func([]byte) bool) [][]byte {
+	t.Helper()
+	var replayed [][]byte
+	n, err := j.Replay(func(txBytes []byte) error {
+		if fail != nil && fail(txBytes) {
+			return errors.New("invalid")
+		}
+		replayed = append(replayed, txBytes)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, len(replayed), n)
+	return replayed
+}
+
+func TestJournal(t *testing.T) {
+	now := time.Now()
+	j := NewJournal(db.NewMemDB(), 60)
+	j.now = func() time.Time { return now }
+
+	txs := [][]byte{[]byte("tx1"), []byte("tx2"), []byte("tx3"), []byte("tx4")}
+	for _, tx := range txs[:2] {
+		require.NoError(t, j.Insert(sha256.Sum256(tx), tx))
+	}
+	now = now.Add(30 * time.Second)
+	for _, tx := range txs[2:] {
+		require.NoError(t, j.Insert(sha256.Sum256(tx), tx))
+	}
+	// inserting a journaled tx keeps its insertion time
+	require.NoError(t, j.Insert(sha256.Sum256(txs[0]), txs[0]))
+
+	// the txs are replayed by insertion time, the failed ones are removed
+	require.NoError(t, j.Remove(sha256.Sum256(txs[1])))
+	require.NoError(t, j.Remove(sha256.Sum256([]byte("unknown"))))
+	require.ElementsMatch(t, [][]byte{txs[0], txs[2], txs[3]}, replayJournal(t, j, nil))
+	require.Equal(t, [][]byte{txs[0], txs[3]}, replayJournal(t, j, func(tx []byte) bool { return string(tx) == "tx3" }))
+
+	// the expired txs are removed
+	now = now.Add(45 * time.Second)
+	require.Equal(t, [][]byte{txs[3]}, replayJournal(t, j, nil))
+	now = now.Add(time.Hour)
+	require.Empty(t, replayJournal(t, j, nil))
+
+	// a ttl of 0 disables the expiry
+	j.ttl = 0
+	require.NoError(t, j.Insert(sha256.Sum256(txs[0]), txs[0]))
+	now = now.Add(24 * time.Hour)
+	require.Equal(t, [][]byte{txs[0]}, replayJournal(t, j, nil))
+	require.NoError(t, j.Close())
+}
+
+func TestJournal_Expire(t *testing.T) {
+	now := time.Now()
+	j := NewJournal(db.NewMemDB(), 60)
+	j.now = func() time.Time { return now }
+
+	txs := [][]byte{[]byte("tx1"), []byte("tx2"), []byte("tx3")}
+	for _, tx := range txs[:2] {
+		require.NoError(t, j.Insert(sha256.Sum256(tx), tx))
+	}
+	now = now.Add(30 * time.Second)
+	require.NoError(t, j.Insert(sha256.Sum256(txs[2]), txs[2]))
+
+	// only the entries older than the ttl are removed, with their index
+	now = now.Add(45 * time.Second)
+	expired, err := j.Expire()
+	require.NoError(t, err)
+	require.Equal(t, 2, expired)
+	ok, err := j.db.Has(journalIndexKey(sha256.Sum256(txs[0])))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	j.ttl = 0
+	require.Equal(t, [][]byte{txs[2]}, replayJournal(t, j, nil))
+
+	// a ttl of 0 disables the expiry
+	now = now.Add(24 * time.Hour)
+	expired, err = j.Expire()
+	require.NoError(t, err)
+	require.Zero(t, expired)
+}
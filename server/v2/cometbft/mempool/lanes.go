@@ -0,0 +1,211 @@
+package mempool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"cosmossdk.io/core/transaction"
+)
+
+var (
+	_ Mempool[transaction.Tx]        = (*LanedMempool[transaction.Tx])(nil)
+	_ Rechecker[transaction.Tx]      = (*LanedMempool[transaction.Tx])(nil)
+	_ RemoveNotifier[transaction.Tx] = (*LanedMempool[transaction.Tx])(nil)
+	_ Iterator[transaction.Tx]       = (*lanedIterator[transaction.Tx])(nil)
+)
+
+// ErrNoLane is returned when no lane of a LanedMempool matches a transaction.
+var ErrNoLane = errors.New("no lane matches tx")
+
+// Lane is a partition of a LanedMempool, holding the transactions it matches in
+// its own mempool and selection policy.
+type Lane[T transaction.Tx] struct {
+	// Name identifies the lane.
+	Name string
+
+	// Mempool holds the transactions of the lane and defines their order.
+	Mempool Mempool[T]
+
+	// Match reports whether a transaction belongs to the lane. A nil Match
+	// matches every transaction, as a default lane does.
+	Match func(tx T) bool
+
+	// MaxBlockSpace is the maximum share, in percent, of the block bytes and gas
+	// the transactions of the lane can take. A value of 0 does not limit the lane.
+	MaxBlockSpace uint64
+
+	// Priority orders the lanes. A transaction belongs to the lane with the
+	// highest priority matching it, and the transactions of the lanes with a
+	// higher priority are included first in a block.
+	Priority int64
+}
+
+// BlockSpaceLimit returns the share of the lane of a block space, in bytes or
+// gas.
+func (l Lane[T]) BlockSpaceLimit(total uint64) uint64 {
+	if l.MaxBlockSpace == 0 {
+		return total
+	}
+	// total * MaxBlockSpace / 100 without overflow
+	return total/100*l.MaxBlockSpace + total%100*l.MaxBlockSpace/100
+}
+
+func (l Lane[T]) matches(tx T) bool {
+	return l.Match == nil || l.Match(tx)
+}
+
+// LanedMempool is a mempool partitioned in lanes, so that the transactions of a
+// lane, for instance oracle or IBC relayer transactions, get their share of the
+// block space regardless of the transactions of the other lanes. The
+// transactions are selected lane by lane by priority, and in the order of the
+// mempool of the lane within a lane.
+//
+// The DefaultProposalHandler caps the transactions of every lane to its share of
+// the block space in PrepareProposal, and verifies in ProcessProposal that the
+// transactions of a proposal are ordered by lane and within the share of their
+// lane. As proposals are rejected on a mismatch, the lanes are part of the app
+// code rather than of the node configuration.
+type LanedMempool[T transaction.Tx] struct {
+	lanes []Lane[T]
+}
+
+// NewLanedMempool returns a new LanedMempool made of the given lanes.
+func NewLanedMempool[T transaction.Tx](lanes ...Lane[T]) (*LanedMempool[T], error) {
+	if len(lanes) == 0 {
+		return nil, errors.New("laned mempool must have at least one lane")
+	}
+
+	names := make(map[string]struct{}, len(lanes))
+	for _, lane := range lanes {
+		if lane.Name == "" {
+			return nil, errors.New("lane name cannot be empty")
+		}
+		if _, ok := names[lane.Name]; ok {
+			return nil, fmt.Errorf("duplicate lane %s", lane.Name)
+		}
+		names[lane.Name] = struct{}{}
+		if lane.Mempool == nil {
+			return nil, fmt.Errorf("lane %s has no mempool", lane.Name)
+		}
+		if lane.MaxBlockSpace > 100 {
+			return nil, fmt.Errorf("max block space of lane %s must be at most 100%%, got %d%%", lane.Name, lane.MaxBlockSpace)
+		}
+	}
+
+	lanes = append([]Lane[T](nil), lanes...)
+	sort.SliceStable(lanes, func(i, j int) bool { return lanes[i].Priority > lanes[j].Priority })
+	return &LanedMempool[T]{lanes: lanes}, nil
+}
+
+// Lanes returns the lanes of the mempool by priority.
+func (mp *LanedMempool[T]) Lanes() []Lane[T] {
+	return append([]Lane[T](nil), mp.lanes...)
+}
+
+// LaneIndex returns the index in Lanes of the lane of a transaction.
+func (mp *LanedMempool[T]) LaneIndex(tx T) (int, error) {
+	for i, lane := range mp.lanes {
+		if lane.matches(tx) {
+			return i, nil
+		}
+	}
+	return 0, ErrNoLane
+}
+
+// Insert inserts a transaction in the mempool of its lane.
+func (mp *LanedMempool[T]) Insert(ctx context.Context, tx T) error {
+	i, err := mp.LaneIndex(tx)
+	if err != nil {
+		return err
+	}
+	return mp.lanes[i].Mempool.Insert(ctx, tx)
+}
+
+// Select returns an iterator over the transactions of the lanes by priority.
+func (mp *LanedMempool[T]) Select(ctx context.Context, txs []T) Iterator[T] {
+	return (&lanedIterator[T]{ctx: ctx, txs: txs, lanes: mp.lanes, lane: -1}).nextLane()
+}
+
+// SelectBy calls callback on the transactions of the lanes by priority until it
+// returns false.
+func (mp *LanedMempool[T]) SelectBy(ctx context.Context, txs []T, callback func(T) bool) {
+	for _, lane := range mp.lanes {
+		stopped := false
+		lane.Mempool.SelectBy(ctx, txs, func(tx T) bool {
+			stopped = !callback(tx)
+			return !stopped
+		})
+		if stopped {
+			return
+		}
+	}
+}
+
+// CountTx returns the number of transactions of all the lanes.
+func (mp *LanedMempool[T]) CountTx() int {
+	count := 0
+	for _, lane := range mp.lanes {
+		count += lane.Mempool.CountTx()
+	}
+	return count
+}
+
+// Remove removes a transaction from the mempool of its lane.
+func (mp *LanedMempool[T]) Remove(tx T) error {
+	i, err := mp.LaneIndex(tx)
+	if err != nil {
+		return ErrTxNotFound
+	}
+	return mp.lanes[i].Mempool.Remove(tx)
+}
+
+// Recheck rechecks the transactions of the lanes whose mempool is a Rechecker.
+func (mp *LanedMempool[T]) Recheck(ctx context.Context, validate func(context.Context, T) error) {
+	for _, lane := range mp.lanes {
+		if rechecker, ok := lane.Mempool.(Rechecker[T]); ok {
+			rechecker.Recheck(ctx, validate)
+		}
+	}
+}
+
+// SetOnRemove sets the function called with every transaction removed from the
+// lanes whose mempool is a RemoveNotifier.
+func (mp *LanedMempool[T]) SetOnRemove(onRemove func(tx T)) {
+	for _, lane := range mp.lanes {
+		if notifier, ok := lane.Mempool.(RemoveNotifier[T]); ok {
+			notifier.SetOnRemove(onRemove)
+		}
+	}
+}
+
+// lanedIterator iterates over the transactions of the lanes of a LanedMempool.
+type lanedIterator[T transaction.Tx] struct {
+	ctx   context.Context
+	txs   []T
+	lanes []Lane[T]
+	lane  int
+	iter  Iterator[T]
+}
+
+// nextLane moves the iterator to the first transaction of the next lanes.
+func (i *lanedIterator[T]) nextLane() Iterator[T] {
+	for i.lane++; i.lane < len(i.lanes); i.lane++ {
+		if i.iter = i.lanes[i.lane].Mempool.Select(i.ctx, i.txs); i.iter != nil {
+			return i
+		}
+	}
+	return nil
+}
+
+func (i *lanedIterator[T]) Next() Iterator[T] {
+	if i.iter = i.iter.Next(); i.iter != nil {
+		return i
+	}
+	return i.nextLane()
+}
+
+func (i *lanedIterator[T]) Tx() T {
+	return i.iter.Tx()
+}
@@ -0,0 +1,97 @@
+package mempool
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLanedMempool(t *testing.T) {
+	_, err := NewLanedMempool[testTx]()
+	require.Error(t, err)
+	_, err = NewLanedMempool(Lane[testTx]{Mempool: NoOpMempool[testTx]{}})
+	require.Error(t, err)
+	_, err = NewLanedMempool(Lane[testTx]{Name: "default"})
+	require.Error(t, err)
+	_, err = NewLanedMempool(Lane[testTx]{Name: "default", Mempool: NoOpMempool[testTx]{}, MaxBlockSpace: 101})
+	require.Error(t, err)
+	_, err = NewLanedMempool(
+		Lane[testTx]{Name: "default", Mempool: NoOpMempool[testTx]{}},
+		Lane[testTx]{Name: "default", Mempool: NoOpMempool[testTx]{}},
+	)
+	require.Error(t, err)
+
+	mp, err := NewLanedMempool(
+		Lane[testTx]{Name: "default", Mempool: NoOpMempool[testTx]{}},
+		Lane[testTx]{Name: "oracle", Mempool: NoOpMempool[testTx]{}, Priority: 10},
+		Lane[testTx]{Name: "relayer", Mempool: NoOpMempool[testTx]{}, Priority: 5},
+	)
+	require.NoError(t, err)
+	var names []string
+	for _, lane := range mp.Lanes() {
+		names = append(names, lane.Name)
+	}
+	require.Equal(t, []string{"oracle", "relayer", "default"}, names)
+}
+
+func TestLaneBlockSpaceLimit(t *testing.T) {
+	require.Equal(t, uint64(1000), Lane[testTx]{}.BlockSpaceLimit(1000))
+	require.Equal(t, uint64(250), Lane[testTx]{MaxBlockSpace: 25}.BlockSpaceLimit(1000))
+	require.Equal(t, uint64(1<<63-1), Lane[testTx]{MaxBlockSpace: 50}.BlockSpaceLimit(1<<64-1))
+}
+
+func TestLanedMempool(t *testing.T) {
+	mp, err := NewLanedMempool(
+		Lane[testTx]{Name: "default", Mempool: newTestMempool(Config{})},
+		Lane[testTx]{
+			Name:          "oracle",
+			Mempool:       newTestMempool(Config{}),
+			Match:         func(tx testTx) bool { return tx.sender == "oracle" },
+			MaxBlockSpace: 20,
+			Priority:      10,
+		},
+	)
+	require.NoError(t, err)
+
+	txs := []testTx{
+		{sender: "oracle", nonce: 1, fee: 1, gas: 10},
+		{sender: "a", nonce: 1, fee: 200, gas: 10},
+		{sender: "oracle", nonce: 2, fee: 1, gas: 10},
+		{sender: "b", nonce: 1, fee: 100, gas: 10},
+	}
+	for _, tx := range txs {
+		require.NoError(t, mp.Insert(context.Background(), tx))
+	}
+	require.Equal(t, len(txs), mp.CountTx())
+
+	i, err := mp.LaneIndex(txs[0])
+	require.NoError(t, err)
+	require.Equal(t, "oracle", mp.Lanes()[i].Name)
+
+	// the txs of the oracle lane come first, whatever their fee
+	require.Equal(t, []testTx{txs[0], txs[2], txs[1], txs[3]}, selectTxs(t, mp))
+
+	var selected []testTx
+	mp.SelectBy(context.Background(), nil, func(tx testTx) bool {
+		selected = append(selected, tx)
+		return len(selected) < 3
+	})
+	require.Equal(t, []testTx{txs[0], txs[2], txs[1]}, selected)
+
+	// the lanes are rechecked
+	mp.Recheck(context.Background(), func(_ context.Context, tx testTx) error {
+		if tx == txs[0] || tx == txs[3] {
+			return errors.New("invalid")
+		}
+		return nil
+	})
+	require.Equal(t, []testTx{txs[2], txs[1]}, selectTxs(t, mp))
+
+	require.NoError(t, mp.Remove(txs[1]))
+	require.NoError(t, mp.Remove(txs[2]))
+	require.Zero(t, mp.CountTx())
+	require.Nil(t, mp.Select(context.Background(), nil))
+	require.ErrorIs(t, mp.Remove(txs[0]), ErrTxNotFound)
+}
@@ -10,6 +10,7 @@ import (
 var (
 	ErrTxNotFound           = errors.New("tx not found in mempool")
 	ErrMempoolTxMaxCapacity = errors.New("pool reached max tx capacity")
+	ErrTxUnderpriced        = errors.New("tx replacement underpriced")
 )
 
 // Mempool defines the required methods of an application's mempool.
@@ -33,6 +34,24 @@ type Mempool[T transaction.Tx] interface {
 	Remove(T) error
 }
 
+// Rechecker defines a mempool which re-checks its transactions against the
+// latest state after each commit. The recheck runs in the background, so the
+// mempool must remain usable while it runs.
+type Rechecker[T transaction.Tx] interface {
+	// Recheck removes the transactions which are no longer valid, validate being
+	// called on the latest committed state. It should return early when ctx is done.
+	Recheck(ctx context.Context, validate func(context.Context, T) error)
+}
+
+// RemoveNotifier defines a mempool which notifies the transactions it removes,
+// whether by Remove or on its own, e.g. on eviction, replacement or recheck.
+type RemoveNotifier[T transaction.Tx] interface {
+	// SetOnRemove sets the function called with every transaction removed from
+	// the mempool. It is called holding the mempool lock, so it must not use the
+	// mempool.
+	SetOnRemove(onRemove func(tx T))
+}
+
 // Iterator defines an app-side mempool iterator interface that is as minimal as
 // possible. The order of iteration is determined by the app-side mempool
 // implementation.
@@ -0,0 +1,382 @@
+package mempool
+
+import (
+	"cmp"
+	"container/heap"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"cosmossdk.io/core/transaction"
+)
+
+var (
+	_ Mempool[transaction.Tx]        = (*PriorityNonceMempool[transaction.Tx])(nil)
+	_ Rechecker[transaction.Tx]      = (*PriorityNonceMempool[transaction.Tx])(nil)
+	_ RemoveNotifier[transaction.Tx] = (*PriorityNonceMempool[transaction.Tx])(nil)
+)
+
+// PriorityNonceMempool is a mempool which orders the transactions of each
+// sender by nonce and the transactions of different senders by fee per gas.
+// Select returns, among the next transactions of every sender by nonce, the one
+// with the highest fee per gas first, the earliest inserted transaction first
+// on ties.
+//
+// A transaction with the same sender and nonce as a transaction of the mempool
+// replaces it when its fee per gas is higher by at least Config.PriceBump
+// percent. When the mempool reached Config.MaxTxs, the last transaction by nonce
+// of a sender with the lowest fee per gas is evicted for a transaction with a
+// higher fee per gas. The transactions older than Config.TTL seconds are evicted
+// along with the following transactions of their sender, on Insert, Select,
+// SelectBy, CountTx and Recheck.
+type PriorityNonceMempool[T transaction.Tx] struct {
+	mtx     sync.Mutex
+	cfg     Config
+	txInfo  TxInfoFunc[T]
+	now     func() time.Time
+	senders map[string][]*mempoolTx[T] // the transactions of each sender sorted by nonce
+	txs     map[[32]byte]*mempoolTx[T]
+	seq     uint64 // seq is the insertion sequence number of the last transaction
+	// byAge holds the transactions in insertion order when Config.TTL is set,
+	// including the removed ones until they are dropped.
+	byAge []*mempoolTx[T]
+	// onRemove is called with the removed transactions, when set.
+	onRemove func(tx T)
+}
+
+// mempoolTx is a transaction of the mempool.
+type mempoolTx[T transaction.Tx] struct {
+	tx    T
+	hash  [32]byte
+	info  TxInfo
+	added time.Time
+	seq   uint64
+}
+
+// NewPriorityNonceMempool returns a new PriorityNonceMempool getting the sender,
+// nonce and fee of the transactions from txInfo.
+func NewPriorityNonceMempool[T transaction.Tx](cfg Config, txInfo TxInfoFunc[T]) *PriorityNonceMempool[T] {
+	return &PriorityNonceMempool[T]{
+		cfg:     cfg,
+		txInfo:  txInfo,
+		now:     time.Now,
+		senders: make(map[string][]*mempoolTx[T]),
+		txs:     make(map[[32]byte]*mempoolTx[T]),
+	}
+}
+
+// Insert inserts a transaction in the mempool. Inserting a transaction already
+// in the mempool is a no-op, and so is any insertion when Config.MaxTxs is
+// negative.
+func (mp *PriorityNonceMempool[T]) Insert(ctx context.Context, tx T) error {
+	if mp.cfg.MaxTxs < 0 {
+		return nil
+	}
+
+	info, err := mp.txInfo(ctx, tx)
+	if err != nil {
+		return err
+	}
+	hash := tx.Hash()
+
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	mp.expire()
+	if _, ok := mp.txs[hash]; ok {
+		return nil
+	}
+
+	senderTxs := mp.senders[info.Sender]
+	i, found := slices.BinarySearchFunc(senderTxs, info.Nonce, compareNonce[T])
+	if found {
+		replaced := senderTxs[i]
+		if !isPriceBump(replaced.info, info, mp.cfg.PriceBump) {
+			return fmt.Errorf("%w: fee per gas must be %d%% higher than the one of tx %X", ErrTxUnderpriced, mp.cfg.PriceBump, replaced.hash)
+		}
+		delete(mp.txs, replaced.hash)
+		mp.notifyRemove(replaced)
+		senderTxs[i] = mp.newTx(tx, hash, info)
+		mp.txs[hash] = senderTxs[i]
+		return nil
+	}
+
+	if mp.cfg.MaxTxs > 0 && len(mp.txs) >= mp.cfg.MaxTxs {
+		if err := mp.evictFor(info); err != nil {
+			return err
+		}
+		senderTxs = mp.senders[info.Sender]
+		i, _ = slices.BinarySearchFunc(senderTxs, info.Nonce, compareNonce[T])
+	}
+
+	mtx := mp.newTx(tx, hash, info)
+	mp.senders[info.Sender] = slices.Insert(senderTxs, i, mtx)
+	mp.txs[hash] = mtx
+	return nil
+}
+
+func (mp *PriorityNonceMempool[T]) newTx(tx T, hash [32]byte, info TxInfo) *mempoolTx[T] {
+	mp.seq++
+	mtx := &mempoolTx[T]{tx: tx, hash: hash, info: info, added: mp.now(), seq: mp.seq}
+	if mp.cfg.TTL > 0 {
+		// drop the removed transactions once they are the majority of the queue
+		if len(mp.byAge) > 2*len(mp.txs) {
+			mp.byAge = slices.DeleteFunc(mp.byAge, func(queued *mempoolTx[T]) bool { return mp.txs[queued.hash] != queued })
+		}
+		mp.byAge = append(mp.byAge, mtx)
+	}
+	return mtx
+}
+
+// expire evicts the transactions older than Config.TTL seconds, along with the
+// following transactions of their sender which can't be executed anymore.
+func (mp *PriorityNonceMempool[T]) expire() {
+	if mp.cfg.TTL == 0 {
+		return
+	}
+
+	expiry := mp.now().Add(-time.Duration(mp.cfg.TTL) * time.Second)
+	for len(mp.byAge) > 0 && mp.byAge[0].added.Before(expiry) {
+		mtx := mp.byAge[0]
+		mp.byAge[0] = nil
+		mp.byAge = mp.byAge[1:]
+		if mp.txs[mtx.hash] != mtx {
+			continue
+		}
+
+		senderTxs := mp.senders[mtx.info.Sender]
+		i, _ := slices.BinarySearchFunc(senderTxs, mtx.info.Nonce, compareNonce[T])
+		for _, following := range slices.Clone(senderTxs[i:]) {
+			mp.remove(following)
+		}
+	}
+}
+
+// evictFor evicts the last transaction of the sender with the lowest fee per
+// gas to make room for a transaction, if its fee per gas is lower.
+func (mp *PriorityNonceMempool[T]) evictFor(info TxInfo) error {
+	var evicted *mempoolTx[T]
+	for sender, senderTxs := range mp.senders {
+		last := senderTxs[len(senderTxs)-1]
+		// evicting a transaction preceding the inserted one would leave a nonce gap
+		if sender == info.Sender && last.info.Nonce < info.Nonce {
+			continue
+		}
+		if evicted == nil || compareFeePerGas(last.info, evicted.info) < 0 {
+			evicted = last
+		}
+	}
+	if evicted == nil || compareFeePerGas(evicted.info, info) >= 0 {
+		return ErrMempoolTxMaxCapacity
+	}
+
+	mp.remove(evicted)
+	return nil
+}
+
+// Select returns an iterator over the transactions of the mempool by fee per
+// gas and nonce. The passed in transactions are ignored. The iterator iterates
+// over a snapshot of the mempool, which can be modified during the iteration.
+func (mp *PriorityNonceMempool[T]) Select(_ context.Context, _ []T) Iterator[T] {
+	mp.mtx.Lock()
+	mp.expire()
+	txs := mp.ordered()
+	mp.mtx.Unlock()
+
+	if len(txs) == 0 {
+		return nil
+	}
+	return &priorityNonceIterator[T]{txs: txs}
+}
+
+// SelectBy calls callback on the transactions of the mempool by fee per gas and
+// nonce until it returns false, holding the mempool lock.
+func (mp *PriorityNonceMempool[T]) SelectBy(_ context.Context, _ []T, callback func(T) bool) {
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	mp.expire()
+	for _, mtx := range mp.ordered() {
+		if !callback(mtx.tx) {
+			return
+		}
+	}
+}
+
+// ordered returns the transactions of the mempool by fee per gas and nonce.
+func (mp *PriorityNonceMempool[T]) ordered() []*mempoolTx[T] {
+	heads := make(senderHeap[T], 0, len(mp.senders))
+	for _, senderTxs := range mp.senders {
+		heads = append(heads, senderTxs)
+	}
+	heap.Init(&heads)
+
+	txs := make([]*mempoolTx[T], 0, len(mp.txs))
+	for heads.Len() > 0 {
+		senderTxs := heads[0]
+		txs = append(txs, senderTxs[0])
+		if len(senderTxs) == 1 {
+			heap.Pop(&heads)
+			continue
+		}
+		heads[0] = senderTxs[1:]
+		heap.Fix(&heads, 0)
+	}
+	return txs
+}
+
+// CountTx returns the number of transactions in the mempool.
+func (mp *PriorityNonceMempool[T]) CountTx() int {
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	mp.expire()
+	return len(mp.txs)
+}
+
+// Remove removes a transaction from the mempool, or the transaction of the
+// mempool with the same sender and nonce, whose nonce was used.
+func (mp *PriorityNonceMempool[T]) Remove(tx T) error {
+	hash := tx.Hash()
+
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	if mtx, ok := mp.txs[hash]; ok {
+		mp.remove(mtx)
+		return nil
+	}
+
+	info, err := mp.txInfo(context.Background(), tx)
+	if err != nil {
+		// the mempool only holds transactions with an info
+		return ErrTxNotFound
+	}
+	senderTxs := mp.senders[info.Sender]
+	i, found := slices.BinarySearchFunc(senderTxs, info.Nonce, compareNonce[T])
+	if !found {
+		return ErrTxNotFound
+	}
+	mp.remove(senderTxs[i])
+	return nil
+}
+
+// SetOnRemove sets the function called with every transaction removed from the
+// mempool, holding the mempool lock.
+func (mp *PriorityNonceMempool[T]) SetOnRemove(onRemove func(tx T)) {
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+	mp.onRemove = onRemove
+}
+
+func (mp *PriorityNonceMempool[T]) notifyRemove(mtx *mempoolTx[T]) {
+	if mp.onRemove != nil {
+		mp.onRemove(mtx.tx)
+	}
+}
+
+// remove removes a transaction of the mempool.
+func (mp *PriorityNonceMempool[T]) remove(mtx *mempoolTx[T]) {
+	delete(mp.txs, mtx.hash)
+	mp.notifyRemove(mtx)
+	senderTxs := mp.senders[mtx.info.Sender]
+	i, _ := slices.BinarySearchFunc(senderTxs, mtx.info.Nonce, compareNonce[T])
+	if len(senderTxs) == 1 {
+		delete(mp.senders, mtx.info.Sender)
+		return
+	}
+	mp.senders[mtx.info.Sender] = slices.Delete(senderTxs, i, i+1)
+}
+
+// Recheck evicts the expired transactions and validates the first transaction
+// of every sender, which is removed until one is valid. The following
+// transactions of a sender depend on the execution of its first transaction
+// and are not validated. The transactions are validated against a snapshot of
+// the mempool without holding its lock, so that the mempool can be used during
+// the recheck, and the recheck stops when ctx is done.
+func (mp *PriorityNonceMempool[T]) Recheck(ctx context.Context, validate func(context.Context, T) error) {
+	mp.mtx.Lock()
+	mp.expire()
+	senders := make([][]*mempoolTx[T], 0, len(mp.senders))
+	for _, senderTxs := range mp.senders {
+		senders = append(senders, slices.Clone(senderTxs))
+	}
+	mp.mtx.Unlock()
+
+	for _, senderTxs := range senders {
+		if ctx.Err() != nil {
+			return
+		}
+
+		i := 0
+		for i < len(senderTxs) && validate(ctx, senderTxs[i].tx) != nil {
+			i++
+		}
+		// a validation failing on the cancellation doesn't invalidate the transaction
+		if ctx.Err() != nil {
+			return
+		}
+		if i == 0 {
+			continue
+		}
+
+		mp.mtx.Lock()
+		for _, mtx := range senderTxs[:i] {
+			// the transaction may have been removed or replaced during the validation
+			if mp.txs[mtx.hash] == mtx {
+				mp.remove(mtx)
+			}
+		}
+		mp.mtx.Unlock()
+	}
+}
+
+func compareNonce[T transaction.Tx](mtx *mempoolTx[T], nonce uint64) int {
+	return cmp.Compare(mtx.info.Nonce, nonce)
+}
+
+// senderHeap is a max heap of the remaining transactions of the senders by the
+// fee per gas of their next transaction.
+type senderHeap[T transaction.Tx] [][]*mempoolTx[T]
+
+func (h senderHeap[T]) Len() int { return len(h) }
+
+func (h senderHeap[T]) Less(i, j int) bool {
+	a, b := h[i][0], h[j][0]
+	if c := compareFeePerGas(a.info, b.info); c != 0 {
+		return c > 0
+	}
+	return a.seq < b.seq
+}
+
+func (h senderHeap[T]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *senderHeap[T]) Push(x any) { *h = append(*h, x.([]*mempoolTx[T])) }
+
+func (h *senderHeap[T]) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// priorityNonceIterator iterates over a snapshot of the transactions of a
+// PriorityNonceMempool.
+type priorityNonceIterator[T transaction.Tx] struct {
+	txs []*mempoolTx[T]
+}
+
+func (i *priorityNonceIterator[T]) Next() Iterator[T] {
+	if len(i.txs) <= 1 {
+		return nil
+	}
+	i.txs = i.txs[1:]
+	return i
+}
+
+func (i *priorityNonceIterator[T]) Tx() T {
+	return i.txs[0].tx
+}
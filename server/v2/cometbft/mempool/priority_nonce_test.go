@@ -0,0 +1,273 @@
+package mempool
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/core/transaction"
+)
+
+type testTx struct {
+	sender string
+	nonce  uint64
+	fee    uint64
+	gas    uint64
+}
+
+func (tx testTx) Hash() [32]byte                              { return sha256.Sum256(tx.Bytes()) }
+func (tx testTx) GetMessages() ([]transaction.Msg, error)     { return nil, nil }
+func (tx testTx) GetSenders() ([]transaction.Identity, error) { return nil, nil }
+func (tx testTx) GetGasLimit() (uint64, error)                { return tx.gas, nil }
+
+func (tx testTx) Bytes() []byte {
+	bz := []byte(tx.sender)
+	bz = binary.BigEndian.AppendUint64(bz, tx.nonce)
+	bz = binary.BigEndian.AppendUint64(bz, tx.fee)
+	return binary.BigEndian.AppendUint64(bz, tx.gas)
+}
+
+func testTxInfo(_ context.Context, tx testTx) (TxInfo, error) {
+	return TxInfo{Sender: tx.sender, Nonce: tx.nonce, Fee: tx.fee, GasLimit: tx.gas}, nil
+}
+
+func newTestMempool(cfg Config) *PriorityNonceMempool[testTx] {
+	return NewPriorityNonceMempool(cfg, testTxInfo)
+}
+
+func selectTxs(t *testing.T, mp Mempool[testTx]) []testTx {
+	t.Helper()
+	var txs []testTx
+	for it := mp.Select(context.Background(), nil); it != nil; it = it.Next() {
+		txs = append(txs, it.Tx())
+	}
+	return txs
+}
+
+func TestPriorityNonceMempool_Select(t *testing.T) {
+	mp := newTestMempool(Config{})
+	txs := []testTx{
+		{sender: "a", nonce: 2, fee: 1000, gas: 10},
+		{sender: "a", nonce: 1, fee: 10, gas: 10},
+		{sender: "b", nonce: 5, fee: 50, gas: 10},
+		{sender: "b", nonce: 6, fee: 40, gas: 10},
+		{sender: "c", nonce: 0, fee: 100, gas: 20},
+		{sender: "d", nonce: 0, fee: 50, gas: 10},
+	}
+	for _, tx := range txs {
+		require.NoError(t, mp.Insert(context.Background(), tx))
+	}
+	// inserting a tx twice is a no-op
+	require.NoError(t, mp.Insert(context.Background(), txs[0]))
+	require.Equal(t, len(txs), mp.CountTx())
+
+	// the next txs of every sender by fee per gas, then by insertion on ties
+	require.Equal(t, []testTx{txs[2], txs[4], txs[5], txs[3], txs[1], txs[0]}, selectTxs(t, mp))
+
+	var selected []testTx
+	mp.SelectBy(context.Background(), nil, func(tx testTx) bool {
+		selected = append(selected, tx)
+		return len(selected) < 2
+	})
+	require.Equal(t, []testTx{txs[2], txs[4]}, selected)
+
+	// the iterator is a snapshot of the mempool
+	it := mp.Select(context.Background(), nil)
+	for _, tx := range txs {
+		require.NoError(t, mp.Remove(tx))
+	}
+	require.Zero(t, mp.CountTx())
+	require.Equal(t, txs[2], it.Tx())
+	require.Nil(t, mp.Select(context.Background(), nil))
+	require.ErrorIs(t, mp.Remove(txs[0]), ErrTxNotFound)
+}
+
+func TestPriorityNonceMempool_Replacement(t *testing.T) {
+	mp := newTestMempool(Config{PriceBump: 10})
+	tx := testTx{sender: "a", nonce: 1, fee: 100, gas: 10}
+	require.NoError(t, mp.Insert(context.Background(), tx))
+
+	// the fee per gas must be 10% higher
+	underpriced := testTx{sender: "a", nonce: 1, fee: 218, gas: 20}
+	require.ErrorIs(t, mp.Insert(context.Background(), underpriced), ErrTxUnderpriced)
+	replacement := testTx{sender: "a", nonce: 1, fee: 220, gas: 20}
+	require.NoError(t, mp.Insert(context.Background(), replacement))
+	require.Equal(t, []testTx{replacement}, selectTxs(t, mp))
+
+	// a tx with the same sender and nonce as a tx of the mempool removes it
+	require.NoError(t, mp.Remove(tx))
+	require.Zero(t, mp.CountTx())
+}
+
+func TestPriorityNonceMempool_Capacity(t *testing.T) {
+	mp := newTestMempool(Config{MaxTxs: 3})
+	txs := []testTx{
+		{sender: "a", nonce: 1, fee: 30, gas: 1},
+		{sender: "a", nonce: 2, fee: 10, gas: 1},
+		{sender: "b", nonce: 1, fee: 20, gas: 1},
+	}
+	for _, tx := range txs {
+		require.NoError(t, mp.Insert(context.Background(), tx))
+	}
+
+	// the lowest fee per gas is not higher
+	require.ErrorIs(t, mp.Insert(context.Background(), testTx{sender: "c", nonce: 1, fee: 10, gas: 1}), ErrMempoolTxMaxCapacity)
+	// the last tx of the sender with a lower nonce can't be evicted
+	require.ErrorIs(t, mp.Insert(context.Background(), testTx{sender: "a", nonce: 3, fee: 15, gas: 1}), ErrMempoolTxMaxCapacity)
+
+	// the last tx with the lowest fee per gas is evicted
+	tx := testTx{sender: "c", nonce: 1, fee: 15, gas: 1}
+	require.NoError(t, mp.Insert(context.Background(), tx))
+	require.Equal(t, []testTx{txs[0], txs[2], tx}, selectTxs(t, mp))
+
+	// a negative max disables the mempool
+	mp = newTestMempool(Config{MaxTxs: -1})
+	require.NoError(t, mp.Insert(context.Background(), tx))
+	require.Zero(t, mp.CountTx())
+}
+
+func TestPriorityNonceMempool_Recheck(t *testing.T) {
+	now := time.Now()
+	mp := newTestMempool(Config{TTL: 60})
+	mp.now = func() time.Time { return now }
+
+	txs := []testTx{
+		{sender: "a", nonce: 1, fee: 10, gas: 1},
+		{sender: "a", nonce: 2, fee: 10, gas: 1},
+		{sender: "a", nonce: 3, fee: 10, gas: 1},
+		{sender: "b", nonce: 1, fee: 10, gas: 1},
+		{sender: "b", nonce: 2, fee: 10, gas: 1},
+	}
+	for _, tx := range txs[:4] {
+		require.NoError(t, mp.Insert(context.Background(), tx))
+	}
+	now = now.Add(30 * time.Second)
+	require.NoError(t, mp.Insert(context.Background(), txs[4]))
+
+	// the first tx of a is invalid, only the first txs of every sender are validated
+	var validated []testTx
+	validate := func(_ context.Context, tx testTx) error {
+		validated = append(validated, tx)
+		if tx == txs[0] {
+			return errors.New("invalid")
+		}
+		return nil
+	}
+	mp.Recheck(context.Background(), validate)
+	require.ElementsMatch(t, []testTx{txs[0], txs[1], txs[3]}, validated)
+	require.Equal(t, []testTx{txs[1], txs[2], txs[3], txs[4]}, selectTxs(t, mp))
+
+	// the expired txs are evicted with the following txs of their sender
+	require.NoError(t, mp.Remove(txs[3]))
+	now = now.Add(45 * time.Second)
+	mp.Recheck(context.Background(), validate)
+	require.Equal(t, []testTx{txs[4]}, selectTxs(t, mp))
+}
+
+func TestPriorityNonceMempool_RecheckUnlocked(t *testing.T) {
+	mp := newTestMempool(Config{})
+	txs := []testTx{
+		{sender: "a", nonce: 1, fee: 10, gas: 1},
+		{sender: "a", nonce: 2, fee: 10, gas: 1},
+	}
+	require.NoError(t, mp.Insert(context.Background(), txs[0]))
+
+	// the mempool can be used during the validation, and a tx replaced meanwhile
+	// isn't removed
+	replacement := testTx{sender: "a", nonce: 1, fee: 20, gas: 1}
+	mp.Recheck(context.Background(), func(_ context.Context, tx testTx) error {
+		require.NoError(t, mp.Insert(context.Background(), replacement))
+		require.NoError(t, mp.Insert(context.Background(), txs[1]))
+		return errors.New("invalid")
+	})
+	require.Equal(t, []testTx{replacement, txs[1]}, selectTxs(t, mp))
+
+	// a cancelled recheck removes no tx
+	ctx, cancel := context.WithCancel(context.Background())
+	mp.Recheck(ctx, func(ctx context.Context, _ testTx) error {
+		cancel()
+		return ctx.Err()
+	})
+	require.Equal(t, 2, mp.CountTx())
+}
+
+func TestPriorityNonceMempool_Expiry(t *testing.T) {
+	now := time.Now()
+	mp := newTestMempool(Config{TTL: 60})
+	mp.now = func() time.Time { return now }
+
+	txs := []testTx{
+		{sender: "a", nonce: 1, fee: 10, gas: 1},
+		{sender: "b", nonce: 1, fee: 10, gas: 1},
+		{sender: "a", nonce: 2, fee: 10, gas: 1},
+		{sender: "c", nonce: 1, fee: 10, gas: 1},
+	}
+	for _, tx := range txs[:3] {
+		require.NoError(t, mp.Insert(context.Background(), tx))
+	}
+	require.NoError(t, mp.Remove(txs[1]))
+	now = now.Add(30 * time.Second)
+	require.NoError(t, mp.Insert(context.Background(), txs[3]))
+
+	// the expired txs are evicted without a recheck, with the following txs of
+	// their sender
+	now = now.Add(45 * time.Second)
+	require.Equal(t, []testTx{txs[3]}, selectTxs(t, mp))
+	require.Equal(t, 1, mp.CountTx())
+
+	// the removed txs are dropped from the insertion queue
+	for i := range 10 {
+		tx := testTx{sender: "d", nonce: uint64(i), fee: 10, gas: 1}
+		require.NoError(t, mp.Insert(context.Background(), tx))
+		require.NoError(t, mp.Remove(tx))
+	}
+	require.LessOrEqual(t, len(mp.byAge), 2*mp.CountTx()+1)
+}
+
+func TestCompareFeePerGas(t *testing.T) {
+	require.Equal(t, 0, compareFeePerGas(TxInfo{Fee: 10, GasLimit: 5}, TxInfo{Fee: 20, GasLimit: 10}))
+	require.Equal(t, 1, compareFeePerGas(TxInfo{Fee: 11, GasLimit: 5}, TxInfo{Fee: 20, GasLimit: 10}))
+	require.Equal(t, -1, compareFeePerGas(TxInfo{Fee: 1 << 63, GasLimit: 1 << 62}, TxInfo{Fee: 1<<63 + 1, GasLimit: 1 << 61}))
+	// a zero gas limit counts as 1
+	require.Equal(t, 0, compareFeePerGas(TxInfo{Fee: 10}, TxInfo{Fee: 10, GasLimit: 1}))
+}
+
+func TestPriorityNonceMempool_OnRemove(t *testing.T) {
+	now := time.Now()
+	mp := newTestMempool(Config{MaxTxs: 2, TTL: 60, PriceBump: 10})
+	mp.now = func() time.Time { return now }
+	var removed []testTx
+	mp.SetOnRemove(func(tx testTx) { removed = append(removed, tx) })
+
+	txs := []testTx{
+		{sender: "a", nonce: 1, fee: 10, gas: 1},
+		{sender: "b", nonce: 1, fee: 20, gas: 1},
+		{sender: "a", nonce: 1, fee: 30, gas: 1},
+		{sender: "c", nonce: 1, fee: 40, gas: 1},
+	}
+	require.NoError(t, mp.Insert(context.Background(), txs[0]))
+	require.NoError(t, mp.Insert(context.Background(), txs[1]))
+
+	// replaced, evicted, removed, expired and invalid txs are notified
+	require.NoError(t, mp.Insert(context.Background(), txs[2]))
+	require.Equal(t, []testTx{txs[0]}, removed)
+	require.NoError(t, mp.Insert(context.Background(), txs[3]))
+	require.Equal(t, []testTx{txs[0], txs[1]}, removed)
+	require.NoError(t, mp.Remove(txs[3]))
+	require.Equal(t, []testTx{txs[0], txs[1], txs[3]}, removed)
+
+	now = now.Add(30 * time.Second)
+	require.NoError(t, mp.Insert(context.Background(), txs[3]))
+	now = now.Add(45 * time.Second)
+	require.Equal(t, 1, mp.CountTx())
+	require.Equal(t, []testTx{txs[0], txs[1], txs[3], txs[2]}, removed)
+
+	mp.Recheck(context.Background(), func(context.Context, testTx) error { return errors.New("invalid") })
+	require.Zero(t, mp.CountTx())
+	require.Equal(t, []testTx{txs[0], txs[1], txs[3], txs[2], txs[3]}, removed)
+}
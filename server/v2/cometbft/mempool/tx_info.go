@@ -0,0 +1,102 @@
+package mempool
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+
+	"cosmossdk.io/core/transaction"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkmempool "github.com/cosmos/cosmos-sdk/types/mempool"
+)
+
+// TxInfo is the information a PriorityNonceMempool orders a transaction by.
+type TxInfo struct {
+	// Sender is the account whose nonce orders its transactions.
+	Sender string
+	// Nonce is the sequence number of the transaction for its sender.
+	Nonce uint64
+	// Fee is the fee paid by the transaction.
+	Fee uint64
+	// GasLimit is the gas limit of the transaction.
+	GasLimit uint64
+}
+
+// TxInfoFunc returns the information of a transaction.
+type TxInfoFunc[T transaction.Tx] func(ctx context.Context, tx T) (TxInfo, error)
+
+// SDKTxInfo returns the information of the transactions implementing sdk.FeeTx
+// and signing.SigVerifiableTx. The sender and nonce are the ones of the first
+// signer, the timeout timestamp being used as nonce for unordered transactions,
+// and the fee is the amount of feeDenom paid, capped to math.MaxUint64.
+func SDKTxInfo[T transaction.Tx](feeDenom string) TxInfoFunc[T] {
+	signerExtractor := sdkmempool.NewDefaultSignerExtractionAdapter()
+	return func(_ context.Context, tx T) (TxInfo, error) {
+		feeTx, ok := any(tx).(sdk.FeeTx)
+		if !ok {
+			return TxInfo{}, fmt.Errorf("tx of type %T does not implement FeeTx", tx)
+		}
+
+		sigs, err := signerExtractor.GetSigners(feeTx)
+		if err != nil {
+			return TxInfo{}, err
+		}
+		if len(sigs) == 0 {
+			return TxInfo{}, errors.New("tx must have at least one signer")
+		}
+
+		nonce := sigs[0].Sequence
+		if unordered, ok := any(tx).(sdk.TxWithUnordered); ok && unordered.GetUnordered() {
+			timestamp := unordered.GetTimeoutTimeStamp().Unix()
+			if timestamp < 0 {
+				return TxInfo{}, errors.New("invalid timestamp value")
+			}
+			nonce = uint64(timestamp)
+		}
+
+		fee := uint64(math.MaxUint64)
+		if amount := feeTx.GetFee().AmountOf(feeDenom); amount.IsUint64() {
+			fee = amount.Uint64()
+		}
+
+		return TxInfo{
+			Sender:   sigs[0].Signer.String(),
+			Nonce:    nonce,
+			Fee:      fee,
+			GasLimit: feeTx.GetGas(),
+		}, nil
+	}
+}
+
+// compareFeePerGas compares the fee per gas of two transactions. The result is
+// 0 if a == b, -1 if a < b, and +1 if a > b.
+func compareFeePerGas(a, b TxInfo) int {
+	// a.Fee / a.GasLimit is compared to b.Fee / b.GasLimit without division
+	// by comparing the 128 bits products a.Fee * b.GasLimit and b.Fee * a.GasLimit.
+	aHi, aLo := bits.Mul64(a.Fee, max(b.GasLimit, 1))
+	bHi, bLo := bits.Mul64(b.Fee, max(a.GasLimit, 1))
+	if aHi != bHi {
+		return cmp.Compare(aHi, bHi)
+	}
+	return cmp.Compare(aLo, bLo)
+}
+
+// isPriceBump reports whether the fee per gas of the replacing transaction
+// exceeds the one of the replaced transaction by at least priceBump percent.
+func isPriceBump(replaced, replacing TxInfo, priceBump uint64) bool {
+	// replacing.Fee / replacing.GasLimit >= replaced.Fee / replaced.GasLimit * (100 + priceBump) / 100
+	lhs := new(big.Int).SetUint64(replacing.Fee)
+	lhs.Mul(lhs, new(big.Int).SetUint64(max(replaced.GasLimit, 1)))
+	lhs.Mul(lhs, big.NewInt(100))
+
+	rhs := new(big.Int).SetUint64(replaced.Fee)
+	rhs.Mul(rhs, new(big.Int).SetUint64(max(replacing.GasLimit, 1)))
+	rhs.Mul(rhs, new(big.Int).SetUint64(100+priceBump))
+
+	return lhs.Cmp(rhs) >= 0
+}
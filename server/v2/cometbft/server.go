@@ -4,9 +4,11 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"sync/atomic"
 
@@ -38,6 +40,8 @@ import (
 	"cosmossdk.io/server/v2/cometbft/mempool"
 	"cosmossdk.io/server/v2/cometbft/oe"
 	"cosmossdk.io/server/v2/cometbft/types"
+	"cosmossdk.io/server/v2/streaming"
+	storedb "cosmossdk.io/store/v2/db"
 	"cosmossdk.io/store/v2/snapshots"
 
 	"github.com/cosmos/cosmos-sdk/client"
@@ -65,6 +69,10 @@ type CometBFTServer[T transaction.Tx] struct {
 	app     appmanager.AppManager[T]
 	txCodec transaction.Codec[T]
 	store   types.Store
+
+	indexerInfos   map[string]indexer.IndexerInfo
+	streamingSink  *streaming.Sink
+	mempoolJournal *mempool.Journal
 }
 
 // AppCodecs contains all codecs that the CometBFT server requires
@@ -154,14 +162,47 @@ func New[T transaction.Tx](
 			Config:       indexerCfg,
 			Resolver:     decoderResolver,
 			Logger:       logger.With(log.ModuleKey, "indexer"),
-			SyncSource:   nil, // TODO: Support catch-up syncs
+			SyncSource:   newIndexerSyncSource(store, decoderResolver),
 			AddressCodec: appCodecs.AppCodec.InterfaceRegistry().SigningContext().AddressCodec(),
+			EventTypes:   typedEventTypes(),
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to start indexing: %w", err)
 		}
 
 		listener = &indexingTarget.Listener
+		srv.indexerInfos = indexingTarget.IndexerInfos
+	}
+
+	// initialize the streaming sink
+	streamingManager := srv.serverOptions.StreamingManager
+	if sinkCfg := srv.config.AppTomlConfig.StreamingSink; sinkCfg.Enable {
+		dir := sinkCfg.Dir
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(srv.config.ConfigTomlConfig.RootDir, dir)
+		}
+		sink, err := streaming.NewSink(sinkCfg, dir, logger.With(log.ModuleKey, "streaming-sink"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create streaming sink: %w", err)
+		}
+		streamingManager.Listeners = append(slices.Clone(streamingManager.Listeners), sink)
+		srv.streamingSink = sink
+	}
+
+	mp := srv.serverOptions.Mempool(cfg)
+
+	// open the mempool journal, unless the app-side mempool is disabled
+	_, isNoOpMempool := mp.(mempool.NoOpMempool[T])
+	if mempoolCfg := srv.config.AppTomlConfig.Mempool; mempoolCfg.JournalDir != "" && mempoolCfg.MaxTxs >= 0 && !isNoOpMempool {
+		dir := mempoolCfg.JournalDir
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(srv.config.ConfigTomlConfig.RootDir, dir)
+		}
+		db, err := storedb.NewDB(storedb.DBType(mempoolCfg.JournalBackend), "mempool_journal", dir, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open mempool journal: %w", err)
+		}
+		srv.mempoolJournal = mempool.NewJournal(db, mempoolCfg.JournalTTL)
 	}
 
 	// snapshot manager
@@ -188,8 +229,8 @@ func New[T transaction.Tx](
 		appCodecs:              appCodecs,
 		listener:               listener,
 		snapshotManager:        snapshotManager,
-		streamingManager:       srv.serverOptions.StreamingManager,
-		mempool:                srv.serverOptions.Mempool(cfg),
+		streamingManager:       streamingManager,
+		mempool:                mp,
 		lastCommittedHeight:    atomic.Int64{},
 		prepareProposalHandler: srv.serverOptions.PrepareProposalHandler,
 		processProposalHandler: srv.serverOptions.ProcessProposalHandler,
@@ -206,6 +247,10 @@ func New[T transaction.Tx](
 		cfgMap:                 cfg,
 	}
 
+	if srv.mempoolJournal != nil {
+		c.setMempoolJournal(srv.mempoolJournal)
+	}
+
 	c.optimisticExec = oe.NewOptimisticExecution(
 		logger,
 		c.internalFinalizeBlock,
@@ -225,11 +270,23 @@ func NewWithConfigOptions[T transaction.Tx](opts ...CfgOption) *CometBFTServer[T
 	}
 }
 
+// IndexerInfos returns the information of the indexer targets started by the server, keyed by target name.
+// It is empty when indexing is not configured.
+func (s *CometBFTServer[T]) IndexerInfos() map[string]indexer.IndexerInfo {
+	return s.indexerInfos
+}
+
 func (s *CometBFTServer[T]) Name() string {
 	return ServerName
 }
 
 func (s *CometBFTServer[T]) Start(ctx context.Context) error {
+	if s.mempoolJournal != nil {
+		if err := s.replayMempoolJournal(ctx); err != nil {
+			return err
+		}
+	}
+
 	wrappedLogger := cometlog.CometLoggerWrapper{Logger: s.logger}
 	if s.config.AppTomlConfig.Standalone {
 		svr, err := abciserver.NewServer(s.config.AppTomlConfig.Address, s.config.AppTomlConfig.Transport, s.Consensus)
@@ -276,11 +333,39 @@ func (s *CometBFTServer[T]) Start(ctx context.Context) error {
 }
 
 func (s *CometBFTServer[T]) Stop(context.Context) error {
+	var err error
 	if s.Node != nil && s.Node.IsRunning() {
 		s.logger.Info("stopping consensus server")
-		return s.Node.Stop()
+		err = s.Node.Stop()
+	}
+	if s.streamingSink != nil {
+		err = errors.Join(err, s.streamingSink.Close())
+	}
+	if s.mempoolJournal != nil {
+		err = errors.Join(err, s.mempoolJournal.Close())
+	}
+
+	return err
+}
+
+// replayMempoolJournal replays the journaled mempool txs through CheckTx, so
+// that the pending txs of the app-side mempool survive a restart.
+func (s *CometBFTServer[T]) replayMempoolJournal(ctx context.Context) error {
+	replayed, err := s.mempoolJournal.Replay(func(txBytes []byte) error {
+		resp, err := s.Consensus.CheckTx(ctx, &abci.CheckTxRequest{Tx: txBytes, Type: abci.CHECK_TX_TYPE_CHECK})
+		if err != nil {
+			return err
+		}
+		if resp.Code != abci.CodeTypeOK {
+			return fmt.Errorf("tx check failed with code %d: %s", resp.Code, resp.Log)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replay mempool journal: %w", err)
 	}
 
+	s.logger.Info("replayed mempool journal", "txs", replayed)
 	return nil
 }
 
@@ -332,6 +417,11 @@ func (s *CometBFTServer[T]) StartCmdFlags() *pflag.FlagSet {
 	flags.Bool(FlagTrace, false, "Provide full stack traces for errors in ABCI Log")
 	flags.Bool(Standalone, false, "Run app without CometBFT")
 	flags.Int(FlagMempoolMaxTxs, mempool.DefaultMaxTx, "Sets MaxTx value for the app-side mempool")
+	flags.Uint64(FlagMempoolTTL, mempool.DefaultConfig().TTL, "Number of seconds after which a tx is evicted from the app-side mempool (0 disables it)")
+	flags.Uint64(FlagMempoolPriceBump, mempool.DefaultConfig().PriceBump, "Minimum fee per gas increase, in percent, of a tx replacing another one in the app-side mempool")
+	flags.String(FlagMempoolJournalDir, mempool.DefaultConfig().JournalDir, "Directory of the on-disk journal of the app-side mempool txs, replayed on startup (empty disables it)")
+	flags.String(FlagMempoolJournalBackend, mempool.DefaultConfig().JournalBackend, "Database backend of the app-side mempool journal (goleveldb, pebbledb)")
+	flags.Uint64(FlagMempoolJournalTTL, mempool.DefaultConfig().JournalTTL, "Number of seconds after which a tx is removed from the app-side mempool journal (0 disables it)")
 
 	// add comet flags, we use an empty command to avoid duplicating CometBFT's AddNodeFlags.
 	// we can then merge the flag sets.
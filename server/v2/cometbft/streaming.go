@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 
+	gogoproto "github.com/cosmos/gogoproto/proto"
+
 	"cosmossdk.io/core/event"
 	"cosmossdk.io/core/server"
 	"cosmossdk.io/core/store"
@@ -157,6 +159,25 @@ func doServeHookListener[T transaction.Tx](
 				JSON: func() (json.RawMessage, error) {
 					return json.Marshal(decodedTXs[i])
 				},
+				Hash: func() ([]byte, error) {
+					hash := decodedTXs[i].Hash()
+					return hash[:], nil
+				},
+				Signers: func() ([][]byte, error) {
+					return decodedTXs[i].GetSenders()
+				},
+				MsgTypeURLs: func() ([]string, error) {
+					msgs, err := decodedTXs[i].GetMessages()
+					if err != nil {
+						return nil, err
+					}
+
+					typeURLs := make([]string, len(msgs))
+					for j, msg := range msgs {
+						typeURLs[j] = "/" + gogoproto.MessageName(msg)
+					}
+					return typeURLs, nil
+				},
 			}); err != nil {
 				return err
 			}
@@ -9,7 +9,8 @@ import (
 
 // ServerConfig defines configuration for the server component.
 type ServerConfig struct {
-	MinGasPrices string `mapstructure:"minimum-gas-prices" toml:"minimum-gas-prices" comment:"minimum-gas-prices defines the price which a validator is willing to accept for processing a transaction. A transaction's fees must meet the minimum of any denomination specified in this config (e.g. 0.25token1;0.0001token2)."`
+	MinGasPrices             string `mapstructure:"minimum-gas-prices" toml:"minimum-gas-prices" comment:"minimum-gas-prices defines the price which a validator is willing to accept for processing a transaction. A transaction's fees must meet the minimum of any denomination specified in this config (e.g. 0.25token1;0.0001token2)."`
+	ParallelExecutionWorkers int    `mapstructure:"parallel-execution-workers" toml:"parallel-execution-workers" comment:"parallel-execution-workers defines the number of workers executing the transactions of a block optimistically in parallel. The transactions are executed in order when it is lower than 2."`
 }
 
 // DefaultServerConfig returns the default config of server component
@@ -13,6 +13,8 @@ var (
 	FlagMinGasPrices       = prefix("minimum-gas-prices")
 	FlagCPUProfiling       = prefix("cpu-profile")
 	FlagUnsafeSkipUpgrades = prefix("unsafe-skip-upgrades")
+
+	FlagParallelExecutionWorkers = prefix("parallel-execution-workers")
 )
 
 const (
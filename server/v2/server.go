@@ -196,6 +196,7 @@ func (s *Server[T]) StartCmdFlags() *pflag.FlagSet {
 	flags.String(FlagMinGasPrices, "", "Minimum gas prices to accept for transactions; Any fee in a tx must meet this minimum (e.g. 0.01photino;0.0001stake)")
 	flags.String(FlagCPUProfiling, "", "Enable CPU profiling and write to the specified file")
 	flags.IntSlice(FlagUnsafeSkipUpgrades, []int{}, "Skip a set of upgrade heights to continue the old binary")
+	flags.Int(FlagParallelExecutionWorkers, 0, "Number of workers executing the transactions of a block in parallel, they are executed in order when lower than 2")
 
 	return flags
 }
@@ -0,0 +1,432 @@
+package stf
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/tidwall/btree"
+
+	"cosmossdk.io/core/store"
+	"cosmossdk.io/server/v2/stf/branch"
+)
+
+// mvMemory is the multi-version memory of the parallel execution of a block. It
+// holds the writes of every tx of the block, so that a tx reads the last value
+// written by the txs which precede it in the block, or the block state if no
+// such tx wrote the key.
+type mvMemory struct {
+	mtx    sync.RWMutex
+	actors map[string]*btree.BTreeG[*mvKey]
+	// written holds the last changes recorded for every tx.
+	written [][]store.StateChanges
+}
+
+// mvKey holds the writes of a key, ordered by tx index.
+type mvKey struct {
+	key    []byte
+	writes []mvWrite
+}
+
+// mvWrite is the value written to a key by a tx, nil if the key is deleted.
+type mvWrite struct {
+	txIndex int
+	value   []byte
+}
+
+func newMVMemory(txs int) *mvMemory {
+	return &mvMemory{
+		actors:  make(map[string]*btree.BTreeG[*mvKey]),
+		written: make([][]store.StateChanges, txs),
+	}
+}
+
+func mvKeyLess(a, b *mvKey) bool {
+	return bytes.Compare(a.key, b.key) < 0
+}
+
+// latest returns the last write which precedes the tx at txIndex.
+func (k *mvKey) latest(txIndex int) (mvWrite, bool) {
+	i := sort.Search(len(k.writes), func(i int) bool { return k.writes[i].txIndex >= txIndex })
+	if i == 0 {
+		return mvWrite{}, false
+	}
+	return k.writes[i-1], true
+}
+
+// read returns the value of the key written by the last tx which precedes the
+// tx at txIndex, and false if no such tx wrote the key.
+func (m *mvMemory) read(actor, key []byte, txIndex int) ([]byte, bool) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	tree, ok := m.actors[unsafeString(actor)]
+	if !ok {
+		return nil, false
+	}
+	item, ok := tree.Get(&mvKey{key: key})
+	if !ok {
+		return nil, false
+	}
+	write, ok := item.latest(txIndex)
+	return write.value, ok
+}
+
+// snapshot returns the last values of the keys of the [start, end) range which
+// are written by the txs preceding the tx at txIndex.
+func (m *mvMemory) snapshot(actor, start, end []byte, txIndex int) []store.KVPair {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	tree, ok := m.actors[unsafeString(actor)]
+	if !ok {
+		return nil
+	}
+	var pairs []store.KVPair
+	iter := func(item *mvKey) bool {
+		if end != nil && bytes.Compare(item.key, end) >= 0 {
+			return false
+		}
+		if write, ok := item.latest(txIndex); ok {
+			pairs = append(pairs, store.KVPair{Key: item.key, Value: write.value, Remove: write.value == nil})
+		}
+		return true
+	}
+	if start == nil {
+		tree.Scan(iter)
+	} else {
+		tree.Ascend(&mvKey{key: start}, iter)
+	}
+	return pairs
+}
+
+// record replaces the writes of the tx at txIndex by the given changes.
+func (m *mvMemory) record(txIndex int, changes []store.StateChanges) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for _, sc := range m.written[txIndex] {
+		tree := m.actors[unsafeString(sc.Actor)]
+		for _, kv := range sc.StateChanges {
+			item, _ := tree.Get(&mvKey{key: kv.Key})
+			i := sort.Search(len(item.writes), func(i int) bool { return item.writes[i].txIndex >= txIndex })
+			item.writes = append(item.writes[:i], item.writes[i+1:]...)
+		}
+	}
+
+	for _, sc := range changes {
+		tree, ok := m.actors[unsafeString(sc.Actor)]
+		if !ok {
+			tree = btree.NewBTreeGOptions(mvKeyLess, btree.Options{NoLocks: true})
+			m.actors[string(sc.Actor)] = tree
+		}
+		for _, kv := range sc.StateChanges {
+			item, ok := tree.Get(&mvKey{key: kv.Key})
+			if !ok {
+				item = &mvKey{key: kv.Key}
+				tree.Set(item)
+			}
+			value := kv.Value
+			if kv.Remove {
+				value = nil
+			}
+			i := sort.Search(len(item.writes), func(i int) bool { return item.writes[i].txIndex >= txIndex })
+			item.writes = append(item.writes, mvWrite{})
+			copy(item.writes[i+1:], item.writes[i:])
+			item.writes[i] = mvWrite{txIndex: txIndex, value: value}
+		}
+	}
+	m.written[txIndex] = changes
+}
+
+// mvRead is a read of a tx: the value of a key, or the pairs of a range read by
+// an iterator.
+type mvRead struct {
+	actor []byte
+	key   []byte
+	value []byte
+	// iterator is set for the reads of an iterator.
+	iterator *iteratorRead
+}
+
+// iteratorRead holds the pairs read by an iterator, and whether the iterator was
+// exhausted after them.
+type iteratorRead struct {
+	start, end []byte
+	ascending  bool
+	pairs      []store.KVPair
+	exhausted  bool
+}
+
+// readSet holds the reads of an execution of a tx.
+type readSet struct {
+	reads []mvRead
+}
+
+func (r *readSet) add(read mvRead) {
+	if r != nil {
+		r.reads = append(r.reads, read)
+	}
+}
+
+// mvView is the state read by the tx at txIndex: the multi-version memory over
+// the block state. The reads are recorded in the read set, if any.
+type mvView struct {
+	mem     *mvMemory
+	base    *lockedState
+	txIndex int
+	reads   *readSet
+}
+
+var _ store.ReaderMap = mvView{}
+
+// GetReader implements store.ReaderMap.
+func (v mvView) GetReader(actor []byte) (store.Reader, error) {
+	base, err := v.base.GetReader(actor)
+	if err != nil {
+		return nil, err
+	}
+	return mvReader{view: v, actor: bytes.Clone(actor), base: base}, nil
+}
+
+// validate reports whether the reads still return the values recorded, so that
+// an execution with these reads would be identical.
+func (v mvView) validate(reads *readSet) (bool, error) {
+	for _, read := range reads.reads {
+		reader, err := v.GetReader(read.actor)
+		if err != nil {
+			return false, err
+		}
+		r := reader.(mvReader)
+		if read.iterator != nil {
+			valid, err := r.validateIterator(read.iterator)
+			if err != nil || !valid {
+				return false, err
+			}
+			continue
+		}
+		value, err := r.get(read.key)
+		if err != nil {
+			return false, err
+		}
+		if (value == nil) != (read.value == nil) || !bytes.Equal(value, read.value) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// mvReader is the reader of an actor of a mvView.
+type mvReader struct {
+	view  mvView
+	actor []byte
+	base  store.Reader
+}
+
+var _ store.Reader = mvReader{}
+
+func (r mvReader) get(key []byte) ([]byte, error) {
+	if value, found := r.view.mem.read(r.actor, key, r.view.txIndex); found {
+		return value, nil
+	}
+	return r.base.Get(key)
+}
+
+// Get implements store.Reader.
+func (r mvReader) Get(key []byte) ([]byte, error) {
+	value, err := r.get(key)
+	if err != nil {
+		return nil, err
+	}
+	r.view.reads.add(mvRead{actor: r.actor, key: bytes.Clone(key), value: value})
+	return value, nil
+}
+
+// Has implements store.Reader.
+func (r mvReader) Has(key []byte) (bool, error) {
+	value, err := r.Get(key)
+	return value != nil, err
+}
+
+// Iterator implements store.Reader.
+func (r mvReader) Iterator(start, end []byte) (store.Iterator, error) {
+	return r.iterator(start, end, true)
+}
+
+// ReverseIterator implements store.Reader.
+func (r mvReader) ReverseIterator(start, end []byte) (store.Iterator, error) {
+	return r.iterator(start, end, false)
+}
+
+func (r mvReader) iterator(start, end []byte, ascending bool) (store.Iterator, error) {
+	itr, err := r.newIterator(start, end, ascending)
+	if err != nil {
+		return nil, err
+	}
+	read := &iteratorRead{start: bytes.Clone(start), end: bytes.Clone(end), ascending: ascending}
+	r.view.reads.add(mvRead{actor: r.actor, iterator: read})
+	return newRecordingIterator(itr, read), nil
+}
+
+// newIterator merges the writes of the preceding txs in the range with the
+// block state.
+func (r mvReader) newIterator(start, end []byte, ascending bool) (store.Iterator, error) {
+	s := branch.NewStore[store.Reader](r.base)
+	if err := s.ApplyChangeSets(r.view.mem.snapshot(r.actor, start, end, r.view.txIndex)); err != nil {
+		return nil, err
+	}
+	if ascending {
+		return s.Iterator(start, end)
+	}
+	return s.ReverseIterator(start, end)
+}
+
+// validateIterator reports whether an iterator of the range still reads the
+// pairs recorded.
+func (r mvReader) validateIterator(read *iteratorRead) (bool, error) {
+	itr, err := r.newIterator(read.start, read.end, read.ascending)
+	if err != nil {
+		return false, err
+	}
+	defer itr.Close()
+
+	for _, pair := range read.pairs {
+		if !itr.Valid() || !bytes.Equal(itr.Key(), pair.Key) || !bytes.Equal(itr.Value(), pair.Value) {
+			return false, nil
+		}
+		itr.Next()
+	}
+	if read.exhausted && itr.Valid() {
+		return false, nil
+	}
+	return true, itr.Error()
+}
+
+// recordingIterator records the pairs read by an iterator.
+type recordingIterator struct {
+	store.Iterator
+	read *iteratorRead
+}
+
+func newRecordingIterator(itr store.Iterator, read *iteratorRead) *recordingIterator {
+	i := &recordingIterator{Iterator: itr, read: read}
+	i.record()
+	return i
+}
+
+// Next implements store.Iterator.
+func (i *recordingIterator) Next() {
+	i.Iterator.Next()
+	i.record()
+}
+
+func (i *recordingIterator) record() {
+	if !i.Iterator.Valid() {
+		i.read.exhausted = true
+		return
+	}
+	i.read.pairs = append(i.read.pairs, store.KVPair{Key: bytes.Clone(i.Iterator.Key()), Value: i.Iterator.Value()})
+}
+
+// lockedState serializes the reads of the block state, which isn't safe for
+// concurrent use, by the txs executed in parallel. The block state must not be
+// written while it is read.
+type lockedState struct {
+	mtx   sync.Mutex
+	state store.ReaderMap
+}
+
+// GetReader implements store.ReaderMap.
+func (l *lockedState) GetReader(actor []byte) (store.Reader, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	reader, err := l.state.GetReader(actor)
+	if err != nil {
+		return nil, err
+	}
+	return lockedReader{mtx: &l.mtx, reader: reader}, nil
+}
+
+type lockedReader struct {
+	mtx    *sync.Mutex
+	reader store.Reader
+}
+
+func (l lockedReader) Has(key []byte) (bool, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.reader.Has(key)
+}
+
+func (l lockedReader) Get(key []byte) ([]byte, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.reader.Get(key)
+}
+
+func (l lockedReader) Iterator(start, end []byte) (store.Iterator, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	itr, err := l.reader.Iterator(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return lockedIterator{mtx: l.mtx, itr: itr}, nil
+}
+
+func (l lockedReader) ReverseIterator(start, end []byte) (store.Iterator, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	itr, err := l.reader.ReverseIterator(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return lockedIterator{mtx: l.mtx, itr: itr}, nil
+}
+
+type lockedIterator struct {
+	mtx *sync.Mutex
+	itr store.Iterator
+}
+
+func (l lockedIterator) Domain() (start, end []byte) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.itr.Domain()
+}
+
+func (l lockedIterator) Valid() bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.itr.Valid()
+}
+
+func (l lockedIterator) Next() {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.itr.Next()
+}
+
+func (l lockedIterator) Key() []byte {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.itr.Key()
+}
+
+func (l lockedIterator) Value() []byte {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.itr.Value()
+}
+
+func (l lockedIterator) Error() error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.itr.Error()
+}
+
+func (l lockedIterator) Close() error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.itr.Close()
+}
@@ -0,0 +1,166 @@
+package stf
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cosmossdk.io/core/header"
+	"cosmossdk.io/core/server"
+	"cosmossdk.io/core/store"
+	"cosmossdk.io/core/transaction"
+)
+
+// Option configures the STF.
+type Option func(*options)
+
+type options struct {
+	parallelWorkers int
+}
+
+// WithParallelExecution executes the txs of a block optimistically in parallel
+// on the given number of workers, the txs are executed in order when it is lower
+// than 2.
+//
+// Every tx is executed speculatively on a multi-version view of the state, made
+// of the writes of the txs which precede it in the block over the block state,
+// while its reads are recorded. The txs are then validated in order: a tx whose
+// reads don't return the same values once the txs preceding it are final is
+// executed again. The results and the state are identical to an execution in
+// order, the txs conflicting with each other being executed in order.
+//
+// The modules must only share state through the store: a module keeping state
+// in memory across the txs of a block must not be used with the parallel
+// execution.
+func WithParallelExecution(workers int) Option {
+	return func(o *options) {
+		o.parallelWorkers = workers
+	}
+}
+
+// txExecution is the execution of a tx of a block.
+type txExecution struct {
+	result  server.TxResult
+	reads   *readSet
+	changes []store.StateChanges
+	err     error
+}
+
+// parallelExecution executes the txs of a block in parallel.
+type parallelExecution[T transaction.Tx] struct {
+	stf        STF[T]
+	ctx        context.Context
+	hi         header.Info
+	txs        []T
+	mem        *mvMemory
+	base       *lockedState
+	executions []txExecution
+}
+
+// doParallelDeliverTXs executes the txs of a block in parallel, see WithParallelExecution.
+func (s STF[T]) doParallelDeliverTXs(
+	exCtx context.Context,
+	txs []T,
+	newState store.WriterMap,
+	hi header.Info,
+) ([]server.TxResult, error) {
+	if s.parallelWorkers < 2 || len(txs) < 2 {
+		return s.doDeliverTXs(exCtx, txs, newState, hi)
+	}
+
+	start := time.Now()
+	p := &parallelExecution[T]{
+		stf:        s,
+		ctx:        exCtx,
+		hi:         hi,
+		txs:        txs,
+		mem:        newMVMemory(len(txs)),
+		base:       &lockedState{state: newState},
+		executions: make([]txExecution, len(txs)),
+	}
+
+	// execute all the txs speculatively
+	var (
+		next atomic.Int64
+		wg   sync.WaitGroup
+	)
+	for range min(s.parallelWorkers, len(txs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(txs) || isCtxCancelled(exCtx) != nil {
+					return
+				}
+				p.execute(i)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := isCtxCancelled(exCtx); err != nil {
+		return nil, err
+	}
+
+	// validate the txs in order, all the txs preceding a tx being final its
+	// execution is final once its reads are valid
+	reexecuted := 0
+	for i := range txs {
+		if err := isCtxCancelled(exCtx); err != nil {
+			return nil, err
+		}
+		valid, err := p.validate(i)
+		if err != nil {
+			return nil, err
+		}
+		if !valid {
+			p.execute(i)
+			reexecuted++
+		}
+		if err := p.executions[i].err; err != nil {
+			return nil, fmt.Errorf("failed to execute tx %d: %w", i, err)
+		}
+	}
+
+	txResults := make([]server.TxResult, len(txs))
+	for i, execution := range p.executions {
+		if err := newState.ApplyStateChanges(execution.changes); err != nil {
+			return nil, fmt.Errorf("failed to apply the state changes of tx %d: %w", i, err)
+		}
+		txResults[i] = execution.result
+	}
+
+	s.logger.Debug("executed txs in parallel", "txs", len(txs), "reexecuted", reexecuted, "duration", time.Since(start))
+	return txResults, nil
+}
+
+// execute executes the tx at index i on the multi-version view of the state
+// and records its writes.
+func (p *parallelExecution[T]) execute(i int) {
+	reads := &readSet{}
+	txState := p.stf.branchFn(mvView{mem: p.mem, base: p.base, txIndex: i, reads: reads})
+	result := p.stf.deliverTx(p.ctx, txState, p.txs[i], transaction.ExecModeFinalize, p.hi, int32(i+1))
+	changes, err := txState.GetStateChanges()
+	if err != nil {
+		p.executions[i] = txExecution{err: err}
+		return
+	}
+	p.mem.record(i, changes)
+	p.executions[i] = txExecution{
+		result:  result,
+		reads:   reads,
+		changes: changes,
+	}
+}
+
+// validate reports whether the reads of the last execution of the tx at index i
+// return the values written by the txs preceding it.
+func (p *parallelExecution[T]) validate(i int) (bool, error) {
+	execution := p.executions[i]
+	if execution.err != nil {
+		return false, nil
+	}
+	return mvView{mem: p.mem, base: p.base, txIndex: i}.validate(execution.reads)
+}
@@ -0,0 +1,385 @@
+package stf
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	gogotypes "github.com/cosmos/gogoproto/types"
+
+	appmodulev2 "cosmossdk.io/core/appmodule/v2"
+	"cosmossdk.io/core/event"
+	"cosmossdk.io/core/server"
+	"cosmossdk.io/core/store"
+	coretesting "cosmossdk.io/core/testing"
+	"cosmossdk.io/core/transaction"
+	"cosmossdk.io/server/v2/stf/branch"
+	"cosmossdk.io/server/v2/stf/gas"
+	"cosmossdk.io/server/v2/stf/mock"
+)
+
+var (
+	bankActor = []byte("bank")
+	authActor = []byte("auth")
+	feeActor  = []byte("fee_collector")
+	feeKey    = []byte("fees")
+	supplyKey = []byte("supply")
+)
+
+// emptyState is a state whose actors are empty.
+type emptyState struct{}
+
+func (emptyState) GetReader([]byte) (store.Reader, error) {
+	return coretesting.NewMemKV(), nil
+}
+
+func getUint(ctx context.Context, actor, key []byte) (uint64, error) {
+	state, err := ctx.(*executionContext).state.GetWriter(actor)
+	if err != nil {
+		return 0, err
+	}
+	bz, err := state.Get(key)
+	if err != nil || bz == nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(bz), nil
+}
+
+func setUint(ctx context.Context, actor, key []byte, value uint64) error {
+	state, err := ctx.(*executionContext).state.GetWriter(actor)
+	if err != nil {
+		return err
+	}
+	return state.Set(key, binary.BigEndian.AppendUint64(nil, value))
+}
+
+// addUint adds the delta to the value of the key and returns the previous value.
+func addUint(ctx context.Context, actor, key []byte, delta int64) (uint64, error) {
+	value, err := getUint(ctx, actor, key)
+	if err != nil {
+		return 0, err
+	}
+	if delta < 0 && value < uint64(-delta) {
+		return value, fmt.Errorf("insufficient funds: %d < %d", value, -delta)
+	}
+	return value, setUint(ctx, actor, key, uint64(int64(value)+delta))
+}
+
+// newBankSTF returns a STF whose txs send coins, audit the supply by iterating
+// over the balances and pay fees to a single account.
+func newBankSTF(t *testing.T, workers int) *STF[mock.Tx] {
+	t.Helper()
+	s := &STF[mock.Tx]{
+		logger:            coretesting.NewNopLogger(),
+		doPreBlock:        func(ctx context.Context, txs []mock.Tx) error { return nil },
+		doBeginBlock:      func(ctx context.Context) error { return nil },
+		doEndBlock:        func(ctx context.Context) error { return nil },
+		doValidatorUpdate: func(ctx context.Context) ([]appmodulev2.ValidatorUpdate, error) { return nil, nil },
+		doTxValidation: func(ctx context.Context, tx mock.Tx) error {
+			_, err := addUint(ctx, authActor, tx.Sender, 1)
+			return err
+		},
+		postTxExec: func(ctx context.Context, tx mock.Tx, success bool) error {
+			// only the txs with a higher gas limit pay fees
+			if tx.GasLimit > 100_000 {
+				_, err := addUint(ctx, feeActor, feeKey, 1)
+				return err
+			}
+			return nil
+		},
+		branchFn:            branch.DefaultNewWriterMap,
+		makeGasMeter:        gas.DefaultGasMeter,
+		makeGasMeteredState: gas.DefaultWrapWithGasMeter,
+		parallelWorkers:     workers,
+	}
+
+	msgRouterBuilder := NewMsgRouterBuilder()
+	err := msgRouterBuilder.RegisterHandler(
+		msgTypeURL(&gogotypes.StringValue{}),
+		func(ctx context.Context, msg transaction.Msg) (transaction.Msg, error) {
+			sender := ctx.(*executionContext).sender
+			to, amountStr, _ := strings.Cut(msg.(*gogotypes.StringValue).Value, ":")
+			amount, err := strconv.ParseInt(amountStr, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			balance, err := addUint(ctx, bankActor, sender, -amount)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := addUint(ctx, bankActor, []byte(to), amount); err != nil {
+				return nil, err
+			}
+			ctx.(*executionContext).events = append(ctx.(*executionContext).events,
+				event.NewEvent("transfer", event.NewAttribute("to", to), event.NewAttribute("amount", amountStr)))
+			return &gogotypes.UInt64Value{Value: balance}, nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to register handler: %v", err)
+	}
+	err = msgRouterBuilder.RegisterHandler(
+		msgTypeURL(&gogotypes.BoolValue{}),
+		func(ctx context.Context, msg transaction.Msg) (transaction.Msg, error) {
+			state, err := ctx.(*executionContext).state.GetWriter(bankActor)
+			if err != nil {
+				return nil, err
+			}
+			itr, err := state.ReverseIterator(nil, nil)
+			if err != nil {
+				return nil, err
+			}
+			defer itr.Close()
+			var supply uint64
+			for ; itr.Valid(); itr.Next() {
+				supply += binary.BigEndian.Uint64(itr.Value())
+			}
+			if err := setUint(ctx, feeActor, supplyKey, supply); err != nil {
+				return nil, err
+			}
+			return &gogotypes.UInt64Value{Value: supply}, nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to register handler: %v", err)
+	}
+	msgRouter, err := msgRouterBuilder.build()
+	if err != nil {
+		t.Fatalf("Failed to build message router: %v", err)
+	}
+	s.msgRouter = msgRouter
+	return s
+}
+
+func TestParallelExecution(t *testing.T) {
+	const accounts = 20
+	account := func(i int) string { return fmt.Sprintf("account%02d", i) }
+
+	// the genesis balances
+	genesis := branch.DefaultNewWriterMap(emptyState{})
+	bank, err := genesis.GetWriter(bankActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range accounts {
+		if err := bank.Set([]byte(account(i)), binary.BigEndian.AppendUint64(nil, 100)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	txs := make([]mock.Tx, 300)
+	for i := range txs {
+		tx := mock.Tx{
+			Sender:   []byte(account(r.Intn(accounts))),
+			GasLimit: 100_000,
+		}
+		switch n := r.Intn(100); {
+		case n < 2:
+			tx.Msg = &gogotypes.BoolValue{Value: true}
+		case n < 5:
+			// out of gas
+			tx.Msg = &gogotypes.StringValue{Value: account(r.Intn(accounts)) + ":1"}
+			tx.GasLimit = 10
+		default:
+			tx.Msg = &gogotypes.StringValue{Value: fmt.Sprintf("%s:%d", account(r.Intn(accounts)), r.Intn(60))}
+		}
+		if r.Intn(10) == 0 {
+			tx.GasLimit = 200_000
+		}
+		txs[i] = tx
+	}
+
+	sum := sha256.Sum256([]byte("test-hash"))
+	deliver := func(workers int, txs []mock.Tx) (*server.BlockResponse, store.WriterMap) {
+		t.Helper()
+		s := newBankSTF(t, workers)
+		result, newState, err := s.DeliverBlock(context.Background(), &server.BlockRequest[mock.Tx]{
+			Height:  1,
+			Time:    time.Date(2024, 2, 3, 18, 23, 0, 0, time.UTC),
+			AppHash: sum[:],
+			Hash:    sum[:],
+			Txs:     txs,
+		}, genesis)
+		if err != nil {
+			t.Fatalf("DeliverBlock error: %v", err)
+		}
+		return result, newState
+	}
+
+	for _, tc := range []struct {
+		name string
+		txs  []mock.Tx
+	}{
+		{"mixed", txs},
+		{"single tx", txs[:1]},
+		{"same sender", func() []mock.Tx {
+			txs := make([]mock.Tx, 50)
+			for i := range txs {
+				txs[i] = mock.Tx{Sender: []byte(account(0)), Msg: &gogotypes.StringValue{Value: account(i%accounts) + ":3"}, GasLimit: 100_000}
+			}
+			return txs
+		}()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			expResult, expState := deliver(0, tc.txs)
+			for range 3 {
+				result, state := deliver(8, tc.txs)
+				requireSameTxResults(t, expResult.TxResults, result.TxResults)
+				requireSameState(t, expState, state)
+			}
+		})
+	}
+}
+
+func requireSameTxResults(t *testing.T, expected, actual []server.TxResult) {
+	t.Helper()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %d TxResults, got %d", len(expected), len(actual))
+	}
+	for i := range expected {
+		exp, act := expected[i], actual[i]
+		if exp.GasUsed != act.GasUsed || exp.GasWanted != act.GasWanted {
+			t.Fatalf("tx %d: expected gas %d/%d, got %d/%d", i, exp.GasUsed, exp.GasWanted, act.GasUsed, act.GasWanted)
+		}
+		if !reflect.DeepEqual(exp.Resp, act.Resp) {
+			t.Fatalf("tx %d: expected responses %v, got %v", i, exp.Resp, act.Resp)
+		}
+		if fmt.Sprint(exp.Error) != fmt.Sprint(act.Error) {
+			t.Fatalf("tx %d: expected error %v, got %v", i, exp.Error, act.Error)
+		}
+		if len(act.Events) != len(exp.Events) {
+			t.Fatalf("tx %d: expected %d events, got %d", i, len(exp.Events), len(act.Events))
+		}
+		for j := range exp.Events {
+			expEvent, actEvent := exp.Events[j], act.Events[j]
+			expAttrs, err := expEvent.Attributes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			actAttrs, err := actEvent.Attributes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expEvent.Type != actEvent.Type || expEvent.BlockStage != actEvent.BlockStage ||
+				expEvent.TxIndex != actEvent.TxIndex || expEvent.MsgIndex != actEvent.MsgIndex ||
+				expEvent.EventIndex != actEvent.EventIndex || !reflect.DeepEqual(expAttrs, actAttrs) {
+				t.Fatalf("tx %d: expected event %s %v, got %s %v", i, expEvent.Type, expAttrs, actEvent.Type, actAttrs)
+			}
+		}
+	}
+}
+
+func requireSameState(t *testing.T, expected, actual store.WriterMap) {
+	t.Helper()
+	sortedChanges := func(state store.WriterMap) []store.StateChanges {
+		changes, err := state.GetStateChanges()
+		if err != nil {
+			t.Fatal(err)
+		}
+		filtered := changes[:0]
+		for _, c := range changes {
+			if len(c.StateChanges) > 0 {
+				filtered = append(filtered, c)
+			}
+		}
+		sort.Slice(filtered, func(i, j int) bool { return bytes.Compare(filtered[i].Actor, filtered[j].Actor) < 0 })
+		return filtered
+	}
+	if exp, act := sortedChanges(expected), sortedChanges(actual); !reflect.DeepEqual(exp, act) {
+		t.Fatalf("Expected state changes %v, got %v", exp, act)
+	}
+}
+
+func TestMVMemory(t *testing.T) {
+	mem := newMVMemory(3)
+	mem.record(0, []store.StateChanges{{Actor: bankActor, StateChanges: []store.KVPair{
+		{Key: []byte("a"), Value: []byte("0")},
+		{Key: []byte("b"), Value: []byte("0")},
+	}}})
+	mem.record(2, []store.StateChanges{{Actor: bankActor, StateChanges: []store.KVPair{
+		{Key: []byte("a"), Value: []byte("2")},
+	}}})
+	mem.record(1, []store.StateChanges{{Actor: bankActor, StateChanges: []store.KVPair{
+		{Key: []byte("a"), Remove: true},
+	}}})
+
+	requireRead := func(actor, key []byte, txIndex int, expected []byte, expFound bool) {
+		t.Helper()
+		value, found := mem.read(actor, key, txIndex)
+		if found != expFound || !bytes.Equal(value, expected) {
+			t.Fatalf("read %s/%s at tx %d: expected %q %v, got %q %v", actor, key, txIndex, expected, expFound, value, found)
+		}
+	}
+	requireRead(bankActor, []byte("a"), 0, nil, false)
+	requireRead(bankActor, []byte("a"), 1, []byte("0"), true)
+	requireRead(bankActor, []byte("a"), 2, nil, true)
+	requireRead(bankActor, []byte("a"), 3, []byte("2"), true)
+	requireRead(authActor, []byte("a"), 3, nil, false)
+
+	expected := []store.KVPair{
+		{Key: []byte("a"), Remove: true},
+		{Key: []byte("b"), Value: []byte("0")},
+	}
+	if snapshot := mem.snapshot(bankActor, nil, nil, 2); !reflect.DeepEqual(expected, snapshot) {
+		t.Fatalf("Expected snapshot %v, got %v", expected, snapshot)
+	}
+	expected = []store.KVPair{{Key: []byte("a"), Value: []byte("0")}}
+	if snapshot := mem.snapshot(bankActor, []byte("a"), []byte("b"), 1); !reflect.DeepEqual(expected, snapshot) {
+		t.Fatalf("Expected snapshot %v, got %v", expected, snapshot)
+	}
+
+	// a new execution replaces the writes of the tx
+	mem.record(1, []store.StateChanges{{Actor: bankActor, StateChanges: []store.KVPair{
+		{Key: []byte("b"), Value: []byte("1")},
+	}}})
+	requireRead(bankActor, []byte("a"), 2, []byte("0"), true)
+	requireRead(bankActor, []byte("b"), 2, []byte("1"), true)
+
+	// the reads are validated against the last writes
+	reads := &readSet{}
+	base := &lockedState{state: emptyState{}}
+	reader, err := mvView{mem: mem, base: base, txIndex: 2, reads: reads}.GetReader(bankActor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reader.Get([]byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	itr, err := reader.Iterator(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ; itr.Valid(); itr.Next() {
+	}
+	if err := itr.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(reads.reads) != 2 {
+		t.Fatalf("Expected 2 reads, got %d", len(reads.reads))
+	}
+
+	view := mvView{mem: mem, base: base, txIndex: 2}
+	if valid, err := view.validate(reads); err != nil || !valid {
+		t.Fatalf("Expected valid reads, got %v %v", valid, err)
+	}
+
+	// a key written in the range read by the iterator
+	mem.record(0, []store.StateChanges{{Actor: bankActor, StateChanges: []store.KVPair{
+		{Key: []byte("a"), Value: []byte("0")},
+		{Key: []byte("b"), Value: []byte("0")},
+		{Key: []byte("c"), Value: []byte("0")},
+	}}})
+	if valid, err := view.validate(reads); err != nil || valid {
+		t.Fatalf("Expected invalid reads, got %v %v", valid, err)
+	}
+}
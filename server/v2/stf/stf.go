@@ -45,6 +45,8 @@ type STF[T transaction.Tx] struct {
 	branchFn            branchFn // branchFn is a function that given a readonly state it returns a writable version of it.
 	makeGasMeter        makeGasMeterFn
 	makeGasMeteredState makeGasMeteredStateFn
+
+	parallelWorkers int // parallelWorkers is the number of workers executing the txs of a block in parallel.
 }
 
 // New returns a new STF instance.
@@ -59,6 +61,7 @@ func New[T transaction.Tx](
 	doValidatorUpdate func(ctx context.Context) ([]appmodulev2.ValidatorUpdate, error),
 	postTxExec func(ctx context.Context, tx T, success bool) error,
 	branch func(store store.ReaderMap) store.WriterMap,
+	opts ...Option,
 ) (*STF[T], error) {
 	msgRouter, err := msgRouterBuilder.build()
 	if err != nil {
@@ -68,6 +71,10 @@ func New[T transaction.Tx](
 	if err != nil {
 		return nil, fmt.Errorf("build query router: %w", err)
 	}
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
 
 	return &STF[T]{
 		logger:              logger,
@@ -82,6 +89,7 @@ func New[T transaction.Tx](
 		branchFn:            branch,
 		makeGasMeter:        stfgas.DefaultGasMeter,
 		makeGasMeteredState: stfgas.DefaultWrapWithGasMeter,
+		parallelWorkers:     o.parallelWorkers,
 	}, nil
 }
 
@@ -103,7 +111,7 @@ func (s STF[T]) DeliverBlock(
 	block *server.BlockRequest[T],
 	state store.ReaderMap,
 ) (blockResult *server.BlockResponse, newState store.WriterMap, err error) {
-	return s.deliverBlock(ctx, block, state, s.doDeliverTXs)
+	return s.deliverBlock(ctx, block, state, s.doParallelDeliverTXs)
 }
 
 // common code path for DeliverSims and DeliverBlock
@@ -588,6 +596,7 @@ func (s STF[T]) clone() STF[T] {
 		branchFn:            s.branchFn,
 		makeGasMeter:        s.makeGasMeter,
 		makeGasMeteredState: s.makeGasMeteredState,
+		parallelWorkers:     s.parallelWorkers,
 	}
 }
 
@@ -3,6 +3,7 @@ package stf
 import (
 	"context"
 	"errors"
+	"unsafe"
 )
 
 // getExecutionCtxFromContext tries to get the execution context from the given go context.
@@ -18,3 +19,5 @@ func getExecutionCtxFromContext(ctx context.Context) (*executionContext, error)
 
 	return nil, errors.New("failed to get executionContext from context")
 }
+
+func unsafeString(b []byte) string { return *(*string)(unsafe.Pointer(&b)) }
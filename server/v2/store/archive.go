@@ -0,0 +1,226 @@
+package store
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/klauspost/compress/zstd"
+
+	"cosmossdk.io/store/v2/snapshots/types"
+)
+
+const (
+	// ArchiveMetadataFileName is the name of the metadata file embedded in a snapshot archive.
+	ArchiveMetadataFileName = "metadata.json"
+
+	// ArchiveFormatTarZst is the format of a tar archive compressed with zstd.
+	ArchiveFormatTarZst = "tar.zst"
+	// ArchiveFormatTarGz is the format of a tar archive compressed with gzip.
+	ArchiveFormatTarGz = "tar.gz"
+)
+
+var (
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	gzipMagic = []byte{0x1f, 0x8b}
+)
+
+// ArchiveMetadata is the content of the metadata file of a snapshot archive. It
+// describes the snapshot and the app hash of the state it contains, so that the
+// restored state can be verified.
+type ArchiveMetadata struct {
+	Height      uint64                         `json:"height"`
+	Format      uint32                         `json:"format"`
+	AppHash     []byte                         `json:"app_hash"`
+	Hash        []byte                         `json:"hash"`
+	ChunkHashes [][]byte                       `json:"chunk_hashes"`
+	Stores      []*types.SnapshotStoreManifest `json:"stores,omitempty"`
+}
+
+// NewArchiveMetadata returns the metadata of the archive of a snapshot.
+func NewArchiveMetadata(snapshot *types.Snapshot, appHash []byte) ArchiveMetadata {
+	return ArchiveMetadata{
+		Height:      snapshot.Height,
+		Format:      snapshot.Format,
+		AppHash:     appHash,
+		Hash:        snapshot.Hash,
+		ChunkHashes: snapshot.Metadata.ChunkHashes,
+		Stores:      snapshot.Metadata.Stores,
+	}
+}
+
+// Snapshot returns the snapshot described by the metadata.
+func (m ArchiveMetadata) Snapshot() *types.Snapshot {
+	return &types.Snapshot{
+		Height: m.Height,
+		Format: m.Format,
+		Chunks: uint32(len(m.ChunkHashes)),
+		Hash:   m.Hash,
+		Metadata: types.Metadata{
+			ChunkHashes: m.ChunkHashes,
+			Stores:      m.Stores,
+		},
+	}
+}
+
+// Validate performs basic validation of the metadata.
+func (m ArchiveMetadata) Validate() error {
+	switch {
+	case m.Height == 0:
+		return errors.New("invalid archive metadata: height is zero")
+	case len(m.AppHash) == 0:
+		return errors.New("invalid archive metadata: app hash is empty")
+	case len(m.ChunkHashes) == 0:
+		return errors.New("invalid archive metadata: no chunks")
+	}
+	return nil
+}
+
+// writeArchive writes a snapshot archive in the given format. The metadata file
+// is written first, followed by the chunk files, named by their index, which
+// are read from the given paths.
+func writeArchive(w io.Writer, format string, metadata ArchiveMetadata, chunkPath func(uint32) string) error {
+	var (
+		compressor io.WriteCloser
+		err        error
+	)
+	// since the chunk files are already compressed, we just use fastest compression here
+	switch format {
+	case ArchiveFormatTarZst:
+		compressor, err = zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.SpeedFastest))
+	case ArchiveFormatTarGz:
+		compressor, err = gzip.NewWriterLevel(w, gzip.BestSpeed)
+	default:
+		return fmt.Errorf("unsupported archive format %s, expected %s or %s", format, ArchiveFormatTarZst, ArchiveFormatTarGz)
+	}
+	if err != nil {
+		return err
+	}
+
+	bz, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tarWriter := tar.NewWriter(compressor)
+	if err := tarWriter.WriteHeader(&tar.Header{
+		Name: ArchiveMetadataFileName,
+		Mode: 0o644,
+		Size: int64(len(bz)),
+	}); err != nil {
+		return fmt.Errorf("failed to write metadata header to tar: %w", err)
+	}
+	if _, err := tarWriter.Write(bz); err != nil {
+		return fmt.Errorf("failed to write metadata to tar: %w", err)
+	}
+
+	for i := uint32(0); i < uint32(len(metadata.ChunkHashes)); i++ {
+		tarName := strconv.FormatUint(uint64(i), 10)
+		if err := processChunk(tarWriter, chunkPath(i), tarName); err != nil {
+			return err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to close tar writer: %w", err)
+	}
+	if err := compressor.Close(); err != nil {
+		return fmt.Errorf("failed to close %s writer: %w", format, err)
+	}
+	return nil
+}
+
+// archiveReader reads a snapshot archive written by writeArchive, the format
+// of the archive is detected from its first bytes.
+type archiveReader struct {
+	tarReader    *tar.Reader
+	decompressor io.Closer
+	metadata     ArchiveMetadata
+	next         uint32
+}
+
+// openArchive opens a snapshot archive and reads its metadata.
+func openArchive(r io.Reader) (*archiveReader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(len(zstdMagic))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	ar := &archiveReader{}
+	switch {
+	case bytes.HasPrefix(magic, zstdMagic):
+		decoder, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+		ar.tarReader = tar.NewReader(decoder)
+		ar.decompressor = decoder.IOReadCloser()
+	case bytes.HasPrefix(magic, gzipMagic):
+		gzipReader, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		ar.tarReader = tar.NewReader(gzipReader)
+		ar.decompressor = gzipReader
+	default:
+		return nil, fmt.Errorf("invalid archive, expected %s or %s", ArchiveFormatTarZst, ArchiveFormatTarGz)
+	}
+
+	hdr, err := ar.tarReader.Next()
+	if err != nil {
+		_ = ar.Close()
+		return nil, fmt.Errorf("failed to read metadata file header: %w", err)
+	}
+	if hdr.Name != ArchiveMetadataFileName {
+		_ = ar.Close()
+		return nil, fmt.Errorf("invalid archive, expect file: %s, got: %s", ArchiveMetadataFileName, hdr.Name)
+	}
+	if err := json.NewDecoder(ar.tarReader).Decode(&ar.metadata); err != nil {
+		_ = ar.Close()
+		return nil, fmt.Errorf("failed to decode metadata file: %w", err)
+	}
+	if err := ar.metadata.Validate(); err != nil {
+		_ = ar.Close()
+		return nil, err
+	}
+
+	return ar, nil
+}
+
+// nextChunk reads the next chunk of the archive. The chunk hashes are verified
+// by the snapshot manager when the chunks are restored.
+func (ar *archiveReader) nextChunk() ([]byte, error) {
+	if ar.next >= uint32(len(ar.metadata.ChunkHashes)) {
+		return nil, io.EOF
+	}
+
+	hdr, err := ar.tarReader.Next()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("invalid archive, expect %d chunks, got: %d", len(ar.metadata.ChunkHashes), ar.next)
+		}
+		return nil, fmt.Errorf("failed to read chunk file header: %w", err)
+	}
+	if hdr.Name != strconv.FormatUint(uint64(ar.next), 10) {
+		return nil, fmt.Errorf("invalid archive, expect file: %d, got: %s", ar.next, hdr.Name)
+	}
+
+	bz, err := io.ReadAll(ar.tarReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chunk file: %w", err)
+	}
+	ar.next++
+	return bz, nil
+}
+
+// Close implements io.Closer interface
+func (ar *archiveReader) Close() error {
+	return ar.decompressor.Close()
+}
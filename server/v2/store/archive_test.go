@@ -0,0 +1,89 @@
+package store
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/store/v2/snapshots/types"
+)
+
+func TestArchive(t *testing.T) {
+	dir := t.TempDir()
+	chunks := [][]byte{[]byte("chunk 0"), []byte("chunk 1"), []byte("chunk 2")}
+	snapshot := &types.Snapshot{
+		Height: 3,
+		Format: types.CurrentFormat,
+		Chunks: uint32(len(chunks)),
+		Hash:   []byte("hash"),
+	}
+	for i, chunk := range chunks {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, strconv.Itoa(i)), chunk, 0o600))
+		hash := sha256.Sum256(chunk)
+		snapshot.Metadata.ChunkHashes = append(snapshot.Metadata.ChunkHashes, hash[:])
+	}
+	snapshot.Metadata.Stores = []*types.SnapshotStoreManifest{{Name: "store", FirstChunk: 0, Chunks: 2, Hash: []byte("store hash")}}
+	metadata := NewArchiveMetadata(snapshot, []byte("app hash"))
+	chunkPath := func(i uint32) string {
+		return filepath.Join(dir, strconv.FormatUint(uint64(i), 10))
+	}
+
+	for _, format := range []string{ArchiveFormatTarZst, ArchiveFormatTarGz} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, writeArchive(&buf, format, metadata, chunkPath))
+
+			archive, err := openArchive(bytes.NewReader(buf.Bytes()))
+			require.NoError(t, err)
+			require.Equal(t, metadata, archive.metadata)
+			require.Equal(t, snapshot, archive.metadata.Snapshot())
+
+			for _, chunk := range chunks {
+				bz, err := archive.nextChunk()
+				require.NoError(t, err)
+				require.Equal(t, chunk, bz)
+			}
+			_, err = archive.nextChunk()
+			require.ErrorContains(t, err, "EOF")
+			require.NoError(t, archive.Close())
+		})
+	}
+
+	t.Run("unsupported format", func(t *testing.T) {
+		require.Error(t, writeArchive(&bytes.Buffer{}, "zip", metadata, chunkPath))
+		_, err := openArchive(bytes.NewReader([]byte("PK\x03\x04")))
+		require.ErrorContains(t, err, "invalid archive")
+	})
+
+	t.Run("missing chunk", func(t *testing.T) {
+		missing := metadata
+		missing.ChunkHashes = append(append([][]byte{}, metadata.ChunkHashes...), []byte("missing"))
+		var buf bytes.Buffer
+		require.NoError(t, writeArchive(&buf, ArchiveFormatTarZst, metadata, chunkPath))
+
+		// the archive is read with the metadata of one more chunk
+		archive, err := openArchive(bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+		archive.metadata = missing
+		for range chunks {
+			_, err := archive.nextChunk()
+			require.NoError(t, err)
+		}
+		_, err = archive.nextChunk()
+		require.ErrorContains(t, err, "expect 4 chunks, got: 3")
+	})
+
+	t.Run("invalid metadata", func(t *testing.T) {
+		invalid := metadata
+		invalid.AppHash = nil
+		var buf bytes.Buffer
+		require.NoError(t, writeArchive(&buf, ArchiveFormatTarGz, invalid, chunkPath))
+		_, err := openArchive(bytes.NewReader(buf.Bytes()))
+		require.ErrorContains(t, err, "app hash is empty")
+	})
+}
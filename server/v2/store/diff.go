@@ -0,0 +1,218 @@
+package store
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"cosmossdk.io/schema"
+	storev2 "cosmossdk.io/store/v2"
+)
+
+// DiffCmd returns a command to print the changes of a store between two heights.
+func (s *Server[T]) DiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Print the keys of a store changed between two heights",
+		Long: `Print the keys of a store added, updated and deleted between two retained heights, in ascending key order.
+Every change is printed as a JSON object on its own line, with the keys and values encoded in hex.
+With --decode, the changes are decoded through the collections schema of the module of the store.
+Daemon should not be running when calling this command.`,
+		Example: "<appd> store diff --from 100 --to 200 --store bank --decode",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, err := cmd.Flags().GetUint64("from")
+			if err != nil {
+				return err
+			}
+			to, err := cmd.Flags().GetUint64("to")
+			if err != nil {
+				return err
+			}
+			storeKey, err := cmd.Flags().GetString("store")
+			if err != nil {
+				return err
+			}
+			start, err := getHexFlag(cmd, "start")
+			if err != nil {
+				return err
+			}
+			end, err := getHexFlag(cmd, "end")
+			if err != nil {
+				return err
+			}
+			decode, err := cmd.Flags().GetBool("decode")
+			if err != nil {
+				return err
+			}
+
+			differ, ok := s.store.GetStateCommitment().(storev2.StateDiffer)
+			if !ok {
+				return errors.New("the state commitment doesn't support state diffs")
+			}
+			if to == 0 {
+				to, err = s.store.GetLatestVersion()
+				if err != nil {
+					return err
+				}
+			}
+
+			var decoder schema.KVDecoder
+			if decode {
+				decoder, err = s.lookupDecoder(storeKey)
+				if err != nil {
+					return err
+				}
+			}
+			w := newDiffWriter(cmd.OutOrStdout(), decoder)
+			if err := differ.DiffState([]byte(storeKey), from, to, start, end, w.write); err != nil {
+				return err
+			}
+
+			cmd.PrintErrf("%d added, %d updated, %d deleted keys in store %s from height %d to %d\n",
+				w.count[storev2.ChangeAdded], w.count[storev2.ChangeUpdated], w.count[storev2.ChangeDeleted], storeKey, from, to)
+			return nil
+		},
+	}
+
+	cmd.Flags().Uint64("from", 0, "Height to diff from")
+	cmd.Flags().Uint64("to", 0, "Height to diff to, default to latest state height")
+	cmd.Flags().String("store", "", "Store key of the store to diff")
+	cmd.Flags().String("start", "", "Start of the key range, inclusive, in hex")
+	cmd.Flags().String("end", "", "End of the key range, exclusive, in hex")
+	cmd.Flags().Bool("decode", false, "Decode the changes through the collections schema of the module")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("store")
+
+	return cmd
+}
+
+// getHexFlag returns the value of a hex encoded flag, nil if it isn't set.
+func getHexFlag(cmd *cobra.Command, name string) ([]byte, error) {
+	value, err := cmd.Flags().GetString(name)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	bz, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s flag: %w", name, err)
+	}
+	return bz, nil
+}
+
+// rawChange is the output of a change which isn't decoded.
+type rawChange struct {
+	Change   string `json:"change"`
+	Key      string `json:"key"`
+	OldValue string `json:"old_value,omitempty"`
+	NewValue string `json:"new_value,omitempty"`
+}
+
+// decodedChange is the output of a change of an object of the collections schema.
+type decodedChange struct {
+	Change   string `json:"change"`
+	Type     string `json:"type"`
+	Key      any    `json:"key"`
+	OldValue any    `json:"old_value,omitempty"`
+	NewValue any    `json:"new_value,omitempty"`
+}
+
+// diffWriter writes the changes of a store as JSON lines, the changes are decoded
+// if the decoder isn't nil.
+type diffWriter struct {
+	encoder *json.Encoder
+	decoder schema.KVDecoder
+	count   map[storev2.ChangeType]int
+}
+
+func newDiffWriter(w io.Writer, decoder schema.KVDecoder) *diffWriter {
+	return &diffWriter{
+		encoder: json.NewEncoder(w),
+		decoder: decoder,
+		count:   make(map[storev2.ChangeType]int),
+	}
+}
+
+func (w *diffWriter) write(change storev2.KVChange) error {
+	w.count[change.Type()]++
+	if w.decoder != nil {
+		ok, err := w.writeDecoded(change)
+		if ok || err != nil {
+			return err
+		}
+	}
+
+	return w.encoder.Encode(rawChange{
+		Change:   change.Type().String(),
+		Key:      hex.EncodeToString(change.Key),
+		OldValue: hex.EncodeToString(change.OldValue),
+		NewValue: hex.EncodeToString(change.NewValue),
+	})
+}
+
+// writeDecoded writes the objects decoded from a change, it returns false if the
+// change isn't decoded to objects, e.g. if the key isn't part of a collection.
+func (w *diffWriter) writeDecoded(change storev2.KVChange) (bool, error) {
+	decode := func(value []byte) ([]schema.StateObjectUpdate, error) {
+		if value == nil {
+			return nil, nil
+		}
+		updates, err := w.decoder(schema.KVPairUpdate{Key: change.Key, Value: value})
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode key %X: %w", change.Key, err)
+		}
+		return updates, nil
+	}
+	oldUpdates, err := decode(change.OldValue)
+	if err != nil {
+		return false, err
+	}
+	newUpdates, err := decode(change.NewValue)
+	if err != nil {
+		return false, err
+	}
+	updates := newUpdates
+	if len(updates) == 0 {
+		updates = oldUpdates
+	}
+	if len(updates) == 0 || (len(oldUpdates) > 0 && len(newUpdates) > 0 && len(oldUpdates) != len(newUpdates)) {
+		return false, nil
+	}
+
+	for i, update := range updates {
+		decoded := decodedChange{
+			Change: change.Type().String(),
+			Type:   update.TypeName,
+			Key:    update.Key,
+		}
+		if len(oldUpdates) > 0 {
+			decoded.OldValue = oldUpdates[i].Value
+		}
+		if len(newUpdates) > 0 {
+			decoded.NewValue = newUpdates[i].Value
+		}
+		if err := w.encoder.Encode(decoded); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// lookupDecoder returns the decoder of the module of the given store key.
+func (s *Server[T]) lookupDecoder(storeKey string) (schema.KVDecoder, error) {
+	if s.decoderResolver == nil {
+		return nil, errors.New("no module decoders are available to decode the state")
+	}
+	cdc, found, err := s.decoderResolver.LookupDecoder(storeKey)
+	if err != nil {
+		return nil, err
+	}
+	if !found || cdc.KVDecoder == nil {
+		return nil, fmt.Errorf("module %s doesn't provide a collections schema to decode its state", storeKey)
+	}
+	return cdc.KVDecoder, nil
+}
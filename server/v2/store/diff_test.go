@@ -0,0 +1,61 @@
+package store
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/schema"
+	storev2 "cosmossdk.io/store/v2"
+)
+
+func TestDiffWriter(t *testing.T) {
+	changes := []storev2.KVChange{
+		{Key: []byte{0x01, 'a'}, NewValue: []byte("1")},
+		{Key: []byte{0x01, 'b'}, OldValue: []byte("1"), NewValue: []byte("2")},
+		{Key: []byte{0x01, 'c'}, OldValue: []byte("3")},
+		{Key: []byte{0x02}, OldValue: []byte{0xff}, NewValue: []byte{0xfe}},
+	}
+	// decodes the keys with the 0x01 prefix as objects of the "items" type
+	decoder := func(update schema.KVPairUpdate) ([]schema.StateObjectUpdate, error) {
+		if update.Key[0] != 0x01 {
+			return nil, nil
+		}
+		return []schema.StateObjectUpdate{{
+			TypeName: "items",
+			Key:      string(update.Key[1:]),
+			Value:    string(update.Value),
+		}}, nil
+	}
+
+	var raw bytes.Buffer
+	w := newDiffWriter(&raw, nil)
+	for _, change := range changes {
+		require.NoError(t, w.write(change))
+	}
+	require.Equal(t, strings.Join([]string{
+		`{"change":"added","key":"0161","new_value":"31"}`,
+		`{"change":"updated","key":"0162","old_value":"31","new_value":"32"}`,
+		`{"change":"deleted","key":"0163","old_value":"33"}`,
+		`{"change":"updated","key":"02","old_value":"ff","new_value":"fe"}`,
+	}, "\n")+"\n", raw.String())
+	require.Equal(t, map[storev2.ChangeType]int{
+		storev2.ChangeAdded:   1,
+		storev2.ChangeUpdated: 2,
+		storev2.ChangeDeleted: 1,
+	}, w.count)
+
+	var decoded bytes.Buffer
+	w = newDiffWriter(&decoded, decoder)
+	for _, change := range changes {
+		require.NoError(t, w.write(change))
+	}
+	require.Equal(t, strings.Join([]string{
+		`{"change":"added","type":"items","key":"a","new_value":"1"}`,
+		`{"change":"updated","type":"items","key":"b","old_value":"1","new_value":"2"}`,
+		`{"change":"deleted","type":"items","key":"c","old_value":"3"}`,
+		`{"change":"updated","key":"02","old_value":"ff","new_value":"fe"}`,
+	}, "\n")+"\n", decoded.String())
+}
@@ -8,6 +8,7 @@ import (
 
 	"cosmossdk.io/core/server"
 	"cosmossdk.io/core/transaction"
+	"cosmossdk.io/schema/decoding"
 	serverv2 "cosmossdk.io/server/v2"
 	storev2 "cosmossdk.io/store/v2"
 	"cosmossdk.io/store/v2/root"
@@ -25,17 +26,35 @@ const ServerName = "store"
 type Server[T transaction.Tx] struct {
 	config *root.Config
 	store  storev2.RootStore
+
+	// decoderResolver resolves the module decoders used to decode the state, it may be nil.
+	decoderResolver decoding.DecoderResolver
 }
 
-func New[T transaction.Tx](store storev2.RootStore, cfg server.ConfigMap) (*Server[T], error) {
+// OptionFunc configures the store server.
+type OptionFunc[T transaction.Tx] func(*Server[T])
+
+// WithDecoderResolver sets the resolver of the module decoders, which decode the state
+// through the collections schemas of the modules.
+func WithDecoderResolver[T transaction.Tx](resolver decoding.DecoderResolver) OptionFunc[T] {
+	return func(srv *Server[T]) {
+		srv.decoderResolver = resolver
+	}
+}
+
+func New[T transaction.Tx](store storev2.RootStore, cfg server.ConfigMap, opts ...OptionFunc[T]) (*Server[T], error) {
 	config, err := UnmarshalConfig(cfg)
 	if err != nil {
 		return nil, err
 	}
-	return &Server[T]{
+	srv := &Server[T]{
 		store:  store,
 		config: config,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(srv)
+	}
+	return srv, nil
 }
 
 func (s *Server[T]) Name() string {
@@ -60,6 +79,8 @@ func (s *Server[T]) CLICommands() serverv2.CLIConfig {
 			s.DumpArchiveCmd(),
 			s.LoadArchiveCmd(),
 			s.RestoreSnapshotCmd(),
+			s.SnapshotCmd(),
+			s.DiffCmd(),
 			s.ModuleHashByHeightQuery(),
 		},
 	}
@@ -298,7 +298,8 @@ func (s *Server[T]) LoadArchiveCmd() *cobra.Command {
 			go func() {
 				defer close(quitChan)
 
-				savedSnapshot, err := snapshotStore.Save(snapshot.Height, snapshot.Format, chunks)
+				// the store manifests are recomputed from the chunks, so they are checked below
+				savedSnapshot, err := snapshotStore.SaveStores(snapshot.Height, snapshot.Format, snapshot.Metadata.Stores, chunks)
 				if err != nil {
 					cmd.Println("failed to save snapshot", err)
 					return
@@ -342,6 +343,189 @@ func (s *Server[T]) LoadArchiveCmd() *cobra.Command {
 	}
 }
 
+// SnapshotCmd returns the command grouping the snapshot archive commands
+func (s *Server[T]) SnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Export and import verified snapshot archives",
+	}
+
+	cmd.AddCommand(
+		s.ExportSnapshotArchiveCmd(),
+		s.ImportSnapshotArchiveCmd(),
+	)
+
+	return cmd
+}
+
+// ExportSnapshotArchiveCmd returns a command to export the app state at a height as a
+// self-describing archive, which embeds the metadata of the snapshot and the app hash.
+func (s *Server[T]) ExportSnapshotArchiveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export app state to a snapshot archive file",
+		Long: `Export app state to a snapshot archive file (.tar.zst or .tar.gz).
+The snapshot at the given height is taken from the snapshot store, or created if it doesn't exist.
+The archive embeds a metadata file with the height, the app hash and the chunk hashes of the snapshot,
+so it can be imported offline by another node with the "snapshot import" command.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			v := serverv2.GetViperFromCmd(cmd)
+			logger := serverv2.GetLoggerFromCmd(cmd)
+
+			height, err := cmd.Flags().GetUint64("height")
+			if err != nil {
+				return err
+			}
+			format, err := cmd.Flags().GetString("format")
+			if err != nil {
+				return err
+			}
+			if format != ArchiveFormatTarZst && format != ArchiveFormatTarGz {
+				return fmt.Errorf("unsupported archive format %s, expected %s or %s", format, ArchiveFormatTarZst, ArchiveFormatTarGz)
+			}
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
+				return err
+			}
+
+			if height == 0 {
+				height, err = s.store.GetLatestVersion()
+				if err != nil {
+					return err
+				}
+			}
+			cInfo, err := s.store.GetStateCommitment().GetCommitInfo(height)
+			if err != nil {
+				return fmt.Errorf("failed to get commit info at height %d: %w", height, err)
+			}
+
+			snapshotStore, err := snapshots.NewStore(filepath.Join(v.GetString(serverv2.FlagHome), "data", "snapshots"))
+			if err != nil {
+				return err
+			}
+			snapshot, err := snapshotStore.Get(height, types.CurrentFormat)
+			if err != nil {
+				return err
+			}
+			if snapshot == nil {
+				cmd.Printf("Creating snapshot for height %d\n", height)
+				sm, err := createSnapshotsManager(cmd, v, logger, s.store)
+				if err != nil {
+					return err
+				}
+				if snapshot, err = sm.Create(height); err != nil {
+					return err
+				}
+			}
+
+			if output == "" {
+				output = fmt.Sprintf("%d-%d.%s", snapshot.Height, snapshot.Format, format)
+			}
+			fp, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				err = errors.Join(err, fp.Close())
+			}()
+
+			metadata := NewArchiveMetadata(snapshot, cInfo.Hash())
+			chunkPath := func(i uint32) string {
+				return snapshotStore.PathChunk(snapshot.Height, snapshot.Format, i)
+			}
+			if err := writeArchive(fp, format, metadata, chunkPath); err != nil {
+				return err
+			}
+
+			cmd.Printf("Snapshot archive %s exported at height %d, format %d, chunks %d, app hash %X\n",
+				output, snapshot.Height, snapshot.Format, snapshot.Chunks, metadata.AppHash)
+			return nil
+		},
+	}
+
+	addSnapshotFlagsToCmd(cmd)
+	cmd.Flags().Uint64("height", 0, "Height to export, default to latest state height")
+	cmd.Flags().String("format", ArchiveFormatTarZst, fmt.Sprintf("Archive format, %s or %s", ArchiveFormatTarZst, ArchiveFormatTarGz))
+	cmd.Flags().StringP("output", "o", "", "output file, default to <height>-<snapshot format>.<archive format>")
+
+	return cmd
+}
+
+// ImportSnapshotArchiveCmd returns a command to restore the app state from a snapshot archive
+// exported by ExportSnapshotArchiveCmd, the restored state is verified against the app hash
+// embedded in the archive.
+func (s *Server[T]) ImportSnapshotArchiveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <archive-file>",
+		Short: "Restore app state from a snapshot archive file",
+		Long: `Restore app state from a snapshot archive file (.tar.zst or .tar.gz) exported by the "snapshot export" command.
+The chunks are verified against the metadata of the archive, and the commit info of the restored state
+against the app hash of the archive. The snapshot is also saved to the snapshot store.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			v := serverv2.GetViperFromCmd(cmd)
+			logger := serverv2.GetLoggerFromCmd(cmd)
+
+			fp, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open archive file: %w", err)
+			}
+			defer func() {
+				err = errors.Join(err, fp.Close())
+			}()
+
+			archive, err := openArchive(fp)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				err = errors.Join(err, archive.Close())
+			}()
+
+			snapshot := archive.metadata.Snapshot()
+
+			sm, err := createSnapshotsManager(cmd, v, logger, s.store)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Importing snapshot at height %d, format %d, chunks %d\n", snapshot.Height, snapshot.Format, snapshot.Chunks)
+			if err := sm.Restore(*snapshot); err != nil {
+				return err
+			}
+			done := false
+			for !done {
+				chunk, err := archive.nextChunk()
+				if err != nil {
+					if errors.Is(err, io.EOF) {
+						return errors.New("invalid archive, the snapshot is incomplete")
+					}
+					return err
+				}
+				if done, err = sm.RestoreChunk(chunk); err != nil {
+					return err
+				}
+			}
+
+			cInfo, err := s.store.GetStateCommitment().GetCommitInfo(snapshot.Height)
+			if err != nil {
+				return fmt.Errorf("failed to get commit info at height %d: %w", snapshot.Height, err)
+			}
+			if !bytes.Equal(cInfo.Hash(), archive.metadata.AppHash) {
+				return fmt.Errorf("app hash mismatch at height %d: expected %X, got %X", snapshot.Height, archive.metadata.AppHash, cInfo.Hash())
+			}
+
+			cmd.Printf("Snapshot imported at height %d, app hash %X\n", snapshot.Height, archive.metadata.AppHash)
+			return nil
+		},
+	}
+
+	addSnapshotFlagsToCmd(cmd)
+
+	return cmd
+}
+
 func createSnapshotsManager(
 	cmd *cobra.Command, v *viper.Viper, logger log.Logger, store storev2.Backend,
 ) (*snapshots.Manager, error) {
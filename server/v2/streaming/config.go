@@ -23,3 +23,26 @@ type ListenerConfig struct {
 	// stop-node-on-err specifies whether to stop the node on message delivery error.
 	StopNodeOnErr bool `mapstructure:"stop-node-on-err" toml:"stop-node-on-err" comment:"stop-node-on-err specifies whether to stop the node on message delivery error."`
 }
+
+// SinkConfig defines the configuration of the built-in sink, which writes the
+// FinalizeBlock and Commit changesets of every block to segment files.
+type SinkConfig struct {
+	Enable         bool     `mapstructure:"enable" toml:"enable" comment:"enable writes the FinalizeBlock and Commit changesets of every block to checksummed segment files. stop-node-on-err should be set, otherwise a failed write is only logged."`
+	Dir            string   `mapstructure:"dir" toml:"dir" comment:"dir is the directory of the segment files, a relative path is relative to the node home directory."`
+	MaxSegmentSize uint64   `mapstructure:"max-segment-size" toml:"max-segment-size" comment:"max-segment-size is the size in bytes from which the next block is written to a new segment file."`
+	RetainBlocks   uint64   `mapstructure:"retain-blocks" toml:"retain-blocks" comment:"retain-blocks is the number of recent blocks kept in the segment files, the older segments are deleted once every registered consumer has acknowledged them. A value of 0 keeps every block."`
+	Consumers      []string `mapstructure:"consumers" toml:"consumers" comment:"consumers is the list of the registered consumers, which acknowledge the blocks they have processed in the consumers directory of the sink."`
+	MaxConsumerLag uint64   `mapstructure:"max-consumer-lag" toml:"max-consumer-lag" comment:"max-consumer-lag pauses the block commit while a registered consumer lags behind by more than max-consumer-lag blocks. A value of 0 never pauses the block commit."`
+}
+
+// DefaultSinkConfig returns the default configuration of the sink.
+func DefaultSinkConfig() SinkConfig {
+	return SinkConfig{
+		Enable:         false,
+		Dir:            "data/streaming",
+		MaxSegmentSize: 128 << 20,
+		RetainBlocks:   0,
+		Consumers:      []string{},
+		MaxConsumerLag: 0,
+	}
+}
@@ -0,0 +1,333 @@
+package streaming
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A segment file is a sequence of blocks, named after the height of its first
+// block. Every block is written as a ListenDeliverBlockRequest record followed
+// by a ListenStateChangesRequest record of the same height. A record is:
+//
+//	| length (4 bytes) | checksum (4 bytes) | type (1 byte) | payload (length bytes) |
+//
+// where the payload is the protobuf encoding of the request, the length and the
+// checksum are big endian and the checksum is the CRC-32C of the type and the
+// payload.
+const (
+	segmentPrefix    = "segment-"
+	segmentExt       = ".log"
+	recordHeaderSize = 9
+	// maxRecordSize bounds the allocation of a record with a corrupted length
+	maxRecordSize = 1 << 30
+
+	recordDeliverBlock byte = 1
+	recordStateChanges byte = 2
+)
+
+var (
+	crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+	// ErrCorruptedRecord is returned when a record of a segment file is corrupted.
+	ErrCorruptedRecord = errors.New("corrupted record")
+	// ErrHeightNotRetained is returned when a block is read from a height which isn't retained anymore.
+	ErrHeightNotRetained = errors.New("height is not retained")
+)
+
+// Block is a block read from the segment files.
+type Block struct {
+	Height       int64
+	DeliverBlock *ListenDeliverBlockRequest
+	StateChanges *ListenStateChangesRequest
+}
+
+// segment is a segment file of the sink.
+type segment struct {
+	path        string
+	firstHeight int64
+}
+
+func segmentPath(dir string, firstHeight int64) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%020d%s", segmentPrefix, firstHeight, segmentExt))
+}
+
+// listSegments returns the segment files of the directory ordered by height.
+func listSegments(dir string) ([]segment, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var segments []segment
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		height, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, segment{path: filepath.Join(dir, name), firstHeight: height})
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].firstHeight < segments[j].firstHeight
+	})
+	return segments, nil
+}
+
+// encodeBlock encodes the records of a block.
+func encodeBlock(deliverBlock *ListenDeliverBlockRequest, stateChanges *ListenStateChangesRequest) ([]byte, error) {
+	var buf []byte
+	for _, record := range []struct {
+		typ byte
+		msg interface{ Marshal() ([]byte, error) }
+	}{
+		{recordDeliverBlock, deliverBlock},
+		{recordStateChanges, stateChanges},
+	} {
+		payload, err := record.msg.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		if len(payload) > maxRecordSize {
+			return nil, fmt.Errorf("record of %d bytes exceeds the maximum size", len(payload))
+		}
+		header := make([]byte, recordHeaderSize)
+		binary.BigEndian.PutUint32(header[0:4], uint32(len(payload)))
+		crc := crc32.Update(crc32.Checksum([]byte{record.typ}, crcTable), crcTable, payload)
+		binary.BigEndian.PutUint32(header[4:8], crc)
+		header[8] = record.typ
+		buf = append(buf, header...)
+		buf = append(buf, payload...)
+	}
+	return buf, nil
+}
+
+// readRecord reads a record. It returns io.EOF if there is no record left and
+// io.ErrUnexpectedEOF if the record is incomplete.
+func readRecord(r io.Reader) (byte, []byte, int64, error) {
+	header := make([]byte, recordHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, 0, err
+	}
+	length := binary.BigEndian.Uint32(header[0:4])
+	if length > maxRecordSize {
+		return 0, nil, 0, fmt.Errorf("%w: invalid length %d", ErrCorruptedRecord, length)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, 0, err
+	}
+	typ := header[8]
+	crc := crc32.Update(crc32.Checksum([]byte{typ}, crcTable), crcTable, payload)
+	if crc != binary.BigEndian.Uint32(header[4:8]) {
+		return 0, nil, 0, fmt.Errorf("%w: checksum mismatch", ErrCorruptedRecord)
+	}
+	return typ, payload, int64(recordHeaderSize) + int64(length), nil
+}
+
+// readBlock reads a block and returns the number of bytes read. It returns
+// io.EOF if there is no block left and io.ErrUnexpectedEOF if the block is
+// incomplete.
+func readBlock(r io.Reader) (*Block, int64, error) {
+	typ, payload, n, err := readRecord(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	if typ != recordDeliverBlock {
+		return nil, 0, fmt.Errorf("%w: expected a deliver block record, got type %d", ErrCorruptedRecord, typ)
+	}
+	deliverBlock := &ListenDeliverBlockRequest{}
+	if err := deliverBlock.Unmarshal(payload); err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", ErrCorruptedRecord, err)
+	}
+
+	typ, payload, m, err := readRecord(r)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, 0, err
+	}
+	if typ != recordStateChanges {
+		return nil, 0, fmt.Errorf("%w: expected a state changes record, got type %d", ErrCorruptedRecord, typ)
+	}
+	stateChanges := &ListenStateChangesRequest{}
+	if err := stateChanges.Unmarshal(payload); err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", ErrCorruptedRecord, err)
+	}
+	if deliverBlock.BlockHeight != stateChanges.BlockHeight {
+		return nil, 0, fmt.Errorf("%w: the state changes of height %d follow the block %d",
+			ErrCorruptedRecord, stateChanges.BlockHeight, deliverBlock.BlockHeight)
+	}
+
+	return &Block{
+		Height:       deliverBlock.BlockHeight,
+		DeliverBlock: deliverBlock,
+		StateChanges: stateChanges,
+	}, n + m, nil
+}
+
+// SegmentReader reads the blocks written by the sink to the segment files of a
+// directory. It can be used by a consumer in another process while the sink
+// writes new blocks.
+type SegmentReader struct {
+	dir     string
+	next    int64   // height of the next block to return
+	segment segment // segment of the open file
+	file    *os.File
+	reader  *bufio.Reader
+	offset  int64 // offset of the next block in the file
+}
+
+// NewSegmentReader creates a reader of the blocks from the given height. It
+// returns ErrHeightNotRetained if the segments of the height are deleted.
+func NewSegmentReader(dir string, from int64) (*SegmentReader, error) {
+	r := &SegmentReader{dir: dir, next: from}
+	segments, err := listSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) > 0 && segments[0].firstHeight > from {
+		return nil, fmt.Errorf("%w: %d, the first retained height is %d", ErrHeightNotRetained, from, segments[0].firstHeight)
+	}
+	return r, nil
+}
+
+// Next returns the next block. It returns io.EOF if the block isn't written
+// yet, Next can be called again once it is written.
+func (r *SegmentReader) Next() (*Block, error) {
+	for {
+		if r.file == nil {
+			ok, err := r.openSegment()
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, io.EOF
+			}
+		}
+
+		block, n, err := readBlock(r.reader)
+		switch {
+		case err == nil:
+			r.offset += n
+			if block.Height < r.next {
+				continue
+			}
+			r.next = block.Height + 1
+			return block, nil
+
+		case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+			// the block may be partially written, it is read again from its start
+			if err := r.rewind(); err != nil {
+				return nil, err
+			}
+			// a segment is complete once the next one is created
+			later, err := r.hasLaterSegment()
+			if err != nil {
+				return nil, err
+			}
+			if !later {
+				return nil, io.EOF
+			}
+			if err := r.closeSegment(); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, fmt.Errorf("failed to read block %d from %s: %w", r.next, r.segment.path, err)
+		}
+	}
+}
+
+// openSegment opens the segment of the next block: the last segment starting
+// at or before the next height for the first segment read, the segment which
+// follows the current segment otherwise. It returns false if the segment isn't
+// created yet.
+func (r *SegmentReader) openSegment() (bool, error) {
+	segments, err := listSegments(r.dir)
+	if err != nil {
+		return false, err
+	}
+	idx := -1
+	for i, s := range segments {
+		if r.segment.path == "" {
+			if s.firstHeight <= r.next {
+				idx = i
+			}
+		} else if s.firstHeight > r.segment.firstHeight {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		if r.segment.path == "" && len(segments) > 0 {
+			return false, fmt.Errorf("%w: %d, the first retained height is %d", ErrHeightNotRetained, r.next, segments[0].firstHeight)
+		}
+		return false, nil
+	}
+
+	file, err := os.Open(segments[idx].path)
+	if err != nil {
+		return false, err
+	}
+	r.segment = segments[idx]
+	r.file = file
+	r.reader = bufio.NewReader(file)
+	r.offset = 0
+	return true, nil
+}
+
+// hasLaterSegment returns whether a segment follows the current segment.
+func (r *SegmentReader) hasLaterSegment() (bool, error) {
+	segments, err := listSegments(r.dir)
+	if err != nil {
+		return false, err
+	}
+	for _, s := range segments {
+		if s.firstHeight > r.segment.firstHeight {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// rewind moves the reader back to the start of the next block.
+func (r *SegmentReader) rewind() error {
+	if _, err := r.file.Seek(r.offset, io.SeekStart); err != nil {
+		return err
+	}
+	r.reader.Reset(r.file)
+	return nil
+}
+
+func (r *SegmentReader) closeSegment() error {
+	err := r.file.Close()
+	r.file, r.reader = nil, nil
+	return err
+}
+
+// Close implements io.Closer interface
+func (r *SegmentReader) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	return r.closeSegment()
+}
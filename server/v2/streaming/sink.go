@@ -0,0 +1,389 @@
+package streaming
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cosmossdk.io/core/log"
+)
+
+const (
+	consumersDir = "consumers"
+	// lagPollInterval is the interval at which the offsets of the consumers are
+	// read while the block commit is paused.
+	lagPollInterval = 100 * time.Millisecond
+	// lagLogInterval is the interval at which a paused block commit is logged.
+	lagLogInterval = 10 * time.Second
+)
+
+var consumerNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+var _ Listener = (*Sink)(nil)
+
+// Sink is a Listener which writes the FinalizeBlock and Commit changesets of
+// every block to segment files, in process. A block is synced to disk before
+// the listener returns, so a committed block is never lost. The blocks already
+// written are skipped when they are replayed after a restart.
+//
+// The consumers registered in the configuration acknowledge the blocks they
+// have processed with AckOffset. The segments are only deleted by the retention
+// once every registered consumer has acknowledged them, and the block commit is
+// paused while a registered consumer lags behind by more than MaxConsumerLag
+// blocks.
+type Sink struct {
+	cfg    SinkConfig
+	dir    string
+	logger log.Logger
+
+	mtx        sync.Mutex
+	segments   []segment // segments ordered by height, the last one is written
+	file       *os.File  // file of the last segment, nil until a block is written to it
+	size       uint64    // size of the last segment
+	lastHeight int64     // height of the last block written
+	pending    *ListenDeliverBlockRequest
+}
+
+// NewSink creates a sink writing to the given directory. The last segment is
+// recovered: a block partially written when the process stopped is truncated.
+func NewSink(cfg SinkConfig, dir string, logger log.Logger) (*Sink, error) {
+	for _, consumer := range cfg.Consumers {
+		if !consumerNameRegex.MatchString(consumer) {
+			return nil, fmt.Errorf("invalid consumer name %q", consumer)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(dir, consumersDir), 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &Sink{
+		cfg:    cfg,
+		dir:    dir,
+		logger: logger,
+	}
+	if err := s.recover(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// recover finds the last block written and truncates the last segment after it.
+func (s *Sink) recover() error {
+	segments, err := listSegments(s.dir)
+	if err != nil {
+		return err
+	}
+
+	for len(segments) > 0 {
+		last := segments[len(segments)-1]
+		file, err := os.OpenFile(last.path, os.O_RDWR, 0o644)
+		if err != nil {
+			return err
+		}
+
+		var (
+			offset     int64
+			lastHeight int64
+			reader     = bufio.NewReader(file)
+		)
+		for {
+			block, n, err := readBlock(reader)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, ErrCorruptedRecord) {
+				s.logger.Warn("truncating the incomplete block of the streaming segment", "segment", last.path, "offset", offset, "err", err)
+				break
+			}
+			if err != nil {
+				return errors.Join(err, file.Close())
+			}
+			offset += n
+			lastHeight = block.Height
+		}
+
+		if offset == 0 {
+			// the segment has no complete block
+			if err := errors.Join(file.Close(), os.Remove(last.path)); err != nil {
+				return err
+			}
+			segments = segments[:len(segments)-1]
+			continue
+		}
+
+		if err := file.Truncate(offset); err != nil {
+			return errors.Join(err, file.Close())
+		}
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			return errors.Join(err, file.Close())
+		}
+		s.file = file
+		s.size = uint64(offset)
+		s.lastHeight = lastHeight
+		break
+	}
+
+	s.segments = segments
+	return nil
+}
+
+// ListenDeliverBlock implements Listener. The block is written with its state
+// changes by ListenStateChanges.
+func (s *Sink) ListenDeliverBlock(_ context.Context, req ListenDeliverBlockRequest) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if req.BlockHeight <= s.lastHeight {
+		// the block is replayed
+		s.pending = nil
+		return nil
+	}
+	s.pending = &req
+	return nil
+}
+
+// ListenStateChanges implements Listener. It writes the block of the state
+// changes and waits for the consumers which lag behind.
+func (s *Sink) ListenStateChanges(ctx context.Context, changeSet []*StoreKVPair) error {
+	height, err := s.writeBlock(changeSet)
+	if err != nil || height == 0 {
+		return err
+	}
+	return s.waitConsumers(ctx, height)
+}
+
+// writeBlock writes the pending block with the given state changes and returns
+// its height, or 0 if there is no pending block.
+func (s *Sink) writeBlock(changeSet []*StoreKVPair) (int64, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.pending == nil {
+		return 0, nil
+	}
+	deliverBlock := s.pending
+	s.pending = nil
+
+	bz, err := encodeBlock(deliverBlock, &ListenStateChangesRequest{
+		BlockHeight: deliverBlock.BlockHeight,
+		ChangeSet:   changeSet,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if s.file != nil && s.size >= s.cfg.MaxSegmentSize {
+		if err := s.closeFile(); err != nil {
+			return 0, err
+		}
+	}
+	if s.file == nil {
+		if err := s.createSegment(deliverBlock.BlockHeight); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := s.file.Write(bz); err != nil {
+		return 0, fmt.Errorf("failed to write block %d: %w", deliverBlock.BlockHeight, err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return 0, fmt.Errorf("failed to sync block %d: %w", deliverBlock.BlockHeight, err)
+	}
+	s.size += uint64(len(bz))
+	s.lastHeight = deliverBlock.BlockHeight
+
+	if err := s.prune(); err != nil {
+		return 0, err
+	}
+	return deliverBlock.BlockHeight, nil
+}
+
+// createSegment creates the segment starting at the given height.
+func (s *Sink) createSegment(height int64) error {
+	path := segmentPath(s.dir, height)
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := syncDir(s.dir); err != nil {
+		return errors.Join(err, file.Close())
+	}
+	s.segments = append(s.segments, segment{path: path, firstHeight: height})
+	s.file = file
+	s.size = 0
+	return nil
+}
+
+// prune deletes the segments whose blocks are all older than the retained
+// blocks and acknowledged by every registered consumer. The last segment is
+// never deleted.
+func (s *Sink) prune() error {
+	if s.cfg.RetainBlocks == 0 || len(s.segments) < 2 {
+		return nil
+	}
+	retainFrom := s.lastHeight - int64(s.cfg.RetainBlocks) + 1
+	acked, err := s.minOffset()
+	if err != nil {
+		return err
+	}
+
+	for len(s.segments) > 1 {
+		// the last height of a segment precedes the first height of the next one
+		lastHeight := s.segments[1].firstHeight - 1
+		if lastHeight >= retainFrom || lastHeight > acked {
+			break
+		}
+		if err := os.Remove(s.segments[0].path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		s.segments = s.segments[1:]
+	}
+	return nil
+}
+
+// minOffset returns the lowest offset of the registered consumers, or the last
+// height if no consumer is registered.
+func (s *Sink) minOffset() (int64, error) {
+	minOffset := s.lastHeight
+	for _, consumer := range s.cfg.Consumers {
+		offset, err := s.offset(consumer)
+		if err != nil {
+			return 0, err
+		}
+		minOffset = min(minOffset, offset)
+	}
+	return minOffset, nil
+}
+
+// offset returns the offset of a registered consumer. A consumer which hasn't
+// acknowledged any block yet is at the height preceding the first block retained.
+func (s *Sink) offset(consumer string) (int64, error) {
+	offset, found, err := ReadOffset(s.dir, consumer)
+	if err != nil || found {
+		return offset, err
+	}
+	if len(s.segments) > 0 {
+		return s.segments[0].firstHeight - 1, nil
+	}
+	return s.lastHeight, nil
+}
+
+// waitConsumers pauses until every registered consumer lags behind the given
+// height by at most MaxConsumerLag blocks.
+func (s *Sink) waitConsumers(ctx context.Context, height int64) error {
+	if s.cfg.MaxConsumerLag == 0 || len(s.cfg.Consumers) == 0 {
+		return nil
+	}
+
+	var (
+		ticker   *time.Ticker
+		lastLog  time.Time
+		pausedAt time.Time
+	)
+	for {
+		s.mtx.Lock()
+		offset, err := s.minOffset()
+		s.mtx.Unlock()
+		if err != nil {
+			return err
+		}
+		lag := height - offset
+		if lag <= int64(s.cfg.MaxConsumerLag) {
+			if ticker != nil {
+				ticker.Stop()
+				s.logger.Info("resuming the block commit", "height", height, "paused", time.Since(pausedAt))
+			}
+			return nil
+		}
+
+		if ticker == nil {
+			ticker = time.NewTicker(lagPollInterval)
+			pausedAt = time.Now()
+		}
+		if time.Since(lastLog) >= lagLogInterval {
+			s.logger.Warn("pausing the block commit until the streaming consumers catch up", "height", height, "lag", lag, "max_lag", s.cfg.MaxConsumerLag)
+			lastLog = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			ticker.Stop()
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// LastHeight returns the height of the last block written.
+func (s *Sink) LastHeight() int64 {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.lastHeight
+}
+
+func (s *Sink) closeFile() error {
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// Close implements io.Closer interface
+func (s *Sink) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	return s.closeFile()
+}
+
+// ReadOffset returns the height of the last block acknowledged by a consumer of
+// the sink writing to the given directory, and false if the consumer hasn't
+// acknowledged any block.
+func ReadOffset(dir, consumer string) (int64, bool, error) {
+	bz, err := os.ReadFile(filepath.Join(dir, consumersDir, consumer))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	offset, err := strconv.ParseInt(strings.TrimSpace(string(bz)), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid offset of consumer %s: %w", consumer, err)
+	}
+	return offset, true, nil
+}
+
+// AckOffset acknowledges that a consumer of the sink writing to the given
+// directory has processed the blocks up to the given height.
+func AckOffset(dir, consumer string, height int64) error {
+	if !consumerNameRegex.MatchString(consumer) {
+		return fmt.Errorf("invalid consumer name %q", consumer)
+	}
+	path := filepath.Join(dir, consumersDir, consumer)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(strconv.FormatInt(height, 10)), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// syncDir syncs a directory, so that the files created in it are durable.
+func syncDir(dir string) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	return errors.Join(file.Sync(), file.Close())
+}
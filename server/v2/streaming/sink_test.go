@@ -0,0 +1,211 @@
+package streaming
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	coretesting "cosmossdk.io/core/testing"
+)
+
+func newTestSink(t *testing.T, dir string, cfg SinkConfig) *Sink {
+	t.Helper()
+	sink, err := NewSink(cfg, dir, coretesting.NewNopLogger())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, sink.Close()) })
+	return sink
+}
+
+func writeTestBlock(t *testing.T, sink *Sink, height int64) {
+	t.Helper()
+	ctx := context.Background()
+	require.NoError(t, sink.ListenDeliverBlock(ctx, ListenDeliverBlockRequest{
+		BlockHeight: height,
+		Txs:         [][]byte{{byte(height)}},
+	}))
+	require.NoError(t, sink.ListenStateChanges(ctx, []*StoreKVPair{
+		{Address: []byte("bank"), Key: []byte{byte(height)}, Value: []byte("value")},
+	}))
+}
+
+func readTestBlocks(t *testing.T, r *SegmentReader) []int64 {
+	t.Helper()
+	var heights []int64
+	for {
+		block, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return heights
+		}
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{{byte(block.Height)}}, block.DeliverBlock.Txs)
+		require.Equal(t, block.Height, block.StateChanges.BlockHeight)
+		require.Equal(t, []byte{byte(block.Height)}, block.StateChanges.ChangeSet[0].Key)
+		heights = append(heights, block.Height)
+	}
+}
+
+func TestSink(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultSinkConfig()
+	cfg.MaxSegmentSize = 100
+	sink := newTestSink(t, dir, cfg)
+
+	r, err := NewSegmentReader(dir, 1)
+	require.NoError(t, err)
+	defer r.Close()
+	require.Empty(t, readTestBlocks(t, r))
+
+	for height := int64(1); height <= 5; height++ {
+		writeTestBlock(t, sink, height)
+	}
+	require.Equal(t, []int64{1, 2, 3, 4, 5}, readTestBlocks(t, r))
+
+	// the reader tails the segments
+	writeTestBlock(t, sink, 6)
+	require.Equal(t, []int64{6}, readTestBlocks(t, r))
+
+	segments, err := listSegments(dir)
+	require.NoError(t, err)
+	require.Greater(t, len(segments), 1)
+
+	// the replayed blocks are skipped
+	writeTestBlock(t, sink, 6)
+	writeTestBlock(t, sink, 7)
+	require.Equal(t, []int64{7}, readTestBlocks(t, r))
+
+	// a reader starts from any height
+	r2, err := NewSegmentReader(dir, 4)
+	require.NoError(t, err)
+	defer r2.Close()
+	require.Equal(t, []int64{4, 5, 6, 7}, readTestBlocks(t, r2))
+}
+
+func TestSink_Recover(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := NewSink(DefaultSinkConfig(), dir, coretesting.NewNopLogger())
+	require.NoError(t, err)
+	for height := int64(1); height <= 3; height++ {
+		writeTestBlock(t, sink, height)
+	}
+	require.NoError(t, sink.Close())
+
+	// a block partially written
+	segments, err := listSegments(dir)
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+	bz, err := encodeBlock(&ListenDeliverBlockRequest{BlockHeight: 4}, &ListenStateChangesRequest{BlockHeight: 4})
+	require.NoError(t, err)
+	file, err := os.OpenFile(segments[0].path, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = file.Write(bz[:len(bz)-1])
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	r, err := NewSegmentReader(dir, 1)
+	require.NoError(t, err)
+	defer r.Close()
+	require.Equal(t, []int64{1, 2, 3}, readTestBlocks(t, r))
+
+	sink = newTestSink(t, dir, DefaultSinkConfig())
+	require.Equal(t, int64(3), sink.LastHeight())
+	writeTestBlock(t, sink, 3)
+	writeTestBlock(t, sink, 4)
+	require.Equal(t, []int64{4}, readTestBlocks(t, r))
+
+	// a corrupted block
+	file, err = os.OpenFile(segments[0].path, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	bz[recordHeaderSize] ^= 0xff
+	_, err = file.Write(bz)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	_, err = r.Next()
+	require.ErrorIs(t, err, ErrCorruptedRecord)
+
+	require.NoError(t, sink.Close())
+	sink = newTestSink(t, dir, DefaultSinkConfig())
+	require.Equal(t, int64(4), sink.LastHeight())
+	writeTestBlock(t, sink, 5)
+	r2, err := NewSegmentReader(dir, 1)
+	require.NoError(t, err)
+	defer r2.Close()
+	require.Equal(t, []int64{1, 2, 3, 4, 5}, readTestBlocks(t, r2))
+}
+
+func TestSink_Retention(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultSinkConfig()
+	cfg.MaxSegmentSize = 1 // one block per segment
+	cfg.RetainBlocks = 2
+	cfg.Consumers = []string{"indexer"}
+	sink := newTestSink(t, dir, cfg)
+
+	for height := int64(1); height <= 5; height++ {
+		writeTestBlock(t, sink, height)
+	}
+	// the consumer hasn't acknowledged any block
+	segments, err := listSegments(dir)
+	require.NoError(t, err)
+	require.Len(t, segments, 5)
+
+	require.NoError(t, AckOffset(dir, "indexer", 2))
+	offset, found, err := ReadOffset(dir, "indexer")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, int64(2), offset)
+
+	writeTestBlock(t, sink, 6)
+	segments, err = listSegments(dir)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), segments[0].firstHeight)
+
+	require.NoError(t, AckOffset(dir, "indexer", 6))
+	writeTestBlock(t, sink, 7)
+	segments, err = listSegments(dir)
+	require.NoError(t, err)
+	require.Equal(t, int64(6), segments[0].firstHeight)
+
+	_, err = NewSegmentReader(dir, 1)
+	require.ErrorIs(t, err, ErrHeightNotRetained)
+
+	require.Error(t, AckOffset(dir, "../indexer", 1))
+	_, err = NewSink(SinkConfig{Consumers: []string{"a/b"}}, t.TempDir(), coretesting.NewNopLogger())
+	require.Error(t, err)
+}
+
+func TestSink_ConsumerLag(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultSinkConfig()
+	cfg.Consumers = []string{"indexer"}
+	cfg.MaxConsumerLag = 1
+	sink := newTestSink(t, dir, cfg)
+
+	writeTestBlock(t, sink, 1)
+
+	// the block commit is paused until the consumer acknowledges the block 1
+	ctx := context.Background()
+	require.NoError(t, sink.ListenDeliverBlock(ctx, ListenDeliverBlockRequest{BlockHeight: 2}))
+	done := make(chan error)
+	go func() {
+		done <- sink.ListenStateChanges(ctx, nil)
+	}()
+	select {
+	case <-done:
+		t.Fatal("the block commit should be paused")
+	case <-time.After(3 * lagPollInterval):
+	}
+	require.NoError(t, AckOffset(dir, "indexer", 1))
+	require.NoError(t, <-done)
+
+	// the pause is interrupted by the context
+	ctx, cancel := context.WithTimeout(ctx, 3*lagPollInterval)
+	defer cancel()
+	require.NoError(t, sink.ListenDeliverBlock(ctx, ListenDeliverBlockRequest{BlockHeight: 3}))
+	require.ErrorIs(t, sink.ListenStateChanges(ctx, nil), context.DeadlineExceeded)
+	require.Equal(t, int64(3), sink.LastHeight())
+}
@@ -32,55 +32,71 @@ import (
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 )
 
+// genesisTime is the time of the genesis of the test apps.
+var genesisTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
 func NewTestApp(t *testing.T) (*SimApp[transaction.Tx], context.Context) {
 	t.Helper()
 
-	logger := log.NewTestLogger(t)
+	// generate genesis account
+	senderPrivKey := secp256k1.GenPrivKey()
+	acc := authtypes.NewBaseAccount(senderPrivKey.PubKey().Address().Bytes(), senderPrivKey.PubKey(), 0, 0)
+	coins := sdk.NewCoins(sdk.NewCoin(sdk.DefaultBondDenom, sdkmath.NewInt(100000000000000)))
+
+	return newTestApp(t, log.NewTestLogger(t), viper.New(), []authtypes.GenesisAccount{acc}, coins)
+}
+
+// newTestApp creates an app with the settings of vp and initializes its genesis
+// with a single validator and the given accounts, each holding coins.
+func newTestApp(
+	tb testing.TB,
+	logger log.Logger,
+	vp *viper.Viper,
+	accounts []authtypes.GenesisAccount,
+	coins sdk.Coins,
+) (*SimApp[transaction.Tx], context.Context) {
+	tb.Helper()
 
-	vp := viper.New()
 	vp.Set(serverv2store.FlagAppDBBackend, string(db.DBTypeGoLevelDB))
-	vp.Set(serverv2.FlagHome, t.TempDir())
+	vp.Set(serverv2.FlagHome, tb.TempDir())
 
 	app, err := NewSimApp[transaction.Tx](depinject.Configs(
 		depinject.Supply(logger, runtime.GlobalConfig(vp.AllSettings()))),
 	)
-	require.NoError(t, err)
+	require.NoError(tb, err)
 
 	genesis := app.ModuleManager().DefaultGenesis()
 
 	privVal := mock.NewPV()
 	pubKey, err := privVal.GetPubKey()
-	require.NoError(t, err)
+	require.NoError(tb, err)
 
 	// create validator set with single validator
 	validator := types.NewValidator(pubKey, 1)
 	valSet := types.NewValidatorSet([]*types.Validator{validator})
 
-	// generate genesis account
-	senderPrivKey := secp256k1.GenPrivKey()
-	acc := authtypes.NewBaseAccount(senderPrivKey.PubKey().Address().Bytes(), senderPrivKey.PubKey(), 0, 0)
-	accAddr, err := app.txConfig.SigningContext().AddressCodec().BytesToString(acc.GetAddress())
-	require.NoError(t, err)
-	balance := banktypes.Balance{
-		Address: accAddr,
-		Coins:   sdk.NewCoins(sdk.NewCoin(sdk.DefaultBondDenom, sdkmath.NewInt(100000000000000))),
+	balances := make([]banktypes.Balance, len(accounts))
+	for i, acc := range accounts {
+		accAddr, err := app.txConfig.SigningContext().AddressCodec().BytesToString(acc.GetAddress())
+		require.NoError(tb, err)
+		balances[i] = banktypes.Balance{Address: accAddr, Coins: coins}
 	}
 
 	genesis, err = simtestutil.GenesisStateWithValSet(
 		app.AppCodec(),
 		genesis,
 		valSet,
-		[]authtypes.GenesisAccount{acc},
-		balance,
+		accounts,
+		balances...,
 	)
-	require.NoError(t, err)
+	require.NoError(tb, err)
 
 	genesisBytes, err := json.Marshal(genesis)
-	require.NoError(t, err)
+	require.NoError(tb, err)
 
 	st := app.Store()
 	ci, err := st.LastCommitID()
-	require.NoError(t, err)
+	require.NoError(tb, err)
 
 	bz := sha256.Sum256([]byte{})
 
@@ -89,7 +105,7 @@ func NewTestApp(t *testing.T) (*SimApp[transaction.Tx], context.Context) {
 	_, newState, err := app.InitGenesis(
 		ctx,
 		&server.BlockRequest[transaction.Tx]{
-			Time:      time.Now(),
+			Time:      genesisTime,
 			Hash:      bz[:],
 			ChainId:   "theChain",
 			AppHash:   ci.Hash,
@@ -99,13 +115,13 @@ func NewTestApp(t *testing.T) (*SimApp[transaction.Tx], context.Context) {
 		genesisBytes,
 		nil,
 	)
-	require.NoError(t, err)
+	require.NoError(tb, err)
 
 	changes, err := newState.GetStateChanges()
-	require.NoError(t, err)
+	require.NoError(tb, err)
 
 	_, err = st.Commit(&store.Changeset{Version: 1, Changes: changes})
-	require.NoError(t, err)
+	require.NoError(tb, err)
 
 	return app, ctx
 }
@@ -0,0 +1,137 @@
+package simapp
+
+import (
+	"context"
+	"crypto/sha256"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/core/comet"
+	corecontext "cosmossdk.io/core/context"
+	"cosmossdk.io/core/server"
+	"cosmossdk.io/core/store"
+	"cosmossdk.io/core/transaction"
+	"cosmossdk.io/log"
+	banktypes "cosmossdk.io/x/bank/types"
+	feemarkettypes "cosmossdk.io/x/feemarket/types"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+// TestFeeMarket runs the fee checker of the fee market in the DeductFeeDecorator
+// of the app and its EndBlocker, on top of the default genesis: a base fee of
+// 0.001stake per gas and a target of 10M gas per block.
+func TestFeeMarket(t *testing.T) {
+	privs := make([]cryptotypes.PrivKey, 3)
+	accounts := make([]authtypes.GenesisAccount, len(privs))
+	for i := range privs {
+		privs[i] = secp256k1.GenPrivKey()
+		accounts[i] = authtypes.NewBaseAccount(privs[i].PubKey().Address().Bytes(), privs[i].PubKey(), uint64(i), 0)
+	}
+	coins := sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 1_000_000_000))
+	app, ctx := newTestApp(t, log.NewNopLogger(), viper.New(), accounts, coins)
+	// the comet info is required by the distribution module
+	ctx = context.WithValue(ctx, corecontext.CometInfoKey, comet.Info{})
+
+	r := rand.New(rand.NewSource(1))
+	sendTx := func(from int, seq uint64, fee int64) transaction.Tx {
+		msg := banktypes.NewMsgSend(
+			accounts[from].GetAddress().String(),
+			accounts[len(accounts)-1].GetAddress().String(),
+			sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 100)),
+		)
+		tx, err := simtestutil.GenSignedMockTx(
+			r,
+			app.TxConfig(),
+			[]sdk.Msg{msg},
+			sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, fee)),
+			simtestutil.DefaultGenTxGas,
+			"theChain",
+			[]uint64{accounts[from].GetAccountNumber()},
+			[]uint64{seq},
+			privs[from],
+		)
+		require.NoError(t, err)
+		return tx.(transaction.Tx)
+	}
+
+	deliver := func(height uint64, txs ...transaction.Tx) *server.BlockResponse {
+		ci, err := app.Store().LastCommitID()
+		require.NoError(t, err)
+		bz := sha256.Sum256([]byte{})
+		res, state, err := app.DeliverBlock(ctx, &server.BlockRequest[transaction.Tx]{
+			Height:  height,
+			Time:    genesisTime.Add(time.Duration(height) * 5 * time.Second),
+			Hash:    bz[:],
+			AppHash: ci.Hash,
+			ChainId: "theChain",
+			Txs:     txs,
+		})
+		require.NoError(t, err)
+		changes, err := state.GetStateChanges()
+		require.NoError(t, err)
+		_, err = app.Store().Commit(&store.Changeset{Version: height, Changes: changes})
+		require.NoError(t, err)
+		return res
+	}
+
+	baseFeeEvent := func(res *server.BlockResponse) map[string]string {
+		for _, e := range res.EndBlockEvents {
+			if e.Type != feemarkettypes.EventTypeBaseFee {
+				continue
+			}
+			attrs, err := e.Attributes()
+			require.NoError(t, err)
+			m := make(map[string]string, len(attrs))
+			for _, attr := range attrs {
+				m[attr.Key] = attr.Value
+			}
+			return m
+		}
+		require.Fail(t, "no base fee event")
+		return nil
+	}
+
+	// the txs pay at least the base fee for their gas limit: 10_000stake
+	res := deliver(2,
+		sendTx(0, 0, 10_000),
+		sendTx(1, 0, 20_000),
+		sendTx(2, 0, 9_999),
+	)
+	require.Len(t, res.TxResults, 3)
+	require.NoError(t, res.TxResults[0].Error)
+	require.NoError(t, res.TxResults[1].Error)
+	require.ErrorIs(t, res.TxResults[2].Error, sdkerrors.ErrInsufficientFee)
+
+	// the base fee paid by the block is burned, and the base fee increases by
+	// (20M - 10M) / 10M / 8 = 12.5% as the block is above the target
+	require.Equal(t, map[string]string{
+		feemarkettypes.AttributeKeyBaseFee:  "0.001125000000000000",
+		feemarkettypes.AttributeKeyBlockGas: "20000000",
+		feemarkettypes.AttributeKeyBurned:   "20000stake",
+	}, baseFeeEvent(res))
+
+	// the next block requires the new base fee, which doesn't change as the block
+	// is at the target
+	res = deliver(3,
+		sendTx(0, 1, 10_000),
+		sendTx(1, 1, 11_250),
+	)
+	require.Len(t, res.TxResults, 2)
+	require.ErrorIs(t, res.TxResults[0].Error, sdkerrors.ErrInsufficientFee)
+	require.NoError(t, res.TxResults[1].Error)
+	require.Equal(t, map[string]string{
+		feemarkettypes.AttributeKeyBaseFee:  "0.001125000000000000",
+		feemarkettypes.AttributeKeyBlockGas: "10000000",
+		feemarkettypes.AttributeKeyBurned:   "11250stake",
+	}, baseFeeEvent(res))
+}
@@ -0,0 +1,170 @@
+package simapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/core/comet"
+	corecontext "cosmossdk.io/core/context"
+	"cosmossdk.io/core/server"
+	"cosmossdk.io/core/store"
+	"cosmossdk.io/core/transaction"
+	"cosmossdk.io/log"
+	serverv2 "cosmossdk.io/server/v2"
+	banktypes "cosmossdk.io/x/bank/types"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	simtestutil "github.com/cosmos/cosmos-sdk/testutil/sims"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+// bankSends holds the accounts of a block of independent bank sends, every tx
+// of the block paying fees.
+type bankSends struct {
+	privs    []cryptotypes.PrivKey
+	accounts []authtypes.GenesisAccount
+}
+
+func newBankSends(numTxs int) bankSends {
+	s := bankSends{
+		privs:    make([]cryptotypes.PrivKey, 2*numTxs),
+		accounts: make([]authtypes.GenesisAccount, 2*numTxs),
+	}
+	for i := range s.privs {
+		s.privs[i] = secp256k1.GenPrivKey()
+		s.accounts[i] = authtypes.NewBaseAccount(s.privs[i].PubKey().Address().Bytes(), s.privs[i].PubKey(), uint64(i), 0)
+	}
+	return s
+}
+
+// newApp creates an app executing the txs of a block on the given number of
+// workers, and returns the block of bank sends on top of its genesis.
+func (s bankSends) newApp(tb testing.TB, workers int) (*SimApp[transaction.Tx], context.Context, *server.BlockRequest[transaction.Tx]) {
+	tb.Helper()
+
+	vp := viper.New()
+	vp.Set(serverv2.FlagParallelExecutionWorkers, workers)
+	coins := sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 1_000_000_000))
+	app, ctx := newTestApp(tb, log.NewNopLogger(), vp, s.accounts, coins)
+
+	// the first half of the accounts send coins to the second half
+	r := rand.New(rand.NewSource(1))
+	numTxs := len(s.accounts) / 2
+	txs := make([]transaction.Tx, numTxs)
+	for i := range txs {
+		msg := banktypes.NewMsgSend(
+			s.accounts[i].GetAddress().String(),
+			s.accounts[numTxs+i].GetAddress().String(),
+			sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 100)),
+		)
+		tx, err := simtestutil.GenSignedMockTx(
+			r,
+			app.TxConfig(),
+			[]sdk.Msg{msg},
+			sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 20_000)),
+			simtestutil.DefaultGenTxGas,
+			"theChain",
+			[]uint64{s.accounts[i].GetAccountNumber()},
+			[]uint64{0},
+			s.privs[i],
+		)
+		require.NoError(tb, err)
+		txs[i] = tx.(transaction.Tx)
+	}
+
+	ci, err := app.Store().LastCommitID()
+	require.NoError(tb, err)
+	bz := sha256.Sum256([]byte{})
+
+	// the comet info is required by the distribution module
+	ctx = context.WithValue(ctx, corecontext.CometInfoKey, comet.Info{})
+	return app, ctx, &server.BlockRequest[transaction.Tx]{
+		Height:  2,
+		Time:    genesisTime.Add(5 * time.Second),
+		Hash:    bz[:],
+		AppHash: ci.Hash,
+		ChainId: "theChain",
+		Txs:     txs,
+	}
+}
+
+func TestParallelBankSends(t *testing.T) {
+	sends := newBankSends(200)
+
+	// the validator and the genesis time of the apps differ, only the state of
+	// the accounts is compared
+	deliver := func(workers int) (gas, results []string, changes map[string][]store.KVPair) {
+		app, ctx, block := sends.newApp(t, workers)
+		res, state, err := app.DeliverBlock(ctx, block)
+		require.NoError(t, err)
+
+		for i, txResult := range res.TxResults {
+			require.NoError(t, txResult.Error, "tx %d", i)
+			gas = append(gas, fmt.Sprintf("gas=%d/%d", txResult.GasUsed, txResult.GasWanted))
+			for _, e := range txResult.Events {
+				attrs, err := e.Attributes()
+				require.NoError(t, err)
+				results = append(results, fmt.Sprintf("%s %v", e.Type, attrs))
+			}
+		}
+
+		stateChanges, err := state.GetStateChanges()
+		require.NoError(t, err)
+		changes = map[string][]store.KVPair{}
+		for _, c := range stateChanges {
+			if actor := string(c.Actor); actor == "acc" || actor == banktypes.StoreKey {
+				changes[actor] = c.StateChanges
+			}
+		}
+		require.Len(t, changes, 2)
+		return gas, results, changes
+	}
+
+	parallelGas, parallelResults, parallelChanges := deliver(8)
+	gas, results, changes := deliver(2)
+	require.Equal(t, parallelGas, gas)
+	require.Equal(t, parallelResults, results)
+	require.Equal(t, parallelChanges, changes)
+
+	// the fees are credited to the fee collector at the end of the block with the
+	// parallel execution, only the gas used to record them and the removal of the
+	// records differ from the sequential execution
+	_, results, changes = deliver(0)
+	require.Equal(t, parallelResults, results)
+	require.Equal(t, parallelChanges["acc"], changes["acc"])
+	var bankChanges []store.KVPair
+	for _, kv := range parallelChanges[banktypes.StoreKey] {
+		if !bytes.HasPrefix(kv.Key, banktypes.DeferredCreditsPrefix) {
+			bankChanges = append(bankChanges, kv)
+		}
+	}
+	require.Equal(t, bankChanges, changes[banktypes.StoreKey])
+}
+
+func BenchmarkParallelBankSends(b *testing.B) {
+	sends := newBankSends(1000)
+
+	for _, workers := range []int{1, 4, 8, 16} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			app, ctx, block := sends.newApp(b, workers)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				res, _, err := app.DeliverBlock(ctx, block)
+				require.NoError(b, err)
+				require.NoError(b, res.TxResults[0].Error)
+			}
+			b.ReportMetric(float64(len(block.Txs)*b.N)/b.Elapsed().Seconds(), "txs/s")
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"cosmossdk.io/log"
 	runtimev2 "cosmossdk.io/runtime/v2"
 	serverv2 "cosmossdk.io/server/v2"
+	"cosmossdk.io/server/v2/api/graphql"
 	grpcserver "cosmossdk.io/server/v2/api/grpc"
 	"cosmossdk.io/server/v2/api/grpcgateway"
 	"cosmossdk.io/server/v2/api/rest"
@@ -88,6 +89,7 @@ func InitRootCmd[T transaction.Tx](
 			&rest.Server[T]{},
 			&grpcgateway.Server[T]{},
 			&swagger.Server[T]{},
+			&graphql.Server[T]{},
 		)
 	}
 
@@ -95,7 +97,11 @@ func InitRootCmd[T transaction.Tx](
 	simApp := deps.SimApp
 
 	// store component (not a server)
-	storeComponent, err := serverstore.New[T](simApp.Store(), deps.GlobalConfig)
+	storeComponent, err := serverstore.New[T](
+		simApp.Store(),
+		deps.GlobalConfig,
+		serverstore.WithDecoderResolver[T](simApp.App.SchemaDecoderResolver()),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -135,6 +141,17 @@ func InitRootCmd[T transaction.Tx](
 		return nil, err
 	}
 
+	graphqlServer, err := graphql.New[T](
+		logger,
+		simApp.App.SchemaDecoderResolver(),
+		deps.ClientContext.AddressCodec,
+		consensusServer.IndexerInfos(),
+		deps.GlobalConfig,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	grpcServer, err := grpcserver.New[T](
 		logger,
 		simApp.InterfaceRegistry(),
@@ -177,6 +194,7 @@ func InitRootCmd[T transaction.Tx](
 		restServer,
 		grpcgatewayServer,
 		swaggerServer,
+		graphqlServer,
 	)
 }
 
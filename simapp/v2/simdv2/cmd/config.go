@@ -97,12 +97,14 @@ func initCometOptions[T transaction.Tx]() cometbft.ServerOptions[T] {
 	// serverOptions.ProcessProposalHandler = CustomProcessProposalHandler[T]()
 	// serverOptions.ExtendVoteHandler = CustomExtendVoteHandler[T]()
 
-	// overwrite app mempool, using max-txs option
+	// overwrite app mempool, using the mempool options
 	// serverOptions.Mempool = func(cfg map[string]any) mempool.Mempool[T] {
 	// 	if maxTxs := cast.ToInt(cfg[cometbft.FlagMempoolMaxTxs]); maxTxs >= 0 {
-	// 		return sdkmempool.NewSenderNonceMempool(
-	// 			sdkmempool.SenderNonceMaxTxOpt(maxTxs),
-	// 		)
+	// 		return mempool.NewPriorityNonceMempool(mempool.Config{
+	// 			MaxTxs:    maxTxs,
+	// 			TTL:       cast.ToUint64(cfg[cometbft.FlagMempoolTTL]),
+	// 			PriceBump: cast.ToUint64(cfg[cometbft.FlagMempoolPriceBump]),
+	// 		}, mempool.SDKTxInfo[T](sdk.DefaultBondDenom))
 	// 	}
 
 	// 	return mempool.NoOpMempool[T]{}
@@ -0,0 +1,141 @@
+package commitment
+
+import (
+	"fmt"
+	"time"
+
+	"cosmossdk.io/store/v2/metrics"
+)
+
+// eraseJob erases the data of a removed store key or orphaned data.
+type eraseJob struct {
+	name   string
+	eraser Eraser
+	// done is called once the data is erased
+	done func() error
+}
+
+// SetMetrics sets the metrics which report the bytes reclaimed by erasing the
+// data of the removed store keys.
+func (c *CommitStore) SetMetrics(m metrics.StoreMetrics) {
+	c.eraseMtx.Lock()
+	defer c.eraseMtx.Unlock()
+	c.metrics = m
+}
+
+// pruneRemovedStoreKeys erases in the background the trees of the store keys
+// removed at or before the version, whose versions are all pruned.
+func (c *CommitStore) pruneRemovedStoreKeys(version uint64) error {
+	removed, err := c.metadata.getRemovedStoreKeys(version)
+	if err != nil {
+		return err
+	}
+
+	var jobs []eraseJob
+	c.eraseMtx.Lock()
+	c.oldTreesMtx.Lock()
+	for _, r := range removed {
+		if c.erasingKeys[r.storeKey] {
+			continue
+		}
+		tree, ok := c.oldTrees[r.storeKey]
+		if !ok {
+			c.oldTreesMtx.Unlock()
+			c.eraseMtx.Unlock()
+			return fmt.Errorf("store %s not found in oldTrees", r.storeKey)
+		}
+		delete(c.oldTrees, r.storeKey)
+		if c.erasingKeys == nil {
+			c.erasingKeys = make(map[string]bool)
+		}
+		c.erasingKeys[r.storeKey] = true
+
+		// a store key added again uses the same data, only its old tree is closed
+		_, inUse := c.multiTrees[r.storeKey]
+		eraser, ok := tree.(Eraser)
+		if !ok || inUse {
+			eraser = EraserFunc(func() (uint64, error) {
+				return 0, tree.Close()
+			})
+		}
+		jobs = append(jobs, eraseJob{
+			name:   r.storeKey,
+			eraser: eraser,
+			done: func() error {
+				return c.metadata.deleteRemovedStoreKey(r.version, r.storeKey, !inUse)
+			},
+		})
+	}
+	c.oldTreesMtx.Unlock()
+	c.eraseMtx.Unlock()
+
+	c.enqueueErase(jobs...)
+	return nil
+}
+
+// EraseOrphans erases in the background the data which isn't referenced by the
+// commitment store anymore, keyed by a name used in the logs. The data must be
+// erased again if the process stops before the jobs are done.
+func (c *CommitStore) EraseOrphans(orphans map[string]Eraser) {
+	jobs := make([]eraseJob, 0, len(orphans))
+	for name, eraser := range orphans {
+		jobs = append(jobs, eraseJob{name: name, eraser: eraser})
+	}
+	c.enqueueErase(jobs...)
+}
+
+// enqueueErase queues the erase jobs, which run one at a time in the background.
+func (c *CommitStore) enqueueErase(jobs ...eraseJob) {
+	if len(jobs) == 0 {
+		return
+	}
+
+	c.eraseMtx.Lock()
+	defer c.eraseMtx.Unlock()
+	c.eraseQueue = append(c.eraseQueue, jobs...)
+	if c.erasing != nil {
+		return
+	}
+	c.erasing = make(chan struct{})
+	go c.runEraseJobs(c.erasing)
+}
+
+func (c *CommitStore) runEraseJobs(done chan struct{}) {
+	defer close(done)
+	for {
+		c.eraseMtx.Lock()
+		if len(c.eraseQueue) == 0 {
+			c.erasing = nil
+			c.eraseMtx.Unlock()
+			return
+		}
+		job := c.eraseQueue[0]
+		c.eraseQueue = c.eraseQueue[1:]
+		m := c.metrics
+		c.eraseMtx.Unlock()
+
+		start := time.Now()
+		reclaimed, err := job.eraser.Erase()
+		if err == nil && job.done != nil {
+			err = job.done()
+		}
+		if err != nil {
+			// the removed store key is erased again once the store is reloaded
+			c.logger.Error("failed to erase the data of the store", "store_key", job.name, "err", err)
+			continue
+		}
+		m.MeasureSince(start, "commitment", "erase")
+		m.IncrCounter(float32(reclaimed), "commitment", "erase", "reclaimed_bytes")
+		c.logger.Info("erased the data of the store", "store_key", job.name, "reclaimed_bytes", reclaimed, "duration", time.Since(start))
+	}
+}
+
+// waitErased waits for the erase jobs to be done.
+func (c *CommitStore) waitErased() {
+	c.eraseMtx.Lock()
+	erasing := c.erasing
+	c.eraseMtx.Unlock()
+	if erasing != nil {
+		<-erasing
+	}
+}
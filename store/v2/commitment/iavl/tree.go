@@ -1,8 +1,11 @@
 package iavl
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/cosmos/iavl"
 	ics23 "github.com/cosmos/ics23/go"
@@ -14,14 +17,18 @@ import (
 )
 
 var (
-	_ commitment.Tree      = (*IavlTree)(nil)
-	_ commitment.Reader    = (*IavlTree)(nil)
-	_ store.PausablePruner = (*IavlTree)(nil)
+	_ commitment.Tree          = (*IavlTree)(nil)
+	_ commitment.Reader        = (*IavlTree)(nil)
+	_ commitment.WorkingHasher = (*IavlTree)(nil)
+	_ commitment.Differ        = (*IavlTree)(nil)
+	_ commitment.Eraser        = (*IavlTree)(nil)
+	_ store.PausablePruner     = (*IavlTree)(nil)
 )
 
 // IavlTree is a wrapper around iavl.MutableTree.
 type IavlTree struct {
 	tree *iavl.MutableTree
+	db   corestore.KVStoreWithBatch
 	// it is only used for new store key during the migration process.
 	initialVersion uint64
 }
@@ -31,6 +38,7 @@ func NewIavlTree(db corestore.KVStoreWithBatch, logger log.Logger, cfg *Config)
 	tree := iavl.NewMutableTree(db, cfg.CacheSize, cfg.SkipFastStorageUpgrade, logger, iavl.AsyncPruningOption(true))
 	return &IavlTree{
 		tree: tree,
+		db:   db,
 	}
 }
 
@@ -155,6 +163,71 @@ func (t *IavlTree) Iterator(version uint64, start, end []byte, ascending bool) (
 	return immutableTree.Iterator(start, end, ascending)
 }
 
+// Diff implements the Differ interface. The changes are extracted by diffing
+// every version of the range with its predecessor, skipping the subtrees they
+// share, so it only reads the nodes written in the range. Since every version
+// of the range is diffed, they must all be retained.
+func (t *IavlTree) Diff(from, to uint64, start, end []byte, fn func(store.KVChange) error) error {
+	if from >= to {
+		return nil
+	}
+	fromTree, err := t.tree.GetImmutable(int64(from))
+	if err != nil {
+		return fmt.Errorf("failed to get immutable tree at version %d: %w", from, err)
+	}
+	toTree, err := t.tree.GetImmutable(int64(to))
+	if err != nil {
+		return fmt.Errorf("failed to get immutable tree at version %d: %w", to, err)
+	}
+
+	// the last change of every key in the range
+	changes := make(map[string]*iavl.KVPair)
+	err = toTree.TraverseStateChanges(int64(from)+1, int64(to), func(_ int64, changeSet *iavl.ChangeSet) error {
+		for _, pair := range changeSet.Pairs {
+			if (start == nil || bytes.Compare(pair.Key, start) >= 0) && (end == nil || bytes.Compare(pair.Key, end) < 0) {
+				changes[string(pair.Key)] = pair
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to traverse the state changes from version %d to %d: %w", from, to, err)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(changes)) {
+		pair := changes[key]
+		oldValue, err := fromTree.Get(pair.Key)
+		if err != nil {
+			return err
+		}
+		if oldValue == nil {
+			// distinguish an empty value from a missing key
+			has, err := fromTree.Has(pair.Key)
+			if err != nil {
+				return err
+			}
+			if has {
+				oldValue = []byte{}
+			}
+		}
+		var newValue []byte
+		if !pair.Delete {
+			newValue = pair.Value
+			if newValue == nil {
+				newValue = []byte{}
+			}
+		}
+		// the key may be changed back to its value at the from version
+		if (oldValue == nil) == (newValue == nil) && bytes.Equal(oldValue, newValue) {
+			continue
+		}
+		if err := fn(store.KVChange{Key: pair.Key, OldValue: oldValue, NewValue: newValue}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetLatestVersion returns the latest version of the tree.
 func (t *IavlTree) GetLatestVersion() (uint64, error) {
 	v, err := t.tree.GetLatestVersion()
@@ -218,6 +291,48 @@ func (t *IavlTree) Close() error {
 	return t.tree.Close()
 }
 
+// eraseBatchSize is the number of keys deleted by a batch when the tree is erased.
+const eraseBatchSize = 10_000
+
+// Erase implements commitment.Eraser. The nodes of the tree are deleted from its
+// database by batches, the reclaimed bytes are the size of their keys and values.
+func (t *IavlTree) Erase() (uint64, error) {
+	if err := t.tree.Close(); err != nil {
+		return 0, err
+	}
+
+	var reclaimed uint64
+	for {
+		// the keys are collected before deleting them, since some databases don't
+		// support writes while iterating
+		itr, err := t.db.Iterator(nil, nil)
+		if err != nil {
+			return reclaimed, err
+		}
+		var keys [][]byte
+		for ; itr.Valid() && len(keys) < eraseBatchSize; itr.Next() {
+			keys = append(keys, itr.Key())
+			reclaimed += uint64(len(itr.Key()) + len(itr.Value()))
+		}
+		if err := errors.Join(itr.Error(), itr.Close()); err != nil {
+			return reclaimed, err
+		}
+		if len(keys) == 0 {
+			return reclaimed, nil
+		}
+
+		batch := t.db.NewBatch()
+		for _, key := range keys {
+			if err := batch.Delete(key); err != nil {
+				return reclaimed, errors.Join(err, batch.Close())
+			}
+		}
+		if err := errors.Join(batch.Write(), batch.Close()); err != nil {
+			return reclaimed, err
+		}
+	}
+}
+
 func (t *IavlTree) IsConcurrentSafe() bool {
 	return false
 }
@@ -1,7 +1,11 @@
 package iavlv2
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 
 	"github.com/cosmos/iavl/v2"
 	ics23 "github.com/cosmos/ics23/go"
@@ -15,6 +19,7 @@ import (
 var (
 	_ commitment.Tree      = (*Tree)(nil)
 	_ commitment.Reader    = (*Tree)(nil)
+	_ commitment.Eraser    = (*Tree)(nil)
 	_ store.PausablePruner = (*Tree)(nil)
 )
 
@@ -143,9 +148,9 @@ func (t *Tree) Iterator(version uint64, start, end []byte, ascending bool) (core
 	if ascending {
 		// inclusive = false is IAVL v1's default behavior.
 		// the read expectations of certain modules (like x/staking) will cause a panic if this is changed.
-		return t.tree.Iterator(start, end, false)
+		return cloned.Iterator(start, end, false)
 	} else {
-		return t.tree.ReverseIterator(start, end)
+		return cloned.ReverseIterator(start, end)
 	}
 }
 
@@ -175,6 +180,38 @@ func (t *Tree) Close() error {
 	return t.tree.Close()
 }
 
+// Erase implements commitment.Eraser, the sqlite directory of the tree is deleted.
+func (t *Tree) Erase() (uint64, error) {
+	if err := t.tree.Close(); err != nil {
+		return 0, err
+	}
+	return EraseDir(t.path)
+}
+
+// EraseDir deletes the sqlite directory of a tree and returns the size of its
+// files.
+func EraseDir(path string) (uint64, error) {
+	var size uint64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += uint64(info.Size())
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return 0, err
+	}
+	return size, os.RemoveAll(path)
+}
+
 func (t *Tree) Prune(version uint64) error {
 	// do nothing, IAVL v2 has its own advanced pruning mechanism
 	return nil
@@ -187,10 +224,6 @@ func (t *Tree) IsConcurrentSafe() bool {
 	return true
 }
 
-func (t *Tree) WorkingHash() []byte {
-	return t.tree.Hash()
-}
-
 func isHighBitSet(version uint64) error {
 	if version&(1<<63) != 0 {
 		return fmt.Errorf("%d too large; uint64 with the highest bit set are not supported", version)
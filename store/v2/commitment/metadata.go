@@ -1,6 +1,8 @@
 package commitment
 
 import (
+	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 
@@ -15,6 +17,8 @@ const (
 	commitInfoKeyFmt      = "s/%d" // s/<version>
 	latestVersionKey      = "s/latest"
 	removedStoreKeyPrefix = "s/removed/" // s/removed/<version>/<store-name>
+	treeTypeKeyFmt        = "s/tree/%s"  // s/tree/<store-name>
+	pendingCommitKey      = "s/pending"
 )
 
 // MetadataStore is a store for metadata related to the commitment store.
@@ -56,6 +60,21 @@ func (m *MetadataStore) setLatestVersion(version uint64) error {
 	return m.kv.Set([]byte(latestVersionKey), bz)
 }
 
+// GetTreeType returns the commitment backend type recorded for the store key,
+// it is empty if no type is recorded.
+func (m *MetadataStore) GetTreeType(storeKey string) (string, error) {
+	value, err := m.kv.Get([]byte(fmt.Sprintf(treeTypeKeyFmt, storeKey)))
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// SetTreeType records the commitment backend type of the store key.
+func (m *MetadataStore) SetTreeType(storeKey, treeType string) error {
+	return m.kv.Set([]byte(fmt.Sprintf(treeTypeKeyFmt, storeKey)), []byte(treeType))
+}
+
 // GetCommitInfo returns the commit info for the given version.
 func (m *MetadataStore) GetCommitInfo(version uint64) (*proof.CommitInfo, error) {
 	key := []byte(fmt.Sprintf(commitInfoKeyFmt, version))
@@ -89,6 +108,29 @@ func (m *MetadataStore) flushCommitInfo(version uint64, cInfo *proof.CommitInfo)
 	defer func() {
 		err = errors.Join(err, batch.Close())
 	}()
+	if err := setCommitInfo(batch, version, cInfo); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// flushPendingCommitInfo flushes the commit info of the pending version once it
+// is persisted by all the trees and removes the pending commit.
+func (m *MetadataStore) flushPendingCommitInfo(version uint64, cInfo *proof.CommitInfo) (err error) {
+	batch := m.kv.NewBatch()
+	defer func() {
+		err = errors.Join(err, batch.Close())
+	}()
+	if err := setCommitInfo(batch, version, cInfo); err != nil {
+		return err
+	}
+	if err := batch.Delete([]byte(pendingCommitKey)); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+func setCommitInfo(batch corestore.Batch, version uint64, cInfo *proof.CommitInfo) error {
 	cInfoKey := []byte(fmt.Sprintf(commitInfoKeyFmt, version))
 	value, err := cInfo.Marshal()
 	if err != nil {
@@ -102,14 +144,57 @@ func (m *MetadataStore) flushCommitInfo(version uint64, cInfo *proof.CommitInfo)
 	if err != nil {
 		return err
 	}
-	if err := batch.Set([]byte(latestVersionKey), bz); err != nil {
+	return batch.Set([]byte(latestVersionKey), bz)
+}
+
+// flushPendingCommit records the commit info and the changeset of a version which
+// is committed but not yet persisted by all the trees, so that the version can be
+// replayed if the process stops before it is persisted.
+func (m *MetadataStore) flushPendingCommit(cInfo *proof.CommitInfo, cs *corestore.Changeset) error {
+	cInfoBz, err := cInfo.Marshal()
+	if err != nil {
+		return err
+	}
+	csBz, err := encoding.MarshalChangeset(cs)
+	if err != nil {
 		return err
 	}
 
-	if err := batch.Write(); err != nil {
+	var buf bytes.Buffer
+	buf.Grow(encoding.EncodeBytesSize(cInfoBz) + len(csBz))
+	if err := encoding.EncodeBytes(&buf, cInfoBz); err != nil {
 		return err
 	}
-	return nil
+	buf.Write(csBz)
+
+	return m.kv.Set([]byte(pendingCommitKey), buf.Bytes())
+}
+
+// getPendingCommit returns the commit info and the changeset recorded by
+// flushPendingCommit, they are nil if no version is pending.
+func (m *MetadataStore) getPendingCommit() (*proof.CommitInfo, *corestore.Changeset, error) {
+	value, err := m.kv.Get([]byte(pendingCommitKey))
+	if err != nil {
+		return nil, nil, err
+	}
+	if value == nil {
+		return nil, nil, nil
+	}
+
+	cInfoBz, n, err := encoding.DecodeBytes(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	cInfo := &proof.CommitInfo{}
+	if err := cInfo.Unmarshal(cInfoBz); err != nil {
+		return nil, nil, err
+	}
+	cs := corestore.NewChangeset(uint64(cInfo.Version))
+	if err := encoding.UnmarshalChangeset(cs, value[n:]); err != nil {
+		return nil, nil, err
+	}
+
+	return cInfo, cs, nil
 }
 
 func (m *MetadataStore) flushRemovedStoreKeys(version uint64, storeKeys []string) (err error) {
@@ -128,6 +213,24 @@ func (m *MetadataStore) flushRemovedStoreKeys(version uint64, storeKeys []string
 }
 
 func (m *MetadataStore) GetRemovedStoreKeys(version uint64) (storeKeys [][]byte, err error) {
+	removed, err := m.getRemovedStoreKeys(version)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range removed {
+		storeKeys = append(storeKeys, []byte(r.storeKey))
+	}
+	return storeKeys, nil
+}
+
+// removedStoreKey is a store key removed at a version.
+type removedStoreKey struct {
+	version  uint64
+	storeKey string
+}
+
+// getRemovedStoreKeys returns the store keys removed at or before the version.
+func (m *MetadataStore) getRemovedStoreKeys(version uint64) (removed []removedStoreKey, err error) {
 	end := encoding.BuildPrefixWithVersion(removedStoreKeyPrefix, version+1)
 	iter, err := m.kv.Iterator([]byte(removedStoreKeyPrefix), end)
 	if err != nil {
@@ -140,34 +243,32 @@ func (m *MetadataStore) GetRemovedStoreKeys(version uint64) (storeKeys [][]byte,
 	}()
 
 	for ; iter.Valid(); iter.Next() {
-		storeKey := iter.Key()[len(end):]
-		storeKeys = append(storeKeys, storeKey)
+		key := iter.Key()
+		removed = append(removed, removedStoreKey{
+			version:  binary.BigEndian.Uint64(key[len(removedStoreKeyPrefix):]),
+			storeKey: string(key[len(end):]),
+		})
 	}
-	return storeKeys, nil
+	return removed, nil
 }
 
-func (m *MetadataStore) deleteRemovedStoreKeys(version uint64, removeStore func(storeKey []byte, version uint64) error) (err error) {
-	removedStoreKeys, err := m.GetRemovedStoreKeys(version)
-	if err != nil {
-		return err
-	}
-	if len(removedStoreKeys) == 0 {
-		return nil
-	}
-
+// deleteRemovedStoreKey deletes the record of a store key removed at the
+// version, along with its tree type unless the store key is in use again.
+func (m *MetadataStore) deleteRemovedStoreKey(version uint64, storeKey string, deleteTreeType bool) (err error) {
 	batch := m.kv.NewBatch()
 	defer func() {
 		err = errors.Join(err, batch.Close())
 	}()
-	for _, storeKey := range removedStoreKeys {
-		if err := removeStore(storeKey, version); err != nil {
-			return err
-		}
-		if err := batch.Delete(storeKey); err != nil {
+
+	key := []byte(fmt.Sprintf("%s%s", encoding.BuildPrefixWithVersion(removedStoreKeyPrefix, version), storeKey))
+	if err := batch.Delete(key); err != nil {
+		return err
+	}
+	if deleteTreeType {
+		if err := batch.Delete([]byte(fmt.Sprintf(treeTypeKeyFmt, storeKey))); err != nil {
 			return err
 		}
 	}
-
 	return batch.Write()
 }
 
@@ -0,0 +1,240 @@
+package commitment
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"golang.org/x/sync/errgroup"
+
+	corestore "cosmossdk.io/core/store"
+	"cosmossdk.io/store/v2"
+	"cosmossdk.io/store/v2/internal"
+	"cosmossdk.io/store/v2/internal/conv"
+	"cosmossdk.io/store/v2/proof"
+)
+
+// SetPipelinedCommit enables or disables the pipelined commit.
+//
+// With the pipelined commit, Commit computes the working hash of the trees which
+// implement WorkingHasher, records the changeset of the version in the metadata
+// and returns, while the trees persist the version in the background. The next
+// call touching the trees waits for the version to be persisted, so persisting
+// version N overlaps the execution of the block N+1. The other trees are
+// committed synchronously.
+//
+// If the process stops before the version is persisted by every tree, the
+// version is replayed from the recorded changeset when the CommitStore is
+// created.
+func (c *CommitStore) SetPipelinedCommit(pipelined bool) error {
+	if err := c.waitPersisted(); err != nil {
+		return err
+	}
+	c.pipelined = pipelined
+	c.changes = nil
+	return nil
+}
+
+// waitPersisted waits for the pending version to be persisted. It returns the
+// error of the persistence, which is returned by every following call since the
+// trees can't be used anymore.
+func (c *CommitStore) waitPersisted() error {
+	c.pipelineMtx.Lock()
+	persisting := c.persisting
+	c.pipelineMtx.Unlock()
+
+	if persisting != nil {
+		<-persisting
+	}
+
+	c.pipelineMtx.Lock()
+	defer c.pipelineMtx.Unlock()
+	return c.persistErr
+}
+
+// recordChanges keeps the changes written to the trees in pipelined mode, they
+// are recorded with the pending version on commit.
+func (c *CommitStore) recordChanges(cs *corestore.Changeset) {
+	if c.pipelined {
+		c.changes = append(c.changes, cs.Changes...)
+	}
+}
+
+// commitPipelined commits the version, only the trees which don't implement
+// WorkingHasher are persisted before it returns.
+func (c *CommitStore) commitPipelined(version uint64) (*proof.CommitInfo, error) {
+	var (
+		storeInfos   = make([]*proof.StoreInfo, 0, len(c.multiTrees))
+		pendingTrees = make(map[*proof.StoreInfo]Tree)
+	)
+
+	eg := new(errgroup.Group)
+	eg.SetLimit(store.MaxWriteParallelism)
+	for storeKey, tree := range c.multiTrees {
+		if internal.IsMemoryStoreKey(storeKey) {
+			continue
+		}
+		si := &proof.StoreInfo{Name: storeKey}
+		storeInfos = append(storeInfos, si)
+
+		if hasher, ok := tree.(WorkingHasher); ok {
+			si.CommitId = &proof.CommitID{
+				Version: int64(version),
+				Hash:    hasher.WorkingHash(),
+			}
+			pendingTrees[si] = tree
+			continue
+		}
+
+		if tree.IsConcurrentSafe() {
+			eg.Go(func() error {
+				err := c.commit(tree, si, version)
+				if err != nil {
+					return fmt.Errorf("commit fail: %s: %w", si.Name, err)
+				}
+				return nil
+			})
+		} else {
+			err := c.commit(tree, si, version)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	cInfo := &proof.CommitInfo{
+		Version:    int64(version),
+		StoreInfos: storeInfos,
+	}
+	cs := &corestore.Changeset{Version: version, Changes: c.changes}
+	c.changes = nil
+	if err := c.metadata.flushPendingCommit(cInfo, cs); err != nil {
+		return nil, err
+	}
+
+	persisting := make(chan struct{})
+	c.pipelineMtx.Lock()
+	c.persisting = persisting
+	c.pipelineMtx.Unlock()
+
+	// the store infos of the returned commit info may be sorted by the caller
+	persistedInfo := &proof.CommitInfo{
+		Version:    cInfo.Version,
+		StoreInfos: slices.Clone(storeInfos),
+	}
+	go func() {
+		err := c.persist(version, persistedInfo, pendingTrees)
+
+		c.pipelineMtx.Lock()
+		if err != nil {
+			c.logger.Error("failed to persist version", "version", version, "err", err)
+			c.persistErr = err
+		}
+		if c.resumePruning {
+			c.resumePruning = false
+			c.pausePruning(false)
+		}
+		c.persisting = nil
+		c.pipelineMtx.Unlock()
+		close(persisting)
+	}()
+
+	return cInfo, nil
+}
+
+// persist commits the pending trees, checks that they are committed with their
+// working hash and flushes the commit info of the version.
+func (c *CommitStore) persist(version uint64, cInfo *proof.CommitInfo, pendingTrees map[*proof.StoreInfo]Tree) error {
+	for si, tree := range pendingTrees {
+		committed := &proof.StoreInfo{Name: si.Name}
+		if err := c.commit(tree, committed, version); err != nil {
+			return fmt.Errorf("commit fail: %s: %w", si.Name, err)
+		}
+		if !bytes.Equal(si.GetHash(), committed.GetHash()) {
+			return fmt.Errorf("commit fail: %s: committed hash %X doesn't match the working hash %X", si.Name, committed.GetHash(), si.GetHash())
+		}
+	}
+
+	return c.metadata.flushPendingCommitInfo(version, cInfo)
+}
+
+// recoverPendingCommit replays the version recorded by the pipelined commit if
+// the process stopped before it was persisted by every tree. The version isn't
+// replayed if a store key of the version isn't mounted, so that a CommitStore
+// holding a subset of the trees doesn't lose it.
+func (c *CommitStore) recoverPendingCommit() error {
+	cInfo, cs, err := c.metadata.getPendingCommit()
+	if err != nil {
+		return err
+	}
+	if cInfo == nil {
+		return nil
+	}
+	for _, si := range cInfo.StoreInfos {
+		if _, ok := c.multiTrees[si.Name]; !ok {
+			return nil
+		}
+	}
+
+	version := uint64(cInfo.Version)
+	changes := make(map[string]corestore.KVPairs, len(cs.Changes))
+	for _, pairs := range cs.Changes {
+		storeKey := conv.UnsafeBytesToStr(pairs.Actor)
+		changes[storeKey] = append(changes[storeKey], pairs.StateChanges...)
+	}
+
+	for _, si := range cInfo.StoreInfos {
+		tree := c.multiTrees[si.Name]
+		if _, ok := tree.(WorkingHasher); !ok {
+			// the other trees are persisted before the version is recorded
+			continue
+		}
+		latestVersion, err := tree.GetLatestVersion()
+		if err != nil {
+			return err
+		}
+		if latestVersion >= version {
+			continue
+		}
+
+		c.logger.Info("replaying pending version", "store_key", si.Name, "version", version)
+		switch {
+		case latestVersion == 0:
+			// the store key was added at the pending version
+			if err := tree.SetInitialVersion(version); err != nil {
+				return err
+			}
+		case latestVersion+1 == version:
+			if err := tree.LoadVersion(latestVersion); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("failed to replay version %d of store %s: the latest version is %d", version, si.Name, latestVersion)
+		}
+
+		if err := writeChangeset(tree, corestore.StateChanges{StateChanges: changes[si.Name]}); err != nil {
+			return err
+		}
+		hash, v, err := tree.Commit()
+		if err != nil {
+			return err
+		}
+		if v != version || !bytes.Equal(hash, si.GetHash()) {
+			return fmt.Errorf("failed to replay version %d of store %s: got version %d and hash %X, expected hash %X", version, si.Name, v, hash, si.GetHash())
+		}
+	}
+
+	return c.metadata.flushPendingCommitInfo(version, cInfo)
+}
+
+// pausePruning pauses or resumes the pruning of the trees.
+func (c *CommitStore) pausePruning(pause bool) {
+	for _, tree := range c.multiTrees {
+		if pruner, ok := tree.(store.PausablePruner); ok {
+			pruner.PausePruning(pause)
+		}
+	}
+}
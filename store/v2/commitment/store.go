@@ -7,6 +7,7 @@ import (
 	"maps"
 	"math"
 	"slices"
+	"sync"
 
 	protoio "github.com/cosmos/gogoproto/io"
 	"golang.org/x/sync/errgroup"
@@ -16,6 +17,7 @@ import (
 	"cosmossdk.io/store/v2"
 	"cosmossdk.io/store/v2/internal"
 	"cosmossdk.io/store/v2/internal/conv"
+	"cosmossdk.io/store/v2/metrics"
 	"cosmossdk.io/store/v2/proof"
 	"cosmossdk.io/store/v2/snapshots"
 	snapshotstypes "cosmossdk.io/store/v2/snapshots/types"
@@ -25,7 +27,10 @@ var (
 	_ store.Committer             = (*CommitStore)(nil)
 	_ store.UpgradeableStore      = (*CommitStore)(nil)
 	_ snapshots.CommitSnapshotter = (*CommitStore)(nil)
+	_ snapshots.StoreRestorer     = (*CommitStore)(nil)
 	_ store.PausablePruner        = (*CommitStore)(nil)
+	_ store.StateDiffer           = (*CommitStore)(nil)
+	_ store.BatchProver           = (*CommitStore)(nil)
 
 	// NOTE: It is not recommended to use the CommitStore as a reader. This is only used
 	// during the migration process. Generally, the SC layer does not provide a reader
@@ -49,19 +54,63 @@ type CommitStore struct {
 	// oldTrees is a map of store keys to old trees that have been deleted or renamed.
 	// It is used to get the proof for the old store keys.
 	oldTrees map[string]Tree
+	// oldTreesMtx guards oldTrees, whose trees are erased in the background once
+	// they fall outside the retention window.
+	oldTreesMtx sync.RWMutex
+
+	// pipelined enables the pipelined commit, see SetPipelinedCommit.
+	pipelined bool
+	// changes are the changes written since the last commit in pipelined mode.
+	changes []corestore.StateChanges
+
+	// pipelineMtx guards persisting, persistErr and resumePruning
+	pipelineMtx sync.Mutex
+	// persisting is closed once the pending version is persisted, it is nil when
+	// no version is being persisted
+	persisting chan struct{}
+	// persistErr is the error of the failed persistence of a pending version
+	persistErr error
+	// resumePruning is set when the pruning is resumed while persisting
+	resumePruning bool
+
+	// importMtx serializes the imports of the trees which are not concurrent safe
+	// while the stores are restored concurrently.
+	importMtx sync.Mutex
+
+	// metrics reports the bytes reclaimed by the erase jobs.
+	metrics metrics.StoreMetrics
+	// eraseMtx guards metrics, erasing, eraseQueue and erasingKeys
+	eraseMtx sync.Mutex
+	// erasing is closed once the erase jobs are done, it is nil when no job runs
+	erasing chan struct{}
+	// eraseQueue are the erase jobs waiting to run
+	eraseQueue []eraseJob
+	// erasingKeys are the removed store keys whose tree is being erased
+	erasingKeys map[string]bool
 }
 
-// NewCommitStore creates a new CommitStore instance.
+// NewCommitStore creates a new CommitStore instance. A version left pending by
+// the pipelined commit is replayed.
 func NewCommitStore(trees, oldTrees map[string]Tree, db corestore.KVStoreWithBatch, logger corelog.Logger) (*CommitStore, error) {
-	return &CommitStore{
+	c := &CommitStore{
 		logger:     logger,
 		multiTrees: trees,
 		oldTrees:   oldTrees,
 		metadata:   NewMetadataStore(db),
-	}, nil
+		metrics:    metrics.NoOpMetrics{},
+	}
+	if err := c.recoverPendingCommit(); err != nil {
+		return nil, fmt.Errorf("failed to recover the pending version: %w", err)
+	}
+	return c, nil
 }
 
 func (c *CommitStore) WriteChangeset(cs *corestore.Changeset) error {
+	if err := c.waitPersisted(); err != nil {
+		return err
+	}
+	c.recordChanges(cs)
+
 	eg := new(errgroup.Group)
 	eg.SetLimit(store.MaxWriteParallelism)
 	for _, pairs := range cs.Changes {
@@ -99,6 +148,9 @@ func writeChangeset(tree Tree, changes corestore.StateChanges) error {
 }
 
 func (c *CommitStore) LoadVersion(targetVersion uint64) error {
+	if err := c.waitPersisted(); err != nil {
+		return err
+	}
 	storeKeys := make([]string, 0, len(c.multiTrees))
 	for storeKey := range c.multiTrees {
 		storeKeys = append(storeKeys, storeKey)
@@ -107,6 +159,9 @@ func (c *CommitStore) LoadVersion(targetVersion uint64) error {
 }
 
 func (c *CommitStore) LoadVersionForOverwriting(targetVersion uint64) error {
+	if err := c.waitPersisted(); err != nil {
+		return err
+	}
 	storeKeys := make([]string, 0, len(c.multiTrees))
 	for storeKey := range c.multiTrees {
 		storeKeys = append(storeKeys, storeKey)
@@ -117,14 +172,27 @@ func (c *CommitStore) LoadVersionForOverwriting(targetVersion uint64) error {
 
 // LoadVersionAndUpgrade implements store.UpgradeableStore.
 func (c *CommitStore) LoadVersionAndUpgrade(targetVersion uint64, upgrades *corestore.StoreUpgrades) error {
+	if err := c.waitPersisted(); err != nil {
+		return err
+	}
 	// deterministic iteration order for upgrades (as the underlying store may change and
 	// upgrades make store changes where the execution order may matter)
 	storeKeys := slices.Sorted(maps.Keys(c.multiTrees))
+	// the tree of a removed store key serves the proofs of its versions until it
+	// is erased by the pruning
 	removeTree := func(storeKey string) error {
 		if oldTree, ok := c.multiTrees[storeKey]; ok {
-			if err := oldTree.Close(); err != nil {
-				return err
+			c.oldTreesMtx.Lock()
+			defer c.oldTreesMtx.Unlock()
+			if prevTree, ok := c.oldTrees[storeKey]; ok && prevTree != oldTree {
+				if err := prevTree.Close(); err != nil {
+					return err
+				}
 			}
+			if c.oldTrees == nil {
+				c.oldTrees = make(map[string]Tree)
+			}
+			c.oldTrees[storeKey] = oldTree
 			delete(c.multiTrees, storeKey)
 		}
 		return nil
@@ -221,6 +289,13 @@ func (c *CommitStore) loadVersion(targetVersion uint64, storeKeys []string, over
 }
 
 func (c *CommitStore) Commit(version uint64) (*proof.CommitInfo, error) {
+	if err := c.waitPersisted(); err != nil {
+		return nil, err
+	}
+	if c.pipelined {
+		return c.commitPipelined(version)
+	}
+
 	storeInfos := make([]*proof.StoreInfo, 0, len(c.multiTrees))
 	eg := new(errgroup.Group)
 	eg.SetLimit(store.MaxWriteParallelism)
@@ -284,6 +359,9 @@ func (c *CommitStore) commit(tree Tree, si *proof.StoreInfo, expected uint64) er
 }
 
 func (c *CommitStore) SetInitialVersion(version uint64) error {
+	if err := c.waitPersisted(); err != nil {
+		return err
+	}
 	for _, tree := range c.multiTrees {
 		if err := tree.SetInitialVersion(version); err != nil {
 			return err
@@ -295,19 +373,73 @@ func (c *CommitStore) SetInitialVersion(version uint64) error {
 
 // GetProof returns a proof for the given key and version.
 func (c *CommitStore) GetProof(storeKey []byte, version uint64, key []byte) ([]proof.CommitmentOp, error) {
+	tree, err := c.getProofTree(storeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	iProof, err := tree.GetProof(version, key)
+	if err != nil {
+		return nil, err
+	}
+	storeCommitmentOp, err := c.getStoreProof(storeKey, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return []proof.CommitmentOp{proof.NewIAVLCommitmentOp(key, iProof), *storeCommitmentOp}, nil
+}
+
+// GetBatchProof returns a single proof of existence or non-existence for all
+// the given keys and version. The tree proofs of the keys are combined into a
+// compressed batch proof.
+func (c *CommitStore) GetBatchProof(storeKey []byte, version uint64, keys [][]byte) ([]proof.CommitmentOp, error) {
+	tree, err := c.getProofTree(storeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ops := make([]proof.CommitmentOp, len(keys))
+	for i, key := range keys {
+		iProof, err := tree.GetProof(version, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get proof of key %X: %w", key, err)
+		}
+		ops[i] = proof.NewIAVLCommitmentOp(key, iProof)
+	}
+	batchOp, err := proof.CombineCommitmentOps(ops)
+	if err != nil {
+		return nil, err
+	}
+	storeCommitmentOp, err := c.getStoreProof(storeKey, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return []proof.CommitmentOp{batchOp, *storeCommitmentOp}, nil
+}
+
+// getProofTree returns the tree of the store key, which may be removed but not
+// pruned yet.
+func (c *CommitStore) getProofTree(storeKey []byte) (Tree, error) {
+	if err := c.waitPersisted(); err != nil {
+		return nil, err
+	}
 	rawStoreKey := conv.UnsafeBytesToStr(storeKey)
 	tree, ok := c.multiTrees[rawStoreKey]
 	if !ok {
+		c.oldTreesMtx.RLock()
 		tree, ok = c.oldTrees[rawStoreKey]
+		c.oldTreesMtx.RUnlock()
 		if !ok {
 			return nil, fmt.Errorf("store %s not found", rawStoreKey)
 		}
 	}
+	return tree, nil
+}
 
-	iProof, err := tree.GetProof(version, key)
-	if err != nil {
-		return nil, err
-	}
+// getStoreProof returns the proof of the store in the commit info of the version.
+func (c *CommitStore) getStoreProof(storeKey []byte, version uint64) (*proof.CommitmentOp, error) {
 	cInfo, err := c.metadata.GetCommitInfo(version)
 	if err != nil {
 		return nil, err
@@ -315,13 +447,8 @@ func (c *CommitStore) GetProof(storeKey []byte, version uint64, key []byte) ([]p
 	if cInfo == nil {
 		return nil, fmt.Errorf("commit info not found for version %d", version)
 	}
-	commitOp := proof.NewIAVLCommitmentOp(key, iProof)
 	_, storeCommitmentOp, err := cInfo.GetStoreProof(storeKey)
-	if err != nil {
-		return nil, err
-	}
-
-	return []proof.CommitmentOp{commitOp, *storeCommitmentOp}, nil
+	return storeCommitmentOp, err
 }
 
 // getReader returns a reader for the given store key. It will return an error if the
@@ -329,13 +456,9 @@ func (c *CommitStore) GetProof(storeKey []byte, version uint64, key []byte) ([]p
 // WARNING: This function is only used during the migration process. The SC layer
 // generally does not provide a reader for the CommitStore.
 func (c *CommitStore) getReader(storeKey string) (Reader, error) {
-	var tree Tree
-	if storeTree, ok := c.oldTrees[storeKey]; ok {
-		tree = storeTree
-	} else if storeTree, ok := c.multiTrees[storeKey]; ok {
-		tree = storeTree
-	} else {
-		return nil, fmt.Errorf("store %s not found", storeKey)
+	tree, err := c.getTree(storeKey)
+	if err != nil {
+		return nil, err
 	}
 
 	reader, ok := tree.(Reader)
@@ -346,8 +469,29 @@ func (c *CommitStore) getReader(storeKey string) (Reader, error) {
 	return reader, nil
 }
 
+// getTree returns the tree of the given store key, including the old trees.
+func (c *CommitStore) getTree(storeKey string) (Tree, error) {
+	if err := c.waitPersisted(); err != nil {
+		return nil, err
+	}
+
+	c.oldTreesMtx.RLock()
+	tree, ok := c.oldTrees[storeKey]
+	c.oldTreesMtx.RUnlock()
+	if ok {
+		return tree, nil
+	}
+	if tree, ok := c.multiTrees[storeKey]; ok {
+		return tree, nil
+	}
+	return nil, fmt.Errorf("store %s not found", storeKey)
+}
+
 // VersionExists implements store.VersionedReader.
 func (c *CommitStore) VersionExists(version uint64) (bool, error) {
+	if err := c.waitPersisted(); err != nil {
+		return false, err
+	}
 	latestVersion, err := c.metadata.GetLatestVersion()
 	if err != nil {
 		return false, err
@@ -401,8 +545,77 @@ func (c *CommitStore) ReverseIterator(storeKey []byte, version uint64, start, en
 	return reader.Iterator(version, start, end, false)
 }
 
+// DiffState implements store.StateDiffer. The changes are computed by the tree
+// if it implements Differ, by iterating over the state of both versions
+// otherwise. The state of a store key which is not part of the from version is
+// empty at that version.
+func (c *CommitStore) DiffState(storeKey []byte, from, to uint64, start, end []byte, fn func(store.KVChange) error) (err error) {
+	if from > to {
+		return fmt.Errorf("invalid version range: from version %d is greater than to version %d", from, to)
+	}
+	name := conv.UnsafeBytesToStr(storeKey)
+	hasStore := func(version uint64) (bool, error) {
+		cInfo, err := c.metadata.GetCommitInfo(version)
+		if err != nil {
+			return false, err
+		}
+		if cInfo == nil {
+			return false, fmt.Errorf("version %d does not exist", version)
+		}
+		return slices.ContainsFunc(cInfo.StoreInfos, func(si *proof.StoreInfo) bool {
+			return si.Name == name
+		}), nil
+	}
+
+	tree, err := c.getTree(name)
+	if err != nil {
+		return err
+	}
+	inFrom, err := hasStore(from)
+	if err != nil {
+		return err
+	}
+	inTo, err := hasStore(to)
+	if err != nil {
+		return err
+	}
+	if !inTo {
+		return fmt.Errorf("store %s not found at version %d", name, to)
+	}
+	if from == to {
+		return nil
+	}
+
+	if differ, ok := tree.(Differ); ok && inFrom {
+		return differ.Diff(from, to, start, end, fn)
+	}
+
+	reader, ok := tree.(Reader)
+	if !ok {
+		return fmt.Errorf("tree for store %s does not implement Reader", name)
+	}
+	var fromItr corestore.Iterator
+	if inFrom {
+		fromItr, err = reader.Iterator(from, start, end, true)
+		if err != nil {
+			return err
+		}
+		defer func() { err = errors.Join(err, fromItr.Close()) }()
+	}
+	toItr, err := reader.Iterator(to, start, end, true)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, toItr.Close()) }()
+
+	return store.DiffIterators(fromItr, toItr, fn)
+}
+
 // Prune implements store.Pruner.
 func (c *CommitStore) Prune(version uint64) error {
+	if err := c.waitPersisted(); err != nil {
+		return err
+	}
 	// prune the metadata
 	for v := version; v > 0; v-- {
 		if err := c.metadata.deleteCommitInfo(v); err != nil {
@@ -423,24 +636,18 @@ func (c *CommitStore) Prune(version uint64) error {
 	return nil
 }
 
-func (c *CommitStore) pruneRemovedStoreKeys(version uint64) error {
-	clearKVStore := func(storeKey []byte, version uint64) (err error) {
-		tree, ok := c.oldTrees[string(storeKey)]
-		if !ok {
-			return fmt.Errorf("store %s not found in oldTrees", storeKey)
-		}
-		return tree.Prune(version)
-	}
-	return c.metadata.deleteRemovedStoreKeys(version, clearKVStore)
-}
-
-// PausePruning implements store.PausablePruner.
+// PausePruning implements store.PausablePruner. The pruning is resumed once the
+// pending version is persisted in pipelined mode.
 func (c *CommitStore) PausePruning(pause bool) {
-	for _, tree := range c.multiTrees {
-		if pruner, ok := tree.(store.PausablePruner); ok {
-			pruner.PausePruning(pause)
-		}
+	c.pipelineMtx.Lock()
+	defer c.pipelineMtx.Unlock()
+
+	if !pause && c.persisting != nil {
+		c.resumePruning = true
+		return
 	}
+	c.resumePruning = false
+	c.pausePruning(pause)
 }
 
 // Snapshot implements snapshotstypes.CommitSnapshotter.
@@ -457,7 +664,9 @@ func (c *CommitStore) Snapshot(version uint64, protoWriter protoio.Writer) error
 		return fmt.Errorf("the snapshot version %d is greater than the latest version %d", version, latestVersion)
 	}
 
-	for storeKey, tree := range c.multiTrees {
+	// the stores are written in order, so that the snapshot is deterministic
+	for _, storeKey := range slices.Sorted(maps.Keys(c.multiTrees)) {
+		tree := c.multiTrees[storeKey]
 		// TODO: check the parallelism of this loop
 		if err := func() error {
 			exporter, err := tree.Export(version)
@@ -509,6 +718,10 @@ func (c *CommitStore) Restore(
 	format uint32,
 	protoReader protoio.Reader,
 ) (snapshotstypes.SnapshotItem, error) {
+	if err := c.waitPersisted(); err != nil {
+		return snapshotstypes.SnapshotItem{}, err
+	}
+
 	var (
 		importer     Importer
 		snapshotItem snapshotstypes.SnapshotItem
@@ -548,27 +761,8 @@ loop:
 			if importer == nil {
 				return snapshotstypes.SnapshotItem{}, errors.New("received IAVL node item before store item")
 			}
-			node := item.IAVL
-			if node.Height > int32(math.MaxInt8) {
-				return snapshotstypes.SnapshotItem{}, fmt.Errorf("node height %v cannot exceed %v",
-					item.IAVL.Height, math.MaxInt8)
-			}
-			// Protobuf does not differentiate between []byte{} and nil, but fortunately IAVL does
-			// not allow nil keys nor nil values for leaf nodes, so we can always set them to empty.
-			if node.Key == nil {
-				node.Key = []byte{}
-			}
-			if node.Height == 0 {
-				if node.Value == nil {
-					node.Value = []byte{}
-				}
-			}
-			if node.Version == 0 {
-				node.Version = int64(version)
-			}
-			err := importer.Add(node)
-			if err != nil {
-				return snapshotstypes.SnapshotItem{}, fmt.Errorf("failed to add node to importer: %w", err)
+			if err := importNode(importer, item.IAVL, version); err != nil {
+				return snapshotstypes.SnapshotItem{}, err
 			}
 		default:
 			break loop
@@ -584,7 +778,102 @@ loop:
 	return snapshotItem, c.LoadVersion(version)
 }
 
+// RestoreStore implements snapshots.StoreRestorer. The imports of the trees which
+// are not concurrent safe are serialized, while the items are still read concurrently.
+func (c *CommitStore) RestoreStore(version uint64, storeKey string, protoReader protoio.Reader) (err error) {
+	if err := c.waitPersisted(); err != nil {
+		return err
+	}
+
+	var snapshotItem snapshotstypes.SnapshotItem
+	if err := protoReader.ReadMsg(&snapshotItem); err != nil {
+		return fmt.Errorf("invalid protobuf message: %w", err)
+	}
+	if snapshotItem.GetStore() == nil || snapshotItem.GetStore().Name != storeKey {
+		return fmt.Errorf("expected store item %s, got %v", storeKey, snapshotItem.Item)
+	}
+	tree := c.multiTrees[storeKey]
+	if tree == nil {
+		return fmt.Errorf("store %s not found", storeKey)
+	}
+
+	// lock runs fn while holding the import lock if the tree is not concurrent safe
+	lock := func(fn func() error) error {
+		if !tree.IsConcurrentSafe() {
+			c.importMtx.Lock()
+			defer c.importMtx.Unlock()
+		}
+		return fn()
+	}
+
+	var importer Importer
+	if err := lock(func() (err error) {
+		importer, err = tree.Import(version)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to import tree for version %d: %w", version, err)
+	}
+	defer func() {
+		err = errors.Join(err, lock(importer.Close))
+	}()
+
+	for {
+		snapshotItem.Reset()
+		err := protoReader.ReadMsg(&snapshotItem)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("invalid protobuf message: %w", err)
+		}
+
+		node := snapshotItem.GetIAVL()
+		if node == nil {
+			return fmt.Errorf("unexpected snapshot item %T in store %s", snapshotItem.Item, storeKey)
+		}
+		if err := lock(func() error { return importNode(importer, node, version) }); err != nil {
+			return err
+		}
+	}
+
+	if err := lock(importer.Commit); err != nil {
+		return fmt.Errorf("failed to commit importer: %w", err)
+	}
+	return nil
+}
+
+// FinishRestore implements snapshots.StoreRestorer.
+func (c *CommitStore) FinishRestore(version uint64) error {
+	return c.LoadVersion(version)
+}
+
+// importNode adds an exported node of a snapshot to the importer.
+func importNode(importer Importer, node *snapshotstypes.SnapshotIAVLItem, version uint64) error {
+	if node.Height > int32(math.MaxInt8) {
+		return fmt.Errorf("node height %v cannot exceed %v", node.Height, math.MaxInt8)
+	}
+	// Protobuf does not differentiate between []byte{} and nil, but fortunately IAVL does
+	// not allow nil keys nor nil values for leaf nodes, so we can always set them to empty.
+	if node.Key == nil {
+		node.Key = []byte{}
+	}
+	if node.Height == 0 {
+		if node.Value == nil {
+			node.Value = []byte{}
+		}
+	}
+	if node.Version == 0 {
+		node.Version = int64(version)
+	}
+	if err := importer.Add(node); err != nil {
+		return fmt.Errorf("failed to add node to importer: %w", err)
+	}
+	return nil
+}
+
 func (c *CommitStore) GetCommitInfo(version uint64) (*proof.CommitInfo, error) {
+	if err := c.waitPersisted(); err != nil {
+		return nil, err
+	}
 	// if the commit info is already stored, return it
 	ci, err := c.metadata.GetCommitInfo(version)
 	if err != nil {
@@ -620,14 +909,28 @@ func (c *CommitStore) GetCommitInfo(version uint64) (*proof.CommitInfo, error) {
 }
 
 func (c *CommitStore) GetLatestVersion() (uint64, error) {
+	if err := c.waitPersisted(); err != nil {
+		return 0, err
+	}
 	return c.metadata.GetLatestVersion()
 }
 
 func (c *CommitStore) Close() error {
+	if err := c.waitPersisted(); err != nil {
+		return err
+	}
+	c.waitErased()
 	for _, tree := range c.multiTrees {
 		if err := tree.Close(); err != nil {
 			return err
 		}
 	}
+	c.oldTreesMtx.Lock()
+	defer c.oldTreesMtx.Unlock()
+	for _, tree := range c.oldTrees {
+		if err := tree.Close(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -3,6 +3,8 @@ package commitment
 import (
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/stretchr/testify/suite"
@@ -12,6 +14,7 @@ import (
 	coretesting "cosmossdk.io/core/testing"
 	"cosmossdk.io/store/v2"
 	dbm "cosmossdk.io/store/v2/db"
+	"cosmossdk.io/store/v2/metrics"
 	"cosmossdk.io/store/v2/proof"
 	"cosmossdk.io/store/v2/snapshots"
 	snapshotstypes "cosmossdk.io/store/v2/snapshots/types"
@@ -143,6 +146,61 @@ func (s *CommitStoreTestSuite) TestStore_Snapshotter() {
 	}
 }
 
+// TestStore_SnapshotStores tests that a snapshot taken by the snapshot manager, in
+// which every store is written to its own chunks, is restored store by store.
+func (s *CommitStoreTestSuite) TestStore_SnapshotStores() {
+	storeKeys := []string{storeKey1, storeKey2, storeKey3}
+	commitStore, err := s.NewStore(dbm.NewMemDB(), s.T().TempDir(), storeKeys, nil, coretesting.NewNopLogger())
+	s.Require().NoError(err)
+
+	latestVersion := uint64(10)
+	var cInfo *proof.CommitInfo
+	for i := uint64(1); i <= latestVersion; i++ {
+		kvPairs := make(map[string]corestore.KVPairs)
+		for _, storeKey := range storeKeys {
+			for j := 0; j < 10; j++ {
+				key := []byte(fmt.Sprintf("key-%d-%d", i, j))
+				value := []byte(fmt.Sprintf("value-%d-%d", i, j))
+				kvPairs[storeKey] = append(kvPairs[storeKey], corestore.KVPair{Key: key, Value: value})
+			}
+		}
+		s.Require().NoError(commitStore.WriteChangeset(corestore.NewChangesetWithPairs(i, kvPairs)))
+		cInfo, err = commitStore.Commit(i)
+		s.Require().NoError(err)
+	}
+
+	snapshotStore, err := snapshots.NewStore(s.T().TempDir())
+	s.Require().NoError(err)
+	opts := snapshots.NewSnapshotOptions(0, 0)
+	manager := snapshots.NewManager(snapshotStore, opts, commitStore, nil, coretesting.NewNopLogger())
+	snapshot, err := manager.Create(latestVersion)
+	s.Require().NoError(err)
+	s.Require().Equal(snapshotstypes.FormatStoreChunks, snapshot.Format)
+	s.Require().Len(snapshot.Metadata.Stores, len(storeKeys))
+	for i, store := range snapshot.Metadata.Stores {
+		s.Require().Equal(storeKeys[i], store.Name)
+	}
+
+	targetStore, err := s.NewStore(dbm.NewMemDB(), s.T().TempDir(), storeKeys, nil, coretesting.NewNopLogger())
+	s.Require().NoError(err)
+	targetSnapshotStore, err := snapshots.NewStore(s.T().TempDir())
+	s.Require().NoError(err)
+	targetManager := snapshots.NewManager(targetSnapshotStore, opts, targetStore, nil, coretesting.NewNopLogger())
+	s.Require().NoError(targetManager.Restore(*snapshot))
+	for i := uint32(0); i < snapshot.Chunks; i++ {
+		chunk, err := manager.LoadChunk(snapshot.Height, snapshot.Format, i)
+		s.Require().NoError(err)
+		done, err := targetManager.RestoreChunk(chunk)
+		s.Require().NoError(err)
+		s.Require().Equal(i == snapshot.Chunks-1, done)
+	}
+
+	// check the restored tree hashes
+	targetCommitInfo, err := targetStore.GetCommitInfo(latestVersion)
+	s.Require().NoError(err)
+	s.Require().Equal(cInfo.Hash(), targetCommitInfo.Hash())
+}
+
 func (s *CommitStoreTestSuite) TestStore_LoadVersion() {
 	storeKeys := []string{storeKey1, storeKey2}
 	mdb := dbm.NewMemDB()
@@ -317,6 +375,103 @@ func (s *CommitStoreTestSuite) TestStore_Get() {
 	}
 }
 
+func (s *CommitStoreTestSuite) TestStore_DiffState() {
+	storeKeys := []string{storeKey1, storeKey2}
+	commitStore, err := s.NewStore(dbm.NewMemDB(), s.T().TempDir(), storeKeys, nil, coretesting.NewNopLogger())
+	s.Require().NoError(err)
+
+	type kv struct {
+		key, value string
+		remove     bool
+	}
+	versions := [][]kv{
+		{{key: "a", value: "1"}, {key: "b", value: "1"}, {key: "c", value: "1"}, {key: "d", value: "1"}},
+		{{key: "a", value: "2"}, {key: "b", remove: true}, {key: "e", value: "1"}},
+		{{key: "c", value: "2"}, {key: "b", value: "3"}, {key: "e", remove: true}},
+		{{key: "a", remove: true}, {key: "d", value: "1"}, {key: "f", value: "1"}},
+	}
+	for i, pairs := range versions {
+		version := uint64(i + 1)
+		cs := corestore.NewChangeset(version)
+		for _, pair := range pairs {
+			cs.Add([]byte(storeKey1), []byte(pair.key), []byte(pair.value), pair.remove)
+		}
+		cs.Add([]byte(storeKey2), []byte("key"), []byte(fmt.Sprintf("value-%d", version)), false)
+		s.Require().NoError(commitStore.WriteChangeset(cs))
+		_, err = commitStore.Commit(version)
+		s.Require().NoError(err)
+	}
+
+	diff := func(storeKey string, from, to uint64, start, end []byte) ([]store.KVChange, error) {
+		var changes []store.KVChange
+		err := commitStore.DiffState([]byte(storeKey), from, to, start, end, func(change store.KVChange) error {
+			changes = append(changes, change)
+			return nil
+		})
+		return changes, err
+	}
+
+	changes, err := diff(storeKey1, 1, 4, nil, nil)
+	s.Require().NoError(err)
+	s.Require().Equal([]store.KVChange{
+		{Key: []byte("a"), OldValue: []byte("1")},
+		{Key: []byte("b"), OldValue: []byte("1"), NewValue: []byte("3")},
+		{Key: []byte("c"), OldValue: []byte("1"), NewValue: []byte("2")},
+		{Key: []byte("f"), NewValue: []byte("1")},
+	}, changes)
+	s.Require().Equal(store.ChangeDeleted, changes[0].Type())
+	s.Require().Equal(store.ChangeUpdated, changes[1].Type())
+	s.Require().Equal(store.ChangeAdded, changes[3].Type())
+
+	// key range
+	changes, err = diff(storeKey1, 1, 4, []byte("b"), []byte("f"))
+	s.Require().NoError(err)
+	s.Require().Equal([]store.KVChange{
+		{Key: []byte("b"), OldValue: []byte("1"), NewValue: []byte("3")},
+		{Key: []byte("c"), OldValue: []byte("1"), NewValue: []byte("2")},
+	}, changes)
+
+	// consecutive versions
+	changes, err = diff(storeKey1, 1, 2, nil, nil)
+	s.Require().NoError(err)
+	s.Require().Equal([]store.KVChange{
+		{Key: []byte("a"), OldValue: []byte("1"), NewValue: []byte("2")},
+		{Key: []byte("b"), OldValue: []byte("1")},
+		{Key: []byte("e"), NewValue: []byte("1")},
+	}, changes)
+
+	changes, err = diff(storeKey2, 2, 4, nil, nil)
+	s.Require().NoError(err)
+	s.Require().Equal([]store.KVChange{
+		{Key: []byte("key"), OldValue: []byte("value-2"), NewValue: []byte("value-4")},
+	}, changes)
+
+	changes, err = diff(storeKey1, 3, 3, nil, nil)
+	s.Require().NoError(err)
+	s.Require().Empty(changes)
+
+	_, err = diff(storeKey1, 4, 1, nil, nil)
+	s.Require().Error(err)
+	_, err = diff(storeKey1, 1, 5, nil, nil)
+	s.Require().ErrorContains(err, "version 5 does not exist")
+	_, err = diff(storeKey3, 1, 4, nil, nil)
+	s.Require().Error(err)
+
+	// the pruned versions can't be diffed
+	s.Require().NoError(commitStore.Prune(1))
+	_, err = diff(storeKey1, 1, 4, nil, nil)
+	s.Require().ErrorContains(err, "version 1 does not exist")
+	changes, err = diff(storeKey1, 2, 4, nil, nil)
+	s.Require().NoError(err)
+	s.Require().Equal([]store.KVChange{
+		{Key: []byte("a"), OldValue: []byte("2")},
+		{Key: []byte("b"), NewValue: []byte("3")},
+		{Key: []byte("c"), OldValue: []byte("1"), NewValue: []byte("2")},
+		{Key: []byte("e"), OldValue: []byte("1")},
+		{Key: []byte("f"), NewValue: []byte("1")},
+	}, changes)
+}
+
 func (s *CommitStoreTestSuite) TestStore_Upgrades() {
 	storeKeys := []string{storeKey1, storeKey2, storeKey3}
 	commitDB := dbm.NewMemDB()
@@ -421,9 +576,12 @@ func (s *CommitStoreTestSuite) TestStore_Upgrades() {
 		Added:   []string{"newStore3"},
 	}
 	newRealStoreKeys := []string{storeKey1, "newStore1", "newStore2", "newStore3"}
-	oldStoreKeys = []string{storeKey2, storeKey3}
-	commitStore, err = s.NewStore(commitDB, commitDir, newRealStoreKeys, oldStoreKeys, coretesting.NewNopLogger())
+	// the tree of store2 is kept to serve its proofs once it is removed
+	oldStoreKeys = []string{storeKey3}
+	commitStore, err = s.NewStore(commitDB, commitDir, append(newRealStoreKeys, storeKey2), oldStoreKeys, coretesting.NewNopLogger())
 	s.Require().NoError(err)
+	m := &reclaimedMetrics{}
+	commitStore.SetMetrics(m)
 	err = commitStore.LoadVersionAndUpgrade(2*latestVersion-1, upgrades)
 	s.Require().NoError(err)
 
@@ -452,6 +610,13 @@ func (s *CommitStoreTestSuite) TestStore_Upgrades() {
 	// prune the old stores
 	s.Require().NoError(commitStore.Prune(latestVersion))
 	s.T().Logf("prune to version %d", latestVersion)
+	// the data of the store removed at the pruned version is erased in the background
+	commitStore.waitErased()
+	s.requireErased(commitDB, commitDir, storeKey3)
+	s.Require().Greater(m.reclaimed, float32(0))
+	removedStoreKeys, err := commitStore.metadata.GetRemovedStoreKeys(latestVersion)
+	s.Require().NoError(err)
+	s.Require().Empty(removedStoreKeys)
 	// GetProof should fail for the old stores
 	for _, storeKey := range []string{storeKey1, storeKey3} {
 		for i := uint64(1); i <= latestVersion; i++ {
@@ -473,6 +638,12 @@ func (s *CommitStoreTestSuite) TestStore_Upgrades() {
 
 	s.T().Logf("Prune to version %d", latestVersion*2)
 	s.Require().NoError(commitStore.Prune(latestVersion * 2))
+	// the store removed while the commitment store is loaded is erased too
+	commitStore.waitErased()
+	s.requireErased(commitDB, commitDir, storeKey2)
+	removedStoreKeys, err = commitStore.metadata.GetRemovedStoreKeys(latestVersion * 2)
+	s.Require().NoError(err)
+	s.Require().Empty(removedStoreKeys)
 	// GetProof should fail for the newly deleted stores
 	for i := uint64(1); i < latestVersion*2; i++ {
 		for j := 0; j < kvCount; j++ {
@@ -490,3 +661,98 @@ func (s *CommitStoreTestSuite) TestStore_Upgrades() {
 		}
 	}
 }
+
+// requireErased checks that the data of the store key is deleted, whether it is
+// stored in a prefix of the database or in its own directory.
+func (s *CommitStoreTestSuite) requireErased(db corestore.KVStoreWithBatch, dbDir, storeKey string) {
+	_, err := os.Stat(filepath.Join(dbDir, storeKey))
+	s.Require().ErrorIs(err, os.ErrNotExist)
+
+	itr, err := dbm.NewPrefixDB(db, []byte(storeKey)).Iterator(nil, nil)
+	s.Require().NoError(err)
+	defer itr.Close()
+	s.Require().False(itr.Valid())
+}
+
+// reclaimedMetrics counts the bytes reclaimed by the erase jobs.
+type reclaimedMetrics struct {
+	metrics.NoOpMetrics
+	reclaimed float32
+}
+
+func (m *reclaimedMetrics) IncrCounter(val float32, keys ...string) {
+	m.reclaimed += val
+}
+
+func (s *CommitStoreTestSuite) TestStore_PipelinedCommit() {
+	storeKeys := []string{storeKey1, storeKey2}
+	mdb := dbm.NewMemDB()
+	dbDir := s.T().TempDir()
+	commitStore, err := s.NewStore(mdb, dbDir, storeKeys, nil, coretesting.NewNopLogger())
+	s.Require().NoError(err)
+	s.Require().NoError(commitStore.SetPipelinedCommit(true))
+
+	// the reference store commits synchronously
+	refStore, err := s.NewStore(dbm.NewMemDB(), s.T().TempDir(), storeKeys, nil, coretesting.NewNopLogger())
+	s.Require().NoError(err)
+
+	newChangeset := func(version uint64) *corestore.Changeset {
+		kvPairs := make(map[string]corestore.KVPairs)
+		for _, storeKey := range storeKeys {
+			for i := 0; i < 10; i++ {
+				key := []byte(fmt.Sprintf("key-%d-%d", version, i))
+				value := []byte(fmt.Sprintf("value-%d-%d", version, i))
+				kvPairs[storeKey] = append(kvPairs[storeKey], corestore.KVPair{Key: key, Value: value})
+			}
+		}
+		return corestore.NewChangesetWithPairs(version, kvPairs)
+	}
+
+	latestVersion := uint64(5)
+	for v := uint64(1); v <= latestVersion; v++ {
+		cs := newChangeset(v)
+		s.Require().NoError(commitStore.WriteChangeset(cs))
+		cInfo, err := commitStore.Commit(v)
+		s.Require().NoError(err)
+
+		s.Require().NoError(refStore.WriteChangeset(cs))
+		refInfo, err := refStore.Commit(v)
+		s.Require().NoError(err)
+		s.Require().Equal(refInfo.Hash(), cInfo.Hash())
+	}
+
+	// the reads wait for the pending version to be persisted
+	version, err := commitStore.GetLatestVersion()
+	s.Require().NoError(err)
+	s.Require().Equal(latestVersion, version)
+	val, err := commitStore.Get([]byte(storeKey1), latestVersion, []byte("key-5-3"))
+	s.Require().NoError(err)
+	s.Require().Equal([]byte("value-5-3"), val)
+	s.Require().NoError(commitStore.Close())
+
+	if _, ok := commitStore.multiTrees[storeKey1].(WorkingHasher); !ok {
+		// the trees are persisted synchronously
+		return
+	}
+
+	// a version which is recorded but not persisted by the trees is replayed
+	cs := newChangeset(latestVersion + 1)
+	s.Require().NoError(refStore.WriteChangeset(cs))
+	refInfo, err := refStore.Commit(latestVersion + 1)
+	s.Require().NoError(err)
+	s.Require().NoError(NewMetadataStore(mdb).flushPendingCommit(refInfo, cs))
+
+	commitStore, err = s.NewStore(mdb, dbDir, storeKeys, nil, coretesting.NewNopLogger())
+	s.Require().NoError(err)
+	version, err = commitStore.GetLatestVersion()
+	s.Require().NoError(err)
+	s.Require().Equal(latestVersion+1, version)
+	s.Require().NoError(commitStore.LoadVersion(version))
+	cInfo, err := commitStore.GetCommitInfo(version)
+	s.Require().NoError(err)
+	s.Require().Equal(refInfo.Hash(), cInfo.Hash())
+
+	pending, _, err := commitStore.metadata.getPendingCommit()
+	s.Require().NoError(err)
+	s.Require().Nil(pending)
+}
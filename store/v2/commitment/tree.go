@@ -7,6 +7,7 @@ import (
 	ics23 "github.com/cosmos/ics23/go"
 
 	corestore "cosmossdk.io/core/store"
+	"cosmossdk.io/store/v2"
 	snapshotstypes "cosmossdk.io/store/v2/snapshots/types"
 )
 
@@ -39,6 +40,14 @@ type Tree interface {
 	io.Closer
 }
 
+// WorkingHasher is the optional interface of the trees which can compute the hash
+// of their working state before it is committed. The CommitStore persists these
+// trees in the background when the pipelined commit is enabled.
+type WorkingHasher interface {
+	// WorkingHash returns the hash the working state will be committed with.
+	WorkingHash() []byte
+}
+
 // Reader is the optional interface that is only used to read data from the tree
 // during the migration process.
 type Reader interface {
@@ -46,6 +55,31 @@ type Reader interface {
 	Iterator(version uint64, start, end []byte, ascending bool) (corestore.Iterator, error)
 }
 
+// Differ is the optional interface of the trees which can compute the changes
+// between two versions without iterating over the whole state of both versions.
+type Differ interface {
+	// Diff calls fn with the changes of the keys in the [start, end) range
+	// between the from and to versions, in ascending key order.
+	Diff(from, to uint64, start, end []byte, fn func(store.KVChange) error) error
+}
+
+// Eraser is the optional interface of the trees which can delete all their data
+// once their store key is removed.
+type Eraser interface {
+	// Erase closes the tree and deletes all its data, the tree must not be used
+	// afterwards. It returns the number of bytes reclaimed.
+	Erase() (uint64, error)
+}
+
+// EraserFunc is an Eraser of data which isn't mounted as a tree, e.g. the
+// orphaned data of a store key which changed of commitment backend.
+type EraserFunc func() (uint64, error)
+
+// Erase implements Eraser.
+func (f EraserFunc) Erase() (uint64, error) {
+	return f()
+}
+
 // Exporter is the interface that wraps the basic Export methods.
 type Exporter interface {
 	Next() (*snapshotstypes.SnapshotIAVLItem, error)
@@ -25,6 +25,36 @@ type LatestReader interface {
 	ReverseIterator(storeKey, start, end []byte) (corestore.Iterator, error)
 }
 
+// VersionedWriter defines an API for a versioned database that allows reads,
+// writes, iteration and pruning over a series of versions. It is implemented by
+// the state storage (SS) backend.
+type VersionedWriter interface {
+	VersionedReader
+	Pruner
+
+	// ApplyChangeset writes the changeset at its version. It doesn't change the
+	// latest version.
+	ApplyChangeset(cs *corestore.Changeset) error
+
+	// SetLatestVersion sets the latest version which can be read.
+	SetLatestVersion(version uint64) error
+
+	// Rollback removes the state written after the given version and sets it
+	// as the latest version.
+	Rollback(version uint64) error
+
+	// GetImportProgress returns the version and the last store key and key written
+	// by an interrupted import, or a zero version if no import was interrupted.
+	GetImportProgress() (version uint64, storeKey, key []byte, err error)
+
+	// SetImportProgress records the last store key and key written by the import
+	// of the version, so that an interrupted import can be resumed. A zero version
+	// clears the import progress.
+	SetImportProgress(version uint64, storeKey, key []byte) error
+
+	io.Closer
+}
+
 // UpgradableDatabase defines an API for a versioned database that allows pruning
 // deleted storeKeys
 type UpgradableDatabase interface {
@@ -68,3 +98,12 @@ type Committer interface {
 	// only be called once and any call after may panic.
 	io.Closer
 }
+
+// BatchProver is the optional interface of the Committer which can prove many
+// keys of a store in a single proof.
+type BatchProver interface {
+	// GetBatchProof returns the proof of existence or non-existence for all the
+	// given keys, made of a batch proof of the store tree and the proof of the
+	// store in the commit info.
+	GetBatchProof(storeKey []byte, version uint64, keys [][]byte) ([]proof.CommitmentOp, error)
+}
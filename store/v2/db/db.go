@@ -1,7 +1,12 @@
 package db
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/spf13/cast"
 
 	coreserver "cosmossdk.io/core/server"
 	corestore "cosmossdk.io/core/store"
@@ -19,16 +24,80 @@ const (
 	DBFileSuffix string = ".db"
 )
 
+// DBCreator creates the database of the given name in the data directory. The
+// options are the options of the database backends, they may be nil.
+type DBCreator func(name, dataDir string, opts coreserver.DynamicConfig) (corestore.KVStoreWithBatch, error)
+
+var (
+	dbCreatorsMtx sync.RWMutex
+	dbCreators    = map[DBType]DBCreator{
+		DBTypeGoLevelDB: func(name, dataDir string, opts coreserver.DynamicConfig) (corestore.KVStoreWithBatch, error) {
+			return NewGoLevelDB(name, dataDir, opts)
+		},
+		DBTypePebbleDB: func(name, dataDir string, opts coreserver.DynamicConfig) (corestore.KVStoreWithBatch, error) {
+			return NewPebbleDBWithOpts(name, dataDir, opts)
+		},
+		DBTypeMemDB: func(string, string, coreserver.DynamicConfig) (corestore.KVStoreWithBatch, error) {
+			return NewMemDB(), nil
+		},
+	}
+)
+
+// RegisterDBCreator registers the creator of a database type, so that NewDB
+// creates the databases of this type, e.g. as the app-db-backend of the store.
+// It must be called before the store is built, usually from an init function.
+func RegisterDBCreator(dbType DBType, creator DBCreator) error {
+	if dbType == "" {
+		return errors.New("db type is required")
+	}
+	if creator == nil {
+		return fmt.Errorf("creator of db type %s is nil", dbType)
+	}
+
+	dbCreatorsMtx.Lock()
+	defer dbCreatorsMtx.Unlock()
+	if _, ok := dbCreators[dbType]; ok {
+		return fmt.Errorf("db type %s is already registered", dbType)
+	}
+	dbCreators[dbType] = creator
+	return nil
+}
+
+// RegisteredDBTypes returns the sorted database types which NewDB can create.
+func RegisteredDBTypes() []DBType {
+	dbCreatorsMtx.RLock()
+	defer dbCreatorsMtx.RUnlock()
+	types := make([]DBType, 0, len(dbCreators))
+	for dbType := range dbCreators {
+		types = append(types, dbType)
+	}
+	slices.Sort(types)
+	return types
+}
+
 func NewDB(dbType DBType, name, dataDir string, opts coreserver.DynamicConfig) (corestore.KVStoreWithBatch, error) {
-	switch dbType {
-	case DBTypeGoLevelDB:
-		return NewGoLevelDB(name, dataDir, opts)
-
-	case DBTypePebbleDB:
-		return NewPebbleDB(name, dataDir)
-	case DBTypeMemDB:
-		return NewMemDB(), nil
+	dbCreatorsMtx.RLock()
+	creator, ok := dbCreators[dbType]
+	dbCreatorsMtx.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported db type: %s, registered types are %v", dbType, RegisteredDBTypes())
 	}
 
-	return nil, fmt.Errorf("unsupported db type: %s", dbType)
+	return creator(name, dataDir, opts)
+}
+
+// Options are the options of the database backends, keyed by option name. The
+// options of pebble are set under PebbleConfigKey.
+type Options map[string]any
+
+var _ coreserver.DynamicConfig = Options(nil)
+
+// Get implements coreserver.DynamicConfig.
+func (o Options) Get(key string) any {
+	return o[key]
+}
+
+// GetString implements coreserver.DynamicConfig.
+func (o Options) GetString(key string) string {
+	return cast.ToString(o[key])
 }
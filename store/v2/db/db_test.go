@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	coreserver "cosmossdk.io/core/server"
 	corestore "cosmossdk.io/core/store"
 )
 
@@ -122,6 +123,75 @@ func TestPebbleDBSuite(t *testing.T) {
 	})
 }
 
+func TestPebbleDBWithConfigSuite(t *testing.T) {
+	cfg := DefaultPebbleConfig()
+	cfg.BlockCacheSize = 1 << 20
+	cfg.MemTableSize = 1 << 20
+	cfg.TargetFileSize = 1 << 20
+	cfg.BloomFilterBitsPerKey = 0
+	cfg.WALDir = t.TempDir()
+	db, err := NewDB(DBTypePebbleDB, "test", t.TempDir(), Options{PebbleConfigKey: cfg})
+	require.NoError(t, err)
+
+	suite.Run(t, &DBTestSuite{
+		db: db,
+	})
+}
+
+func TestPebbleConfig(t *testing.T) {
+	cfg := pebbleConfigFromOpts(nil)
+	require.Equal(t, DefaultPebbleConfig(), cfg)
+
+	custom := DefaultPebbleConfig()
+	custom.MaxOpenFiles = 100
+	cfg = pebbleConfigFromOpts(Options{PebbleConfigKey: &custom, "maxopenfiles": 200})
+	require.Equal(t, 200, cfg.MaxOpenFiles)
+
+	do := cfg.options()
+	defer do.Cache.Unref()
+	require.Equal(t, int64(DefaultPebbleConfig().BlockCacheSize), do.Cache.MaxSize())
+	require.Equal(t, 3, do.MaxConcurrentCompactions())
+	require.NotNil(t, do.Level(6).FilterPolicy)
+	require.Equal(t, 2*do.Level(0).TargetFileSize, do.Level(1).TargetFileSize)
+
+	custom.L0CompactionThreshold = 8
+	custom.L0StopWritesThreshold = 4
+	_, err := NewPebbleDBWithConfig("test", t.TempDir(), custom)
+	require.Error(t, err)
+	custom.L0StopWritesThreshold = -1
+	require.Error(t, custom.Validate())
+}
+
+func TestRegisterDBCreator(t *testing.T) {
+	const dbType DBType = "testdb"
+	var gotOpts coreserver.DynamicConfig
+	require.NoError(t, RegisterDBCreator(dbType, func(name, dataDir string, opts coreserver.DynamicConfig) (corestore.KVStoreWithBatch, error) {
+		gotOpts = opts
+		return NewMemDB(), nil
+	}))
+	t.Cleanup(func() {
+		dbCreatorsMtx.Lock()
+		delete(dbCreators, dbType)
+		dbCreatorsMtx.Unlock()
+	})
+	require.Contains(t, RegisteredDBTypes(), dbType)
+
+	opts := Options{"cache": 10}
+	db, err := NewDB(dbType, "test", t.TempDir(), opts)
+	require.NoError(t, err)
+	require.IsType(t, &MemDB{}, db)
+	require.Equal(t, "10", gotOpts.GetString("cache"))
+
+	require.Error(t, RegisterDBCreator(dbType, func(string, string, coreserver.DynamicConfig) (corestore.KVStoreWithBatch, error) {
+		return NewMemDB(), nil
+	}))
+	require.Error(t, RegisterDBCreator(DBTypePebbleDB, nil))
+	require.Error(t, RegisterDBCreator("", nil))
+
+	_, err = NewDB("unknown", "test", t.TempDir(), nil)
+	require.ErrorContains(t, err, "unsupported db type")
+}
+
 func TestGoLevelDBSuite(t *testing.T) {
 	db, err := NewGoLevelDB("test", t.TempDir(), nil)
 	require.NoError(t, err)
@@ -8,6 +8,7 @@ import (
 	"slices"
 
 	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
 	"github.com/spf13/cast"
 
 	coreserver "cosmossdk.io/core/server"
@@ -24,24 +25,131 @@ type PebbleDB struct {
 	storage *pebble.DB
 }
 
-func NewPebbleDB(name, dataDir string) (*PebbleDB, error) {
-	return NewPebbleDBWithOpts(name, dataDir, nil)
+// PebbleConfigKey is the key of the PebbleConfig in the options of NewDB.
+const PebbleConfigKey = "pebble"
+
+// PebbleConfig is the tuning of PebbleDB. A zero value leaves the pebble default.
+type PebbleConfig struct {
+	MaxOpenFiles                int    `mapstructure:"max-open-files" toml:"max-open-files" comment:"MaxOpenFiles is the soft limit on the number of open files."`
+	BlockCacheSize              int64  `mapstructure:"block-cache-size" toml:"block-cache-size" comment:"BlockCacheSize is the size in bytes of the block cache."`
+	MemTableSize                uint64 `mapstructure:"mem-table-size" toml:"mem-table-size" comment:"MemTableSize is the size in bytes of a memtable, the writes are buffered in memtables before being flushed to disk."`
+	MemTableStopWritesThreshold int    `mapstructure:"mem-table-stop-writes-threshold" toml:"mem-table-stop-writes-threshold" comment:"MemTableStopWritesThreshold is the number of queued memtables from which the writes are stopped."`
+	MaxConcurrentCompactions    int    `mapstructure:"max-concurrent-compactions" toml:"max-concurrent-compactions" comment:"MaxConcurrentCompactions is the maximum number of concurrent compactions."`
+	L0CompactionThreshold       int    `mapstructure:"l0-compaction-threshold" toml:"l0-compaction-threshold" comment:"L0CompactionThreshold is the amount of L0 read-amplification from which a L0 compaction is triggered."`
+	L0StopWritesThreshold       int    `mapstructure:"l0-stop-writes-threshold" toml:"l0-stop-writes-threshold" comment:"L0StopWritesThreshold is the amount of L0 read-amplification from which the writes are stopped."`
+	LBaseMaxBytes               int64  `mapstructure:"lbase-max-bytes" toml:"lbase-max-bytes" comment:"LBaseMaxBytes is the maximum size in bytes of the base level, the size of the next levels is derived from it."`
+	TargetFileSize              int64  `mapstructure:"target-file-size" toml:"target-file-size" comment:"TargetFileSize is the target size in bytes of the sstables of L0, it doubles at every level."`
+	BlockSize                   int    `mapstructure:"block-size" toml:"block-size" comment:"BlockSize is the target size in bytes of the uncompressed blocks of the sstables."`
+	BloomFilterBitsPerKey       int    `mapstructure:"bloom-filter-bits-per-key" toml:"bloom-filter-bits-per-key" comment:"BloomFilterBitsPerKey is the number of bits per key of the bloom filters of the sstables, 0 disables the bloom filters."`
+	BytesPerSync                int    `mapstructure:"bytes-per-sync" toml:"bytes-per-sync" comment:"BytesPerSync is the number of bytes written to a sstable between two background syncs."`
+	DisableWAL                  bool   `mapstructure:"disable-wal" toml:"disable-wal" comment:"DisableWAL disables the write-ahead log. The writes which aren't flushed are lost if the process stops, which is only safe for a node able to resync."`
+	WALDir                      string `mapstructure:"wal-dir" toml:"wal-dir" comment:"WALDir is the directory of the write-ahead log, e.g. on a faster disk. It is the database directory when empty."`
+	WALBytesPerSync             int    `mapstructure:"wal-bytes-per-sync" toml:"wal-bytes-per-sync" comment:"WALBytesPerSync is the number of bytes written to the write-ahead log between two background syncs."`
 }
 
-func NewPebbleDBWithOpts(name, dataDir string, opts coreserver.DynamicConfig) (*PebbleDB, error) {
+// DefaultPebbleConfig returns the default tuning of PebbleDB.
+func DefaultPebbleConfig() PebbleConfig {
+	return PebbleConfig{
+		BlockCacheSize:           64 << 20,
+		MaxConcurrentCompactions: 3, // pebble default is 1
+		BloomFilterBitsPerKey:    10,
+	}
+}
+
+// Validate validates the tuning.
+func (c PebbleConfig) Validate() error {
+	switch {
+	case c.MaxOpenFiles < 0, c.BlockCacheSize < 0, c.MemTableStopWritesThreshold < 0,
+		c.MaxConcurrentCompactions < 0, c.L0CompactionThreshold < 0, c.L0StopWritesThreshold < 0,
+		c.LBaseMaxBytes < 0, c.TargetFileSize < 0, c.BlockSize < 0, c.BloomFilterBitsPerKey < 0,
+		c.BytesPerSync < 0, c.WALBytesPerSync < 0:
+		return errors.New("pebble options must not be negative")
+	case c.L0CompactionThreshold > 0 && c.L0StopWritesThreshold > 0 && c.L0StopWritesThreshold < c.L0CompactionThreshold:
+		return fmt.Errorf("pebble l0-stop-writes-threshold %d is lower than l0-compaction-threshold %d", c.L0StopWritesThreshold, c.L0CompactionThreshold)
+	}
+	return nil
+}
+
+// options returns the pebble options of the tuning, the block cache must be
+// released by the caller once the database is opened.
+func (c PebbleConfig) options() *pebble.Options {
 	do := &pebble.Options{
-		Logger:                   &fatalLogger{},          // pebble info logs are messing up the logs (not a cosmossdk.io/log logger)
-		MaxConcurrentCompactions: func() int { return 3 }, // default 1
+		Logger:                      &fatalLogger{}, // pebble info logs are messing up the logs (not a cosmossdk.io/log logger)
+		MaxOpenFiles:                c.MaxOpenFiles,
+		MemTableSize:                c.MemTableSize,
+		MemTableStopWritesThreshold: c.MemTableStopWritesThreshold,
+		L0CompactionThreshold:       c.L0CompactionThreshold,
+		L0StopWritesThreshold:       c.L0StopWritesThreshold,
+		LBaseMaxBytes:               c.LBaseMaxBytes,
+		BytesPerSync:                c.BytesPerSync,
+		DisableWAL:                  c.DisableWAL,
+		WALDir:                      c.WALDir,
+		WALBytesPerSync:             c.WALBytesPerSync,
+	}
+	if c.MaxConcurrentCompactions > 0 {
+		compactions := c.MaxConcurrentCompactions
+		do.MaxConcurrentCompactions = func() int { return compactions }
+	}
+	if c.BlockCacheSize > 0 {
+		do.Cache = pebble.NewCache(c.BlockCacheSize)
+	}
+
+	// the options of the next levels are derived from the first level, with a
+	// target file size doubled at every level
+	do.Levels = []pebble.LevelOptions{{
+		BlockSize:      c.BlockSize,
+		TargetFileSize: c.TargetFileSize,
+	}}
+	if c.BloomFilterBitsPerKey > 0 {
+		do.Levels[0].FilterPolicy = bloom.FilterPolicy(c.BloomFilterBitsPerKey)
+		do.Levels[0].FilterType = pebble.TableFilter
 	}
 
 	do.EnsureDefaults()
+	return do
+}
 
-	if opts != nil {
-		files := cast.ToInt(opts.Get("maxopenfiles"))
-		if files > 0 {
-			do.MaxOpenFiles = files
+// pebbleConfigFromOpts returns the tuning set in the options of NewDB, or the
+// default tuning. The "maxopenfiles" option overrides the max open files.
+func pebbleConfigFromOpts(opts coreserver.DynamicConfig) PebbleConfig {
+	cfg := DefaultPebbleConfig()
+	if opts == nil {
+		return cfg
+	}
+	switch v := opts.Get(PebbleConfigKey).(type) {
+	case PebbleConfig:
+		cfg = v
+	case *PebbleConfig:
+		if v != nil {
+			cfg = *v
 		}
 	}
+	if files := cast.ToInt(opts.Get("maxopenfiles")); files > 0 {
+		cfg.MaxOpenFiles = files
+	}
+	return cfg
+}
+
+func NewPebbleDB(name, dataDir string) (*PebbleDB, error) {
+	return NewPebbleDBWithOpts(name, dataDir, nil)
+}
+
+func NewPebbleDBWithOpts(name, dataDir string, opts coreserver.DynamicConfig) (*PebbleDB, error) {
+	return NewPebbleDBWithConfig(name, dataDir, pebbleConfigFromOpts(opts))
+}
+
+// NewPebbleDBWithConfig opens the PebbleDB of the given name in the data
+// directory with the given tuning.
+func NewPebbleDBWithConfig(name, dataDir string, cfg PebbleConfig) (*PebbleDB, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	do := cfg.options()
+	if do.Cache != nil {
+		// the database holds its own reference to the cache
+		defer do.Cache.Unref()
+	}
+
 	dbPath := filepath.Join(dataDir, name+DBFileSuffix)
 	db, err := pebble.Open(dbPath, do)
 	if err != nil {
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+
+	corestore "cosmossdk.io/core/store"
+)
+
+// ChangeType is the type of the change of a key between two versions.
+type ChangeType int
+
+const (
+	// ChangeAdded means the key doesn't exist at the first version.
+	ChangeAdded ChangeType = iota
+	// ChangeUpdated means the value of the key is different at both versions.
+	ChangeUpdated
+	// ChangeDeleted means the key doesn't exist at the second version.
+	ChangeDeleted
+)
+
+// String implements fmt.Stringer.
+func (t ChangeType) String() string {
+	switch t {
+	case ChangeAdded:
+		return "added"
+	case ChangeUpdated:
+		return "updated"
+	case ChangeDeleted:
+		return "deleted"
+	default:
+		return "unknown"
+	}
+}
+
+// KVChange is the change of a key between two versions of a store. OldValue is
+// nil if the key is added, NewValue is nil if the key is deleted.
+type KVChange struct {
+	Key      []byte
+	OldValue []byte
+	NewValue []byte
+}
+
+// Type returns the type of the change.
+func (c KVChange) Type() ChangeType {
+	switch {
+	case c.OldValue == nil:
+		return ChangeAdded
+	case c.NewValue == nil:
+		return ChangeDeleted
+	default:
+		return ChangeUpdated
+	}
+}
+
+// StateDiffer is the optional interface of the Committer which can compute the
+// changes of a store between two retained versions.
+type StateDiffer interface {
+	// DiffState calls fn with the changes of the keys of the store in the
+	// [start, end) range between the from and to versions, in ascending key
+	// order. A nil start or end leaves the range unbounded on that side.
+	DiffState(storeKey []byte, from, to uint64, start, end []byte, fn func(KVChange) error) error
+}
+
+// DiffIterators calls fn with the changes between the state iterated by the
+// from and to iterators, which must iterate over the same range in ascending
+// order, in ascending key order. A nil from iterator is an empty state.
+func DiffIterators(from, to corestore.Iterator, fn func(KVChange) error) error {
+	valid := func(itr corestore.Iterator) bool {
+		return itr != nil && itr.Valid()
+	}
+	// an iterated key exists, even if its value is empty
+	value := func(itr corestore.Iterator) []byte {
+		if v := itr.Value(); v != nil {
+			return v
+		}
+		return []byte{}
+	}
+
+	for valid(from) || valid(to) {
+		cmp := 0
+		switch {
+		case !valid(from):
+			cmp = 1
+		case !valid(to):
+			cmp = -1
+		default:
+			cmp = bytes.Compare(from.Key(), to.Key())
+		}
+
+		var change KVChange
+		switch {
+		case cmp < 0:
+			change = KVChange{Key: from.Key(), OldValue: value(from)}
+		case cmp > 0:
+			change = KVChange{Key: to.Key(), NewValue: value(to)}
+		default:
+			change = KVChange{Key: to.Key(), OldValue: value(from), NewValue: value(to)}
+		}
+		if cmp != 0 || !bytes.Equal(change.OldValue, change.NewValue) {
+			if err := fn(change); err != nil {
+				return err
+			}
+		}
+
+		if cmp <= 0 {
+			from.Next()
+		}
+		if cmp >= 0 {
+			to.Next()
+		}
+	}
+
+	var err error
+	if from != nil {
+		err = from.Error()
+	}
+	return errors.Join(err, to.Error())
+}
@@ -12,6 +12,7 @@ var _ StoreMetrics = Metrics{}
 // StoreMetrics defines the set of supported metric APIs for the store package.
 type StoreMetrics interface {
 	MeasureSince(start time.Time, keys ...string)
+	IncrCounter(val float32, keys ...string)
 }
 
 // Metrics defines a default StoreMetrics implementation.
@@ -46,6 +47,12 @@ func (m Metrics) MeasureSince(start time.Time, keys ...string) {
 	metrics.MeasureSinceWithLabels(keys, start.UTC(), m.Labels)
 }
 
+// IncrCounter provides a wrapper functionality for emitting a counter metric
+// with global labels (if any).
+func (m Metrics) IncrCounter(val float32, keys ...string) {
+	metrics.IncrCounterWithLabels(keys, val, m.Labels)
+}
+
 // NoOpMetrics is a no-op implementation of the StoreMetrics interface
 type NoOpMetrics struct{}
 
@@ -56,3 +63,6 @@ func NewNoOpMetrics() NoOpMetrics {
 
 // MeasureSince is a no-op implementation of the StoreMetrics interface to avoid time.Now() calls
 func (m NoOpMetrics) MeasureSince(start time.Time, keys ...string) {}
+
+// IncrCounter is a no-op implementation of the StoreMetrics interface
+func (m NoOpMetrics) IncrCounter(val float32, keys ...string) {}
@@ -0,0 +1,116 @@
+package migration
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+
+	"cosmossdk.io/core/log"
+	corestore "cosmossdk.io/core/store"
+	"cosmossdk.io/store/v2/commitment"
+)
+
+// MigrateTree migrates the state of a single store key at the given version from
+// one commitment tree to another, e.g. when the commitment backend of the store
+// key is changed. The state is streamed from the exporter of the source tree to
+// the importer of the target tree with a MigrationStream, so it is never written
+// to disk in between.
+//
+// The version must be the latest version recorded in the metadata of the
+// commitment store, which is kept in db. The target tree must be empty and the
+// migration fails if its hash doesn't match the hash of the source tree, or if
+// its state can't be read back when both trees implement commitment.Reader.
+func MigrateTree(
+	storeKey string,
+	version uint64,
+	from, to commitment.Tree,
+	db corestore.KVStoreWithBatch,
+	logger log.Logger,
+) error {
+	fromStore, err := commitment.NewCommitStore(map[string]commitment.Tree{storeKey: from}, nil, db, logger)
+	if err != nil {
+		return err
+	}
+	toStore, err := commitment.NewCommitStore(map[string]commitment.Tree{storeKey: to}, nil, db, logger)
+	if err != nil {
+		return err
+	}
+
+	if err := fromStore.LoadVersion(version); err != nil {
+		return fmt.Errorf("failed to load store %s at version %d: %w", storeKey, version, err)
+	}
+
+	// create the migration stream, which acts as protoio.Reader and snapshots.WriteCloser.
+	ms := NewMigrationStream(defaultChannelBufferSize)
+
+	eg := new(errgroup.Group)
+	eg.Go(func() error {
+		if err := fromStore.Snapshot(version, ms); err != nil {
+			ms.CloseWithError(err)
+			return err
+		}
+		return ms.Close()
+	})
+
+	if _, err := toStore.Restore(version, 0, ms); err != nil {
+		// drain the stream so the writer doesn't block on a full buffer
+		for range ms.chBuffer {
+		}
+		return errors.Join(fmt.Errorf("failed to migrate store %s: %w", storeKey, err), eg.Wait())
+	}
+	if err := eg.Wait(); err != nil {
+		return fmt.Errorf("failed to migrate store %s: %w", storeKey, err)
+	}
+
+	if !bytes.Equal(from.Hash(), to.Hash()) {
+		return fmt.Errorf("failed to migrate store %s: hash mismatch, expected %X, got %X", storeKey, from.Hash(), to.Hash())
+	}
+
+	if err := verifyTree(version, from, to); err != nil {
+		return fmt.Errorf("failed to migrate store %s: %w", storeKey, err)
+	}
+
+	logger.Info("migrated store", "store_key", storeKey, "version", version)
+	return nil
+}
+
+// verifyTree checks that the state of both trees at the given version is the same
+// by iterating over them, since a matching hash doesn't ensure that every node
+// of the imported tree can be read.
+func verifyTree(version uint64, from, to commitment.Tree) (err error) {
+	fromReader, ok := from.(commitment.Reader)
+	if !ok {
+		return nil
+	}
+	toReader, ok := to.(commitment.Reader)
+	if !ok {
+		return nil
+	}
+
+	fromItr, err := fromReader.Iterator(version, nil, nil, true)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, fromItr.Close()) }()
+	toItr, err := toReader.Iterator(version, nil, nil, true)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, toItr.Close()) }()
+
+	for ; fromItr.Valid(); fromItr.Next() {
+		if !toItr.Valid() {
+			return fmt.Errorf("key %X is missing", fromItr.Key())
+		}
+		if !bytes.Equal(fromItr.Key(), toItr.Key()) || !bytes.Equal(fromItr.Value(), toItr.Value()) {
+			return fmt.Errorf("state mismatch at key %X", fromItr.Key())
+		}
+		toItr.Next()
+	}
+	if toItr.Valid() {
+		return fmt.Errorf("unexpected key %X", toItr.Key())
+	}
+	return errors.Join(fromItr.Error(), toItr.Error())
+}
@@ -0,0 +1,198 @@
+package proof
+
+import (
+	"bytes"
+
+	ics23 "github.com/cosmos/ics23/go"
+
+	"cosmossdk.io/errors/v2"
+	storeerrors "cosmossdk.io/store/v2/errors"
+)
+
+// KVPair is a key-value pair of a store proven by a batch or range proof. A
+// nil value proves the absence of the key.
+type KVPair struct {
+	Key   []byte
+	Value []byte
+}
+
+// CombineCommitmentOps combines the tree proofs of many keys of a store into a
+// single compressed batch proof. The key of the returned op is nil.
+func CombineCommitmentOps(ops []CommitmentOp) (CommitmentOp, error) {
+	if len(ops) == 0 {
+		return CommitmentOp{}, errors.Wrap(storeerrors.ErrInvalidProof, "no proof to combine")
+	}
+
+	proofs := make([]*ics23.CommitmentProof, len(ops))
+	for i, op := range ops {
+		if op.Type != ops[0].Type {
+			return CommitmentOp{}, errors.Wrapf(storeerrors.ErrInvalidProof, "cannot combine proofs of types %s and %s", ops[0].Type, op.Type)
+		}
+		proofs[i] = op.Proof
+	}
+	batch, err := ics23.CombineProofs(proofs)
+	if err != nil {
+		return CommitmentOp{}, errors.Wrapf(storeerrors.ErrInvalidProof, "could not combine proofs: %v", err)
+	}
+
+	return CommitmentOp{
+		Type:  ops[0].Type,
+		Spec:  ops[0].Spec,
+		Proof: batch,
+	}, nil
+}
+
+// RangeProbeKeys returns the keys to prove so that a range proof proves the
+// given keys are all the keys of the store in [start, end). The keys must be
+// sorted and in the range. Every key is proven to exist and the gaps between
+// the keys and the bounds of the range are proven to be empty by a proof of
+// absence of their first key, whose right neighbor must be the next key.
+func RangeProbeKeys(start, end []byte, keys [][]byte) [][]byte {
+	probes := make([][]byte, 0, 2*len(keys)+1)
+	next := start
+	for _, key := range keys {
+		if !bytes.Equal(key, next) {
+			probes = append(probes, next)
+		}
+		probes = append(probes, key)
+		next = successor(key)
+	}
+	if end == nil || bytes.Compare(next, end) < 0 {
+		probes = append(probes, next)
+	}
+	return probes
+}
+
+// VerifyBatchProof verifies the proof of the given pairs of a store against
+// the app hash. The proof is made of the batch proof of the store tree and the
+// proof of the store in the commit info. A pair with a nil value is verified
+// to be absent from the store.
+func VerifyBatchProof(ops []CommitmentOp, appHash, storeKey []byte, pairs []KVPair) error {
+	spec, root, batch, err := verifyStoreRoot(ops, appHash, storeKey)
+	if err != nil {
+		return err
+	}
+
+	for _, pair := range pairs {
+		if pair.Value == nil {
+			if !ics23.VerifyNonMembership(spec, root, batch, pair.Key) {
+				return errors.Wrapf(storeerrors.ErrInvalidProof, "proof did not verify absence of key: %s", string(pair.Key))
+			}
+			continue
+		}
+		if !ics23.VerifyMembership(spec, root, batch, pair.Key, pair.Value) {
+			return errors.Wrapf(storeerrors.ErrInvalidProof, "proof did not verify existence of key %s with given value %x", pair.Key, pair.Value)
+		}
+	}
+
+	return nil
+}
+
+// VerifyRangeProof verifies against the app hash that the given pairs are all
+// the pairs of a store in [start, end), a nil start or end being unbounded. The
+// pairs must be sorted by key. The proof is generated for the keys returned by
+// RangeProbeKeys.
+func VerifyRangeProof(ops []CommitmentOp, appHash, storeKey, start, end []byte, pairs []KVPair) error {
+	spec, root, batch, err := verifyStoreRoot(ops, appHash, storeKey)
+	if err != nil {
+		return err
+	}
+
+	// next is the lowest key of the range which may follow the keys proven so far
+	next := start
+	for _, pair := range pairs {
+		if pair.Value == nil {
+			return errors.Wrapf(storeerrors.ErrInvalidProof, "value of key %s is nil", pair.Key)
+		}
+		if bytes.Compare(pair.Key, next) < 0 {
+			return errors.Wrapf(storeerrors.ErrInvalidProof, "key %s is not sorted or below the range", pair.Key)
+		}
+		if end != nil && bytes.Compare(pair.Key, end) >= 0 {
+			return errors.Wrapf(storeerrors.ErrInvalidProof, "key %s is above the range", pair.Key)
+		}
+		if !bytes.Equal(pair.Key, next) {
+			if err := verifyGap(spec, root, batch, next, pair.Key); err != nil {
+				return err
+			}
+		}
+		if !ics23.VerifyMembership(spec, root, batch, pair.Key, pair.Value) {
+			return errors.Wrapf(storeerrors.ErrInvalidProof, "proof did not verify existence of key %s with given value %x", pair.Key, pair.Value)
+		}
+		next = successor(pair.Key)
+	}
+	if end == nil || bytes.Compare(next, end) < 0 {
+		return verifyGap(spec, root, batch, next, end)
+	}
+
+	return nil
+}
+
+// verifyStoreRoot verifies the proof of the store against the app hash and
+// returns the spec, the root and the decompressed batch proof of the store tree.
+func verifyStoreRoot(ops []CommitmentOp, appHash, storeKey []byte) (*ics23.ProofSpec, []byte, *ics23.CommitmentProof, error) {
+	if len(ops) != 2 {
+		return nil, nil, nil, errors.Wrapf(storeerrors.ErrInvalidProof, "expected 2 proof ops, got: %d", len(ops))
+	}
+	treeOp, storeOp := ops[0], ops[1]
+	if treeOp.Proof == nil || treeOp.Spec == nil {
+		return nil, nil, nil, errors.Wrap(storeerrors.ErrInvalidProof, "tree proof is empty")
+	}
+	if !bytes.Equal(storeOp.Key, storeKey) {
+		return nil, nil, nil, errors.Wrapf(storeerrors.ErrInvalidProof, "proof of store %s does not prove store %s", storeOp.Key, storeKey)
+	}
+
+	// decompress it once for all the keys
+	batch := ics23.Decompress(treeOp.Proof)
+	root, err := batch.Calculate()
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(storeerrors.ErrInvalidProof, "could not calculate root for proof: %v", err)
+	}
+	roots, err := storeOp.Run([][]byte{root})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !bytes.Equal(roots[0], appHash) {
+		return nil, nil, nil, errors.Wrapf(storeerrors.ErrInvalidProof, "proof root %x does not match app hash %x", roots[0], appHash)
+	}
+
+	return treeOp.Spec, root, batch, nil
+}
+
+// verifyGap verifies that no key exists in [from, to), a nil to being unbounded.
+// The proof of absence of from must have no right neighbor or a right neighbor
+// at or above to.
+func verifyGap(spec *ics23.ProofSpec, root []byte, batch *ics23.CommitmentProof, from, to []byte) error {
+	for _, entry := range batchEntries(batch) {
+		nonExist := entry.GetNonexist()
+		if nonExist == nil || !bytes.Equal(nonExist.Key, from) {
+			continue
+		}
+		if err := nonExist.Verify(spec, root, from); err != nil {
+			return errors.Wrapf(storeerrors.ErrInvalidProof, "proof did not verify absence of key %s: %v", from, err)
+		}
+		if nonExist.Right == nil || (to != nil && bytes.Compare(nonExist.Right.Key, to) >= 0) {
+			return nil
+		}
+		return errors.Wrapf(storeerrors.ErrInvalidProof, "key %s exists in the range", nonExist.Right.Key)
+	}
+
+	return errors.Wrapf(storeerrors.ErrInvalidProof, "proof did not verify absence of keys from %s", from)
+}
+
+func batchEntries(proof *ics23.CommitmentProof) []*ics23.BatchEntry {
+	switch p := proof.Proof.(type) {
+	case *ics23.CommitmentProof_Batch:
+		return p.Batch.Entries
+	case *ics23.CommitmentProof_Nonexist:
+		return []*ics23.BatchEntry{{Proof: &ics23.BatchEntry_Nonexist{Nonexist: p.Nonexist}}}
+	default:
+		return nil
+	}
+}
+
+// successor returns the lowest key greater than the given key.
+func successor(key []byte) []byte {
+	next := make([]byte, len(key)+1)
+	copy(next, key)
+	return next
+}
@@ -10,13 +10,20 @@ type Manager struct {
 	scPruner store.Pruner
 	// scPruningOption are the pruning options for the SC.
 	scPruningOption *store.PruningOption
+	// ssPruner is the pruner for the SS, it is nil if there is no SS.
+	ssPruner store.Pruner
+	// ssPruningOption are the pruning options for the SS.
+	ssPruningOption *store.PruningOption
 }
 
-// NewManager creates a new Pruning Manager.
-func NewManager(scPruner store.Pruner, scPruningOption *store.PruningOption) *Manager {
+// NewManager creates a new Pruning Manager. The SS pruner is optional and can
+// be nil if the store has no SS.
+func NewManager(scPruner store.Pruner, scPruningOption *store.PruningOption, ssPruner store.Pruner, ssPruningOption *store.PruningOption) *Manager {
 	return &Manager{
 		scPruner:        scPruner,
 		scPruningOption: scPruningOption,
+		ssPruner:        ssPruner,
+		ssPruningOption: ssPruningOption,
 	}
 }
 
@@ -33,6 +40,15 @@ func (m *Manager) Prune(version uint64) error {
 		}
 	}
 
+	// Prune the SS.
+	if m.ssPruner != nil && m.ssPruningOption != nil {
+		if prune, pruneTo := m.ssPruningOption.ShouldPrune(version); prune {
+			if err := m.ssPruner.Prune(pruneTo); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -43,7 +43,7 @@ func (s *PruningManagerTestSuite) SetupTest() {
 	s.Require().NoError(err)
 
 	scPruningOption := store.NewPruningOptionWithCustom(0, 1) // prune all
-	s.manager = NewManager(s.sc, scPruningOption)
+	s.manager = NewManager(s.sc, scPruningOption, nil, nil)
 }
 
 func (s *PruningManagerTestSuite) TestPrune() {
@@ -60,11 +60,12 @@ func (sb *builder) Build(
 		return nil, fmt.Errorf("application db backend is required")
 	}
 
+	dbOptions := config.BackendOptions()
 	scRawDb, err := db.NewDB(
 		db.DBType(config.AppDBBackend),
 		"application",
 		filepath.Join(config.Home, "data"),
-		nil,
+		dbOptions,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SCRawDB: %w", err)
@@ -81,6 +82,7 @@ func (sb *builder) Build(
 		Options:   config.Options,
 		StoreKeys: storeKeys,
 		SCRawDB:   scRawDb,
+		DBOptions: dbOptions,
 	}
 
 	rs, err := CreateRootStore(factoryOptions)
@@ -1,14 +1,33 @@
 package root
 
+import (
+	"maps"
+
+	"cosmossdk.io/store/v2/db"
+)
+
 func DefaultConfig() *Config {
 	return &Config{
 		AppDBBackend: "goleveldb",
 		Options:      DefaultStoreOptions(),
+		Pebble:       db.DefaultPebbleConfig(),
 	}
 }
 
 type Config struct {
-	Home         string  `toml:"-"` // this field is omitted in the TOML file
-	AppDBBackend string  `mapstructure:"app-db-backend" toml:"app-db-backend" comment:"The type of database for application and snapshots databases."`
-	Options      Options `mapstructure:"options" toml:"options"`
+	Home         string          `toml:"-"` // this field is omitted in the TOML file
+	AppDBBackend string          `mapstructure:"app-db-backend" toml:"app-db-backend" comment:"The type of database for application and snapshots databases. Currently we support: \"goleveldb\" and \"pebbledb\", and the database types registered by the application."`
+	Options      Options         `mapstructure:"options" toml:"options"`
+	Pebble       db.PebbleConfig `mapstructure:"pebble" toml:"pebble" comment:"Tuning of the pebbledb databases, used for the application database and the state storage. A zero value leaves the pebble default."`
+	DBOptions    map[string]any  `mapstructure:"db-options" toml:"db-options" comment:"Options of the database types registered by the application, passed to their creator."`
+}
+
+// BackendOptions returns the options of the database backends of the config.
+func (c *Config) BackendOptions() db.Options {
+	opts := db.Options(maps.Clone(c.DBOptions))
+	if opts == nil {
+		opts = db.Options{}
+	}
+	opts[db.PebbleConfigKey] = c.Pebble
+	return opts
 }
@@ -3,10 +3,13 @@ package root
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	iavl_v2 "github.com/cosmos/iavl/v2"
 
 	"cosmossdk.io/core/log"
+	coreserver "cosmossdk.io/core/server"
 	corestore "cosmossdk.io/core/store"
 	"cosmossdk.io/store/v2"
 	"cosmossdk.io/store/v2/commitment"
@@ -16,26 +19,36 @@ import (
 	"cosmossdk.io/store/v2/db"
 	"cosmossdk.io/store/v2/internal"
 	"cosmossdk.io/store/v2/metrics"
+	"cosmossdk.io/store/v2/migration"
 	"cosmossdk.io/store/v2/pruning"
+	"cosmossdk.io/store/v2/storage"
 )
 
 type (
 	SCType string
+	SSType string
 )
 
 const (
 	SCTypeIavl   SCType = "iavl"
 	SCTypeIavlV2 SCType = "iavl-v2"
+
+	SSTypePebbleDB  SSType = "pebbledb"
+	SSTypeGoLevelDB SSType = "goleveldb"
 )
 
 const storePrefixTpl = "s/k:%s/" // s/k:<storeKey>
 
 // Options are the options for creating a root store.
 type Options struct {
-	SCType          SCType               `mapstructure:"sc-type" toml:"sc-type" comment:"State commitment database type. Currently we support: \"iavl\" and \"iavl-v2\""`
-	SCPruningOption *store.PruningOption `mapstructure:"sc-pruning-option" toml:"sc-pruning-option" comment:"Pruning options for state commitment"`
-	IavlConfig      *iavl.Config         `mapstructure:"iavl-config" toml:"iavl-config"`
-	IavlV2Config    iavlv2.Config        `mapstructure:"iavl-v2-config" toml:"iavl-v2-config"`
+	SCType            SCType               `mapstructure:"sc-type" toml:"sc-type" comment:"State commitment database type. Currently we support: \"iavl\" and \"iavl-v2\""`
+	SCTypes           map[string]SCType    `mapstructure:"sc-types" toml:"sc-types" comment:"State commitment database type per store key, overriding sc-type for the given store keys. When the type of a store key changes, its state is migrated to the new type when the store is loaded. Memory store keys are always kept in memory and ignore this option."`
+	SCPruningOption   *store.PruningOption `mapstructure:"sc-pruning-option" toml:"sc-pruning-option" comment:"Pruning options for state commitment"`
+	SCPipelinedCommit bool                 `mapstructure:"sc-pipelined-commit" toml:"sc-pipelined-commit" comment:"Persist the iavl state commitment trees in the background while the next block is executed, it requires the state storage which serves the reads meanwhile. The app hash is still computed when committing, and a version which isn't fully persisted is replayed on restart."`
+	SSType            SSType               `mapstructure:"ss-type" toml:"ss-type" comment:"State storage database type. Currently we support: \"pebbledb\" and \"goleveldb\". The state storage serves historical queries without traversing the state commitment, it is disabled when empty."`
+	SSPruningOption   *store.PruningOption `mapstructure:"ss-pruning-option" toml:"ss-pruning-option" comment:"Pruning options for state storage"`
+	IavlConfig        *iavl.Config         `mapstructure:"iavl-config" toml:"iavl-config"`
+	IavlV2Config      iavlv2.Config        `mapstructure:"iavl-v2-config" toml:"iavl-v2-config"`
 }
 
 // FactoryOptions are the options for creating a root store.
@@ -45,6 +58,11 @@ type FactoryOptions struct {
 	Options   Options
 	StoreKeys []string
 	SCRawDB   corestore.KVStoreWithBatch
+	// SSRawDB is the database of the state storage. It is opened in the data
+	// directory of RootDir when it is nil and the state storage is enabled.
+	SSRawDB corestore.KVStoreWithBatch
+	// DBOptions are the options of the databases opened by the factory.
+	DBOptions coreserver.DynamicConfig
 }
 
 // DefaultStoreOptions returns the default options for creating a root store.
@@ -55,6 +73,10 @@ func DefaultStoreOptions() Options {
 			KeepRecent: 2,
 			Interval:   100,
 		},
+		SSPruningOption: &store.PruningOption{
+			KeepRecent: 2,
+			Interval:   100,
+		},
 		IavlConfig: &iavl.Config{
 			CacheSize:              500_000,
 			SkipFastStorageUpgrade: true,
@@ -62,6 +84,14 @@ func DefaultStoreOptions() Options {
 	}
 }
 
+// storeKeySCType returns the commitment store type of the store key.
+func (o Options) storeKeySCType(key string) SCType {
+	if scType, ok := o.SCTypes[key]; ok {
+		return scType
+	}
+	return o.SCType
+}
+
 // CreateRootStore is a convenience function to create a root store based on the
 // provided FactoryOptions. Strictly speaking app developers can create the root
 // store directly by calling root.New, so this function is not
@@ -73,6 +103,11 @@ func CreateRootStore(opts *FactoryOptions) (store.RootStore, error) {
 	)
 
 	storeOpts := opts.Options
+	if storeOpts.SCPipelinedCommit && storeOpts.SSType == "" {
+		// the reads of the state commitment wait for the previous version to be
+		// persisted, which would serialize the pipeline
+		return nil, errors.New("the pipelined commit requires the state storage, ss-type must be set")
+	}
 
 	metadata := commitment.NewMetadataStore(opts.SCRawDB)
 	latestVersion, err := metadata.GetLatestVersion()
@@ -104,7 +139,7 @@ func CreateRootStore(opts *FactoryOptions) (store.RootStore, error) {
 			case SCTypeIavl:
 				return iavl.NewIavlTree(db.NewPrefixDB(opts.SCRawDB, []byte(fmt.Sprintf(storePrefixTpl, key))), opts.Logger, storeOpts.IavlConfig), nil
 			case SCTypeIavlV2:
-				dir := fmt.Sprintf("%s/data/iavl-v2/%s", opts.RootDir, key)
+				dir := filepath.Join(iavlV2Dir(opts.RootDir), key)
 				return iavlv2.NewTree(opts.Options.IavlV2Config, iavl_v2.SqliteDbOptions{Path: dir}, opts.Logger)
 			default:
 				return nil, errors.New("unsupported commitment store type")
@@ -112,32 +147,167 @@ func CreateRootStore(opts *FactoryOptions) (store.RootStore, error) {
 		}
 	}
 
-	// check if we need to migrate the store
+	// prevTreeType returns the commitment store type the store key was committed
+	// with, the store keys committed before the types were recorded use the
+	// global type.
+	prevTreeType := func(key string) (SCType, error) {
+		treeType, err := metadata.GetTreeType(key)
+		if err != nil {
+			return "", err
+		}
+		if treeType == "" {
+			return storeOpts.SCType, nil
+		}
+		return SCType(treeType), nil
+	}
 
-	scType := storeOpts.SCType
+	var committedStoreKeys map[string]bool
+	if latestVersion > 0 {
+		lastCommitInfo, err := metadata.GetCommitInfo(latestVersion)
+		if err != nil {
+			return nil, err
+		}
+		if lastCommitInfo != nil {
+			committedStoreKeys = make(map[string]bool, len(lastCommitInfo.StoreInfos))
+			for _, si := range lastCommitInfo.StoreInfos {
+				committedStoreKeys[si.Name] = true
+			}
+		}
+	}
 
+	// orphans are the data of the store keys which isn't referenced anymore, it
+	// is erased in the background
+	orphans := make(map[string]commitment.Eraser)
 	trees := make(map[string]commitment.Tree, len(opts.StoreKeys))
 	for _, key := range opts.StoreKeys {
+		scType := storeOpts.storeKeySCType(key)
 		tree, err := newTreeFn(key, scType)
 		if err != nil {
 			return nil, err
 		}
 		trees[key] = tree
+		if internal.IsMemoryStoreKey(key) {
+			continue
+		}
+
+		// check if we need to migrate the store key to another commitment store type
+		prevType, err := prevTreeType(key)
+		if err != nil {
+			return nil, err
+		}
+		if prevType != scType && committedStoreKeys[key] {
+			// a migration interrupted before the new type was recorded leaves a
+			// partially imported target tree, which is erased before migrating again
+			if eraser, ok := tree.(commitment.Eraser); ok {
+				if _, err := eraser.Erase(); err != nil {
+					return nil, fmt.Errorf("failed to erase the %s tree of store %s before migrating it: %w", scType, key, err)
+				}
+				if tree, err = newTreeFn(key, scType); err != nil {
+					return nil, err
+				}
+				trees[key] = tree
+			}
+
+			prevTree, err := newTreeFn(key, prevType)
+			if err != nil {
+				return nil, err
+			}
+			opts.Logger.Info("migrating store to another commitment store type", "store_key", key, "from", prevType, "to", scType)
+			if err := migration.MigrateTree(key, latestVersion, prevTree, tree, opts.SCRawDB, opts.Logger); err != nil {
+				return nil, errors.Join(err, prevTree.Close())
+			}
+			if eraser, ok := prevTree.(commitment.Eraser); ok {
+				orphans[key] = eraser
+			} else if err := prevTree.Close(); err != nil {
+				return nil, err
+			}
+		}
+		if err := metadata.SetTreeType(key, string(scType)); err != nil {
+			return nil, err
+		}
 	}
 	oldTrees := make(map[string]commitment.Tree, len(opts.StoreKeys))
 	for _, key := range removedStoreKeys {
+		scType, err := prevTreeType(string(key))
+		if err != nil {
+			return nil, err
+		}
 		tree, err := newTreeFn(string(key), scType)
 		if err != nil {
 			return nil, err
 		}
 		oldTrees[string(key)] = tree
 	}
+	if latestVersion > 0 {
+		if err := findOrphanedIavlV2Dirs(opts.RootDir, trees, oldTrees, orphans); err != nil {
+			return nil, err
+		}
+	}
 
 	sc, err = commitment.NewCommitStore(trees, oldTrees, opts.SCRawDB, opts.Logger)
 	if err != nil {
 		return nil, err
 	}
+	sc.EraseOrphans(orphans)
+	if err := sc.SetPipelinedCommit(storeOpts.SCPipelinedCommit); err != nil {
+		return nil, err
+	}
 
-	pm := pruning.NewManager(sc, storeOpts.SCPruningOption)
-	return New(opts.SCRawDB, opts.Logger, sc, pm, metrics.NoOpMetrics{})
+	if storeOpts.SSType == "" {
+		pm := pruning.NewManager(sc, storeOpts.SCPruningOption, nil, nil)
+		return New(opts.SCRawDB, opts.Logger, nil, sc, pm, metrics.NoOpMetrics{})
+	}
+
+	ssRawDB := opts.SSRawDB
+	if ssRawDB == nil {
+		switch storeOpts.SSType {
+		case SSTypePebbleDB, SSTypeGoLevelDB:
+			ssRawDB, err = db.NewDB(db.DBType(storeOpts.SSType), "ss", filepath.Join(opts.RootDir, "data"), opts.DBOptions)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open SS database: %w", err)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported state storage type: %s", storeOpts.SSType)
+		}
+	}
+
+	ss := storage.NewStorageStore(storage.NewMVCCDatabase(ssRawDB), opts.Logger)
+	pm := pruning.NewManager(sc, storeOpts.SCPruningOption, ss, storeOpts.SSPruningOption)
+	return New(opts.SCRawDB, opts.Logger, ss, sc, pm, metrics.NoOpMetrics{})
+}
+
+// iavlV2Dir returns the directory of the iavl-v2 trees, one sqlite directory per
+// store key.
+func iavlV2Dir(rootDir string) string {
+	return filepath.Join(rootDir, "data", "iavl-v2")
+}
+
+// findOrphanedIavlV2Dirs adds to the orphans the iavl-v2 directories which
+// aren't used by a tree, e.g. the directory of a store key removed before its
+// data was erased by the pruning or which changed of commitment backend.
+func findOrphanedIavlV2Dirs(rootDir string, trees, oldTrees map[string]commitment.Tree, orphans map[string]commitment.Eraser) error {
+	entries, err := os.ReadDir(iavlV2Dir(rootDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for _, entry := range entries {
+		key := entry.Name()
+		if !entry.IsDir() || orphans[key] != nil {
+			continue
+		}
+		if _, ok := trees[key].(*iavlv2.Tree); ok {
+			continue
+		}
+		if _, ok := oldTrees[key].(*iavlv2.Tree); ok {
+			continue
+		}
+		path := filepath.Join(iavlV2Dir(rootDir), key)
+		orphans[key] = commitment.EraserFunc(func() (uint64, error) {
+			return iavlv2.EraseDir(path)
+		})
+	}
+	return nil
 }
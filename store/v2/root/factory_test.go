@@ -1,6 +1,9 @@
 package root
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	gogotypes "github.com/cosmos/gogoproto/types"
@@ -8,6 +11,8 @@ import (
 
 	corestore "cosmossdk.io/core/store"
 	coretesting "cosmossdk.io/core/testing"
+	"cosmossdk.io/store/v2/commitment"
+	"cosmossdk.io/store/v2/commitment/iavlv2"
 	"cosmossdk.io/store/v2/db"
 )
 
@@ -25,6 +30,7 @@ func TestFactory(t *testing.T) {
 	require.NotNil(t, f)
 
 	fop.Options.SCType = SCTypeIavlV2
+	fop.Options.IavlV2Config = iavlv2.DefaultConfig()
 	f, err = CreateRootStore(&fop)
 	require.NoError(t, err)
 	require.NotNil(t, f)
@@ -34,6 +40,140 @@ func TestFactory(t *testing.T) {
 	f, err = CreateRootStore(&fop)
 	require.NoError(t, err)
 	require.NotNil(t, f)
+
+	// the pipelined commit requires the state storage
+	fop.Options.SCPipelinedCommit = true
+	_, err = CreateRootStore(&fop)
+	require.Error(t, err)
+	fop.Options.SSType = SSTypeGoLevelDB
+	fop.SSRawDB = db.NewMemDB()
+	f, err = CreateRootStore(&fop)
+	require.NoError(t, err)
+	require.NotNil(t, f)
+}
+
+func TestFactoryMigrateStoreKey(t *testing.T) {
+	fop := FactoryOptions{
+		Logger:    coretesting.NewNopLogger(),
+		RootDir:   t.TempDir(),
+		Options:   DefaultStoreOptions(),
+		StoreKeys: storeKeys,
+		SCRawDB:   db.NewMemDB(),
+	}
+	fop.Options.SCType = SCTypeIavlV2
+	fop.Options.IavlV2Config = iavlv2.DefaultConfig()
+
+	rs, err := CreateRootStore(&fop)
+	require.NoError(t, err)
+	require.NoError(t, rs.LoadLatestVersion())
+	for v := uint64(1); v <= 5; v++ {
+		cs := corestore.NewChangeset(v)
+		for _, storeKey := range storeKeys {
+			for i := 0; i < 10; i++ {
+				cs.Add([]byte(storeKey), []byte(fmt.Sprintf("key%d-%d", v, i)), []byte(fmt.Sprintf("val%d", i)), false)
+			}
+		}
+		_, err := rs.Commit(cs)
+		require.NoError(t, err)
+	}
+	commitID, err := rs.LastCommitID()
+	require.NoError(t, err)
+	require.NoError(t, rs.Close())
+
+	// migrate store1 to iavl
+	fop.Options.SCTypes = map[string]SCType{"store1": SCTypeIavl}
+	rs, err = CreateRootStore(&fop)
+	require.NoError(t, err)
+	require.NoError(t, rs.LoadLatestVersion())
+	migratedCommitID, err := rs.LastCommitID()
+	require.NoError(t, err)
+	require.Equal(t, commitID, migratedCommitID)
+
+	treeType, err := commitment.NewMetadataStore(fop.SCRawDB).GetTreeType("store1")
+	require.NoError(t, err)
+	require.Equal(t, string(SCTypeIavl), treeType)
+
+	_, ro, err := rs.StateLatest()
+	require.NoError(t, err)
+	r, err := ro.GetReader([]byte("store1"))
+	require.NoError(t, err)
+	val, err := r.Get([]byte("key3-7"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("val7"), val)
+
+	// the migrated store key keeps committing
+	cs := corestore.NewChangeset(6)
+	cs.Add([]byte("store1"), []byte("key6"), []byte("val6"), false)
+	_, err = rs.Commit(cs)
+	require.NoError(t, err)
+
+	// reloading with the same types doesn't migrate again
+	orphanDir := filepath.Join(fop.RootDir, "data", "iavl-v2", "orphan")
+	require.NoError(t, os.MkdirAll(orphanDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(orphanDir, "data.sqlite"), []byte("data"), 0o644))
+	rs, err = CreateRootStore(&fop)
+	require.NoError(t, err)
+	require.NoError(t, rs.LoadLatestVersion())
+	latestVersion, err := rs.GetLatestVersion()
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), latestVersion)
+
+	// the iavl-v2 data of the migrated store key and the orphaned data are erased
+	// once the background jobs are done
+	require.NoError(t, rs.Close())
+	for _, key := range []string{"store1", "orphan"} {
+		_, err = os.Stat(filepath.Join(fop.RootDir, "data", "iavl-v2", key))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	}
+	_, err = os.Stat(filepath.Join(fop.RootDir, "data", "iavl-v2", "store2"))
+	require.NoError(t, err)
+}
+
+func TestFactoryMigrateStoreKeyInterrupted(t *testing.T) {
+	fop := FactoryOptions{
+		Logger:    coretesting.NewNopLogger(),
+		RootDir:   t.TempDir(),
+		Options:   DefaultStoreOptions(),
+		StoreKeys: storeKeys,
+		SCRawDB:   db.NewMemDB(),
+	}
+	fop.Options.SCType = SCTypeIavlV2
+	fop.Options.IavlV2Config = iavlv2.DefaultConfig()
+
+	rs, err := CreateRootStore(&fop)
+	require.NoError(t, err)
+	require.NoError(t, rs.LoadLatestVersion())
+	for v := uint64(1); v <= 2; v++ {
+		cs := corestore.NewChangeset(v)
+		for i := 0; i < 10; i++ {
+			cs.Add([]byte("store1"), []byte(fmt.Sprintf("key%d-%d", v, i)), []byte(fmt.Sprintf("val%d", i)), false)
+		}
+		_, err = rs.Commit(cs)
+		require.NoError(t, err)
+	}
+	require.NoError(t, rs.Close())
+
+	// a migration interrupted before the new type is recorded leaves a partially
+	// imported target tree, which is erased before migrating again
+	partialKey := []byte(fmt.Sprintf(storePrefixTpl, "store1") + "partial")
+	require.NoError(t, fop.SCRawDB.Set(partialKey, []byte("partial")))
+
+	fop.Options.SCTypes = map[string]SCType{"store1": SCTypeIavl}
+	rs, err = CreateRootStore(&fop)
+	require.NoError(t, err)
+	require.NoError(t, rs.LoadLatestVersion())
+	has, err := fop.SCRawDB.Has(partialKey)
+	require.NoError(t, err)
+	require.False(t, has)
+
+	_, ro, err := rs.StateLatest()
+	require.NoError(t, err)
+	r, err := ro.GetReader([]byte("store1"))
+	require.NoError(t, err)
+	val, err := r.Get([]byte("key1-0"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("val0"), val)
+	require.NoError(t, rs.Close())
 }
 
 func setLatestVersion(db corestore.KVStoreWithBatch, version int64) error {
@@ -64,10 +64,10 @@ func (s *MigrateStoreTestSuite) SetupTest() {
 	sc, err := commitment.NewCommitStore(multiTrees1, nil, dbm.NewMemDB(), testLog)
 	s.Require().NoError(err)
 
-	pm := pruning.NewManager(sc, nil)
+	pm := pruning.NewManager(sc, nil, nil, nil)
 
 	// assume no storage store, simulate the migration process
-	s.rootStore, err = New(dbm.NewMemDB(), testLog, orgSC, pm, nil)
+	s.rootStore, err = New(dbm.NewMemDB(), testLog, nil, orgSC, pm, nil)
 	s.Require().NoError(err)
 }
 
@@ -1,15 +1,20 @@
 package root
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"time"
 
+	"golang.org/x/sync/errgroup"
+
 	corelog "cosmossdk.io/core/log"
 	corestore "cosmossdk.io/core/store"
 	"cosmossdk.io/store/v2"
+	"cosmossdk.io/store/v2/internal"
 	"cosmossdk.io/store/v2/metrics"
 	"cosmossdk.io/store/v2/proof"
 	"cosmossdk.io/store/v2/pruning"
@@ -18,6 +23,17 @@ import (
 var (
 	_ store.RootStore        = (*Store)(nil)
 	_ store.UpgradeableStore = (*Store)(nil)
+	_ store.BatchQuerier     = (*Store)(nil)
+)
+
+const (
+	// importBatchSize is the number of key-value pairs written to the SS in a batch
+	// while it is populated from the SC.
+	importBatchSize = 10_000
+
+	// importLogInterval is the number of batches after which the progress of the
+	// SS import is logged.
+	importLogInterval = 100
 )
 
 // Store defines the SDK's default RootStore implementation. It contains a single
@@ -30,6 +46,10 @@ type Store struct {
 	// holds the db instance for closing it
 	dbCloser io.Closer
 
+	// stateStorage reflects the state storage (SS) backend, it is optional and
+	// serves the versioned reads when set
+	stateStorage store.VersionedWriter
+
 	// stateCommitment reflects the state commitment (SC) backend
 	stateCommitment store.Committer
 
@@ -45,29 +65,39 @@ type Store struct {
 
 // New creates a new root Store instance.
 //
-// NOTE: The migration manager is optional and can be nil if no migration is required.
+// NOTE: The SS backend is optional and can be nil, in which case the versioned
+// reads are served by the SC backend.
 func New(
 	dbCloser io.Closer,
 	logger corelog.Logger,
+	ss store.VersionedWriter,
 	sc store.Committer,
 	pm *pruning.Manager,
 	m metrics.StoreMetrics,
 ) (store.RootStore, error) {
-	return &Store{
+	s := &Store{
 		dbCloser:        dbCloser,
 		logger:          logger,
+		stateStorage:    ss,
 		stateCommitment: sc,
 		pruningManager:  pm,
-		telemetry:       m,
-	}, nil
+	}
+	if m != nil {
+		s.setMetrics(m)
+	}
+	return s, nil
 }
 
 // Close closes the store and resets all internal fields. Note, Close() is NOT
 // idempotent and should only be called once.
 func (s *Store) Close() (err error) {
+	if s.stateStorage != nil {
+		err = errors.Join(err, s.stateStorage.Close())
+	}
 	err = errors.Join(err, s.stateCommitment.Close())
 	err = errors.Join(err, s.dbCloser.Close())
 
+	s.stateStorage = nil
 	s.stateCommitment = nil
 	s.lastCommitInfo = nil
 
@@ -75,7 +105,16 @@ func (s *Store) Close() (err error) {
 }
 
 func (s *Store) SetMetrics(m metrics.Metrics) {
+	s.setMetrics(m)
+}
+
+// setMetrics sets the telemetry of the store, which is also used by the state
+// commitment to report the bytes reclaimed from the removed store keys.
+func (s *Store) setMetrics(m metrics.StoreMetrics) {
 	s.telemetry = m
+	if sc, ok := s.stateCommitment.(interface{ SetMetrics(metrics.StoreMetrics) }); ok {
+		sc.SetMetrics(m)
+	}
 }
 
 func (s *Store) SetInitialVersion(v uint64) error {
@@ -85,9 +124,19 @@ func (s *Store) SetInitialVersion(v uint64) error {
 // getVersionedReader returns a VersionedReader based on the given version. If the
 // version exists in the state storage, it returns the state storage.
 // If not, it checks if the state commitment implements the VersionedReader interface
-// and the version exists in the state commitment, since the state storage will be
-// synced during migration.
+// and the version exists in the state commitment, since the state storage may not
+// hold the versions committed before it was enabled or which it pruned.
 func (s *Store) getVersionedReader(version uint64) (store.VersionedReader, error) {
+	if s.stateStorage != nil {
+		isExist, err := s.stateStorage.VersionExists(version)
+		if err != nil {
+			return nil, err
+		}
+		if isExist {
+			return s.stateStorage, nil
+		}
+	}
+
 	isExist, err := s.stateCommitment.VersionExists(version)
 	if err != nil {
 		return nil, err
@@ -121,6 +170,11 @@ func (s *Store) GetStateCommitment() store.Committer {
 	return s.stateCommitment
 }
 
+// GetStateStorage returns the SS backend or nil if the store has no SS backend.
+func (s *Store) GetStateStorage() store.VersionedWriter {
+	return s.stateStorage
+}
+
 // LastCommitID returns a CommitID based off of the latest internal CommitInfo.
 // If an internal CommitInfo is not set, a new one will be returned with only the
 // latest version set, which is based off of the SC view.
@@ -156,9 +210,14 @@ func (s *Store) Query(storeKey []byte, version uint64, key []byte, prove bool) (
 		defer s.telemetry.MeasureSince(time.Now(), "root_store", "query")
 	}
 
-	val, err := s.stateCommitment.Get(storeKey, version, key)
+	reader, err := s.queryReader(version)
+	if err != nil {
+		return store.QueryResult{}, err
+	}
+
+	val, err := reader.Get(storeKey, version, key)
 	if err != nil {
-		return store.QueryResult{}, fmt.Errorf("failed to query SC store: %w", err)
+		return store.QueryResult{}, fmt.Errorf("failed to query store: %w", err)
 	}
 
 	result := store.QueryResult{
@@ -177,6 +236,116 @@ func (s *Store) Query(storeKey []byte, version uint64, key []byte, prove bool) (
 	return result, nil
 }
 
+// QueryBatch implements store.BatchQuerier.
+func (s *Store) QueryBatch(storeKey []byte, version uint64, keys [][]byte, prove bool) (store.BatchQueryResult, error) {
+	if s.telemetry != nil {
+		defer s.telemetry.MeasureSince(time.Now(), "root_store", "query_batch")
+	}
+
+	reader, err := s.queryReader(version)
+	if err != nil {
+		return store.BatchQueryResult{}, err
+	}
+
+	result := store.BatchQueryResult{
+		Pairs:   make([]proof.KVPair, len(keys)),
+		Version: version,
+	}
+	for i, key := range keys {
+		val, err := reader.Get(storeKey, version, key)
+		if err != nil {
+			return store.BatchQueryResult{}, fmt.Errorf("failed to query store: %w", err)
+		}
+		result.Pairs[i] = proof.KVPair{Key: key, Value: val}
+	}
+
+	if prove {
+		result.ProofOps, err = s.getBatchProof(storeKey, version, keys)
+		if err != nil {
+			return store.BatchQueryResult{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// QueryRange implements store.BatchQuerier.
+func (s *Store) QueryRange(storeKey []byte, version uint64, start, end []byte, limit int, prove bool) (store.RangeQueryResult, error) {
+	if s.telemetry != nil {
+		defer s.telemetry.MeasureSince(time.Now(), "root_store", "query_range")
+	}
+
+	if start != nil && end != nil && bytes.Compare(start, end) >= 0 {
+		return store.RangeQueryResult{}, fmt.Errorf("invalid range [%X, %X)", start, end)
+	}
+	reader, err := s.queryReader(version)
+	if err != nil {
+		return store.RangeQueryResult{}, err
+	}
+
+	itr, err := reader.Iterator(storeKey, version, start, end)
+	if err != nil {
+		return store.RangeQueryResult{}, fmt.Errorf("failed to query store: %w", err)
+	}
+	defer itr.Close()
+
+	result := store.RangeQueryResult{
+		Start:   start,
+		End:     end,
+		Version: version,
+	}
+	var keys [][]byte
+	for ; itr.Valid(); itr.Next() {
+		if limit > 0 && len(keys) == limit {
+			// the range of the result ends right after the last key
+			result.End = append(bytes.Clone(keys[len(keys)-1]), 0)
+			break
+		}
+		key := bytes.Clone(itr.Key())
+		keys = append(keys, key)
+		result.Pairs = append(result.Pairs, proof.KVPair{Key: key, Value: bytes.Clone(itr.Value())})
+	}
+	if err := itr.Error(); err != nil {
+		return store.RangeQueryResult{}, fmt.Errorf("failed to query store: %w", err)
+	}
+
+	if prove {
+		result.ProofOps, err = s.getBatchProof(storeKey, version, proof.RangeProbeKeys(result.Start, result.End, keys))
+		if err != nil {
+			return store.RangeQueryResult{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// queryReader returns the reader of the queries at the version. Values are read
+// from the SS when it holds the version, proofs always come from the SC.
+func (s *Store) queryReader(version uint64) (store.VersionedReader, error) {
+	if s.stateStorage != nil {
+		isExist, err := s.stateStorage.VersionExists(version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query SS store: %w", err)
+		}
+		if isExist {
+			return s.stateStorage, nil
+		}
+	}
+	return s.stateCommitment, nil
+}
+
+func (s *Store) getBatchProof(storeKey []byte, version uint64, keys [][]byte) ([]proof.CommitmentOp, error) {
+	prover, ok := s.stateCommitment.(store.BatchProver)
+	if !ok {
+		return nil, errors.New("SC store does not support batch proofs")
+	}
+	proofOps, err := prover.GetBatchProof(storeKey, version, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SC store batch proof: %w", err)
+	}
+	return proofOps, nil
+}
+
 func (s *Store) LoadLatestVersion() error {
 	if s.telemetry != nil {
 		defer s.telemetry.MeasureSince(time.Now(), "root_store", "load_latest_version")
@@ -251,9 +420,146 @@ func (s *Store) loadVersion(v uint64, upgrades *corestore.StoreUpgrades, overrid
 		return fmt.Errorf("failed to get commit info for version %d: %w", v, err)
 	}
 
+	if err := s.syncStateStorage(v); err != nil {
+		return fmt.Errorf("failed to sync SS with SC version %d: %w", v, err)
+	}
+
 	return nil
 }
 
+// syncStateStorage makes the SS hold the same latest version as the SC once the
+// SC is loaded at the given version.
+func (s *Store) syncStateStorage(version uint64) error {
+	if s.stateStorage == nil {
+		return nil
+	}
+
+	ssVersion, err := s.stateStorage.GetLatestVersion()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case ssVersion == version:
+		return nil
+
+	case ssVersion > version:
+		// the SS is written before the SC is committed, so it is ahead of the SC
+		// if the node stopped during a commit or if the store is loaded for overwriting
+		s.logger.Info("rolling back SS", "from", ssVersion, "to", version)
+		return s.stateStorage.Rollback(version)
+
+	case ssVersion == 0:
+		// the SS was just enabled or the SC was restored from a snapshot
+		return s.importStateStorage(version)
+
+	default:
+		return fmt.Errorf("SS version %d is behind SC version %d, the SS must be removed to be imported from the SC again", ssVersion, version)
+	}
+}
+
+// importStateStorage populates the empty SS with the state of the SC at the given
+// version, which becomes the earliest version of the SS. The position of every
+// written batch is recorded, so an interrupted import resumes where it stopped the
+// next time the store is loaded.
+func (s *Store) importStateStorage(version uint64) error {
+	progressVersion, resumeStoreKey, resumeKey, err := s.stateStorage.GetImportProgress()
+	if err != nil {
+		return err
+	}
+	if progressVersion != 0 && progressVersion != version {
+		// the state written by an import of another version can't be reused
+		s.logger.Info("removing interrupted SS import", "version", progressVersion)
+		if err := s.stateStorage.Rollback(0); err != nil {
+			return err
+		}
+		resumeStoreKey, resumeKey = nil, nil
+	}
+
+	if resumeStoreKey != nil {
+		s.logger.Info("resuming SS import from SC", "version", version, "store_key", string(resumeStoreKey))
+	} else {
+		s.logger.Info("importing SS from SC", "version", version)
+	}
+
+	var (
+		imported, batches  int
+		lastStore, lastKey []byte
+	)
+	cs := corestore.NewChangeset(version)
+	flush := func() error {
+		if cs.Size() == 0 {
+			return nil
+		}
+		if err := s.stateStorage.ApplyChangeset(cs); err != nil {
+			return err
+		}
+		if err := s.stateStorage.SetImportProgress(version, lastStore, lastKey); err != nil {
+			return err
+		}
+
+		imported += cs.Size()
+		batches++
+		if batches%importLogInterval == 0 {
+			s.logger.Info("importing SS from SC", "version", version, "store_key", string(lastStore), "keys", imported)
+		}
+		cs = corestore.NewChangeset(version)
+		return nil
+	}
+
+	storeNames := make([]string, 0, len(s.lastCommitInfo.StoreInfos))
+	for _, si := range s.lastCommitInfo.StoreInfos {
+		if !internal.IsMemoryStoreKey(si.Name) {
+			storeNames = append(storeNames, si.Name)
+		}
+	}
+	sort.Strings(storeNames)
+
+	for _, name := range storeNames {
+		storeKey := []byte(name)
+
+		var start []byte
+		if resumeStoreKey != nil {
+			switch bytes.Compare(storeKey, resumeStoreKey) {
+			case -1:
+				continue
+			case 0:
+				start = append(bytes.Clone(resumeKey), 0)
+			}
+		}
+
+		itr, err := s.stateCommitment.Iterator(storeKey, version, start, nil)
+		if err != nil {
+			return err
+		}
+
+		for ; itr.Valid(); itr.Next() {
+			lastStore, lastKey = storeKey, bytes.Clone(itr.Key())
+			cs.Add(storeKey, lastKey, bytes.Clone(itr.Value()), false)
+			if cs.Size() >= importBatchSize {
+				if err := flush(); err != nil {
+					return errors.Join(err, itr.Close())
+				}
+			}
+		}
+
+		if err := errors.Join(itr.Error(), itr.Close()); err != nil {
+			return err
+		}
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+
+	// the version is only readable from the SS once the whole state is imported
+	if err := s.stateStorage.SetLatestVersion(version); err != nil {
+		return err
+	}
+	s.logger.Info("imported SS from SC", "version", version, "keys", imported)
+	return s.stateStorage.SetImportProgress(0, nil, nil)
+}
+
 // Commit commits all state changes to the underlying SS and SC backends. It
 // writes a batch of the changeset to the SC tree, and retrieves the CommitInfo
 // from the SC tree. Finally, it commits the SC tree and returns the hash of
@@ -271,9 +577,26 @@ func (s *Store) Commit(cs *corestore.Changeset) ([]byte, error) {
 	// background pruning process (iavl v1 for example) which must be paused during the commit
 	s.pruningManager.PausePruning()
 
+	// the changeset is written to the SS concurrently with the SC and before the
+	// SC is committed, so that the SS is never behind the SC
 	st := time.Now()
-	if err := s.stateCommitment.WriteChangeset(cs); err != nil {
-		return nil, fmt.Errorf("failed to write batch to SC store: %w", err)
+	eg := new(errgroup.Group)
+	if s.stateStorage != nil {
+		eg.Go(func() error {
+			if err := s.stateStorage.ApplyChangeset(cs); err != nil {
+				return fmt.Errorf("failed to write batch to SS store: %w", err)
+			}
+			return s.stateStorage.SetLatestVersion(cs.Version)
+		})
+	}
+	eg.Go(func() error {
+		if err := s.stateCommitment.WriteChangeset(cs); err != nil {
+			return fmt.Errorf("failed to write batch to SC store: %w", err)
+		}
+		return nil
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, err
 	}
 	writeDur := time.Since(st)
 	st = time.Now()
@@ -17,7 +17,7 @@ import (
 
 func newTestRootStore(sc store.Committer) *Store {
 	noopLog := coretesting.NewNopLogger()
-	pm := pruning.NewManager(sc.(store.Pruner), nil)
+	pm := pruning.NewManager(sc.(store.Pruner), nil, nil, nil)
 	return &Store{
 		logger:          noopLog,
 		telemetry:       metrics.Metrics{},
@@ -1,8 +1,10 @@
 package root
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"fmt"
+	"slices"
 	"testing"
 	"time"
 
@@ -16,6 +18,7 @@ import (
 	dbm "cosmossdk.io/store/v2/db"
 	"cosmossdk.io/store/v2/proof"
 	"cosmossdk.io/store/v2/pruning"
+	"cosmossdk.io/store/v2/storage"
 )
 
 const (
@@ -51,8 +54,8 @@ func (s *RootStoreTestSuite) SetupTest() {
 	sc, err := commitment.NewCommitStore(map[string]commitment.Tree{testStoreKey: tree, testStoreKey2: tree2, testStoreKey3: tree3}, nil, dbm.NewMemDB(), noopLog)
 	s.Require().NoError(err)
 
-	pm := pruning.NewManager(sc, nil)
-	rs, err := New(dbm.NewMemDB(), noopLog, sc, pm, nil)
+	pm := pruning.NewManager(sc, nil, nil, nil)
+	rs, err := New(dbm.NewMemDB(), noopLog, nil, sc, pm, nil)
 	s.Require().NoError(err)
 
 	s.rootStore = rs
@@ -71,9 +74,9 @@ func (s *RootStoreTestSuite) newStoreWithPruneConfig(config *store.PruningOption
 	sc, err := commitment.NewCommitStore(multiTrees, nil, dbm.NewMemDB(), noopLog)
 	s.Require().NoError(err)
 
-	pm := pruning.NewManager(sc, config)
+	pm := pruning.NewManager(sc, config, nil, nil)
 
-	rs, err := New(dbm.NewMemDB(), noopLog, sc, pm, nil)
+	rs, err := New(dbm.NewMemDB(), noopLog, nil, sc, pm, nil)
 	s.Require().NoError(err)
 
 	s.rootStore = rs
@@ -82,7 +85,7 @@ func (s *RootStoreTestSuite) newStoreWithPruneConfig(config *store.PruningOption
 func (s *RootStoreTestSuite) newStoreWithBackendMount(sc store.Committer, pm *pruning.Manager) {
 	noopLog := coretesting.NewNopLogger()
 
-	rs, err := New(dbm.NewMemDB(), noopLog, sc, pm, nil)
+	rs, err := New(dbm.NewMemDB(), noopLog, nil, sc, pm, nil)
 	s.Require().NoError(err)
 
 	s.rootStore = rs
@@ -201,6 +204,121 @@ func (s *RootStoreTestSuite) TestQueryProof() {
 	s.Require().Equal(expRoots[0], cInfo.Hash())
 }
 
+func (s *RootStoreTestSuite) TestQueryBatchProof() {
+	querier, ok := s.rootStore.(store.BatchQuerier)
+	s.Require().True(ok)
+
+	cs := corestore.NewChangeset(1)
+	for i := 0; i < 10; i++ {
+		cs.Add(testStoreKeyBytes, []byte(fmt.Sprintf("key%02d", 2*i)), []byte(fmt.Sprintf("value%02d", 2*i)), false)
+	}
+	cs.Add(testStoreKey2Bytes, []byte("key01"), []byte("value01"), false)
+	appHash, err := s.rootStore.Commit(cs)
+	s.Require().NoError(err)
+
+	keys := [][]byte{[]byte("key00"), []byte("key01"), []byte("key08"), []byte("key19"), []byte("key99")}
+	result, err := querier.QueryBatch(testStoreKeyBytes, 1, keys, true)
+	s.Require().NoError(err)
+	s.Require().Len(result.Pairs, len(keys))
+	s.Require().Equal([]byte("value00"), result.Pairs[0].Value)
+	s.Require().Nil(result.Pairs[1].Value)
+	s.Require().Equal([]byte("value08"), result.Pairs[2].Value)
+	s.Require().Nil(result.Pairs[3].Value)
+	s.Require().Nil(result.Pairs[4].Value)
+	s.Require().NoError(proof.VerifyBatchProof(result.ProofOps, appHash, testStoreKeyBytes, result.Pairs))
+
+	// a tampered value, a present key claimed absent or another store are rejected
+	tampered := slices.Clone(result.Pairs)
+	tampered[0].Value = []byte("value01")
+	s.Require().Error(proof.VerifyBatchProof(result.ProofOps, appHash, testStoreKeyBytes, tampered))
+	tampered = slices.Clone(result.Pairs)
+	tampered[2].Value = nil
+	s.Require().Error(proof.VerifyBatchProof(result.ProofOps, appHash, testStoreKeyBytes, tampered))
+	s.Require().Error(proof.VerifyBatchProof(result.ProofOps, appHash, testStoreKey2Bytes, result.Pairs))
+	s.Require().Error(proof.VerifyBatchProof(result.ProofOps, []byte("invalid"), testStoreKeyBytes, result.Pairs))
+
+	// a key absent from the proof is not verified
+	s.Require().Error(proof.VerifyBatchProof(result.ProofOps, appHash, testStoreKeyBytes, []proof.KVPair{{Key: []byte("key02"), Value: []byte("value02")}}))
+}
+
+func (s *RootStoreTestSuite) TestQueryRangeProof() {
+	querier, ok := s.rootStore.(store.BatchQuerier)
+	s.Require().True(ok)
+
+	cs := corestore.NewChangeset(1)
+	for i := 0; i < 10; i++ {
+		cs.Add(testStoreKeyBytes, []byte(fmt.Sprintf("key%02d", 2*i)), []byte(fmt.Sprintf("value%02d", 2*i)), false)
+	}
+	cs.Add(testStoreKeyBytes, []byte("other"), []byte("other"), false)
+	appHash, err := s.rootStore.Commit(cs)
+	s.Require().NoError(err)
+
+	testCases := []struct {
+		name       string
+		start, end []byte
+		expected   []string
+	}{
+		{"bounded", []byte("key03"), []byte("key08"), []string{"key04", "key06"}},
+		{"bounds are keys", []byte("key04"), []byte("key08"), []string{"key04", "key06"}},
+		{"prefix", []byte("key1"), []byte("key2"), []string{"key10", "key12", "key14", "key16", "key18"}},
+		{"empty", []byte("key050"), []byte("key06"), nil},
+		{"unbounded start", nil, []byte("key03"), []string{"key00", "key02"}},
+		{"unbounded end", []byte("key17"), nil, []string{"key18", "other"}},
+	}
+	for _, tc := range testCases {
+		result, err := querier.QueryRange(testStoreKeyBytes, 1, tc.start, tc.end, 0, true)
+		s.Require().NoError(err, tc.name)
+		keys := make([]string, 0, len(result.Pairs))
+		for _, pair := range result.Pairs {
+			keys = append(keys, string(pair.Key))
+		}
+		if tc.expected == nil {
+			s.Require().Empty(keys, tc.name)
+		} else {
+			s.Require().Equal(tc.expected, keys, tc.name)
+		}
+		s.Require().NoError(proof.VerifyRangeProof(result.ProofOps, appHash, testStoreKeyBytes, tc.start, tc.end, result.Pairs), tc.name)
+	}
+
+	result, err := querier.QueryRange(testStoreKeyBytes, 1, []byte("key03"), []byte("key09"), 0, true)
+	s.Require().NoError(err)
+	s.Require().Len(result.Pairs, 3)
+
+	// an omitted, a tampered or an unsorted pair, or a wider range are rejected
+	s.Require().Error(proof.VerifyRangeProof(result.ProofOps, appHash, testStoreKeyBytes, result.Start, result.End, result.Pairs[1:]))
+	s.Require().Error(proof.VerifyRangeProof(result.ProofOps, appHash, testStoreKeyBytes, result.Start, result.End, []proof.KVPair{result.Pairs[0], result.Pairs[2]}))
+	tampered := slices.Clone(result.Pairs)
+	tampered[1].Value = []byte("invalid")
+	s.Require().Error(proof.VerifyRangeProof(result.ProofOps, appHash, testStoreKeyBytes, result.Start, result.End, tampered))
+	tampered = []proof.KVPair{result.Pairs[1], result.Pairs[0], result.Pairs[2]}
+	s.Require().Error(proof.VerifyRangeProof(result.ProofOps, appHash, testStoreKeyBytes, result.Start, result.End, tampered))
+	s.Require().Error(proof.VerifyRangeProof(result.ProofOps, appHash, testStoreKeyBytes, result.Start, []byte("key11"), result.Pairs))
+	s.Require().Error(proof.VerifyRangeProof(result.ProofOps, appHash, testStoreKeyBytes, []byte("key01"), result.End, result.Pairs))
+
+	// the pages of a limited query are verified over the range of the result
+	var (
+		start = []byte("key05")
+		keys  []string
+	)
+	for {
+		result, err := querier.QueryRange(testStoreKeyBytes, 1, start, []byte("key15"), 2, true)
+		s.Require().NoError(err)
+		s.Require().LessOrEqual(len(result.Pairs), 2)
+		s.Require().NoError(proof.VerifyRangeProof(result.ProofOps, appHash, testStoreKeyBytes, result.Start, result.End, result.Pairs))
+		for _, pair := range result.Pairs {
+			keys = append(keys, string(pair.Key))
+		}
+		if bytes.Equal(result.End, []byte("key15")) {
+			break
+		}
+		start = result.End
+	}
+	s.Require().Equal([]string{"key06", "key08", "key10", "key12", "key14"}, keys)
+
+	_, err = querier.QueryRange(testStoreKeyBytes, 1, []byte("key05"), []byte("key05"), 0, false)
+	s.Require().Error(err)
+}
+
 func (s *RootStoreTestSuite) TestLoadVersion() {
 	// write and commit a few changesets
 	for v := uint64(1); v <= 5; v++ {
@@ -594,7 +712,7 @@ func (s *RootStoreTestSuite) TestMultiStore_PruningRestart() {
 	sc, err := commitment.NewCommitStore(map[string]commitment.Tree{testStoreKey: tree}, nil, mdb2, noopLog)
 	s.Require().NoError(err)
 
-	pm := pruning.NewManager(sc, pruneOpt)
+	pm := pruning.NewManager(sc, pruneOpt, nil, nil)
 
 	s.newStoreWithBackendMount(sc, pm)
 	s.Require().NoError(s.rootStore.LoadLatestVersion())
@@ -620,7 +738,7 @@ func (s *RootStoreTestSuite) TestMultiStore_PruningRestart() {
 	sc, err = commitment.NewCommitStore(map[string]commitment.Tree{testStoreKey: tree}, nil, mdb2, noopLog)
 	s.Require().NoError(err)
 
-	pm = pruning.NewManager(sc, pruneOpt)
+	pm = pruning.NewManager(sc, pruneOpt, nil, nil)
 
 	s.newStoreWithBackendMount(sc, pm)
 	err = s.rootStore.LoadLatestVersion()
@@ -673,7 +791,7 @@ func (s *RootStoreTestSuite) TestMultiStoreRestart() {
 	sc, err := commitment.NewCommitStore(multiTrees, nil, mdb2, noopLog)
 	s.Require().NoError(err)
 
-	pm := pruning.NewManager(sc, nil)
+	pm := pruning.NewManager(sc, nil, nil, nil)
 
 	s.newStoreWithBackendMount(sc, pm)
 	s.Require().NoError(s.rootStore.LoadLatestVersion())
@@ -760,7 +878,7 @@ func (s *RootStoreTestSuite) TestMultiStoreRestart() {
 	sc, err = commitment.NewCommitStore(multiTrees, nil, mdb2, noopLog)
 	s.Require().NoError(err)
 
-	pm = pruning.NewManager(sc, nil)
+	pm = pruning.NewManager(sc, nil, nil, nil)
 
 	s.newStoreWithBackendMount(sc, pm)
 	err = s.rootStore.LoadLatestVersion()
@@ -828,3 +946,207 @@ func (s *RootStoreTestSuite) TestHashStableWithEmptyCommitAndRestart() {
 	s.Require().NoError(err)
 	s.Require().Equal(lastCommitID.Hash, hash)
 }
+
+func (s *RootStoreTestSuite) newStoreWithStateStorage(scDB, metadataDB, ssDB corestore.KVStoreWithBatch, scPruneOpt *store.PruningOption) {
+	noopLog := coretesting.NewNopLogger()
+
+	multiTrees := make(map[string]commitment.Tree)
+	for _, storeKey := range testStoreKeys {
+		prefixDB := dbm.NewPrefixDB(scDB, []byte(storeKey))
+		multiTrees[storeKey] = iavl.NewIavlTree(prefixDB, noopLog, iavl.DefaultConfig())
+	}
+
+	sc, err := commitment.NewCommitStore(multiTrees, nil, metadataDB, noopLog)
+	s.Require().NoError(err)
+
+	if ssDB == nil {
+		s.newStoreWithBackendMount(sc, pruning.NewManager(sc, scPruneOpt, nil, nil))
+		return
+	}
+
+	ss := storage.NewStorageStore(storage.NewMVCCDatabase(ssDB), noopLog)
+	rs, err := New(dbm.NewMemDB(), noopLog, ss, sc, pruning.NewManager(sc, scPruneOpt, ss, nil), nil)
+	s.Require().NoError(err)
+	s.rootStore = rs
+}
+
+func (s *RootStoreTestSuite) commitVersions(from, to uint64) {
+	for v := from; v <= to; v++ {
+		cs := corestore.NewChangeset(v)
+		cs.Add(testStoreKeyBytes, []byte("key"), []byte(fmt.Sprintf("val%d", v)), false)
+		cs.Add(testStoreKey2Bytes, []byte(fmt.Sprintf("key%d", v)), []byte("val"), false)
+		_, err := s.rootStore.Commit(cs)
+		s.Require().NoError(err)
+	}
+}
+
+func (s *RootStoreTestSuite) requireStateAt(version uint64, fromSS bool) {
+	reader, err := s.rootStore.(*Store).getVersionedReader(version)
+	s.Require().NoError(err)
+	if fromSS {
+		s.Require().Equal(s.rootStore.GetStateStorage(), reader)
+	} else {
+		s.Require().Equal(s.rootStore.GetStateCommitment(), reader)
+	}
+
+	ro, err := s.rootStore.StateAt(version)
+	s.Require().NoError(err)
+	r, err := ro.GetReader(testStoreKeyBytes)
+	s.Require().NoError(err)
+	val, err := r.Get([]byte("key"))
+	s.Require().NoError(err)
+	s.Require().Equal([]byte(fmt.Sprintf("val%d", version)), val)
+
+	r, err = ro.GetReader(testStoreKey2Bytes)
+	s.Require().NoError(err)
+	itr, err := r.Iterator(nil, nil)
+	s.Require().NoError(err)
+	defer itr.Close()
+	count := uint64(0)
+	for ; itr.Valid(); itr.Next() {
+		count++
+	}
+	s.Require().Equal(version, count)
+}
+
+func (s *RootStoreTestSuite) TestStateStorage() {
+	s.Require().NoError(s.rootStore.Close())
+	s.newStoreWithStateStorage(dbm.NewMemDB(), dbm.NewMemDB(), dbm.NewMemDB(), store.NewPruningOptionWithCustom(1, 1))
+	s.Require().NoError(s.rootStore.LoadLatestVersion())
+
+	s.commitVersions(1, 5)
+
+	// all the versions are served by the SS, regardless of the SC pruning
+	for v := uint64(1); v <= 5; v++ {
+		s.requireStateAt(v, true)
+	}
+
+	res, err := s.rootStore.Query(testStoreKeyBytes, 2, []byte("key"), false)
+	s.Require().NoError(err)
+	s.Require().Equal([]byte("val2"), res.Value)
+}
+
+func (s *RootStoreTestSuite) TestStateStorageImport() {
+	s.Require().NoError(s.rootStore.Close())
+	scDB, metadataDB := dbm.NewMemDB(), dbm.NewMemDB()
+
+	// commit without SS
+	s.newStoreWithStateStorage(scDB, metadataDB, nil, nil)
+	s.Require().NoError(s.rootStore.LoadLatestVersion())
+	s.commitVersions(1, 3)
+
+	// enabling the SS imports the latest version of the SC
+	s.newStoreWithStateStorage(scDB, metadataDB, dbm.NewMemDB(), nil)
+	s.Require().NoError(s.rootStore.LoadLatestVersion())
+
+	ss := s.rootStore.GetStateStorage()
+	latest, err := ss.GetLatestVersion()
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(3), latest)
+
+	s.requireStateAt(2, false)
+	s.requireStateAt(3, true)
+
+	s.commitVersions(4, 5)
+	s.requireStateAt(5, true)
+}
+
+func (s *RootStoreTestSuite) TestStateStorageImportResume() {
+	s.Require().NoError(s.rootStore.Close())
+	scDB, metadataDB := dbm.NewMemDB(), dbm.NewMemDB()
+
+	s.newStoreWithStateStorage(scDB, metadataDB, nil, nil)
+	s.Require().NoError(s.rootStore.LoadLatestVersion())
+	s.commitVersions(1, 3)
+
+	// an import of the latest version which was interrupted after the first keys of the second store
+	ssDB := dbm.NewMemDB()
+	db := storage.NewMVCCDatabase(ssDB)
+	batch, err := db.NewBatch(3)
+	s.Require().NoError(err)
+	s.Require().NoError(batch.Set(testStoreKeyBytes, []byte("key"), []byte("val3")))
+	s.Require().NoError(batch.Set(testStoreKey2Bytes, []byte("key1"), []byte("val")))
+	s.Require().NoError(batch.Write())
+	s.Require().NoError(db.SetImportProgress(3, testStoreKey2Bytes, []byte("key1")))
+
+	s.newStoreWithStateStorage(scDB, metadataDB, ssDB, nil)
+	s.Require().NoError(s.rootStore.LoadLatestVersion())
+	s.requireStateAt(3, true)
+
+	version, _, _, err := s.rootStore.GetStateStorage().GetImportProgress()
+	s.Require().NoError(err)
+	s.Require().Zero(version)
+	s.Require().NoError(s.rootStore.Close())
+
+	// the state written by an interrupted import of another version is removed
+	ssDB = dbm.NewMemDB()
+	db = storage.NewMVCCDatabase(ssDB)
+	batch, err = db.NewBatch(2)
+	s.Require().NoError(err)
+	s.Require().NoError(batch.Set(testStoreKeyBytes, []byte("stale"), []byte("val")))
+	s.Require().NoError(batch.Write())
+	s.Require().NoError(db.SetImportProgress(2, testStoreKeyBytes, []byte("stale")))
+
+	s.newStoreWithStateStorage(scDB, metadataDB, ssDB, nil)
+	s.Require().NoError(s.rootStore.LoadLatestVersion())
+	s.requireStateAt(3, true)
+	has, err := s.rootStore.GetStateStorage().Has(testStoreKeyBytes, 3, []byte("stale"))
+	s.Require().NoError(err)
+	s.Require().False(has)
+}
+
+func (s *RootStoreTestSuite) TestStateStorageRollback() {
+	s.Require().NoError(s.rootStore.Close())
+	scDB, metadataDB, ssDB := dbm.NewMemDB(), dbm.NewMemDB(), dbm.NewMemDB()
+
+	s.newStoreWithStateStorage(scDB, metadataDB, ssDB, nil)
+	s.Require().NoError(s.rootStore.LoadLatestVersion())
+	s.commitVersions(1, 3)
+
+	// the SS is rolled back with the SC
+	s.Require().NoError(s.rootStore.LoadVersionForOverwriting(1))
+	latest, err := s.rootStore.GetStateStorage().GetLatestVersion()
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(1), latest)
+
+	cs := corestore.NewChangeset(2)
+	cs.Add(testStoreKeyBytes, []byte("key"), []byte("overwritten"), false)
+	_, err = s.rootStore.Commit(cs)
+	s.Require().NoError(err)
+
+	res, err := s.rootStore.Query(testStoreKeyBytes, 2, []byte("key"), false)
+	s.Require().NoError(err)
+	s.Require().Equal([]byte("overwritten"), res.Value)
+	has, err := s.rootStore.GetStateStorage().Has(testStoreKey2Bytes, 2, []byte("key2"))
+	s.Require().NoError(err)
+	s.Require().False(has)
+}
+
+func (s *RootStoreTestSuite) TestPipelinedCommit() {
+	s.Require().NoError(s.rootStore.Close())
+	scDB, metadataDB, ssDB := dbm.NewMemDB(), dbm.NewMemDB(), dbm.NewMemDB()
+
+	s.newStoreWithStateStorage(scDB, metadataDB, ssDB, &store.PruningOption{KeepRecent: 2, Interval: 1})
+	s.Require().NoError(s.rootStore.GetStateCommitment().(*commitment.CommitStore).SetPipelinedCommit(true))
+	s.Require().NoError(s.rootStore.LoadLatestVersion())
+	s.commitVersions(1, 5)
+	commitID, err := s.rootStore.LastCommitID()
+	s.Require().NoError(err)
+
+	// the latest version is read from the SS while the SC persists it
+	s.requireStateAt(5, true)
+	proofRes, err := s.rootStore.Query(testStoreKeyBytes, 5, []byte("key"), true)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(proofRes.ProofOps)
+	s.Require().NoError(s.rootStore.Close())
+
+	// the store is reloaded at the committed version
+	s.newStoreWithStateStorage(scDB, metadataDB, ssDB, nil)
+	s.Require().NoError(s.rootStore.LoadLatestVersion())
+	reloadedCommitID, err := s.rootStore.LastCommitID()
+	s.Require().NoError(err)
+	s.Require().Equal(commitID, reloadedCommitID)
+	val, err := s.rootStore.GetStateCommitment().Get(testStoreKeyBytes, 5, []byte("key"))
+	s.Require().NoError(err)
+	s.Require().Equal([]byte("val5"), val)
+}
@@ -43,8 +43,8 @@ func (s *UpgradeStoreTestSuite) SetupTest() {
 
 	sc, err := commitment.NewCommitStore(multiTrees, nil, s.commitDB, testLog)
 	s.Require().NoError(err)
-	pm := pruning.NewManager(sc, nil)
-	s.rootStore, err = New(s.commitDB, testLog, sc, pm, nil)
+	pm := pruning.NewManager(sc, nil, nil, nil)
+	s.rootStore, err = New(s.commitDB, testLog, nil, sc, pm, nil)
 	s.Require().NoError(err)
 
 	// commit changeset
@@ -85,8 +85,8 @@ func (s *UpgradeStoreTestSuite) loadWithUpgrades(upgrades *corestore.StoreUpgrad
 
 	sc, err := commitment.NewCommitStore(multiTrees, oldTrees, s.commitDB, testLog)
 	s.Require().NoError(err)
-	pm := pruning.NewManager(sc, nil)
-	s.rootStore, err = New(s.commitDB, testLog, sc, pm, nil)
+	pm := pruning.NewManager(sc, nil, nil, nil)
+	s.rootStore, err = New(s.commitDB, testLog, nil, sc, pm, nil)
 	s.Require().NoError(err)
 }
 
@@ -8,6 +8,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -154,6 +157,115 @@ func (m *mockCommitSnapshotter) SupportedFormats() []uint32 {
 	return []uint32{snapshotstypes.CurrentFormat}
 }
 
+// mockStoreSnapshotter snapshots the items of every store as IAVL items and restores them
+// with StoreRestorer. The restore of the store failStore fails.
+type mockStoreSnapshotter struct {
+	mtx       sync.Mutex
+	stores    map[string][][]byte
+	failStore string
+	finished  bool
+}
+
+var _ snapshots.StoreRestorer = (*mockStoreSnapshotter)(nil)
+
+func (m *mockStoreSnapshotter) Snapshot(height uint64, protoWriter protoio.Writer) error {
+	for _, name := range slices.Sorted(maps.Keys(m.stores)) {
+		err := protoWriter.WriteMsg(&snapshotstypes.SnapshotItem{
+			Item: &snapshotstypes.SnapshotItem_Store{Store: &snapshotstypes.SnapshotStoreItem{Name: name}},
+		})
+		if err != nil {
+			return err
+		}
+		for _, item := range m.stores[name] {
+			err := protoWriter.WriteMsg(&snapshotstypes.SnapshotItem{
+				Item: &snapshotstypes.SnapshotItem_IAVL{IAVL: &snapshotstypes.SnapshotIAVLItem{Key: item}},
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *mockStoreSnapshotter) Restore(
+	height uint64, format uint32, protoReader protoio.Reader,
+) (snapshotstypes.SnapshotItem, error) {
+	var (
+		item  snapshotstypes.SnapshotItem
+		store string
+	)
+	for {
+		item.Reset()
+		err := protoReader.ReadMsg(&item)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return snapshotstypes.SnapshotItem{}, fmt.Errorf("invalid protobuf message: %w", err)
+		}
+		switch item := item.Item.(type) {
+		case *snapshotstypes.SnapshotItem_Store:
+			store = item.Store.Name
+			m.addStore(store)
+		case *snapshotstypes.SnapshotItem_IAVL:
+			m.addItem(store, item.IAVL.Key)
+		default:
+			return snapshotstypes.SnapshotItem{Item: item}, m.FinishRestore(height)
+		}
+	}
+	return snapshotstypes.SnapshotItem{}, m.FinishRestore(height)
+}
+
+func (m *mockStoreSnapshotter) RestoreStore(height uint64, storeKey string, protoReader protoio.Reader) error {
+	if storeKey == m.failStore {
+		return errors.New("mock restore store error")
+	}
+
+	var item snapshotstypes.SnapshotItem
+	if err := protoReader.ReadMsg(&item); err != nil {
+		return err
+	}
+	if item.GetStore().GetName() != storeKey {
+		return fmt.Errorf("unexpected store item %v", item.Item)
+	}
+	m.addStore(storeKey)
+	for {
+		item.Reset()
+		err := protoReader.ReadMsg(&item)
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if item.GetIAVL() == nil {
+			return fmt.Errorf("unexpected item %v", item.Item)
+		}
+		m.addItem(storeKey, item.GetIAVL().Key)
+	}
+}
+
+func (m *mockStoreSnapshotter) FinishRestore(height uint64) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.finished = true
+	return nil
+}
+
+func (m *mockStoreSnapshotter) addStore(store string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.stores == nil {
+		m.stores = map[string][][]byte{}
+	}
+	m.stores[store] = [][]byte{}
+}
+
+func (m *mockStoreSnapshotter) addItem(store string, item []byte) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.stores[store] = append(m.stores[store], item)
+}
+
 type mockErrorCommitSnapshotter struct{}
 
 var _ snapshots.CommitSnapshotter = (*mockErrorCommitSnapshotter)(nil)
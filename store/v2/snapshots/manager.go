@@ -2,6 +2,7 @@ package snapshots
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"errors"
 	"fmt"
@@ -11,8 +12,12 @@ import (
 	"sort"
 	"sync"
 
+	protoio "github.com/cosmos/gogoproto/io"
+	"golang.org/x/sync/errgroup"
+
 	corelog "cosmossdk.io/core/log"
 	errorsmod "cosmossdk.io/errors/v2"
+	storev2 "cosmossdk.io/store/v2"
 	storeerrors "cosmossdk.io/store/v2/errors"
 	"cosmossdk.io/store/v2/snapshots/types"
 )
@@ -183,18 +188,18 @@ func (m *Manager) Create(height uint64) (*types.Snapshot, error) {
 
 	// Spawn goroutine to generate snapshot chunks and pass their io.ReadClosers through a channel
 	ch := make(chan io.ReadCloser)
-	go m.createSnapshot(height, ch)
+	streamWriter := newStoreChunksWriter(ch)
+	go m.createSnapshot(height, streamWriter)
 
-	return m.store.Save(height, types.CurrentFormat, ch)
+	// the store manifests are complete once the channel is closed
+	return m.store.save(height, types.CurrentFormat, ch, func() []*types.SnapshotStoreManifest {
+		return streamWriter.stores
+	})
 }
 
 // createSnapshot do the heavy work of snapshotting after the validations of request are done
-// the produced chunks are written to the channel.
-func (m *Manager) createSnapshot(height uint64, ch chan<- io.ReadCloser) {
-	streamWriter := NewStreamWriter(ch)
-	if streamWriter == nil {
-		return
-	}
+// the produced chunks are written to the stream writer.
+func (m *Manager) createSnapshot(height uint64, streamWriter *storeChunksWriter) {
 	defer func() {
 		if err := streamWriter.Close(); err != nil {
 			streamWriter.CloseWithError(err)
@@ -205,6 +210,11 @@ func (m *Manager) createSnapshot(height uint64, ch chan<- io.ReadCloser) {
 		streamWriter.CloseWithError(err)
 		return
 	}
+	// the extension items are not part of the chunks of the last store
+	if err := streamWriter.endStores(); err != nil {
+		streamWriter.CloseWithError(err)
+		return
+	}
 	for _, name := range m.sortedExtensionNames() {
 		extension := m.extensions[name]
 		// write extension metadata
@@ -306,7 +316,7 @@ func (m *Manager) Restore(snapshot types.Snapshot) error {
 	defer m.mtx.Unlock()
 
 	// check multistore supported format preemptive
-	if snapshot.Format != types.CurrentFormat {
+	if !types.IsFormatSupported(snapshot.Format) {
 		return errorsmod.Wrapf(types.ErrUnknownFormat, "snapshot format %v", snapshot.Format)
 	}
 	if snapshot.Height == 0 {
@@ -317,6 +327,15 @@ func (m *Manager) Restore(snapshot types.Snapshot) error {
 			"snapshot height %v cannot exceed %v", snapshot.Height, int64(math.MaxInt64))
 	}
 
+	var segments []segment
+	if snapshot.Format == types.FormatStoreChunks {
+		var err error
+		segments, err = snapshotSegments(&snapshot)
+		if err != nil {
+			return err
+		}
+	}
+
 	err := m.beginLocked(opRestore)
 	if err != nil {
 		return err
@@ -331,10 +350,13 @@ func (m *Manager) Restore(snapshot types.Snapshot) error {
 		return errorsmod.Wrapf(err, "failed to create snapshot directory %q", dir)
 	}
 
-	chChunks := m.loadChunkStream(snapshot.Height, snapshot.Format, chChunkIDs)
-
 	go func() {
-		err := m.doRestoreSnapshot(snapshot, chChunks)
+		var err error
+		if snapshot.Format == types.FormatStoreChunks {
+			err = m.doRestoreStores(snapshot, segments, chChunkIDs)
+		} else {
+			err = m.doRestoreSnapshot(snapshot, m.loadChunkStream(snapshot.Height, snapshot.Format, chChunkIDs))
+		}
 		chDone <- restoreDone{
 			complete: err == nil,
 			err:      err,
@@ -374,17 +396,148 @@ func (m *Manager) doRestoreSnapshot(snapshot types.Snapshot, chChunks <-chan io.
 		return errorsmod.Wrapf(err, "failed to create snapshot directory %q", dir)
 	}
 
-	var nextItem types.SnapshotItem
 	streamReader, err := NewStreamReader(chChunks)
 	if err != nil {
 		return err
 	}
 	defer streamReader.Close()
 
+	return m.restoreStream(snapshot, streamReader)
+}
+
+// restoreStream restores the commitment state and the extensions from a single stream.
+func (m *Manager) restoreStream(snapshot types.Snapshot, protoReader protoio.Reader) error {
+	nextItem, err := m.commitSnapshotter.Restore(snapshot.Height, snapshot.Format, protoReader)
+	if err != nil {
+		return errorsmod.Wrap(err, "multistore restore")
+	}
+
+	return m.restoreExtensions(snapshot.Height, nextItem, protoReader)
+}
+
+// doRestoreStores do the heavy work of the restoration of a snapshot in the FormatStoreChunks
+// format, the chunk IDs are received in order once the chunks are saved in the store.
+//
+// If the commitment snapshotter implements StoreRestorer, a store is restored as soon as its
+// chunks are received, concurrently with the other stores, and it's recorded as restored so
+// that it isn't restored again if the restore is interrupted and started again. Otherwise,
+// the snapshot is restored as a single stream once every chunk is received. The extensions
+// are restored last.
+func (m *Manager) doRestoreStores(snapshot types.Snapshot, segments []segment, chunkIDs <-chan uint32) error {
+	dir := m.store.pathSnapshot(snapshot.Height, snapshot.Format)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return errorsmod.Wrapf(err, "failed to create snapshot directory %q", dir)
+	}
+
+	restorer, ok := m.commitSnapshotter.(StoreRestorer)
+	if !ok {
+		received := uint32(0)
+		for range chunkIDs {
+			received++
+		}
+		if received != snapshot.Chunks {
+			return errorsmod.Wrap(storeerrors.ErrLogic, "restore ended prematurely")
+		}
+
+		reader := newSegmentsReader(m.store, snapshot, segments)
+		defer reader.Close()
+		return m.restoreStream(snapshot, reader)
+	}
+
+	restored, err := m.store.loadRestoredStores(snapshot.Height, snapshot.Format)
+	if err != nil {
+		return err
+	}
+	restoredHashes := make(map[string][]byte, len(restored))
+	for _, store := range restored {
+		restoredHashes[store.Name] = store.Hash
+	}
+
+	var (
+		mtx         sync.Mutex
+		received    uint32
+		next        int
+		extSegments []segment
+	)
+	eg, ctx := errgroup.WithContext(context.Background())
+	eg.SetLimit(storev2.MaxWriteParallelism)
+loop:
+	for chunkID := range chunkIDs {
+		if chunkID != received {
+			return errors.Join(
+				errorsmod.Wrapf(storeerrors.ErrLogic, "received chunk %d, expected chunk %d", chunkID, received),
+				eg.Wait(),
+			)
+		}
+		received++
+
+		// start the restoration of the segments whose chunks are all received
+		for ; next < len(segments) && segments[next].end <= received; next++ {
+			seg := segments[next]
+			if seg.store == nil {
+				extSegments = append(extSegments, seg)
+				continue
+			}
+			if hash, ok := restoredHashes[seg.store.Name]; ok && bytes.Equal(hash, seg.store.Hash) {
+				m.logger.Info("store already restored", "height", snapshot.Height, "store", seg.store.Name)
+				continue
+			}
+			if ctx.Err() != nil {
+				break loop
+			}
+			eg.Go(func() error {
+				if err := m.restoreStore(restorer, snapshot, seg); err != nil {
+					return errorsmod.Wrapf(err, "store %s restore", seg.store.Name)
+				}
+
+				mtx.Lock()
+				defer mtx.Unlock()
+				restored = append(restored, seg.store)
+				return m.store.saveRestoredStores(snapshot.Height, snapshot.Format, restored)
+			})
+		}
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	if received != snapshot.Chunks {
+		return errorsmod.Wrap(storeerrors.ErrLogic, "restore ended prematurely")
+	}
+
+	if err := restorer.FinishRestore(snapshot.Height); err != nil {
+		return errorsmod.Wrap(err, "multistore restore")
+	}
+
+	reader := newSegmentsReader(m.store, snapshot, extSegments)
+	defer reader.Close()
+	var nextItem types.SnapshotItem
+	if err := reader.ReadMsg(&nextItem); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if err := m.restoreExtensions(snapshot.Height, nextItem, reader); err != nil {
+		return err
+	}
+
+	return m.store.deleteRestoredStores(snapshot.Height, snapshot.Format)
+}
+
+// restoreStore restores a store from its segment.
+func (m *Manager) restoreStore(restorer StoreRestorer, snapshot types.Snapshot, seg segment) error {
+	streamReader, err := m.store.loadSegment(snapshot.Height, snapshot.Format, seg)
+	if err != nil {
+		return err
+	}
+	defer streamReader.Close()
+
+	return restorer.RestoreStore(snapshot.Height, seg.store.Name, streamReader)
+}
+
+// restoreExtensions restores the extensions from the stream, starting with the given item.
+func (m *Manager) restoreExtensions(height uint64, nextItem types.SnapshotItem, protoReader protoio.Reader) error {
 	// payloadReader reads an extension payload for extension snapshotter, it returns `io.EOF` at extension boundaries.
 	payloadReader := func() ([]byte, error) {
 		nextItem.Reset()
-		if err := streamReader.ReadMsg(&nextItem); err != nil {
+		if err := protoReader.ReadMsg(&nextItem); err != nil {
 			return nil, err
 		}
 		payload := nextItem.GetExtensionPayload()
@@ -394,11 +547,6 @@ func (m *Manager) doRestoreSnapshot(snapshot types.Snapshot, chChunks <-chan io.
 		return payload.Payload, nil
 	}
 
-	nextItem, err = m.commitSnapshotter.Restore(snapshot.Height, snapshot.Format, streamReader)
-	if err != nil {
-		return errorsmod.Wrap(err, "multistore restore")
-	}
-
 	for {
 		if nextItem.Item == nil {
 			// end of stream
@@ -416,7 +564,7 @@ func (m *Manager) doRestoreSnapshot(snapshot types.Snapshot, chChunks <-chan io.
 			return errorsmod.Wrapf(types.ErrUnknownFormat, "format %v for extension %s", metadata.Format, metadata.Name)
 		}
 
-		if err := extension.RestoreExtension(snapshot.Height, metadata.Format, payloadReader); err != nil {
+		if err := extension.RestoreExtension(height, metadata.Format, payloadReader); err != nil {
 			return errorsmod.Wrapf(err, "extension %s restore", metadata.Name)
 		}
 
@@ -497,7 +645,7 @@ func (m *Manager) RestoreChunk(chunk []byte) (bool, error) {
 
 // RestoreLocalSnapshot restores app state from a local snapshot.
 func (m *Manager) RestoreLocalSnapshot(height uint64, format uint32) error {
-	snapshot, ch, err := m.store.Load(height, format)
+	snapshot, err := m.store.Get(height, format)
 	if err != nil {
 		return err
 	}
@@ -515,6 +663,23 @@ func (m *Manager) RestoreLocalSnapshot(height uint64, format uint32) error {
 	}
 	defer m.endLocked()
 
+	if snapshot.Format == types.FormatStoreChunks {
+		segments, err := snapshotSegments(snapshot)
+		if err != nil {
+			return err
+		}
+		chunkIDs := make(chan uint32, snapshot.Chunks)
+		for i := uint32(0); i < snapshot.Chunks; i++ {
+			chunkIDs <- i
+		}
+		close(chunkIDs)
+		return m.doRestoreStores(*snapshot, segments, chunkIDs)
+	}
+
+	_, ch, err := m.store.Load(height, format)
+	if err != nil {
+		return err
+	}
 	return m.doRestoreSnapshot(*snapshot, ch)
 }
 
@@ -2,6 +2,8 @@ package snapshots_test
 
 import (
 	"errors"
+	"io"
+	"maps"
 	"testing"
 	"time"
 
@@ -245,6 +247,118 @@ func TestManager_Restore(t *testing.T) {
 	}
 }
 
+func TestManager_RestoreStores(t *testing.T) {
+	stores := map[string][][]byte{
+		"a": {{1, 2, 3}, {4, 5, 6}},
+		"b": {{7, 8, 9}},
+		"c": {{10, 11, 12}, {13, 14, 15}, {16, 17, 18}},
+	}
+	source := snapshots.NewManager(setupStore(t), opts, &mockStoreSnapshotter{stores: stores}, nil, coretesting.NewNopLogger())
+	require.NoError(t, source.RegisterExtensions(newExtSnapshotter(10)))
+
+	// every store is written to its own chunks, followed by the extension chunks
+	snapshot, err := source.Create(5)
+	require.NoError(t, err)
+	require.Equal(t, types.FormatStoreChunks, snapshot.Format)
+	require.Equal(t, uint32(4), snapshot.Chunks)
+	require.Len(t, snapshot.Metadata.Stores, 3)
+	for i, store := range snapshot.Metadata.Stores {
+		require.Equal(t, []string{"a", "b", "c"}[i], store.Name)
+		require.Equal(t, uint32(i), store.FirstChunk)
+		require.Equal(t, uint32(1), store.Chunks)
+		require.Equal(t, hash(snapshot.Metadata.ChunkHashes[i:i+1]), store.Hash)
+	}
+
+	chunks := make([][]byte, snapshot.Chunks)
+	for i := range chunks {
+		chunks[i], err = source.LoadChunk(snapshot.Height, snapshot.Format, uint32(i))
+		require.NoError(t, err)
+	}
+
+	// a manifest which doesn't match the chunk hashes is rejected
+	invalid := *snapshot
+	invalid.Metadata.Stores = []*types.SnapshotStoreManifest{{Name: "a", FirstChunk: 0, Chunks: 1, Hash: []byte{1}}}
+	store := setupStore(t)
+	target := &mockStoreSnapshotter{failStore: "b"}
+	manager := snapshots.NewManager(store, opts, target, nil, coretesting.NewNopLogger())
+	require.ErrorIs(t, manager.Restore(invalid), types.ErrChunkHashMismatch)
+
+	// the restore fails on the store b, the store a is still restored
+	require.NoError(t, manager.RegisterExtensions(newExtSnapshotter(0)))
+	require.NoError(t, manager.Restore(*snapshot))
+	for _, chunk := range chunks {
+		if _, err = manager.RestoreChunk(chunk); err != nil {
+			break
+		}
+	}
+	require.ErrorContains(t, err, "mock restore store error")
+	require.Equal(t, stores["a"], target.stores["a"])
+	require.False(t, target.finished)
+
+	// the restore is resumed without restoring the store a again
+	resumed := &mockStoreSnapshotter{}
+	extSnapshotter := newExtSnapshotter(0)
+	manager = snapshots.NewManager(store, opts, resumed, nil, coretesting.NewNopLogger())
+	require.NoError(t, manager.RegisterExtensions(extSnapshotter))
+	require.NoError(t, manager.Restore(*snapshot))
+	for i, chunk := range chunks {
+		done, err := manager.RestoreChunk(chunk)
+		require.NoError(t, err)
+		require.Equal(t, i == len(chunks)-1, done)
+	}
+	require.True(t, resumed.finished)
+	require.NotContains(t, resumed.stores, "a")
+	require.Equal(t, stores["b"], resumed.stores["b"])
+	restored := maps.Clone(target.stores)
+	maps.Copy(restored, resumed.stores)
+	require.Equal(t, stores, restored)
+	require.Len(t, extSnapshotter.state, 10)
+
+	// the restored snapshot can be restored locally
+	local := &mockStoreSnapshotter{}
+	manager = snapshots.NewManager(store, opts, local, nil, coretesting.NewNopLogger())
+	require.NoError(t, manager.RegisterExtensions(newExtSnapshotter(0)))
+	require.NoError(t, manager.RestoreLocalSnapshot(snapshot.Height, snapshot.Format))
+	require.Equal(t, stores, local.stores)
+	require.True(t, local.finished)
+}
+
+func TestManager_RestoreFormatStream(t *testing.T) {
+	stores := map[string][][]byte{
+		"a": {{1, 2, 3}, {4, 5, 6}},
+		"b": {{7, 8, 9}},
+	}
+
+	// write a snapshot in the previous format, in which the items are written to a single stream
+	ch := make(chan io.ReadCloser)
+	go func() {
+		streamWriter := snapshots.NewStreamWriter(ch)
+		defer streamWriter.Close()
+		source := &mockStoreSnapshotter{stores: stores}
+		if err := source.Snapshot(5, streamWriter); err != nil {
+			streamWriter.CloseWithError(err)
+		}
+	}()
+	chunks := readChunks(ch)
+
+	target := &mockStoreSnapshotter{}
+	manager := snapshots.NewManager(setupStore(t), opts, target, nil, coretesting.NewNopLogger())
+	require.NoError(t, manager.Restore(types.Snapshot{
+		Height:   5,
+		Format:   types.FormatStream,
+		Chunks:   uint32(len(chunks)),
+		Hash:     hash(chunks),
+		Metadata: types.Metadata{ChunkHashes: checksums(chunks)},
+	}))
+	for i, chunk := range chunks {
+		done, err := manager.RestoreChunk(chunk)
+		require.NoError(t, err)
+		require.Equal(t, i == len(chunks)-1, done)
+	}
+	require.Equal(t, stores, target.stores)
+	require.True(t, target.finished)
+}
+
 func TestManager_TakeError(t *testing.T) {
 	snapshotter := &mockErrorCommitSnapshotter{}
 	store, err := snapshots.NewStore(t.TempDir())
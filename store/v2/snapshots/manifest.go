@@ -0,0 +1,276 @@
+package snapshots
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"github.com/cosmos/gogoproto/proto"
+
+	errorsmod "cosmossdk.io/errors/v2"
+	"cosmossdk.io/store/v2/snapshots/types"
+)
+
+// segment is a range of chunks of a snapshot in the FormatStoreChunks format,
+// containing an independent stream of snapshot items. The segment of a store
+// contains the items of the store, the other segments contain the items of the
+// extensions.
+type segment struct {
+	first uint32
+	end   uint32 // exclusive
+	store *types.SnapshotStoreManifest
+}
+
+// storeHash returns the hash of the chunk hashes of a store.
+func storeHash(chunkHashes [][]byte) []byte {
+	hasher := sha256.New()
+	for _, chunkHash := range chunkHashes {
+		hasher.Write(chunkHash)
+	}
+	return hasher.Sum(nil)
+}
+
+// snapshotSegments validates the store manifests of a snapshot in the
+// FormatStoreChunks format and splits its chunks into segments.
+func snapshotSegments(snapshot *types.Snapshot) ([]segment, error) {
+	if uint32(len(snapshot.Metadata.ChunkHashes)) != snapshot.Chunks {
+		return nil, errorsmod.Wrapf(types.ErrInvalidMetadata, "snapshot has %v chunk hashes, but %v chunks",
+			len(snapshot.Metadata.ChunkHashes), snapshot.Chunks)
+	}
+
+	var (
+		segments []segment
+		next     uint32
+		names    = make(map[string]bool, len(snapshot.Metadata.Stores))
+	)
+	for _, store := range snapshot.Metadata.Stores {
+		if store.Name == "" {
+			return nil, errorsmod.Wrap(types.ErrInvalidMetadata, "store manifest without name")
+		}
+		if names[store.Name] {
+			return nil, errorsmod.Wrapf(types.ErrInvalidMetadata, "duplicated store manifest %s", store.Name)
+		}
+		names[store.Name] = true
+
+		if store.Chunks == 0 {
+			return nil, errorsmod.Wrapf(types.ErrInvalidMetadata, "store %s has no chunks", store.Name)
+		}
+		if store.FirstChunk < next {
+			return nil, errorsmod.Wrapf(types.ErrInvalidMetadata, "chunks of store %s overlap the previous chunks", store.Name)
+		}
+		end := uint64(store.FirstChunk) + uint64(store.Chunks)
+		if end > uint64(snapshot.Chunks) {
+			return nil, errorsmod.Wrapf(types.ErrInvalidMetadata, "chunks of store %s exceed the %v chunks of the snapshot",
+				store.Name, snapshot.Chunks)
+		}
+		hash := storeHash(snapshot.Metadata.ChunkHashes[store.FirstChunk:end])
+		if !bytes.Equal(hash, store.Hash) {
+			return nil, errorsmod.Wrapf(types.ErrChunkHashMismatch, "store %s: expected %x, got %x", store.Name, store.Hash, hash)
+		}
+
+		if store.FirstChunk > next {
+			segments = append(segments, segment{first: next, end: store.FirstChunk})
+		}
+		segments = append(segments, segment{first: store.FirstChunk, end: uint32(end), store: store})
+		next = uint32(end)
+	}
+	if next < snapshot.Chunks {
+		segments = append(segments, segment{first: next, end: snapshot.Chunks})
+	}
+	return segments, nil
+}
+
+// storeChunksWriter writes the snapshot items in the FormatStoreChunks format:
+// the items of every store are written to their own stream, so they are split
+// into their own chunks, while the other items are written to the streams in
+// between.
+type storeChunksWriter struct {
+	ch     chan<- io.ReadCloser
+	chunks uint32 // number of chunks of the closed streams
+	stores []*types.SnapshotStoreManifest
+	closed bool
+
+	stream       *StreamWriter
+	store        *types.SnapshotStoreManifest // store of the current stream, if any
+	streamChunks <-chan uint32                // number of chunks of the current stream, once closed
+}
+
+var _ WriteCloser = (*storeChunksWriter)(nil)
+
+// newStoreChunksWriter creates a new storeChunksWriter.
+func newStoreChunksWriter(ch chan<- io.ReadCloser) *storeChunksWriter {
+	return &storeChunksWriter{ch: ch}
+}
+
+// openStream opens a new stream for the items of the given store, or for the
+// other items if the store is nil.
+func (w *storeChunksWriter) openStream(store *types.SnapshotStoreManifest) error {
+	chunks := make(chan io.ReadCloser)
+	streamChunks := make(chan uint32, 1)
+	go func() {
+		count := uint32(0)
+		for chunk := range chunks {
+			w.ch <- chunk
+			count++
+		}
+		streamChunks <- count
+	}()
+
+	stream := NewStreamWriter(chunks)
+	if stream == nil {
+		// the error is already passed to the reader
+		<-streamChunks
+		return errors.New("failed to create snapshot stream")
+	}
+	w.stream = stream
+	w.store = store
+	w.streamChunks = streamChunks
+	return nil
+}
+
+// closeStream closes the current stream, if any.
+func (w *storeChunksWriter) closeStream() error {
+	if w.stream == nil {
+		return nil
+	}
+	err := w.stream.Close()
+	count := <-w.streamChunks
+	store := w.store
+	w.stream, w.store, w.streamChunks = nil, nil, nil
+	if err != nil {
+		return err
+	}
+
+	if store != nil {
+		store.Chunks = count
+		w.stores = append(w.stores, store)
+	}
+	w.chunks += count
+	return nil
+}
+
+// WriteMsg implements protoio.Writer interface. A store item starts the stream
+// of a new store.
+func (w *storeChunksWriter) WriteMsg(msg proto.Message) error {
+	if item, ok := msg.(*types.SnapshotItem); ok && item.GetStore() != nil {
+		if err := w.closeStream(); err != nil {
+			return err
+		}
+		store := &types.SnapshotStoreManifest{
+			Name:       item.GetStore().Name,
+			FirstChunk: w.chunks,
+		}
+		if err := w.openStream(store); err != nil {
+			return err
+		}
+	} else if w.stream == nil {
+		if err := w.openStream(nil); err != nil {
+			return err
+		}
+	}
+	return w.stream.WriteMsg(msg)
+}
+
+// endStores closes the stream of the last store, so that the following items
+// are written to their own chunks.
+func (w *storeChunksWriter) endStores() error {
+	if w.store != nil {
+		return w.closeStream()
+	}
+	return nil
+}
+
+// Close implements io.Closer interface
+func (w *storeChunksWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	if w.stream == nil && w.chunks == 0 {
+		// a snapshot contains at least one chunk
+		if err := w.openStream(nil); err != nil {
+			return err
+		}
+	}
+	if err := w.closeStream(); err != nil {
+		return err
+	}
+	w.closed = true
+	close(w.ch)
+	return nil
+}
+
+// CloseWithError closes the writer and sends an error to the reader.
+func (w *storeChunksWriter) CloseWithError(err error) {
+	if w.closed {
+		return
+	}
+	w.closed = true
+	if w.stream != nil {
+		w.stream.CloseWithError(err)
+		<-w.streamChunks
+	} else {
+		pr, pw := io.Pipe()
+		w.ch <- pr
+		_ = pw.CloseWithError(err) // CloseWithError always returns nil
+	}
+	close(w.ch)
+}
+
+// segmentsReader reads the snapshot items of consecutive segments, which are
+// independent streams, as a single stream.
+type segmentsReader struct {
+	store    *Store
+	height   uint64
+	format   uint32
+	segments []segment
+	stream   *StreamReader
+}
+
+// newSegmentsReader creates a reader of the given segments of a snapshot saved
+// in the store.
+func newSegmentsReader(store *Store, snapshot types.Snapshot, segments []segment) *segmentsReader {
+	return &segmentsReader{
+		store:    store,
+		height:   snapshot.Height,
+		format:   snapshot.Format,
+		segments: segments,
+	}
+}
+
+// ReadMsg implements protoio.Reader interface
+func (r *segmentsReader) ReadMsg(msg proto.Message) error {
+	for {
+		if r.stream == nil {
+			if len(r.segments) == 0 {
+				return io.EOF
+			}
+			stream, err := r.store.loadSegment(r.height, r.format, r.segments[0])
+			if err != nil {
+				return err
+			}
+			r.stream = stream
+			r.segments = r.segments[1:]
+		}
+
+		err := r.stream.ReadMsg(msg)
+		if !errors.Is(err, io.EOF) {
+			return err
+		}
+		err = r.stream.Close()
+		r.stream = nil
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Close implements io.Closer interface
+func (r *segmentsReader) Close() error {
+	if r.stream == nil {
+		return nil
+	}
+	err := r.stream.Close()
+	r.stream = nil
+	return err
+}
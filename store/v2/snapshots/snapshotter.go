@@ -16,6 +16,18 @@ type CommitSnapshotter interface {
 	Restore(version uint64, format uint32, protoReader protoio.Reader) (types.SnapshotItem, error)
 }
 
+// StoreRestorer is implemented by the CommitSnapshotter which can restore the stores of
+// a snapshot independently, it allows the manager to restore the stores of a snapshot in
+// the FormatStoreChunks format concurrently.
+type StoreRestorer interface {
+	// RestoreStore restores a single store from the snapshot reader, which returns io.EOF
+	// at the end of the store items. It may be called concurrently for different stores.
+	RestoreStore(version uint64, storeKey string, protoReader protoio.Reader) error
+
+	// FinishRestore is called once every store is restored, to load the restored version.
+	FinishRestore(version uint64) error
+}
+
 // ExtensionPayloadReader read extension payloads,
 // it returns io.EOF when reached either end of stream or the extension boundaries.
 type ExtensionPayloadReader = func() ([]byte, error)
@@ -161,10 +161,16 @@ func (s *Store) Load(height uint64, format uint32) (*types.Snapshot, <-chan io.R
 		return nil, nil, err
 	}
 
+	return snapshot, s.loadChunks(height, format, 0, snapshot.Chunks), nil
+}
+
+// loadChunks loads the chunks of a snapshot in the range [first, end). The chunks must be
+// consumed and closed.
+func (s *Store) loadChunks(height uint64, format, first, end uint32) <-chan io.ReadCloser {
 	ch := make(chan io.ReadCloser)
 	go func() {
 		defer close(ch)
-		for i := uint32(0); i < snapshot.Chunks; i++ {
+		for i := first; i < end; i++ {
 			pr, pw := io.Pipe()
 			ch <- pr
 			chunk, err := s.loadChunkFile(height, format, i)
@@ -184,7 +190,18 @@ func (s *Store) Load(height uint64, format uint32) (*types.Snapshot, <-chan io.R
 		}
 	}()
 
-	return snapshot, ch, nil
+	return ch
+}
+
+// loadSegment loads a segment of a snapshot as a stream. The stream must be closed.
+func (s *Store) loadSegment(height uint64, format uint32, seg segment) (*StreamReader, error) {
+	chunks := s.loadChunks(height, format, seg.first, seg.end)
+	stream, err := NewStreamReader(chunks)
+	if err != nil {
+		DrainChunks(chunks)
+		return nil, err
+	}
+	return stream, nil
 }
 
 // LoadChunk loads a chunk from disk, or returns nil if it does not exist. The caller must call
@@ -247,6 +264,22 @@ func (s *Store) Prune(retain uint32) (uint64, error) {
 // Save saves a snapshot to disk, returning it.
 func (s *Store) Save(
 	height uint64, format uint32, chunks <-chan io.ReadCloser,
+) (*types.Snapshot, error) {
+	return s.save(height, format, chunks, nil)
+}
+
+// SaveStores saves a snapshot in the FormatStoreChunks format to disk, returning it.
+// The hashes of the given store manifests are computed from the chunks.
+func (s *Store) SaveStores(
+	height uint64, format uint32, stores []*types.SnapshotStoreManifest, chunks <-chan io.ReadCloser,
+) (*types.Snapshot, error) {
+	return s.save(height, format, chunks, func() []*types.SnapshotStoreManifest { return stores })
+}
+
+// save saves a snapshot to disk, returning it. If given, stores is called once every chunk
+// is saved to get the store manifests of the snapshot.
+func (s *Store) save(
+	height uint64, format uint32, chunks <-chan io.ReadCloser, stores func() []*types.SnapshotStoreManifest,
 ) (*types.Snapshot, error) {
 	defer DrainChunks(chunks)
 	if height == 0 {
@@ -292,6 +325,21 @@ func (s *Store) Save(
 	}
 	snapshot.Chunks = index
 	snapshot.Hash = snapshotHasher.Sum(nil)
+	if stores != nil {
+		for _, store := range stores() {
+			end := uint64(store.FirstChunk) + uint64(store.Chunks)
+			if end > uint64(snapshot.Chunks) {
+				return nil, errors.Wrapf(types.ErrInvalidMetadata, "chunks of store %s exceed the %v chunks of the snapshot",
+					store.Name, snapshot.Chunks)
+			}
+			snapshot.Metadata.Stores = append(snapshot.Metadata.Stores, &types.SnapshotStoreManifest{
+				Name:       store.Name,
+				FirstChunk: store.FirstChunk,
+				Chunks:     store.Chunks,
+				Hash:       storeHash(snapshot.Metadata.ChunkHashes[store.FirstChunk:end]),
+			})
+		}
+	}
 	return snapshot, s.saveSnapshot(snapshot)
 }
 
@@ -345,6 +393,47 @@ func (s *Store) saveSnapshot(snapshot *types.Snapshot) error {
 	return nil
 }
 
+// loadRestoredStores loads the manifests of the stores restored from a snapshot by an
+// interrupted restore.
+func (s *Store) loadRestoredStores(height uint64, format uint32) ([]*types.SnapshotStoreManifest, error) {
+	bz, err := os.ReadFile(s.pathRestored(height, format))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to read restored stores of snapshot for height %v format %v", height, format)
+	}
+	metadata := &types.Metadata{}
+	if err := proto.Unmarshal(bz, metadata); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode restored stores of snapshot for height %v format %v", height, format)
+	}
+	return metadata.Stores, nil
+}
+
+// saveRestoredStores records the manifests of the stores restored from a snapshot, so
+// that they are not restored again if the restore is interrupted.
+func (s *Store) saveRestoredStores(height uint64, format uint32, stores []*types.SnapshotStoreManifest) error {
+	bz, err := proto.Marshal(&types.Metadata{Stores: stores})
+	if err != nil {
+		return errors.Wrap(err, "failed to encode restored stores")
+	}
+	path := s.pathRestored(height, format)
+	if err := os.WriteFile(path+".tmp", bz, 0o600); err != nil {
+		return errors.Wrap(err, "failed to write restored stores")
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return errors.Wrap(err, "failed to write restored stores")
+	}
+	return nil
+}
+
+// deleteRestoredStores deletes the record of the stores restored from a snapshot.
+func (s *Store) deleteRestoredStores(height uint64, format uint32) error {
+	if err := os.Remove(s.pathRestored(height, format)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to delete restored stores")
+	}
+	return nil
+}
+
 // pathHeight generates the path to a height, containing multiple snapshot formats.
 func (s *Store) pathHeight(height uint64) string {
 	return filepath.Join(s.dir, strconv.FormatUint(height, 10))
@@ -355,6 +444,11 @@ func (s *Store) pathSnapshot(height uint64, format uint32) string {
 	return filepath.Join(s.pathHeight(height), strconv.FormatUint(uint64(format), 10))
 }
 
+// pathRestored generates the path to the record of the stores restored from a snapshot.
+func (s *Store) pathRestored(height uint64, format uint32) string {
+	return filepath.Join(s.pathSnapshot(height, format), "restored")
+}
+
 func (s *Store) pathMetadataDir() string {
 	return filepath.Join(s.dir, "metadata")
 }
@@ -1,6 +1,22 @@
 package types
 
+const (
+	// FormatStream is the snapshot format in which the items of every store and extension
+	// are written sequentially to a single stream.
+	FormatStream uint32 = 3
+
+	// FormatStoreChunks is the snapshot format in which the items of every store are written
+	// to their own stream and chunks, which are listed by the store manifests of the metadata.
+	// The items of the extensions are written to the remaining chunks.
+	FormatStoreChunks uint32 = 4
+)
+
 // CurrentFormat is the currently used format for snapshots. Snapshots using the same format
 // must be identical across all nodes for a given height, so this must be bumped when the binary
 // snapshot output changes.
-const CurrentFormat uint32 = 3
+const CurrentFormat = FormatStoreChunks
+
+// IsFormatSupported returns whether a snapshot in the given format can be restored.
+func IsFormatSupported(format uint32) bool {
+	return format == FormatStream || format == FormatStoreChunks
+}
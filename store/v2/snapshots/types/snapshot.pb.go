@@ -104,6 +104,9 @@ func (m *Snapshot) GetMetadata() Metadata {
 // Metadata contains SDK-specific snapshot metadata.
 type Metadata struct {
 	ChunkHashes [][]byte `protobuf:"bytes,1,rep,name=chunk_hashes,json=chunkHashes,proto3" json:"chunk_hashes,omitempty"`
+	// stores lists the chunks of every store, it's only set by the snapshots using
+	// the format 4, in which the items of every store are written to their own chunks.
+	Stores []*SnapshotStoreManifest `protobuf:"bytes,2,rep,name=stores,proto3" json:"stores,omitempty"`
 }
 
 func (m *Metadata) Reset()         { *m = Metadata{} }
@@ -146,6 +149,82 @@ func (m *Metadata) GetChunkHashes() [][]byte {
 	return nil
 }
 
+func (m *Metadata) GetStores() []*SnapshotStoreManifest {
+	if m != nil {
+		return m.Stores
+	}
+	return nil
+}
+
+// SnapshotStoreManifest describes the chunks containing the items of a store.
+type SnapshotStoreManifest struct {
+	Name       string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	FirstChunk uint32 `protobuf:"varint,2,opt,name=first_chunk,json=firstChunk,proto3" json:"first_chunk,omitempty"`
+	Chunks     uint32 `protobuf:"varint,3,opt,name=chunks,proto3" json:"chunks,omitempty"`
+	Hash       []byte `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (m *SnapshotStoreManifest) Reset()         { *m = SnapshotStoreManifest{} }
+func (m *SnapshotStoreManifest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotStoreManifest) ProtoMessage()    {}
+func (*SnapshotStoreManifest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6851f1463fcbb80c, []int{2}
+}
+func (m *SnapshotStoreManifest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SnapshotStoreManifest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SnapshotStoreManifest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SnapshotStoreManifest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotStoreManifest.Merge(m, src)
+}
+func (m *SnapshotStoreManifest) XXX_Size() int {
+	return m.Size()
+}
+func (m *SnapshotStoreManifest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotStoreManifest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotStoreManifest proto.InternalMessageInfo
+
+func (m *SnapshotStoreManifest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SnapshotStoreManifest) GetFirstChunk() uint32 {
+	if m != nil {
+		return m.FirstChunk
+	}
+	return 0
+}
+
+func (m *SnapshotStoreManifest) GetChunks() uint32 {
+	if m != nil {
+		return m.Chunks
+	}
+	return 0
+}
+
+func (m *SnapshotStoreManifest) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
 // SnapshotItem is an item contained in a rootmulti.Store snapshot.
 type SnapshotItem struct {
 	// item is the specific type of snapshot item.
@@ -163,7 +242,7 @@ func (m *SnapshotItem) Reset()         { *m = SnapshotItem{} }
 func (m *SnapshotItem) String() string { return proto.CompactTextString(m) }
 func (*SnapshotItem) ProtoMessage()    {}
 func (*SnapshotItem) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6851f1463fcbb80c, []int{2}
+	return fileDescriptor_6851f1463fcbb80c, []int{3}
 }
 func (m *SnapshotItem) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -270,7 +349,7 @@ func (m *SnapshotStoreItem) Reset()         { *m = SnapshotStoreItem{} }
 func (m *SnapshotStoreItem) String() string { return proto.CompactTextString(m) }
 func (*SnapshotStoreItem) ProtoMessage()    {}
 func (*SnapshotStoreItem) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6851f1463fcbb80c, []int{3}
+	return fileDescriptor_6851f1463fcbb80c, []int{4}
 }
 func (m *SnapshotStoreItem) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -320,7 +399,7 @@ func (m *SnapshotIAVLItem) Reset()         { *m = SnapshotIAVLItem{} }
 func (m *SnapshotIAVLItem) String() string { return proto.CompactTextString(m) }
 func (*SnapshotIAVLItem) ProtoMessage()    {}
 func (*SnapshotIAVLItem) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6851f1463fcbb80c, []int{4}
+	return fileDescriptor_6851f1463fcbb80c, []int{5}
 }
 func (m *SnapshotIAVLItem) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -387,7 +466,7 @@ func (m *SnapshotExtensionMeta) Reset()         { *m = SnapshotExtensionMeta{} }
 func (m *SnapshotExtensionMeta) String() string { return proto.CompactTextString(m) }
 func (*SnapshotExtensionMeta) ProtoMessage()    {}
 func (*SnapshotExtensionMeta) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6851f1463fcbb80c, []int{5}
+	return fileDescriptor_6851f1463fcbb80c, []int{6}
 }
 func (m *SnapshotExtensionMeta) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -439,7 +518,7 @@ func (m *SnapshotExtensionPayload) Reset()         { *m = SnapshotExtensionPaylo
 func (m *SnapshotExtensionPayload) String() string { return proto.CompactTextString(m) }
 func (*SnapshotExtensionPayload) ProtoMessage()    {}
 func (*SnapshotExtensionPayload) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6851f1463fcbb80c, []int{6}
+	return fileDescriptor_6851f1463fcbb80c, []int{7}
 }
 func (m *SnapshotExtensionPayload) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -478,6 +557,7 @@ func (m *SnapshotExtensionPayload) GetPayload() []byte {
 func init() {
 	proto.RegisterType((*Snapshot)(nil), "cosmos.store.snapshots.v2.Snapshot")
 	proto.RegisterType((*Metadata)(nil), "cosmos.store.snapshots.v2.Metadata")
+	proto.RegisterType((*SnapshotStoreManifest)(nil), "cosmos.store.snapshots.v2.SnapshotStoreManifest")
 	proto.RegisterType((*SnapshotItem)(nil), "cosmos.store.snapshots.v2.SnapshotItem")
 	proto.RegisterType((*SnapshotStoreItem)(nil), "cosmos.store.snapshots.v2.SnapshotStoreItem")
 	proto.RegisterType((*SnapshotIAVLItem)(nil), "cosmos.store.snapshots.v2.SnapshotIAVLItem")
@@ -490,41 +570,44 @@ func init() {
 }
 
 var fileDescriptor_6851f1463fcbb80c = []byte{
-	// 541 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x41, 0x6b, 0x13, 0x41,
-	0x14, 0xde, 0x69, 0x36, 0x31, 0x7d, 0xbb, 0x62, 0x3a, 0x56, 0x59, 0x7b, 0xd8, 0xae, 0x11, 0x61,
-	0x41, 0xb3, 0x29, 0x5b, 0xf1, 0x20, 0x82, 0x18, 0x2c, 0x24, 0xa8, 0x50, 0xa6, 0x20, 0xe2, 0x25,
-	0x4c, 0x9b, 0x31, 0x1b, 0x92, 0xcd, 0x84, 0xcc, 0x34, 0x98, 0xa3, 0xff, 0xc0, 0x3f, 0xe2, 0xcd,
-	0x1f, 0xd1, 0x63, 0xf1, 0xe4, 0xa9, 0x48, 0xf2, 0x17, 0xfc, 0x01, 0x32, 0x33, 0xbb, 0xb1, 0xd4,
-	0x8d, 0xd4, 0xdb, 0xfb, 0xde, 0xbc, 0xef, 0x9b, 0x79, 0xdf, 0xcc, 0x3c, 0x08, 0x4f, 0xb8, 0x48,
-	0xb9, 0x68, 0x0a, 0xc9, 0xa7, 0xac, 0x29, 0xc6, 0x74, 0x22, 0x12, 0x2e, 0x45, 0x73, 0x16, 0xaf,
-	0x40, 0x34, 0x99, 0x72, 0xc9, 0xf1, 0x3d, 0x53, 0x19, 0xe9, 0xca, 0x68, 0x55, 0x19, 0xcd, 0xe2,
-	0x9d, 0xed, 0x3e, 0xef, 0x73, 0x5d, 0xd5, 0x54, 0x91, 0x21, 0xec, 0x64, 0x84, 0xae, 0x59, 0xc8,
-	0xd8, 0x1a, 0xd4, 0xbf, 0x22, 0xa8, 0x1e, 0x65, 0x0a, 0xf8, 0x2e, 0x54, 0x12, 0x36, 0xe8, 0x27,
-	0xd2, 0x43, 0x01, 0x0a, 0x6d, 0x92, 0x21, 0x95, 0xff, 0xc8, 0xa7, 0x29, 0x95, 0xde, 0x46, 0x80,
-	0xc2, 0x9b, 0x24, 0x43, 0x2a, 0x7f, 0x92, 0x9c, 0x8e, 0x87, 0xc2, 0x2b, 0x99, 0xbc, 0x41, 0x18,
-	0x83, 0x9d, 0x50, 0x91, 0x78, 0x76, 0x80, 0x42, 0x97, 0xe8, 0x18, 0x1f, 0x40, 0x35, 0x65, 0x92,
-	0xf6, 0xa8, 0xa4, 0x5e, 0x39, 0x40, 0xa1, 0x13, 0x3f, 0x88, 0xd6, 0xf6, 0x11, 0xbd, 0xcd, 0x4a,
-	0x5b, 0xf6, 0xd9, 0xc5, 0xae, 0x45, 0x56, 0xd4, 0x7a, 0x03, 0xaa, 0xf9, 0x1a, 0xbe, 0x0f, 0xae,
-	0xde, 0xb0, 0xab, 0x36, 0x60, 0xc2, 0x43, 0x41, 0x29, 0x74, 0x89, 0xa3, 0x73, 0x6d, 0x9d, 0xaa,
-	0xff, 0xda, 0x00, 0x37, 0x6f, 0xaf, 0x23, 0x59, 0x8a, 0x5f, 0x41, 0x59, 0x6f, 0xa7, 0x3b, 0x74,
-	0xe2, 0xc7, 0xff, 0x38, 0x43, 0xce, 0x3b, 0x52, 0x4b, 0x8a, 0xdc, 0xb6, 0x88, 0x21, 0xe3, 0xd7,
-	0x60, 0x0f, 0xe8, 0x6c, 0xa4, 0xed, 0x70, 0xe2, 0x47, 0xd7, 0x10, 0xe9, 0xbc, 0x7c, 0xf7, 0x46,
-	0x69, 0xb4, 0xaa, 0x8b, 0x8b, 0x5d, 0x5b, 0xa1, 0xb6, 0x45, 0xb4, 0x08, 0x3e, 0x84, 0x4d, 0xf6,
-	0x49, 0xb2, 0xb1, 0x18, 0xf0, 0xb1, 0x36, 0xd2, 0x89, 0xf7, 0xae, 0xa1, 0x78, 0x90, 0x73, 0x94,
-	0x1f, 0x6d, 0x8b, 0xfc, 0x11, 0xc1, 0xc7, 0xb0, 0xb5, 0x02, 0xdd, 0x09, 0x9d, 0x8f, 0x38, 0xed,
-	0xe9, 0xcb, 0x70, 0xe2, 0xfd, 0xff, 0x51, 0x3e, 0x34, 0xd4, 0xb6, 0x45, 0x6a, 0xec, 0x4a, 0xee,
-	0xd9, 0xed, 0xef, 0xdf, 0x1a, 0xb7, 0x8c, 0x56, 0x43, 0xf4, 0x86, 0xc1, 0x5e, 0xf4, 0xe4, 0x69,
-	0xab, 0x02, 0xf6, 0x40, 0xb2, 0xb4, 0xfe, 0x1c, 0xb6, 0xfe, 0x72, 0x4f, 0xbd, 0x8a, 0x31, 0x4d,
-	0x8d, 0xf3, 0x9b, 0x44, 0xc7, 0x85, 0x2a, 0xf5, 0xcf, 0x08, 0x6a, 0x57, 0x7d, 0xc3, 0x35, 0x28,
-	0x0d, 0xd9, 0x5c, 0x93, 0x5d, 0xa2, 0x42, 0xbc, 0x0d, 0xe5, 0x19, 0x1d, 0x9d, 0x32, 0x7d, 0x0b,
-	0x2e, 0x31, 0x00, 0x7b, 0x70, 0x63, 0xc6, 0xa6, 0x2b, 0x2f, 0x4b, 0x24, 0x87, 0x97, 0x5e, 0xb7,
-	0xb2, 0xa2, 0x9c, 0xbf, 0xee, 0xe2, 0x33, 0xbc, 0x87, 0x3b, 0x85, 0x46, 0x17, 0x75, 0xb1, 0xee,
-	0x7f, 0x14, 0x2b, 0x77, 0xc0, 0x5b, 0x67, 0xb4, 0x3a, 0x7c, 0x7e, 0x5d, 0xa6, 0xd1, 0x1c, 0x16,
-	0xdb, 0xfd, 0xe2, 0x6c, 0xe1, 0xa3, 0xf3, 0x85, 0x8f, 0x7e, 0x2e, 0x7c, 0xf4, 0x65, 0xe9, 0x5b,
-	0xe7, 0x4b, 0xdf, 0xfa, 0xb1, 0xf4, 0xad, 0x0f, 0x0f, 0x4d, 0xa9, 0xe8, 0x0d, 0xa3, 0x01, 0xcf,
-	0x46, 0xca, 0xa5, 0x41, 0x22, 0x9a, 0x72, 0x3e, 0x61, 0xe2, 0xb8, 0xa2, 0x87, 0xc0, 0xfe, 0xef,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0xb3, 0x23, 0x48, 0xd5, 0x7c, 0x04, 0x00, 0x00,
+	// 589 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xc1, 0x6e, 0xd3, 0x40,
+	0x10, 0xb5, 0x1b, 0x27, 0xa4, 0x63, 0x23, 0xda, 0xa5, 0x45, 0xa6, 0x07, 0xd7, 0x18, 0x21, 0x59,
+	0x82, 0x3a, 0x95, 0x8b, 0x38, 0x20, 0x24, 0x44, 0xa1, 0x92, 0x2b, 0xa8, 0x54, 0x6d, 0x25, 0x84,
+	0xb8, 0x44, 0xdb, 0x66, 0x5b, 0x5b, 0x89, 0xbd, 0x51, 0x76, 0x1b, 0x9a, 0x23, 0x7f, 0xc0, 0x8f,
+	0x70, 0xe3, 0x23, 0x7a, 0xac, 0x38, 0x71, 0xaa, 0x50, 0xf2, 0x0b, 0x7c, 0x00, 0xda, 0xb5, 0x1d,
+	0xaa, 0xd6, 0x41, 0xe9, 0x6d, 0xdf, 0x78, 0xde, 0x9b, 0x99, 0x37, 0xde, 0x05, 0xff, 0x88, 0xf1,
+	0x94, 0xf1, 0x16, 0x17, 0x6c, 0x40, 0x5b, 0x3c, 0x23, 0x7d, 0x1e, 0x33, 0xc1, 0x5b, 0xc3, 0x70,
+	0x0a, 0x82, 0xfe, 0x80, 0x09, 0x86, 0x1e, 0xe6, 0x99, 0x81, 0xca, 0x0c, 0xa6, 0x99, 0xc1, 0x30,
+	0x5c, 0x5b, 0x39, 0x61, 0x27, 0x4c, 0x65, 0xb5, 0xe4, 0x29, 0x27, 0xac, 0x15, 0x84, 0x76, 0xfe,
+	0xa1, 0x60, 0x2b, 0xe0, 0x7d, 0xd7, 0xa1, 0x79, 0x50, 0x28, 0xa0, 0x07, 0xd0, 0x88, 0x69, 0x72,
+	0x12, 0x0b, 0x5b, 0x77, 0x75, 0xdf, 0xc0, 0x05, 0x92, 0xf1, 0x63, 0x36, 0x48, 0x89, 0xb0, 0x17,
+	0x5c, 0xdd, 0xbf, 0x8b, 0x0b, 0x24, 0xe3, 0x47, 0xf1, 0x69, 0xd6, 0xe5, 0x76, 0x2d, 0x8f, 0xe7,
+	0x08, 0x21, 0x30, 0x62, 0xc2, 0x63, 0xdb, 0x70, 0x75, 0xdf, 0xc2, 0xea, 0x8c, 0x76, 0xa0, 0x99,
+	0x52, 0x41, 0x3a, 0x44, 0x10, 0xbb, 0xee, 0xea, 0xbe, 0x19, 0x3e, 0x0e, 0x66, 0xce, 0x11, 0xec,
+	0x15, 0xa9, 0xdb, 0xc6, 0xf9, 0xe5, 0xba, 0x86, 0xa7, 0x54, 0xef, 0x0b, 0x34, 0xcb, 0x6f, 0xe8,
+	0x11, 0x58, 0xaa, 0x60, 0x5b, 0x16, 0xa0, 0xdc, 0xd6, 0xdd, 0x9a, 0x6f, 0x61, 0x53, 0xc5, 0x22,
+	0x15, 0x42, 0x11, 0x34, 0x94, 0x3a, 0xb7, 0x17, 0xdc, 0x9a, 0x6f, 0x86, 0x9b, 0xff, 0xa9, 0x59,
+	0xda, 0x70, 0x20, 0x3f, 0xed, 0x91, 0x2c, 0x39, 0xa6, 0x5c, 0xe0, 0x82, 0xef, 0x9d, 0xc1, 0x6a,
+	0x65, 0x82, 0x1c, 0x36, 0x23, 0x29, 0x55, 0x96, 0x2d, 0x62, 0x75, 0x46, 0xeb, 0x60, 0x1e, 0x27,
+	0x03, 0x2e, 0xda, 0xaa, 0x97, 0xc2, 0x35, 0x50, 0xa1, 0xb7, 0x32, 0x72, 0x1b, 0xe7, 0xbc, 0x3f,
+	0x0b, 0x60, 0x95, 0xa5, 0x77, 0x05, 0x4d, 0xd1, 0x3b, 0xa8, 0xab, 0xa6, 0x54, 0x49, 0x33, 0x7c,
+	0x36, 0xef, 0x4c, 0x92, 0x1c, 0x69, 0x38, 0x27, 0xa3, 0xf7, 0x60, 0x24, 0x64, 0xd8, 0x53, 0xcd,
+	0x99, 0xe1, 0xd3, 0x39, 0x44, 0x76, 0xdf, 0x7c, 0xfc, 0x20, 0x35, 0xb6, 0x9b, 0xe3, 0xcb, 0x75,
+	0x43, 0xa2, 0x48, 0xc3, 0x4a, 0x04, 0xed, 0xc3, 0x22, 0x3d, 0x13, 0x34, 0xe3, 0x09, 0xcb, 0xd4,
+	0x48, 0xf3, 0x59, 0xbd, 0x53, 0x72, 0xe4, 0x4e, 0x23, 0x0d, 0xff, 0x13, 0x41, 0x87, 0xb0, 0x3c,
+	0x05, 0xed, 0x3e, 0x19, 0xf5, 0x18, 0xe9, 0x28, 0x5b, 0xcc, 0x70, 0xeb, 0x36, 0xca, 0xfb, 0x39,
+	0x35, 0xd2, 0xf0, 0x12, 0xbd, 0x16, 0x7b, 0x79, 0xff, 0xe7, 0x8f, 0x8d, 0x7b, 0xb9, 0xd6, 0x06,
+	0xef, 0x74, 0xdd, 0xcd, 0xe0, 0xf9, 0x8b, 0xed, 0x06, 0x18, 0x89, 0xa0, 0xa9, 0xf7, 0x0a, 0x96,
+	0x6f, 0xb8, 0x57, 0xb5, 0xec, 0x4a, 0x15, 0xef, 0xab, 0x0e, 0x4b, 0xd7, 0x7d, 0x43, 0x4b, 0x50,
+	0xeb, 0xd2, 0x91, 0x22, 0x5b, 0x58, 0x1e, 0xd1, 0x0a, 0xd4, 0x87, 0xa4, 0x77, 0x4a, 0xd5, 0x16,
+	0x2c, 0x9c, 0x03, 0x64, 0xc3, 0x9d, 0x21, 0x1d, 0x4c, 0xbd, 0xac, 0xe1, 0x12, 0x5e, 0xb9, 0xa1,
+	0xd2, 0x8a, 0x7a, 0x79, 0x43, 0xab, 0x7b, 0xf8, 0x04, 0xab, 0x37, 0xec, 0x90, 0x46, 0x57, 0xfe,
+	0xb2, 0x33, 0xee, 0x78, 0xb5, 0xf2, 0x2e, 0xd8, 0xb3, 0x8c, 0x96, 0xcd, 0x97, 0xeb, 0xca, 0x07,
+	0x2d, 0x61, 0xb5, 0xdd, 0xaf, 0xcf, 0xc7, 0x8e, 0x7e, 0x31, 0x76, 0xf4, 0xdf, 0x63, 0x47, 0xff,
+	0x36, 0x71, 0xb4, 0x8b, 0x89, 0xa3, 0xfd, 0x9a, 0x38, 0xda, 0xe7, 0x27, 0x79, 0x2a, 0xef, 0x74,
+	0x83, 0x84, 0x15, 0xcf, 0xe2, 0x95, 0xc7, 0x90, 0xb7, 0xc4, 0xa8, 0x4f, 0xf9, 0x61, 0x43, 0x3d,
+	0x64, 0x5b, 0x7f, 0x07, 0x00, 0xe9, 0x07, 0x62, 0x8d, 0x40, 0x05, 0x00, 0x00,
 }
 
 func (m *Snapshot) Marshal() (dAtA []byte, err error) {
@@ -602,6 +685,20 @@ func (m *Metadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Stores) > 0 {
+		for iNdEx := len(m.Stores) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Stores[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSnapshot(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
 	if len(m.ChunkHashes) > 0 {
 		for iNdEx := len(m.ChunkHashes) - 1; iNdEx >= 0; iNdEx-- {
 			i -= len(m.ChunkHashes[iNdEx])
@@ -614,6 +711,53 @@ func (m *Metadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *SnapshotStoreManifest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SnapshotStoreManifest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SnapshotStoreManifest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Hash) > 0 {
+		i -= len(m.Hash)
+		copy(dAtA[i:], m.Hash)
+		i = encodeVarintSnapshot(dAtA, i, uint64(len(m.Hash)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Chunks != 0 {
+		i = encodeVarintSnapshot(dAtA, i, uint64(m.Chunks))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.FirstChunk != 0 {
+		i = encodeVarintSnapshot(dAtA, i, uint64(m.FirstChunk))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintSnapshot(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *SnapshotItem) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -919,6 +1063,35 @@ func (m *Metadata) Size() (n int) {
 			n += 1 + l + sovSnapshot(uint64(l))
 		}
 	}
+	if len(m.Stores) > 0 {
+		for _, e := range m.Stores {
+			l = e.Size()
+			n += 1 + l + sovSnapshot(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SnapshotStoreManifest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovSnapshot(uint64(l))
+	}
+	if m.FirstChunk != 0 {
+		n += 1 + sovSnapshot(uint64(m.FirstChunk))
+	}
+	if m.Chunks != 0 {
+		n += 1 + sovSnapshot(uint64(m.Chunks))
+	}
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovSnapshot(uint64(l))
+	}
 	return n
 }
 
@@ -1288,6 +1461,194 @@ func (m *Metadata) Unmarshal(dAtA []byte) error {
 			m.ChunkHashes = append(m.ChunkHashes, make([]byte, postIndex-iNdEx))
 			copy(m.ChunkHashes[len(m.ChunkHashes)-1], dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stores", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSnapshot
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSnapshot
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSnapshot
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Stores = append(m.Stores, &SnapshotStoreManifest{})
+			if err := m.Stores[len(m.Stores)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSnapshot(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthSnapshot
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SnapshotStoreManifest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSnapshot
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SnapshotStoreManifest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SnapshotStoreManifest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSnapshot
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSnapshot
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSnapshot
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FirstChunk", wireType)
+			}
+			m.FirstChunk = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSnapshot
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FirstChunk |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Chunks", wireType)
+			}
+			m.Chunks = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSnapshot
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Chunks |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSnapshot
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthSnapshot
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSnapshot
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = append(m.Hash[:0], dAtA[iNdEx:postIndex]...)
+			if m.Hash == nil {
+				m.Hash = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipSnapshot(dAtA[iNdEx:])
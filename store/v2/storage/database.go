@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"io"
+
+	"cosmossdk.io/store/v2"
+)
+
+// Database is an interface that wraps the versioned database of the state
+// storage (SS) backend. A wrapper is useful for instances where you want to
+// perform logic that is identical for all SS backends, such as applying
+// changesets and pruning in the background.
+type Database interface {
+	store.VersionedReader
+
+	// NewBatch returns a batch which writes the state of the given version.
+	// Writing the batch doesn't change the latest version.
+	NewBatch(version uint64) (store.Batch, error)
+
+	// SetLatestVersion sets the latest version which can be read.
+	SetLatestVersion(version uint64) error
+
+	// GetEarliestVersion returns the earliest version which can be read. Versions
+	// before it were pruned or committed before the database was populated.
+	GetEarliestVersion() (uint64, error)
+
+	// SetEarliestVersion sets the earliest version which can be read.
+	SetEarliestVersion(version uint64) error
+
+	// Prune removes the state of all the versions up to and including the given
+	// version which isn't needed to read the state of later versions.
+	Prune(version uint64) error
+
+	// Rollback removes the state of all the versions after the given version and
+	// sets it as the latest version.
+	Rollback(version uint64) error
+
+	// GetImportProgress returns the version and the last store key and key written
+	// by an interrupted import, or a zero version if no import was interrupted.
+	GetImportProgress() (version uint64, storeKey, key []byte, err error)
+
+	// SetImportProgress records the last store key and key written by the import
+	// of the version. A zero version clears the import progress.
+	SetImportProgress(version uint64, storeKey, key []byte) error
+
+	io.Closer
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"bytes"
+
+	corestore "cosmossdk.io/core/store"
+)
+
+var _ corestore.Iterator = (*mvccIterator)(nil)
+
+// mvccIterator iterates over the keys of a store at a version. It wraps an
+// iterator over the entries of the underlying database and yields, for every key,
+// the value of its newest entry which isn't newer than the version, skipping the
+// keys which didn't exist or were removed at the version.
+type mvccIterator struct {
+	raw        corestore.Iterator
+	prefixLen  int
+	version    uint64
+	start, end []byte
+
+	key, value []byte
+	valid      bool
+	err        error
+}
+
+func newMVCCIterator(raw corestore.Iterator, prefixLen int, version uint64, start, end []byte) *mvccIterator {
+	itr := &mvccIterator{
+		raw:       raw,
+		prefixLen: prefixLen,
+		version:   version,
+		start:     start,
+		end:       end,
+	}
+	itr.advance()
+	return itr
+}
+
+// advance moves to the next key which exists at the version. The entries of a key
+// are contiguous but are visited from the newest to the oldest when iterating
+// forward and from the oldest to the newest when iterating in reverse, so all of
+// them are visited to find the newest one which isn't newer than the version.
+func (itr *mvccIterator) advance() {
+	itr.valid = false
+	for itr.raw.Valid() {
+		keyEntries, _, err := splitEntryKey(itr.raw.Key())
+		if err != nil {
+			itr.err = err
+			return
+		}
+		keyEntries = bytes.Clone(keyEntries)
+
+		var (
+			found      bool
+			newest     uint64
+			entryValue []byte
+		)
+		for ; itr.raw.Valid(); itr.raw.Next() {
+			entries, entryVersion, err := splitEntryKey(itr.raw.Key())
+			if err != nil {
+				itr.err = err
+				return
+			}
+			if !bytes.Equal(entries, keyEntries) {
+				break
+			}
+			if entryVersion <= itr.version && (!found || entryVersion > newest) {
+				found, newest, entryValue = true, entryVersion, bytes.Clone(itr.raw.Value())
+			}
+		}
+
+		if !found || len(entryValue) == 0 || entryValue[0] == valueTypeTombstone {
+			continue
+		}
+
+		key, err := unescapeKey(keyEntries[itr.prefixLen:])
+		if err != nil {
+			itr.err = err
+			return
+		}
+		itr.key, itr.value, itr.valid = key, entryValue[1:], true
+		return
+	}
+	itr.err = itr.raw.Error()
+}
+
+func (itr *mvccIterator) Domain() (start, end []byte) {
+	return itr.start, itr.end
+}
+
+func (itr *mvccIterator) Valid() bool {
+	return itr.valid && itr.err == nil
+}
+
+func (itr *mvccIterator) Next() {
+	if !itr.Valid() {
+		panic("iterator is invalid")
+	}
+	itr.advance()
+}
+
+func (itr *mvccIterator) Key() []byte {
+	if !itr.Valid() {
+		panic("iterator is invalid")
+	}
+	return bytes.Clone(itr.key)
+}
+
+func (itr *mvccIterator) Value() []byte {
+	if !itr.Valid() {
+		panic("iterator is invalid")
+	}
+	return bytes.Clone(itr.value)
+}
+
+func (itr *mvccIterator) Error() error {
+	return itr.err
+}
+
+func (itr *mvccIterator) Close() error {
+	itr.valid = false
+	return itr.raw.Close()
+}
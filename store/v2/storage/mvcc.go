@@ -0,0 +1,555 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	corestore "cosmossdk.io/core/store"
+	"cosmossdk.io/store/v2"
+	storeerrors "cosmossdk.io/store/v2/errors"
+)
+
+const (
+	// dataPrefix is the prefix of the versioned entries of the store keys.
+	dataPrefix byte = 'd'
+	// changePrefix is the prefix of the change index which records the keys
+	// written at every version which wasn't pruned yet.
+	changePrefix byte = 'c'
+
+	latestVersionKey   = "m/latest"
+	earliestVersionKey = "m/earliest"
+	importProgressKey  = "m/import"
+
+	// valueTypeSet and valueTypeTombstone are the first byte of the value of an
+	// entry, they distinguish entries which set a value from entries which remove it.
+	valueTypeSet       byte = 0
+	valueTypeTombstone byte = 1
+
+	// pruneBatchSize is the number of entries removed in a batch while pruning or
+	// rolling back, so that the iteration over the database isn't held open for the
+	// whole operation.
+	pruneBatchSize = 10_000
+)
+
+var _ Database = (*MVCCDatabase)(nil)
+
+// MVCCDatabase implements the Database interface on top of any flat key-value
+// database, such as goleveldb or pebbledb. Every version of every key is stored
+// as a separate entry whose key is composed of the store key, the key and the
+// inverted version:
+//
+//	d | uvarint(len(storeKey)) | storeKey | escaped(key) | 0x00 0x01 | ^version
+//
+// Keys are escaped so that the entries of a key are contiguous and ordered
+// before the entries of any bigger key, and the version is inverted so that the
+// entries of a key are ordered from the newest version to the oldest. Reading a
+// key at a version is therefore a single seek, regardless of the number of
+// versions of the key. Removals are stored as tombstone entries.
+//
+// Every entry is also recorded in a change index keyed by its version:
+//
+//	c | version | d | uvarint(len(storeKey)) | storeKey | escaped(key)
+//
+// Only the keys which were written since the last pruning can have entries which
+// became prunable, so pruning and rolling back only visit the keys recorded in the
+// change index for the pruned or removed versions instead of the whole database.
+type MVCCDatabase struct {
+	kv corestore.KVStoreWithBatch
+}
+
+// NewMVCCDatabase returns a new MVCCDatabase which stores its entries in the
+// given key-value database. The database should be dedicated to the state storage.
+func NewMVCCDatabase(kv corestore.KVStoreWithBatch) *MVCCDatabase {
+	return &MVCCDatabase{kv: kv}
+}
+
+// Close closes the underlying key-value database.
+func (db *MVCCDatabase) Close() error {
+	return db.kv.Close()
+}
+
+func (db *MVCCDatabase) NewBatch(version uint64) (store.Batch, error) {
+	return &mvccBatch{
+		kv:      db.kv,
+		batch:   db.kv.NewBatch(),
+		version: version,
+	}, nil
+}
+
+func (db *MVCCDatabase) GetLatestVersion() (uint64, error) {
+	return db.getVersion(latestVersionKey)
+}
+
+func (db *MVCCDatabase) SetLatestVersion(version uint64) error {
+	return db.kv.Set([]byte(latestVersionKey), binary.BigEndian.AppendUint64(nil, version))
+}
+
+func (db *MVCCDatabase) GetEarliestVersion() (uint64, error) {
+	return db.getVersion(earliestVersionKey)
+}
+
+func (db *MVCCDatabase) SetEarliestVersion(version uint64) error {
+	return db.kv.Set([]byte(earliestVersionKey), binary.BigEndian.AppendUint64(nil, version))
+}
+
+func (db *MVCCDatabase) getVersion(key string) (uint64, error) {
+	bz, err := db.kv.Get([]byte(key))
+	if err != nil {
+		return 0, err
+	}
+	if bz == nil {
+		return 0, nil
+	}
+	if len(bz) != 8 {
+		return 0, fmt.Errorf("invalid %s value length: %d", key, len(bz))
+	}
+	return binary.BigEndian.Uint64(bz), nil
+}
+
+// VersionExists returns true if the state of the version can be read, i.e. if
+// the version is between the earliest and the latest version.
+func (db *MVCCDatabase) VersionExists(version uint64) (bool, error) {
+	latestVersion, err := db.GetLatestVersion()
+	if err != nil {
+		return false, err
+	}
+	if latestVersion == 0 {
+		return false, nil
+	}
+
+	earliestVersion, err := db.GetEarliestVersion()
+	if err != nil {
+		return false, err
+	}
+	return version >= earliestVersion && version <= latestVersion, nil
+}
+
+// checkVersion returns an ErrVersionPruned error if the version was pruned.
+func (db *MVCCDatabase) checkVersion(version uint64) error {
+	earliestVersion, err := db.GetEarliestVersion()
+	if err != nil {
+		return err
+	}
+	if version < earliestVersion {
+		return storeerrors.ErrVersionPruned{EarliestVersion: earliestVersion, RequestedVersion: version}
+	}
+	return nil
+}
+
+func (db *MVCCDatabase) Has(storeKey []byte, version uint64, key []byte) (bool, error) {
+	val, err := db.Get(storeKey, version, key)
+	if err != nil {
+		return false, err
+	}
+
+	return val != nil, nil
+}
+
+func (db *MVCCDatabase) Get(storeKey []byte, version uint64, key []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, storeerrors.ErrKeyEmpty
+	}
+	if err := db.checkVersion(version); err != nil {
+		return nil, err
+	}
+
+	// the first entry of the key which isn't newer than the version holds its value
+	itr, err := db.kv.Iterator(mvccKey(storeKey, key, version), keyEntriesEnd(storeKey, key))
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Close()
+
+	if !itr.Valid() {
+		return nil, itr.Error()
+	}
+
+	value := itr.Value()
+	if len(value) == 0 {
+		return nil, fmt.Errorf("invalid empty entry for key %X of store %s", key, storeKey)
+	}
+	if value[0] == valueTypeTombstone {
+		return nil, nil
+	}
+	return bytes.Clone(value[1:]), nil
+}
+
+func (db *MVCCDatabase) Iterator(storeKey []byte, version uint64, start, end []byte) (corestore.Iterator, error) {
+	return db.newIterator(storeKey, version, start, end, false)
+}
+
+func (db *MVCCDatabase) ReverseIterator(storeKey []byte, version uint64, start, end []byte) (corestore.Iterator, error) {
+	return db.newIterator(storeKey, version, start, end, true)
+}
+
+func (db *MVCCDatabase) newIterator(storeKey []byte, version uint64, start, end []byte, reverse bool) (corestore.Iterator, error) {
+	if (start != nil && len(start) == 0) || (end != nil && len(end) == 0) {
+		return nil, storeerrors.ErrKeyEmpty
+	}
+	if start != nil && end != nil && bytes.Compare(start, end) > 0 {
+		return nil, storeerrors.ErrStartAfterEnd
+	}
+	if err := db.checkVersion(version); err != nil {
+		return nil, err
+	}
+
+	prefix := storePrefix(storeKey)
+	lower, upper := prefix, prefixEnd(prefix)
+	if start != nil {
+		lower = appendEscapedKey(bytes.Clone(prefix), start)
+	}
+	if end != nil {
+		upper = appendEscapedKey(bytes.Clone(prefix), end)
+	}
+
+	var (
+		raw corestore.Iterator
+		err error
+	)
+	if reverse {
+		raw, err = db.kv.ReverseIterator(lower, upper)
+	} else {
+		raw, err = db.kv.Iterator(lower, upper)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newMVCCIterator(raw, len(prefix), version, start, end), nil
+}
+
+// Prune removes the entries of every key which were overwritten by a newer entry
+// which isn't newer than the version, as well as tombstones which aren't newer
+// than the version. The newest entry of every key up to the version is kept so
+// that the state of the following versions can still be read. The earliest version
+// is set before any entry is removed, so reads of pruned versions fail rather than
+// returning partially pruned state.
+//
+// Only the keys recorded in the change index for the versions up to the version
+// are visited, and their index entries are removed once they are pruned, so an
+// interrupted pruning resumes where it stopped the next time the store is pruned.
+func (db *MVCCDatabase) Prune(version uint64) error {
+	earliestVersion, err := db.GetEarliestVersion()
+	if err != nil {
+		return err
+	}
+	if version < earliestVersion {
+		return nil
+	}
+	if err := db.SetEarliestVersion(version + 1); err != nil {
+		return err
+	}
+
+	for {
+		done, err := db.pruneChanges(version)
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// pruneChanges prunes the entries of the keys recorded in up to pruneBatchSize
+// change index entries of the versions up to the given version, and removes these
+// index entries. It returns true once no index entries are left to prune.
+func (db *MVCCDatabase) pruneChanges(version uint64) (bool, error) {
+	indexKeys, err := db.changeIndexKeys([]byte{changePrefix}, prefixEnd(changeVersionPrefix(version)))
+	if err != nil || len(indexKeys) == 0 {
+		return true, err
+	}
+
+	var (
+		deleted = indexKeys
+		visited = make(map[string]struct{}, len(indexKeys))
+	)
+	for _, indexKey := range indexKeys {
+		keyEntries := indexKey[1+8:]
+		if _, ok := visited[string(keyEntries)]; ok {
+			continue
+		}
+		visited[string(keyEntries)] = struct{}{}
+
+		prunable, err := db.prunableEntries(keyEntries, version)
+		if err != nil {
+			return false, err
+		}
+		deleted = append(deleted, prunable...)
+	}
+
+	return false, db.deleteEntries(deleted)
+}
+
+// prunableEntries returns the entries of the key with the given entries prefix
+// which aren't needed to read the state after the version: all the entries up to
+// the version except the newest one, which is only kept if it isn't a tombstone.
+func (db *MVCCDatabase) prunableEntries(keyEntries []byte, version uint64) ([][]byte, error) {
+	end := bytes.Clone(keyEntries)
+	end[len(end)-1]++
+	itr, err := db.kv.Iterator(binary.BigEndian.AppendUint64(bytes.Clone(keyEntries), ^version), end)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted [][]byte
+	for kept := false; itr.Valid(); itr.Next() {
+		if !kept {
+			kept = true
+			if value := itr.Value(); len(value) > 0 && value[0] != valueTypeTombstone {
+				continue
+			}
+		}
+		deleted = append(deleted, bytes.Clone(itr.Key()))
+	}
+
+	return deleted, errors.Join(itr.Error(), itr.Close())
+}
+
+// Rollback removes all the entries which are newer than the version and sets the
+// version as the latest version. The entries are found through the change index.
+func (db *MVCCDatabase) Rollback(version uint64) error {
+	start := prefixEnd(changeVersionPrefix(version))
+	for {
+		indexKeys, err := db.changeIndexKeys(start, []byte{changePrefix + 1})
+		if err != nil {
+			return err
+		}
+		if len(indexKeys) == 0 {
+			break
+		}
+
+		deleted := indexKeys
+		for _, indexKey := range indexKeys {
+			entryVersion := binary.BigEndian.Uint64(indexKey[1 : 1+8])
+			deleted = append(deleted, binary.BigEndian.AppendUint64(bytes.Clone(indexKey[1+8:]), ^entryVersion))
+		}
+		if err := db.deleteEntries(deleted); err != nil {
+			return err
+		}
+	}
+
+	return db.SetLatestVersion(version)
+}
+
+// changeIndexKeys returns up to pruneBatchSize change index keys in the range.
+func (db *MVCCDatabase) changeIndexKeys(start, end []byte) ([][]byte, error) {
+	itr, err := db.kv.Iterator(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexKeys [][]byte
+	for ; itr.Valid() && len(indexKeys) < pruneBatchSize; itr.Next() {
+		indexKey := itr.Key()
+		if len(indexKey) < 1+8+2 {
+			_ = itr.Close()
+			return nil, fmt.Errorf("invalid change index key %X", indexKey)
+		}
+		indexKeys = append(indexKeys, bytes.Clone(indexKey))
+	}
+
+	return indexKeys, errors.Join(itr.Error(), itr.Close())
+}
+
+// GetImportProgress returns the version and the last store key and key written by
+// an interrupted import, or a zero version if no import was interrupted.
+func (db *MVCCDatabase) GetImportProgress() (uint64, []byte, []byte, error) {
+	bz, err := db.kv.Get([]byte(importProgressKey))
+	if err != nil || bz == nil {
+		return 0, nil, nil, err
+	}
+
+	if len(bz) < 8 {
+		return 0, nil, nil, fmt.Errorf("invalid %s value %X", importProgressKey, bz)
+	}
+	storeKeyLen, n := binary.Uvarint(bz[8:])
+	if n <= 0 || uint64(len(bz)-8-n) < storeKeyLen {
+		return 0, nil, nil, fmt.Errorf("invalid %s value %X", importProgressKey, bz)
+	}
+
+	storeKey := bz[8+n : 8+n+int(storeKeyLen)]
+	return binary.BigEndian.Uint64(bz), bytes.Clone(storeKey), bytes.Clone(bz[8+n+len(storeKey):]), nil
+}
+
+// SetImportProgress records the last store key and key written by the import of
+// the version. A zero version clears the import progress.
+func (db *MVCCDatabase) SetImportProgress(version uint64, storeKey, key []byte) error {
+	if version == 0 {
+		return db.kv.Delete([]byte(importProgressKey))
+	}
+
+	bz := binary.BigEndian.AppendUint64(nil, version)
+	bz = binary.AppendUvarint(bz, uint64(len(storeKey)))
+	bz = append(bz, storeKey...)
+	return db.kv.Set([]byte(importProgressKey), append(bz, key...))
+}
+
+func (db *MVCCDatabase) deleteEntries(entryKeys [][]byte) error {
+	if len(entryKeys) == 0 {
+		return nil
+	}
+
+	batch := db.kv.NewBatch()
+	defer batch.Close()
+
+	for _, entryKey := range entryKeys {
+		if err := batch.Delete(entryKey); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// mvccBatch is a store.Batch which writes the entries of a version.
+type mvccBatch struct {
+	kv      corestore.KVStoreWithBatch
+	batch   corestore.Batch
+	version uint64
+}
+
+func (b *mvccBatch) Set(storeKey, key, value []byte) error {
+	if len(key) == 0 {
+		return storeerrors.ErrKeyEmpty
+	}
+	if value == nil {
+		return storeerrors.ErrValueNil
+	}
+
+	entry := make([]byte, 0, len(value)+1)
+	entry = append(entry, valueTypeSet)
+	entry = append(entry, value...)
+	if err := b.batch.Set(mvccKey(storeKey, key, b.version), entry); err != nil {
+		return err
+	}
+	return b.batch.Set(changeKey(storeKey, key, b.version), []byte{})
+}
+
+func (b *mvccBatch) Delete(storeKey, key []byte) error {
+	if len(key) == 0 {
+		return storeerrors.ErrKeyEmpty
+	}
+
+	if err := b.batch.Set(mvccKey(storeKey, key, b.version), []byte{valueTypeTombstone}); err != nil {
+		return err
+	}
+	return b.batch.Set(changeKey(storeKey, key, b.version), []byte{})
+}
+
+func (b *mvccBatch) Size() int {
+	size, err := b.batch.GetByteSize()
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+func (b *mvccBatch) Write() error {
+	defer b.batch.Close()
+	return b.batch.Write()
+}
+
+func (b *mvccBatch) Reset() error {
+	if err := b.batch.Close(); err != nil {
+		return err
+	}
+	b.batch = b.kv.NewBatch()
+	return nil
+}
+
+// storePrefix returns the prefix of the entries of the store key.
+func storePrefix(storeKey []byte) []byte {
+	prefix := make([]byte, 0, 1+binary.MaxVarintLen64+len(storeKey))
+	prefix = append(prefix, dataPrefix)
+	prefix = binary.AppendUvarint(prefix, uint64(len(storeKey)))
+	return append(prefix, storeKey...)
+}
+
+// appendEscapedKey appends the key followed by the 0x00 0x01 terminator, with
+// every 0x00 byte of the key escaped as 0x00 0xFF. The escaping preserves the
+// order of the keys and ensures no escaped key is a prefix of another one, so the
+// result is both the prefix of the entries of the key and a bound which is bigger
+// than all the entries of the smaller keys.
+func appendEscapedKey(dst, key []byte) []byte {
+	for _, b := range key {
+		if b == 0 {
+			dst = append(dst, 0, 0xff)
+		} else {
+			dst = append(dst, b)
+		}
+	}
+	return append(dst, 0, 1)
+}
+
+// unescapeKey decodes a key escaped by appendEscapedKey, including its terminator.
+func unescapeKey(bz []byte) ([]byte, error) {
+	key := make([]byte, 0, len(bz))
+	for i := 0; i < len(bz); i++ {
+		if bz[i] != 0 {
+			key = append(key, bz[i])
+			continue
+		}
+		if i+1 >= len(bz) {
+			return nil, fmt.Errorf("invalid escaped key %X", bz)
+		}
+		switch bz[i+1] {
+		case 0xff:
+			key = append(key, 0)
+			i++
+		case 1:
+			if i+2 != len(bz) {
+				return nil, fmt.Errorf("invalid escaped key %X", bz)
+			}
+			return key, nil
+		default:
+			return nil, fmt.Errorf("invalid escaped key %X", bz)
+		}
+	}
+	return nil, fmt.Errorf("invalid escaped key %X", bz)
+}
+
+// mvccKey returns the key of the entry of the key at the version.
+func mvccKey(storeKey, key []byte, version uint64) []byte {
+	entryKey := appendEscapedKey(storePrefix(storeKey), key)
+	return binary.BigEndian.AppendUint64(entryKey, ^version)
+}
+
+// changeVersionPrefix returns the prefix of the change index entries of the version.
+func changeVersionPrefix(version uint64) []byte {
+	return binary.BigEndian.AppendUint64([]byte{changePrefix}, version)
+}
+
+// changeKey returns the key of the change index entry of the key at the version.
+func changeKey(storeKey, key []byte, version uint64) []byte {
+	indexKey := append(changeVersionPrefix(version), storePrefix(storeKey)...)
+	return appendEscapedKey(indexKey, key)
+}
+
+// keyEntriesEnd returns the exclusive upper bound of the entries of the key.
+func keyEntriesEnd(storeKey, key []byte) []byte {
+	end := appendEscapedKey(storePrefix(storeKey), key)
+	end[len(end)-1]++
+	return end
+}
+
+// splitEntryKey splits the entry key in the prefix shared by all the entries of
+// its key and its version.
+func splitEntryKey(entryKey []byte) ([]byte, uint64, error) {
+	if len(entryKey) < 8+2 {
+		return nil, 0, fmt.Errorf("invalid entry key %X", entryKey)
+	}
+
+	n := len(entryKey) - 8
+	return entryKey[:n], ^binary.BigEndian.Uint64(entryKey[n:]), nil
+}
+
+// prefixEnd returns the exclusive upper bound of the keys with the prefix.
+func prefixEnd(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
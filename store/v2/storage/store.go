@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"sync"
+
+	corelog "cosmossdk.io/core/log"
+	corestore "cosmossdk.io/core/store"
+	"cosmossdk.io/store/v2"
+)
+
+var (
+	_ store.VersionedWriter = (*StorageStore)(nil)
+	_ store.Pruner          = (*StorageStore)(nil)
+)
+
+// StorageStore is the state storage (SS) of the root store. It wraps a versioned
+// Database and is the flat, versioned counterpart of the state commitment (SC):
+// it holds the same state as the SC but answers reads of any retained version
+// without traversing the commitment trees.
+//
+// Pruning runs in the background so that it doesn't delay commits, even though it
+// only visits the keys written since the last pruning. Reads of a version which is
+// being pruned fail as soon as the pruning starts.
+type StorageStore struct {
+	logger corelog.Logger
+	db     Database
+
+	// pruneMtx guards pruning, pruneTarget and prunedVersion
+	pruneMtx sync.Mutex
+	// pruning is true while a background pruning is running
+	pruning bool
+	// pruneTarget is the version to prune to once the running pruning is done
+	pruneTarget uint64
+	// prunedVersion is the version the store was last pruned to
+	prunedVersion uint64
+	// pruneDone is used by Close to wait for the background pruning
+	pruneDone sync.WaitGroup
+}
+
+// NewStorageStore returns a reference to a new StorageStore.
+func NewStorageStore(db Database, logger corelog.Logger) *StorageStore {
+	return &StorageStore{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// Has returns true if the key exists in the store.
+func (ss *StorageStore) Has(storeKey []byte, version uint64, key []byte) (bool, error) {
+	return ss.db.Has(storeKey, version, key)
+}
+
+// Get returns the value associated with the given key.
+func (ss *StorageStore) Get(storeKey []byte, version uint64, key []byte) ([]byte, error) {
+	return ss.db.Get(storeKey, version, key)
+}
+
+// ApplyChangeset writes the changeset to the database at the version of the
+// changeset. It doesn't change the latest version, which must be set with
+// SetLatestVersion once the whole state of the version is written.
+func (ss *StorageStore) ApplyChangeset(cs *corestore.Changeset) error {
+	b, err := ss.db.NewBatch(cs.Version)
+	if err != nil {
+		return err
+	}
+
+	for _, pairs := range cs.Changes {
+		for _, kvPair := range pairs.StateChanges {
+			if kvPair.Remove {
+				if err := b.Delete(pairs.Actor, kvPair.Key); err != nil {
+					return err
+				}
+			} else {
+				if err := b.Set(pairs.Actor, kvPair.Key, kvPair.Value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return b.Write()
+}
+
+// GetLatestVersion returns the latest version of the store.
+func (ss *StorageStore) GetLatestVersion() (uint64, error) {
+	return ss.db.GetLatestVersion()
+}
+
+// SetLatestVersion sets the latest version of the store. When the store is empty,
+// the version is also set as the earliest version, since the store doesn't hold
+// the state of the previous versions.
+func (ss *StorageStore) SetLatestVersion(version uint64) error {
+	latestVersion, err := ss.db.GetLatestVersion()
+	if err != nil {
+		return err
+	}
+	if latestVersion == 0 {
+		if err := ss.db.SetEarliestVersion(version); err != nil {
+			return err
+		}
+	}
+
+	return ss.db.SetLatestVersion(version)
+}
+
+// GetEarliestVersion returns the earliest version which can be read from the store.
+func (ss *StorageStore) GetEarliestVersion() (uint64, error) {
+	return ss.db.GetEarliestVersion()
+}
+
+// VersionExists returns true if the given version can be read from the store.
+func (ss *StorageStore) VersionExists(version uint64) (bool, error) {
+	return ss.db.VersionExists(version)
+}
+
+// Iterator returns an iterator over the specified domain and prefix.
+func (ss *StorageStore) Iterator(storeKey []byte, version uint64, start, end []byte) (corestore.Iterator, error) {
+	return ss.db.Iterator(storeKey, version, start, end)
+}
+
+// ReverseIterator returns an iterator over the specified domain and prefix in reverse.
+func (ss *StorageStore) ReverseIterator(storeKey []byte, version uint64, start, end []byte) (corestore.Iterator, error) {
+	return ss.db.ReverseIterator(storeKey, version, start, end)
+}
+
+// Rollback removes the state written after the given version and sets it as the
+// latest version.
+func (ss *StorageStore) Rollback(version uint64) error {
+	ss.pruneDone.Wait()
+	return ss.db.Rollback(version)
+}
+
+// GetImportProgress returns the version and the last store key and key written by
+// an interrupted import, or a zero version if no import was interrupted.
+func (ss *StorageStore) GetImportProgress() (uint64, []byte, []byte, error) {
+	return ss.db.GetImportProgress()
+}
+
+// SetImportProgress records the last store key and key written by the import of
+// the version. A zero version clears the import progress.
+func (ss *StorageStore) SetImportProgress(version uint64, storeKey, key []byte) error {
+	return ss.db.SetImportProgress(version, storeKey, key)
+}
+
+// Prune prunes the store up to and including the given version in the background.
+// If a pruning is already running, the store is pruned to the given version once
+// it is done.
+func (ss *StorageStore) Prune(version uint64) error {
+	ss.pruneMtx.Lock()
+	defer ss.pruneMtx.Unlock()
+
+	if version > ss.pruneTarget {
+		ss.pruneTarget = version
+	}
+	if ss.pruning || ss.pruneTarget == ss.prunedVersion {
+		return nil
+	}
+
+	ss.pruning = true
+	ss.pruneDone.Add(1)
+	go ss.prune()
+	return nil
+}
+
+func (ss *StorageStore) prune() {
+	defer ss.pruneDone.Done()
+
+	for {
+		ss.pruneMtx.Lock()
+		target := ss.pruneTarget
+		if target == ss.prunedVersion {
+			ss.pruning = false
+			ss.pruneMtx.Unlock()
+			return
+		}
+		ss.pruneMtx.Unlock()
+
+		if err := ss.db.Prune(target); err != nil {
+			ss.logger.Error("failed to prune state storage", "version", target, "err", err)
+		}
+
+		ss.pruneMtx.Lock()
+		ss.prunedVersion = target
+		ss.pruneMtx.Unlock()
+	}
+}
+
+// Close waits for the background pruning and closes the store.
+func (ss *StorageStore) Close() error {
+	ss.pruneDone.Wait()
+	return ss.db.Close()
+}
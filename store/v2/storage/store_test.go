@@ -0,0 +1,294 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	corestore "cosmossdk.io/core/store"
+	coretesting "cosmossdk.io/core/testing"
+	dbm "cosmossdk.io/store/v2/db"
+	storeerrors "cosmossdk.io/store/v2/errors"
+)
+
+const storeKey1 = "store1"
+
+var storeKey1Bytes = []byte(storeKey1)
+
+type StorageTestSuite struct {
+	suite.Suite
+
+	NewDB func(dir string) (corestore.KVStoreWithBatch, error)
+}
+
+func TestStorageTestSuite(t *testing.T) {
+	for _, dbType := range []dbm.DBType{dbm.DBTypeMemDB, dbm.DBTypeGoLevelDB, dbm.DBTypePebbleDB} {
+		t.Run(string(dbType), func(t *testing.T) {
+			suite.Run(t, &StorageTestSuite{
+				NewDB: func(dir string) (corestore.KVStoreWithBatch, error) {
+					return dbm.NewDB(dbType, "ss", dir, nil)
+				},
+			})
+		})
+	}
+}
+
+func (s *StorageTestSuite) newStore() *StorageStore {
+	kv, err := s.NewDB(s.T().TempDir())
+	s.Require().NoError(err)
+
+	ss := NewStorageStore(NewMVCCDatabase(kv), coretesting.NewNopLogger())
+	s.T().Cleanup(func() { s.Require().NoError(ss.Close()) })
+	return ss
+}
+
+// commit applies the key value pairs at the version, a nil value removes the key.
+func (s *StorageTestSuite) commit(ss *StorageStore, version uint64, pairs ...string) {
+	s.Require().Zero(len(pairs) % 2)
+
+	cs := corestore.NewChangeset(version)
+	for i := 0; i < len(pairs); i += 2 {
+		if pairs[i+1] == "" {
+			cs.Add(storeKey1Bytes, []byte(pairs[i]), nil, true)
+		} else {
+			cs.Add(storeKey1Bytes, []byte(pairs[i]), []byte(pairs[i+1]), false)
+		}
+	}
+	s.Require().NoError(ss.ApplyChangeset(cs))
+	s.Require().NoError(ss.SetLatestVersion(version))
+}
+
+func (s *StorageTestSuite) collect(itr corestore.Iterator, err error) []string {
+	s.Require().NoError(err)
+	defer itr.Close()
+
+	var pairs []string
+	for ; itr.Valid(); itr.Next() {
+		pairs = append(pairs, string(itr.Key())+"="+string(itr.Value()))
+	}
+	s.Require().NoError(itr.Error())
+	return pairs
+}
+
+func (s *StorageTestSuite) TestVersions() {
+	ss := s.newStore()
+
+	ok, err := ss.VersionExists(0)
+	s.Require().NoError(err)
+	s.Require().False(ok)
+
+	s.commit(ss, 3, "a", "1")
+	s.commit(ss, 4, "b", "2")
+
+	latest, err := ss.GetLatestVersion()
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(4), latest)
+
+	earliest, err := ss.GetEarliestVersion()
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(3), earliest)
+
+	for v, exists := range map[uint64]bool{2: false, 3: true, 4: true, 5: false} {
+		ok, err := ss.VersionExists(v)
+		s.Require().NoError(err)
+		s.Require().Equal(exists, ok, "version %d", v)
+	}
+}
+
+func (s *StorageTestSuite) TestGet() {
+	ss := s.newStore()
+
+	s.commit(ss, 1, "a", "1", "b", "1")
+	s.commit(ss, 2, "a", "2")
+	s.commit(ss, 3, "b", "")
+	s.commit(ss, 4, "b", "4")
+
+	for _, tc := range []struct {
+		version uint64
+		key     string
+		value   string
+	}{
+		{1, "a", "1"},
+		{2, "a", "2"},
+		{4, "a", "2"},
+		{1, "b", "1"},
+		{2, "b", "1"},
+		{3, "b", ""},
+		{4, "b", "4"},
+		{4, "c", ""},
+	} {
+		value, err := ss.Get(storeKey1Bytes, tc.version, []byte(tc.key))
+		s.Require().NoError(err)
+		s.Require().Equal(tc.value, string(value), "key %s at version %d", tc.key, tc.version)
+
+		has, err := ss.Has(storeKey1Bytes, tc.version, []byte(tc.key))
+		s.Require().NoError(err)
+		s.Require().Equal(tc.value != "", has)
+	}
+
+	// other store keys are isolated
+	value, err := ss.Get([]byte("store2"), 4, []byte("a"))
+	s.Require().NoError(err)
+	s.Require().Nil(value)
+
+	_, err = ss.Get(storeKey1Bytes, 4, nil)
+	s.Require().ErrorIs(err, storeerrors.ErrKeyEmpty)
+}
+
+func (s *StorageTestSuite) TestIterator() {
+	ss := s.newStore()
+
+	// keys which are prefixes of each other or contain zero bytes must keep their order
+	s.commit(ss, 1, "a", "1", "a\x00", "1", "a\x00b", "1", "ab", "1", "b", "1")
+	s.commit(ss, 2, "a\x00", "", "ab", "2", "c", "2")
+	s.commit(ss, 3, "a", "", "a\x00", "3")
+
+	s.Require().Equal(
+		[]string{"a=1", "a\x00=1", "a\x00b=1", "ab=1", "b=1"},
+		s.collect(ss.Iterator(storeKey1Bytes, 1, nil, nil)),
+	)
+	s.Require().Equal(
+		[]string{"a=1", "a\x00b=1", "ab=2", "b=1", "c=2"},
+		s.collect(ss.Iterator(storeKey1Bytes, 2, nil, nil)),
+	)
+	s.Require().Equal(
+		[]string{"c=2", "b=1", "ab=2", "a\x00b=1", "a\x00=3"},
+		s.collect(ss.ReverseIterator(storeKey1Bytes, 3, nil, nil)),
+	)
+
+	// bounds
+	s.Require().Equal(
+		[]string{"a\x00=1", "a\x00b=1", "ab=1"},
+		s.collect(ss.Iterator(storeKey1Bytes, 1, []byte("a\x00"), []byte("b"))),
+	)
+	s.Require().Equal(
+		[]string{"ab=2", "a\x00b=1", "a=1"},
+		s.collect(ss.ReverseIterator(storeKey1Bytes, 2, []byte("a"), []byte("b"))),
+	)
+	s.Require().Empty(s.collect(ss.Iterator(storeKey1Bytes, 3, []byte("d"), nil)))
+
+	_, err := ss.Iterator(storeKey1Bytes, 3, []byte("b"), []byte("a"))
+	s.Require().ErrorIs(err, storeerrors.ErrStartAfterEnd)
+}
+
+func (s *StorageTestSuite) TestPrune() {
+	ss := s.newStore()
+
+	for v := uint64(1); v <= 10; v++ {
+		s.commit(ss, v, "a", fmt.Sprintf("%d", v), fmt.Sprintf("key%02d", v), "x")
+	}
+	s.commit(ss, 11, "key03", "")
+	s.commit(ss, 12, "a", "")
+
+	s.Require().NoError(ss.db.Prune(11))
+
+	// pruned versions can't be read
+	_, err := ss.Get(storeKey1Bytes, 11, []byte("a"))
+	s.Require().ErrorAs(err, &storeerrors.ErrVersionPruned{})
+	_, err = ss.Iterator(storeKey1Bytes, 5, nil, nil)
+	s.Require().ErrorAs(err, &storeerrors.ErrVersionPruned{})
+
+	ok, err := ss.VersionExists(11)
+	s.Require().NoError(err)
+	s.Require().False(ok)
+
+	// the state of the following versions is intact
+	value, err := ss.Get(storeKey1Bytes, 12, []byte("key05"))
+	s.Require().NoError(err)
+	s.Require().Equal([]byte("x"), value)
+	s.Require().Len(s.collect(ss.Iterator(storeKey1Bytes, 12, nil, nil)), 9)
+
+	// only the newest entries up to the pruned version are kept, removed keys are gone
+	s.Require().Equal(11, s.countEntries(ss))
+
+	// the change index only holds the keys of the versions which weren't pruned
+	s.Require().Equal(1, s.countChanges(ss))
+}
+
+func (s *StorageTestSuite) TestPruneInBackground() {
+	ss := s.newStore()
+
+	for v := uint64(1); v <= 5; v++ {
+		s.commit(ss, v, "a", fmt.Sprintf("%d", v))
+	}
+
+	s.Require().NoError(ss.Prune(3))
+	s.Require().Eventually(func() bool {
+		ss.pruneMtx.Lock()
+		defer ss.pruneMtx.Unlock()
+		return !ss.pruning
+	}, 5*time.Second, 10*time.Millisecond)
+
+	earliest, err := ss.GetEarliestVersion()
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(4), earliest)
+	s.Require().Equal(3, s.countEntries(ss))
+}
+
+func (s *StorageTestSuite) TestRollback() {
+	ss := s.newStore()
+
+	s.commit(ss, 1, "a", "1")
+	s.commit(ss, 2, "a", "2", "b", "2")
+	s.commit(ss, 3, "a", "", "c", "3")
+
+	s.Require().NoError(ss.Rollback(1))
+
+	latest, err := ss.GetLatestVersion()
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(1), latest)
+	s.Require().Equal([]string{"a=1"}, s.collect(ss.Iterator(storeKey1Bytes, 3, nil, nil)))
+	s.Require().Equal(1, s.countEntries(ss))
+	s.Require().Equal(1, s.countChanges(ss))
+
+	// the rolled back versions can be written again
+	s.commit(ss, 2, "d", "2")
+	s.Require().Equal([]string{"a=1", "d=2"}, s.collect(ss.Iterator(storeKey1Bytes, 2, nil, nil)))
+}
+
+func (s *StorageTestSuite) TestImportProgress() {
+	ss := s.newStore()
+
+	version, storeKey, key, err := ss.GetImportProgress()
+	s.Require().NoError(err)
+	s.Require().Zero(version)
+	s.Require().Nil(storeKey)
+	s.Require().Nil(key)
+
+	s.Require().NoError(ss.SetImportProgress(7, storeKey1Bytes, []byte{0, 1, 2}))
+	version, storeKey, key, err = ss.GetImportProgress()
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(7), version)
+	s.Require().Equal(storeKey1Bytes, storeKey)
+	s.Require().Equal([]byte{0, 1, 2}, key)
+
+	s.Require().NoError(ss.SetImportProgress(0, nil, nil))
+	version, _, _, err = ss.GetImportProgress()
+	s.Require().NoError(err)
+	s.Require().Zero(version)
+}
+
+// countEntries returns the number of entries of all the versions of the keys.
+func (s *StorageTestSuite) countEntries(ss *StorageStore) int {
+	return s.countPrefix(ss, dataPrefix)
+}
+
+// countChanges returns the number of entries of the change index.
+func (s *StorageTestSuite) countChanges(ss *StorageStore) int {
+	return s.countPrefix(ss, changePrefix)
+}
+
+func (s *StorageTestSuite) countPrefix(ss *StorageStore, prefix byte) int {
+	kv := ss.db.(*MVCCDatabase).kv
+	itr, err := kv.Iterator([]byte{prefix}, []byte{prefix + 1})
+	s.Require().NoError(err)
+	defer itr.Close()
+
+	n := 0
+	for ; itr.Valid(); itr.Next() {
+		n++
+	}
+	return n
+}
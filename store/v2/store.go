@@ -65,6 +65,9 @@ type RootStore interface {
 type Backend interface {
 	// GetStateCommitment returns the SC backend.
 	GetStateCommitment() Committer
+
+	// GetStateStorage returns the SS backend or nil if the RootStore has no SS backend.
+	GetStateStorage() VersionedWriter
 }
 
 // UpgradeableStore defines the interface for upgrading store keys.
@@ -101,3 +104,37 @@ type QueryResult struct {
 	Version  uint64
 	ProofOps []proof.CommitmentOp
 }
+
+// BatchQuerier is the optional interface of the RootStore which can query many
+// keys, or all the keys of a range, of a store with a single proof.
+type BatchQuerier interface {
+	// QueryBatch queries the given keys of the store at the version. The proof
+	// is verified by proof.VerifyBatchProof.
+	QueryBatch(storeKey []byte, version uint64, keys [][]byte, prove bool) (BatchQueryResult, error)
+
+	// QueryRange queries the pairs of the store in the [start, end) range at the
+	// version, a nil start or end leaving the range unbounded on that side. At
+	// most limit pairs are returned if limit is positive, the range of the result
+	// then ends after the last pair. The proof is verified by
+	// proof.VerifyRangeProof over the range of the result.
+	QueryRange(storeKey []byte, version uint64, start, end []byte, limit int, prove bool) (RangeQueryResult, error)
+}
+
+// BatchQueryResult defines the response type to performing a batch query on a
+// RootStore. The value of an absent key is nil.
+type BatchQueryResult struct {
+	Pairs    []proof.KVPair
+	Version  uint64
+	ProofOps []proof.CommitmentOp
+}
+
+// RangeQueryResult defines the response type to performing a range query on a
+// RootStore. Pairs are all the pairs of the store in the [Start, End) range, the
+// next page of a limited query starts at End.
+type RangeQueryResult struct {
+	Start    []byte
+	End      []byte
+	Pairs    []proof.KVPair
+	Version  uint64
+	ProofOps []proof.CommitmentOp
+}
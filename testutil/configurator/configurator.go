@@ -75,10 +75,10 @@ func defaultConfig() *Config {
 			testutil.EpochsModuleName,
 		},
 		EndBlockersOrder: []string{
+			testutil.BankModuleName,
 			testutil.GovModuleName,
 			testutil.StakingModuleName,
 			testutil.AuthModuleName,
-			testutil.BankModuleName,
 			testutil.DistributionModuleName,
 			testutil.SlashingModuleName,
 			testutil.MintModuleName,
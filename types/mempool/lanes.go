@@ -0,0 +1,188 @@
+package mempool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	_ Mempool  = (*LanedMempool)(nil)
+	_ Iterator = (*lanedIterator)(nil)
+)
+
+// ErrNoLane is returned when no lane of a LanedMempool matches a tx.
+var ErrNoLane = errors.New("no lane matches tx")
+
+// Lane is a partition of a LanedMempool, holding the txs it matches in its own
+// mempool and selection policy.
+type Lane struct {
+	// Name identifies the lane.
+	Name string
+
+	// Mempool holds the txs of the lane and defines their order.
+	Mempool Mempool
+
+	// Match reports whether a tx belongs to the lane. A nil Match matches every
+	// tx, as a default lane does.
+	Match func(tx sdk.Tx) bool
+
+	// MaxBlockSpace is the maximum share, in percent, of the block bytes and gas
+	// the txs of the lane can take. A value of 0 does not limit the lane.
+	MaxBlockSpace uint64
+
+	// Priority orders the lanes. A tx belongs to the lane with the highest
+	// priority matching it, and the txs of the lanes with a higher priority are
+	// included first in a block.
+	Priority int64
+}
+
+// BlockSpaceLimit returns the share of the lane of a block space, in bytes or
+// gas.
+func (l Lane) BlockSpaceLimit(total uint64) uint64 {
+	if l.MaxBlockSpace == 0 {
+		return total
+	}
+	// total * MaxBlockSpace / 100 without overflow
+	return total/100*l.MaxBlockSpace + total%100*l.MaxBlockSpace/100
+}
+
+func (l Lane) matches(tx sdk.Tx) bool {
+	return l.Match == nil || l.Match(tx)
+}
+
+// LanedMempool is a mempool partitioned in lanes, so that the txs of a lane,
+// for instance oracle or IBC relayer txs, get their share of the block space
+// regardless of the txs of the other lanes. The txs are selected lane by lane
+// by priority, and in the order of the mempool of the lane within a lane.
+//
+// The DefaultProposalHandler caps the txs of every lane to its share of the
+// block space in PrepareProposal, and verifies in ProcessProposal that the txs
+// of a proposal are ordered by lane and within the share of their lane. The
+// lanes must therefore be defined in the app code, identically on every node.
+type LanedMempool struct {
+	lanes []Lane
+}
+
+// NewLanedMempool returns a new LanedMempool made of the given lanes.
+func NewLanedMempool(lanes ...Lane) (*LanedMempool, error) {
+	if len(lanes) == 0 {
+		return nil, errors.New("laned mempool must have at least one lane")
+	}
+
+	names := make(map[string]struct{}, len(lanes))
+	for _, lane := range lanes {
+		if lane.Name == "" {
+			return nil, errors.New("lane name cannot be empty")
+		}
+		if _, ok := names[lane.Name]; ok {
+			return nil, fmt.Errorf("duplicate lane %s", lane.Name)
+		}
+		names[lane.Name] = struct{}{}
+		if lane.Mempool == nil {
+			return nil, fmt.Errorf("lane %s has no mempool", lane.Name)
+		}
+		if lane.MaxBlockSpace > 100 {
+			return nil, fmt.Errorf("max block space of lane %s must be at most 100%%, got %d%%", lane.Name, lane.MaxBlockSpace)
+		}
+	}
+
+	lanes = append([]Lane(nil), lanes...)
+	sort.SliceStable(lanes, func(i, j int) bool { return lanes[i].Priority > lanes[j].Priority })
+	return &LanedMempool{lanes: lanes}, nil
+}
+
+// Lanes returns the lanes of the mempool by priority.
+func (mp *LanedMempool) Lanes() []Lane {
+	return append([]Lane(nil), mp.lanes...)
+}
+
+// LaneIndex returns the index in Lanes of the lane of a tx.
+func (mp *LanedMempool) LaneIndex(tx sdk.Tx) (int, error) {
+	for i, lane := range mp.lanes {
+		if lane.matches(tx) {
+			return i, nil
+		}
+	}
+	return 0, ErrNoLane
+}
+
+// Insert inserts a tx in the mempool of its lane.
+func (mp *LanedMempool) Insert(ctx context.Context, tx sdk.Tx) error {
+	i, err := mp.LaneIndex(tx)
+	if err != nil {
+		return err
+	}
+	return mp.lanes[i].Mempool.Insert(ctx, tx)
+}
+
+// Select returns an iterator over the txs of the lanes by priority.
+func (mp *LanedMempool) Select(ctx context.Context, txs []sdk.Tx) Iterator {
+	return (&lanedIterator{ctx: ctx, txs: txs, lanes: mp.lanes, lane: -1}).nextLane()
+}
+
+// SelectBy calls callback on the txs of the lanes by priority until it returns
+// false.
+func (mp *LanedMempool) SelectBy(ctx context.Context, txs []sdk.Tx, callback func(sdk.Tx) bool) {
+	for _, lane := range mp.lanes {
+		stopped := false
+		lane.Mempool.SelectBy(ctx, txs, func(tx sdk.Tx) bool {
+			stopped = !callback(tx)
+			return !stopped
+		})
+		if stopped {
+			return
+		}
+	}
+}
+
+// CountTx returns the number of txs of all the lanes.
+func (mp *LanedMempool) CountTx() int {
+	count := 0
+	for _, lane := range mp.lanes {
+		count += lane.Mempool.CountTx()
+	}
+	return count
+}
+
+// Remove removes a tx from the mempool of its lane.
+func (mp *LanedMempool) Remove(tx sdk.Tx) error {
+	i, err := mp.LaneIndex(tx)
+	if err != nil {
+		return ErrTxNotFound
+	}
+	return mp.lanes[i].Mempool.Remove(tx)
+}
+
+// lanedIterator iterates over the txs of the lanes of a LanedMempool.
+type lanedIterator struct {
+	ctx   context.Context
+	txs   []sdk.Tx
+	lanes []Lane
+	lane  int
+	iter  Iterator
+}
+
+// nextLane moves the iterator to the first tx of the next lanes.
+func (i *lanedIterator) nextLane() Iterator {
+	for i.lane++; i.lane < len(i.lanes); i.lane++ {
+		if i.iter = i.lanes[i.lane].Mempool.Select(i.ctx, i.txs); i.iter != nil {
+			return i
+		}
+	}
+	return nil
+}
+
+func (i *lanedIterator) Next() Iterator {
+	if i.iter = i.iter.Next(); i.iter != nil {
+		return i
+	}
+	return i.nextLane()
+}
+
+func (i *lanedIterator) Tx() sdk.Tx {
+	return i.iter.Tx()
+}
@@ -0,0 +1,104 @@
+package mempool_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/log"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/mempool"
+	simtypes "github.com/cosmos/cosmos-sdk/types/simulation"
+)
+
+func TestNewLanedMempool(t *testing.T) {
+	_, err := mempool.NewLanedMempool()
+	require.Error(t, err)
+	_, err = mempool.NewLanedMempool(mempool.Lane{Mempool: mempool.NoOpMempool{}})
+	require.Error(t, err)
+	_, err = mempool.NewLanedMempool(mempool.Lane{Name: "default"})
+	require.Error(t, err)
+	_, err = mempool.NewLanedMempool(mempool.Lane{Name: "default", Mempool: mempool.NoOpMempool{}, MaxBlockSpace: 101})
+	require.Error(t, err)
+	_, err = mempool.NewLanedMempool(
+		mempool.Lane{Name: "default", Mempool: mempool.NoOpMempool{}},
+		mempool.Lane{Name: "default", Mempool: mempool.NoOpMempool{}},
+	)
+	require.Error(t, err)
+
+	mp, err := mempool.NewLanedMempool(
+		mempool.Lane{Name: "default", Mempool: mempool.NoOpMempool{}},
+		mempool.Lane{Name: "oracle", Mempool: mempool.NoOpMempool{}, Priority: 10},
+		mempool.Lane{Name: "relayer", Mempool: mempool.NoOpMempool{}, Priority: 5},
+	)
+	require.NoError(t, err)
+	var names []string
+	for _, lane := range mp.Lanes() {
+		names = append(names, lane.Name)
+	}
+	require.Equal(t, []string{"oracle", "relayer", "default"}, names)
+}
+
+func TestLaneBlockSpaceLimit(t *testing.T) {
+	require.Equal(t, uint64(1000), mempool.Lane{}.BlockSpaceLimit(1000))
+	require.Equal(t, uint64(250), mempool.Lane{MaxBlockSpace: 25}.BlockSpaceLimit(1000))
+	require.Equal(t, uint64(1<<63-1), mempool.Lane{MaxBlockSpace: 50}.BlockSpaceLimit(1<<64-1))
+}
+
+func TestLanedMempool(t *testing.T) {
+	ctx := sdk.NewContext(nil, false, log.NewNopLogger())
+	accounts := simtypes.RandomAccounts(rand.New(rand.NewSource(0)), 3)
+	sa := accounts[0].Address
+	sb := accounts[1].Address
+	sc := accounts[2].Address
+
+	mp, err := mempool.NewLanedMempool(
+		mempool.Lane{Name: "default", Mempool: mempool.DefaultPriorityMempool()},
+		mempool.Lane{
+			Name:          "oracle",
+			Mempool:       mempool.DefaultPriorityMempool(),
+			Match:         func(tx sdk.Tx) bool { return tx.(testTx).address.Equals(sa) },
+			MaxBlockSpace: 20,
+			Priority:      10,
+		},
+	)
+	require.NoError(t, err)
+
+	txs := []testTx{
+		{id: 0, priority: 5, nonce: 1, address: sa},
+		{id: 1, priority: 20, nonce: 1, address: sb},
+		{id: 2, priority: 5, nonce: 2, address: sa},
+		{id: 3, priority: 10, nonce: 1, address: sc},
+	}
+	for _, tx := range txs {
+		require.NoError(t, mp.Insert(ctx.WithPriority(tx.priority), tx))
+	}
+	require.Equal(t, len(txs), mp.CountTx())
+
+	i, err := mp.LaneIndex(txs[0])
+	require.NoError(t, err)
+	require.Equal(t, "oracle", mp.Lanes()[i].Name)
+
+	// the txs of the oracle lane come first, whatever their priority
+	var selected []int
+	for it := mp.Select(ctx, nil); it != nil; it = it.Next() {
+		selected = append(selected, it.Tx().(testTx).id)
+	}
+	require.Equal(t, []int{0, 2, 1, 3}, selected)
+
+	selected = nil
+	mp.SelectBy(ctx, nil, func(tx sdk.Tx) bool {
+		selected = append(selected, tx.(testTx).id)
+		return len(selected) < 3
+	})
+	require.Equal(t, []int{0, 2, 1}, selected)
+
+	for _, tx := range txs {
+		require.NoError(t, mp.Remove(tx))
+	}
+	require.Zero(t, mp.CountTx())
+	require.Nil(t, mp.Select(ctx, nil))
+	require.ErrorIs(t, mp.Remove(txs[0]), mempool.ErrTxNotFound)
+}
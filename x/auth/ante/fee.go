@@ -93,6 +93,8 @@ func (dfd *DeductFeeDecorator) innerValidateTx(ctx context.Context, tx transacti
 
 // ValidateTx implements an TxValidator for DeductFeeDecorator
 // Note: This method is applicable only for transactions that implement the sdk.FeeTx interface.
+// The priority returned by the TxFeeChecker is dropped, the mempools of server/v2
+// compute the priority of a transaction from its fee and gas instead.
 func (dfd *DeductFeeDecorator) ValidateTx(ctx context.Context, tx transaction.Tx) error {
 	_, err := dfd.innerValidateTx(ctx, tx)
 	return err
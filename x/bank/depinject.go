@@ -6,9 +6,12 @@ import (
 	"slices"
 	"sort"
 
+	"github.com/spf13/cast"
+
 	modulev1 "cosmossdk.io/api/cosmos/bank/module/v1"
 	"cosmossdk.io/core/address"
 	"cosmossdk.io/core/appmodule"
+	"cosmossdk.io/core/server"
 	"cosmossdk.io/depinject"
 	"cosmossdk.io/depinject/appconfig"
 	"cosmossdk.io/x/bank/keeper"
@@ -23,14 +26,29 @@ var _ depinject.OnePerModuleType = AppModule{}
 // IsOnePerModuleType implements the depinject.OnePerModuleType interface.
 func (am AppModule) IsOnePerModuleType() {}
 
+// flagParallelExecutionWorkersV2 is the flag name for the number of workers
+// executing the txs of a block in parallel in the main server v2 component.
+const flagParallelExecutionWorkersV2 = "server.parallel-execution-workers"
+
 func init() {
 	appconfig.RegisterModule(
 		&modulev1.Module{},
-		appconfig.Provide(ProvideModule),
+		appconfig.Provide(ProvideModule, ProvideConfig),
 		appconfig.Invoke(InvokeSetSendRestrictions),
 	)
 }
 
+// ProvideConfig specifies the configuration key for the parallel execution.
+// During dependency injection, a configuration map is provided with the key set.
+func ProvideConfig(key depinject.OwnModuleKey) server.ModuleConfigMap {
+	return server.ModuleConfigMap{
+		Module: depinject.ModuleKey(key).Name(),
+		Config: server.ConfigMap{
+			flagParallelExecutionWorkersV2: 0,
+		},
+	}
+}
+
 type ModuleInputs struct {
 	depinject.In
 
@@ -38,6 +56,7 @@ type ModuleInputs struct {
 	Cdc          codec.Codec
 	Environment  appmodule.Environment
 	AddressCodec address.Codec
+	ConfigMap    server.ConfigMap
 
 	AccountKeeper types.AccountKeeper
 }
@@ -91,6 +110,11 @@ func ProvideModule(in ModuleInputs) ModuleOutputs {
 		blockedAddresses,
 		authStr,
 	)
+	// the fees are credited to the fee collector at the end of the block when the
+	// txs are executed in parallel, so that they don't conflict with each other
+	if cast.ToInt(in.ConfigMap[flagParallelExecutionWorkersV2]) > 1 {
+		bankKeeper = bankKeeper.WithDeferredCredits(authtypes.FeeCollectorName)
+	}
 	m := NewAppModule(in.Cdc, bankKeeper, in.AccountKeeper)
 
 	return ModuleOutputs{BankKeeper: bankKeeper, Module: m}
@@ -42,6 +42,7 @@ type Keeper interface {
 	SendCoinsFromModuleToAccount(ctx context.Context, senderModule string, recipientAddr sdk.AccAddress, amt sdk.Coins) error
 	SendCoinsFromModuleToModule(ctx context.Context, senderModule, recipientModule string, amt sdk.Coins) error
 	SendCoinsFromAccountToModule(ctx context.Context, senderAddr sdk.AccAddress, recipientModule string, amt sdk.Coins) error
+	CreditDeferredCoins(ctx context.Context) error
 	DelegateCoinsFromAccountToModule(ctx context.Context, senderAddr sdk.AccAddress, recipientModule string, amt sdk.Coins) error
 	UndelegateCoinsFromModuleToAccount(ctx context.Context, senderModule string, recipientAddr sdk.AccAddress, amt sdk.Coins) error
 	MintCoins(ctx context.Context, moduleName string, amt sdk.Coins) error
@@ -62,6 +63,9 @@ type BaseKeeper struct {
 	cdc                    codec.BinaryCodec
 	mintCoinsRestrictionFn types.MintingRestrictionFn
 	addrCdc                address.Codec
+	// deferredCreditModules are the module accounts whose credits are deferred
+	// to the end of the block, see WithDeferredCredits.
+	deferredCreditModules map[string]bool
 }
 
 // GetPaginatedTotalSupply queries for the supply, ignoring 0 coins, with a given pagination
@@ -114,6 +118,24 @@ func (k BaseKeeper) WithMintCoinsRestriction(check types.MintingRestrictionFn) B
 	return k
 }
 
+// WithDeferredCredits defers the credit of the coins sent to the given module
+// accounts with SendCoinsFromAccountToModule to the EndBlock of the module, e.g.
+// the fee collector, so that the txs of a block sending coins to them don't all
+// write their balance and can be executed in parallel. The coins are debited from
+// the sender right away, but aren't part of the balance of the module account
+// until the end of the block.
+func (k BaseKeeper) WithDeferredCredits(moduleNames ...string) BaseKeeper {
+	deferredCreditModules := make(map[string]bool, len(k.deferredCreditModules)+len(moduleNames))
+	for name := range k.deferredCreditModules {
+		deferredCreditModules[name] = true
+	}
+	for _, name := range moduleNames {
+		deferredCreditModules[name] = true
+	}
+	k.deferredCreditModules = deferredCreditModules
+	return k
+}
+
 // DelegateCoins performs delegation by deducting amt coins from an account with
 // address addr. For vesting accounts, delegations amounts are tracked for both
 // vesting and vested coins. The coins are then transferred from the delegator
@@ -293,6 +315,10 @@ func (k BaseKeeper) SendCoinsFromAccountToModule(
 		return errorsmod.Wrapf(sdkerrors.ErrUnknownAddress, "module account %s does not exist", recipientModule)
 	}
 
+	if k.deferredCreditModules[recipientModule] {
+		return k.sendCoinsDeferred(ctx, senderAddr, recipientAcc.GetAddress(), amt)
+	}
+
 	return k.SendCoins(ctx, senderAddr, recipientAcc.GetAddress(), amt)
 }
 
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
 
+	"cosmossdk.io/collections"
 	"cosmossdk.io/core/address"
 	coreevent "cosmossdk.io/core/event"
 	"cosmossdk.io/core/header"
@@ -655,6 +656,48 @@ func (suite *KeeperTestSuite) TestSendCoinsNewAccount() {
 	require.Equal(acc1Balances, updatedAcc1Bal)
 }
 
+func (suite *KeeperTestSuite) TestSendCoinsToFeeCollector() {
+	ctx := suite.ctx
+	require := suite.Require()
+	feeCollectorAcc := authtypes.NewEmptyModuleAccount(authtypes.FeeCollectorName)
+	fees := sdk.NewCoins(newFooCoin(10))
+
+	acc0 := authtypes.NewBaseAccountWithAddress(accAddrs[0])
+	acc1 := authtypes.NewBaseAccountWithAddress(accAddrs[1])
+	for _, acc := range []*authtypes.BaseAccount{acc0, acc1} {
+		suite.mockFundAccount(acc.GetAddress())
+		require.NoError(banktestutil.FundAccount(ctx, suite.bankKeeper, acc.GetAddress(), sdk.NewCoins(newFooCoin(100))))
+	}
+
+	// the coins are credited right away unless the credits are deferred
+	suite.mockSendCoinsFromAccountToModule(acc1, feeCollectorAcc)
+	require.NoError(suite.bankKeeper.SendCoinsFromAccountToModule(ctx, acc1.GetAddress(), authtypes.FeeCollectorName, fees))
+	require.Equal(fees, suite.bankKeeper.GetAllBalances(ctx, feeCollectorAcc.GetAddress()))
+
+	// the fees of each sender are recorded separately until the end of the block
+	bankKeeper := suite.bankKeeper.WithDeferredCredits(authtypes.FeeCollectorName)
+	for _, acc := range []*authtypes.BaseAccount{acc0, acc1, acc0} {
+		suite.mockSendCoinsFromAccountToModule(acc, feeCollectorAcc)
+		require.NoError(bankKeeper.SendCoinsFromAccountToModule(ctx, acc.GetAddress(), authtypes.FeeCollectorName, fees))
+	}
+	require.Equal(sdk.NewCoins(newFooCoin(80)), bankKeeper.GetAllBalances(ctx, accAddrs[0]))
+	require.Equal(sdk.NewCoins(newFooCoin(80)), bankKeeper.GetAllBalances(ctx, accAddrs[1]))
+	require.Equal(fees, bankKeeper.GetAllBalances(ctx, feeCollectorAcc.GetAddress()))
+
+	deferred, err := bankKeeper.DeferredCredits.Get(ctx, collections.Join3(feeCollectorAcc.GetAddress(), accAddrs[0], fooDenom))
+	require.NoError(err)
+	require.Equal(math.NewInt(20), deferred)
+
+	require.NoError(bankKeeper.CreditDeferredCoins(ctx))
+	require.Equal(sdk.NewCoins(newFooCoin(40)), bankKeeper.GetAllBalances(ctx, feeCollectorAcc.GetAddress()))
+
+	iter, err := bankKeeper.DeferredCredits.Iterate(ctx, nil)
+	require.NoError(err)
+	keys, err := iter.Keys()
+	require.NoError(err)
+	require.Empty(keys)
+}
+
 func (suite *KeeperTestSuite) TestInputOutputNewAccount() {
 	ctx := suite.ctx
 	require := suite.Require()
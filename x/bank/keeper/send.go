@@ -2,6 +2,7 @@ package keeper
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"cosmossdk.io/collections"
@@ -218,6 +219,11 @@ func (k BaseSendKeeper) SendCoins(ctx context.Context, fromAddr, toAddr sdk.AccA
 		return err
 	}
 
+	return k.emitTransferEvent(ctx, fromAddr, toAddr, amt)
+}
+
+// emitTransferEvent emits the transfer event of amt coins from fromAddr to toAddr.
+func (k BaseSendKeeper) emitTransferEvent(ctx context.Context, fromAddr, toAddr sdk.AccAddress, amt sdk.Coins) error {
 	fromAddrString, err := k.addrCdc.BytesToString(fromAddr)
 	if err != nil {
 		return err
@@ -235,6 +241,96 @@ func (k BaseSendKeeper) SendCoins(ctx context.Context, fromAddr, toAddr sdk.AccA
 	)
 }
 
+// sendCoinsDeferred transfers amt coins from fromAddr to toAddr like SendCoins,
+// but the coins are only credited to toAddr by CreditDeferredCoins at the end of
+// the block. The coins sent by different senders are recorded separately, so
+// that the txs of different senders don't write the same state.
+func (k BaseSendKeeper) sendCoinsDeferred(ctx context.Context, fromAddr, toAddr sdk.AccAddress, amt sdk.Coins) error {
+	if !amt.IsValid() {
+		return errorsmod.Wrap(sdkerrors.ErrInvalidCoins, amt.String())
+	}
+
+	recipient, err := k.sendRestriction.apply(ctx, fromAddr, toAddr, amt)
+	if err != nil {
+		return err
+	}
+
+	err = k.subUnlockedCoins(ctx, fromAddr, amt)
+	if err != nil {
+		return err
+	}
+
+	// coins redirected by a send restriction are credited directly
+	if recipient.Equals(toAddr) {
+		err = k.addDeferredCoins(ctx, recipient, fromAddr, amt)
+	} else {
+		err = k.addCoins(ctx, recipient, amt)
+	}
+	if err != nil {
+		return err
+	}
+
+	return k.emitTransferEvent(ctx, fromAddr, recipient, amt)
+}
+
+// addDeferredCoins records amt coins sent by sender to be credited to addr at
+// the end of the block.
+//
+// It emits a coin_received event after the operation.
+func (k BaseSendKeeper) addDeferredCoins(ctx context.Context, addr, sender sdk.AccAddress, amt sdk.Coins) error {
+	for _, coin := range amt {
+		key := collections.Join3(addr, sender, coin.Denom)
+		deferred, err := k.DeferredCredits.Get(ctx, key)
+		if errors.Is(err, collections.ErrNotFound) {
+			deferred = math.ZeroInt()
+		} else if err != nil {
+			return err
+		}
+
+		if err := k.DeferredCredits.Set(ctx, key, deferred.Add(coin.Amount)); err != nil {
+			return err
+		}
+	}
+
+	addrStr, err := k.addrCdc.BytesToString(addr)
+	if err != nil {
+		return err
+	}
+
+	return k.EventService.EventManager(ctx).EmitKV(
+		types.EventTypeCoinReceived,
+		event.NewAttribute(types.AttributeKeyReceiver, addrStr),
+		event.NewAttribute(sdk.AttributeKeyAmount, amt.String()),
+	)
+}
+
+// CreditDeferredCoins credits the coins sent by sendCoinsDeferred to their
+// recipients. It must be called at the end of every block, before the recipients
+// spend their balance.
+func (k BaseSendKeeper) CreditDeferredCoins(ctx context.Context) error {
+	iter, err := k.DeferredCredits.Iterate(ctx, nil)
+	if err != nil {
+		return err
+	}
+	credits, err := iter.KeyValues()
+	if err != nil {
+		return err
+	}
+
+	for _, credit := range credits {
+		recipient, denom := credit.Key.K1(), credit.Key.K3()
+		balance := k.GetBalance(ctx, recipient, denom)
+		if err := k.setBalance(ctx, recipient, balance.AddAmount(credit.Value)); err != nil {
+			return err
+		}
+		if err := k.DeferredCredits.Remove(ctx, credit.Key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // subUnlockedCoins removes the unlocked amt coins of the given account.
 // An error is returned if the resulting balance is negative.
 //
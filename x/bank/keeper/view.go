@@ -68,6 +68,9 @@ type BaseViewKeeper struct {
 	SendEnabled   collections.Map[string, bool]
 	Balances      *collections.IndexedMap[collections.Pair[sdk.AccAddress, string], math.Int, BalancesIndexes]
 	Params        collections.Item[types.Params]
+	// DeferredCredits holds the coins sent to a module account during the block
+	// by a sender, keyed by recipient, sender and denom.
+	DeferredCredits collections.Map[collections.Triple[sdk.AccAddress, sdk.AccAddress, string], math.Int]
 }
 
 // NewBaseViewKeeper returns a new BaseViewKeeper.
@@ -83,6 +86,11 @@ func NewBaseViewKeeper(env appmodule.Environment, cdc codec.BinaryCodec, ak type
 		SendEnabled:   collections.NewMap(sb, types.SendEnabledPrefix, "send_enabled", collections.StringKey.WithName("denom"), codec.BoolValue), // NOTE: we use a bool value which uses protobuf to retain state backwards compat
 		Balances:      collections.NewIndexedMap(sb, types.BalancesPrefix, "balances", collections.NamedPairKeyCodec("address", sdk.AccAddressKey, "denom", collections.StringKey), types.BalanceValueCodec, newBalancesIndexes(sb)),
 		Params:        collections.NewItem(sb, types.ParamsKey, "params", codec.CollValue[types.Params](cdc)),
+		DeferredCredits: collections.NewMap(
+			sb, types.DeferredCreditsPrefix, "deferred_credits",
+			collections.NamedTripleKeyCodec("recipient", sdk.AccAddressKey, "sender", sdk.AccAddressKey, "denom", collections.StringKey),
+			sdk.IntValue,
+		),
 	}
 
 	schema, err := sb.Build()
@@ -36,6 +36,7 @@ var (
 	_ appmodule.AppModule             = AppModule{}
 	_ appmodule.HasMigrations         = AppModule{}
 	_ appmodule.HasGenesis            = AppModule{}
+	_ appmodule.HasEndBlocker         = AppModule{}
 	_ appmodule.HasRegisterInterfaces = AppModule{}
 	_ schema.HasModuleCodec           = AppModule{}
 )
@@ -93,6 +94,12 @@ func (am AppModule) RegisterServices(registrar grpc.ServiceRegistrar) error {
 	return nil
 }
 
+// EndBlock credits the coins sent during the block to the module accounts whose
+// credits are deferred, see keeper.BaseKeeper.WithDeferredCredits.
+func (am AppModule) EndBlock(ctx context.Context) error {
+	return am.keeper.CreditDeferredCoins(ctx)
+}
+
 // RegisterMigrations registers the bank module's migrations.
 func (am AppModule) RegisterMigrations(mr appmodule.MigrationRegistrar) error {
 	m := keeper.NewMigrator(am.keeper.(keeper.BaseKeeper))
@@ -39,6 +39,11 @@ var (
 
 	// ParamsKey is the prefix for x/bank parameters
 	ParamsKey = collections.NewPrefix(5)
+
+	// DeferredCreditsPrefix is the prefix for the coins sent during a block to the
+	// module accounts whose credits are deferred, which are credited at the end of
+	// the block.
+	DeferredCreditsPrefix = collections.NewPrefix(6)
 )
 
 // BalanceValueCodec is a codec for encoding bank balances in a backwards compatible way.
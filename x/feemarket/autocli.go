@@ -0,0 +1,44 @@
+package feemarket
+
+import (
+	"fmt"
+
+	autocliv1 "cosmossdk.io/api/cosmos/autocli/v1"
+	feemarketv1 "cosmossdk.io/api/cosmos/feemarket/v1"
+
+	"github.com/cosmos/cosmos-sdk/version"
+)
+
+func (am AppModule) AutoCLIOptions() *autocliv1.ModuleOptions {
+	return &autocliv1.ModuleOptions{
+		Query: &autocliv1.ServiceCommandDescriptor{
+			Service: feemarketv1.Query_ServiceDesc.ServiceName,
+			RpcCommandOptions: []*autocliv1.RpcCommandOptions{
+				{
+					RpcMethod: "Params",
+					Use:       "params",
+					Short:     "Query the current fee market parameters",
+				},
+				{
+					RpcMethod: "BaseFee",
+					Use:       "base-fee",
+					Short:     "Query the current base fee, the minimum gas price of the transactions",
+				},
+			},
+		},
+		Tx: &autocliv1.ServiceCommandDescriptor{
+			Service: feemarketv1.Msg_ServiceDesc.ServiceName,
+			RpcCommandOptions: []*autocliv1.RpcCommandOptions{
+				{
+					RpcMethod:      "UpdateParams",
+					Use:            "update-params-proposal <params>",
+					Short:          "Submit a proposal to update feemarket module params. Note: the entire params must be provided.",
+					Long:           fmt.Sprintf("Submit a proposal to update feemarket module params. Note: the entire params must be provided.\n See the fields to fill in by running `%s query feemarket params --output json`", version.AppName),
+					Example:        fmt.Sprintf(`%s tx feemarket update-params-proposal '{ "fee_denom": "stake", ... }'`, version.AppName),
+					PositionalArgs: []*autocliv1.PositionalArgDescriptor{{ProtoField: "params"}},
+					GovProposal:    true,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,132 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	stdmath "math"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/core/transaction"
+	errorsmod "cosmossdk.io/errors"
+	"cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// CheckTxFee is an x/auth/ante TxFeeChecker requiring the transactions to pay
+// at least the base fee for their gas, in the fee denom. The part of the fee
+// above the base fee is a tip, and the priority of a transaction is its gas
+// price in the fee denom, so that the mempools order the transactions by tip.
+//
+// The gas and the base fee of the transactions of a block are tracked so that
+// the EndBlocker adjusts the base fee to the block gas and burns the base fee.
+// As the fees are charged on the gas limit of the transactions, the block gas is
+// the sum of their gas limits. They are recorded per transaction, so that the
+// transactions of a block don't write the same state and can be executed in
+// parallel.
+//
+// The genesis transactions, which pay no fees, are not checked.
+//
+// CheckTxFee replaces the default fee checker, which enforces the minimum gas
+// prices of the validator.
+func (k *Keeper) CheckTxFee(ctx context.Context, tx transaction.Tx) (sdk.Coins, int64, error) {
+	feeTx, ok := tx.(sdk.FeeTx)
+	if !ok {
+		return nil, 0, errorsmod.Wrap(sdkerrors.ErrTxDecode, "Tx must be a FeeTx")
+	}
+
+	// the gentxs are delivered at genesis, possibly before the fee market genesis
+	if k.HeaderService.HeaderInfo(ctx).Height == 0 {
+		return feeTx.GetFee(), 0, nil
+	}
+
+	params, err := k.Params.Get(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	baseFee, err := k.BaseFee.Get(ctx)
+	if errors.Is(err, collections.ErrNotFound) {
+		return nil, 0, errors.New("base fee not set: the feemarket module must be initialized in genesis")
+	} else if err != nil {
+		return nil, 0, err
+	}
+
+	feeCoins := feeTx.GetFee()
+	gas := feeTx.GetGas()
+
+	// fee = ceil(baseFee * gasLimit)
+	requiredFee := baseFee.MulInt(math.NewIntFromUint64(gas)).Ceil().TruncateInt()
+	paidFee := feeCoins.AmountOf(params.FeeDenom)
+	if paidFee.LT(requiredFee) {
+		return nil, 0, errorsmod.Wrapf(sdkerrors.ErrInsufficientFee, "insufficient fees; got: %s required: %s", feeCoins, sdk.NewCoin(params.FeeDenom, requiredFee))
+	}
+
+	if k.TransactionService.ExecMode(ctx) == transaction.ExecModeFinalize {
+		if err := k.addBlockFee(ctx, tx, gas, requiredFee); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	priority := int64(stdmath.MaxInt64)
+	gasPrice := paidFee.Quo(math.NewIntFromUint64(max(gas, 1)))
+	if gasPrice.IsInt64() {
+		priority = gasPrice.Int64()
+	}
+
+	return feeCoins, priority, nil
+}
+
+// addBlockFee records the gas and the base fee of a transaction of the current
+// block.
+func (k *Keeper) addBlockFee(ctx context.Context, tx transaction.Tx, gas uint64, baseFee math.Int) error {
+	hash := tx.Hash()
+	txGas, err := k.BlockGas.Get(ctx, hash[:])
+	if err != nil && !errors.Is(err, collections.ErrNotFound) {
+		return err
+	}
+	if err := k.BlockGas.Set(ctx, hash[:], saturatingAdd(txGas, gas)); err != nil {
+		return err
+	}
+
+	txBaseFee, err := k.BlockBaseFee.Get(ctx, hash[:])
+	if errors.Is(err, collections.ErrNotFound) {
+		txBaseFee = math.ZeroInt()
+	} else if err != nil {
+		return err
+	}
+	return k.BlockBaseFee.Set(ctx, hash[:], txBaseFee.Add(baseFee))
+}
+
+// blockFees returns the gas and the base fee of the transactions of the current
+// block.
+func (k *Keeper) blockFees(ctx context.Context) (uint64, math.Int, error) {
+	var blockGas uint64
+	err := k.BlockGas.Walk(ctx, nil, func(_ []byte, gas uint64) (bool, error) {
+		blockGas = saturatingAdd(blockGas, gas)
+		return false, nil
+	})
+	if err != nil {
+		return 0, math.Int{}, err
+	}
+
+	blockBaseFee := math.ZeroInt()
+	err = k.BlockBaseFee.Walk(ctx, nil, func(_ []byte, baseFee math.Int) (bool, error) {
+		blockBaseFee = blockBaseFee.Add(baseFee)
+		return false, nil
+	})
+	if err != nil {
+		return 0, math.Int{}, err
+	}
+
+	return blockGas, blockBaseFee, nil
+}
+
+// saturatingAdd returns a + b, capped to the maximum uint64.
+func saturatingAdd(a, b uint64) uint64 {
+	if a > stdmath.MaxUint64-b {
+		return stdmath.MaxUint64
+	}
+	return a + b
+}
@@ -0,0 +1,37 @@
+package keeper
+
+import (
+	"context"
+
+	"cosmossdk.io/x/feemarket/types"
+)
+
+// InitGenesis new feemarket genesis
+func (keeper Keeper) InitGenesis(ctx context.Context, ak types.AccountKeeper, data *types.GenesisState) error {
+	if err := keeper.Params.Set(ctx, data.Params); err != nil {
+		return err
+	}
+
+	if err := keeper.BaseFee.Set(ctx, data.BaseFee); err != nil {
+		return err
+	}
+
+	ak.GetModuleAccount(ctx, types.ModuleName)
+
+	return nil
+}
+
+// ExportGenesis returns a GenesisState for a given context and keeper.
+func (keeper Keeper) ExportGenesis(ctx context.Context) (*types.GenesisState, error) {
+	params, err := keeper.Params.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	baseFee, err := keeper.BaseFee.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewGenesisState(params, baseFee), nil
+}
@@ -0,0 +1,44 @@
+package keeper
+
+import (
+	"context"
+
+	"cosmossdk.io/x/feemarket/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var _ types.QueryServer = queryServer{}
+
+func NewQueryServerImpl(k *Keeper) types.QueryServer {
+	return queryServer{k}
+}
+
+type queryServer struct {
+	k *Keeper
+}
+
+// Params returns params of the feemarket module.
+func (q queryServer) Params(ctx context.Context, _ *types.QueryParamsRequest) (*types.QueryParamsResponse, error) {
+	params, err := q.k.Params.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryParamsResponse{Params: params}, nil
+}
+
+// BaseFee returns the current base fee of the feemarket module.
+func (q queryServer) BaseFee(ctx context.Context, _ *types.QueryBaseFeeRequest) (*types.QueryBaseFeeResponse, error) {
+	params, err := q.k.Params.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	baseFee, err := q.k.BaseFee.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryBaseFeeResponse{BaseFee: sdk.NewDecCoinFromDec(params.FeeDenom, baseFee)}, nil
+}
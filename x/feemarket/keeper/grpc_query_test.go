@@ -0,0 +1,20 @@
+package keeper_test
+
+import (
+	"cosmossdk.io/math"
+	"cosmossdk.io/x/feemarket/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func (s *KeeperTestSuite) TestGRPCParams() {
+	params, err := s.queryServer.Params(s.ctx, &types.QueryParamsRequest{})
+	s.Require().NoError(err)
+	kparams, err := s.feeMarketKeeper.Params.Get(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(kparams, params.Params)
+
+	baseFee, err := s.queryServer.BaseFee(s.ctx, &types.QueryBaseFeeRequest{})
+	s.Require().NoError(err)
+	s.Require().Equal(sdk.NewDecCoinFromDec("stake", math.LegacyNewDec(10)), baseFee.BaseFee)
+}
@@ -0,0 +1,133 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/core/appmodule"
+	"cosmossdk.io/core/event"
+	"cosmossdk.io/math"
+	"cosmossdk.io/x/feemarket/types"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Keeper of the feemarket store
+type Keeper struct {
+	appmodule.Environment
+
+	cdc              codec.BinaryCodec
+	bankKeeper       types.BankKeeper
+	moduleAddress    sdk.AccAddress
+	feeCollectorName string
+	// the address capable of executing a MsgUpdateParams message. Typically, this
+	// should be the x/gov module account.
+	authority string
+
+	Schema  collections.Schema
+	Params  collections.Item[types.Params]
+	BaseFee collections.Item[math.LegacyDec]
+	// BlockGas is the gas of the transactions of the current block, by tx hash.
+	BlockGas collections.Map[[]byte, uint64]
+	// BlockBaseFee is the base fee paid by the transactions of the current block,
+	// by tx hash.
+	BlockBaseFee collections.Map[[]byte, math.Int]
+}
+
+// NewKeeper creates a new feemarket Keeper instance
+func NewKeeper(
+	cdc codec.BinaryCodec,
+	env appmodule.Environment,
+	ak types.AccountKeeper,
+	bk types.BankKeeper,
+	feeCollectorName string,
+	authority string,
+) *Keeper {
+	// ensure feemarket module account is set
+	moduleAddress := ak.GetModuleAddress(types.ModuleName)
+	if moduleAddress == nil {
+		panic(fmt.Sprintf("the x/%s module account has not been set", types.ModuleName))
+	}
+
+	sb := collections.NewSchemaBuilder(env.KVStoreService)
+	k := Keeper{
+		Environment:      env,
+		cdc:              cdc,
+		bankKeeper:       bk,
+		moduleAddress:    moduleAddress,
+		feeCollectorName: feeCollectorName,
+		authority:        authority,
+		Params:           collections.NewItem(sb, types.ParamsKey, "params", codec.CollValue[types.Params](cdc)),
+		BaseFee:          collections.NewItem(sb, types.BaseFeeKey, "base_fee", sdk.LegacyDecValue),
+		BlockGas:         collections.NewMap(sb, types.BlockGasKey, "block_gas", collections.BytesKey.WithName("tx_hash"), collections.Uint64Value),
+		BlockBaseFee:     collections.NewMap(sb, types.BlockBaseFeeKey, "block_base_fee", collections.BytesKey.WithName("tx_hash"), sdk.IntValue),
+	}
+
+	schema, err := sb.Build()
+	if err != nil {
+		panic(err)
+	}
+	k.Schema = schema
+
+	return &k
+}
+
+// GetAuthority returns the x/feemarket module's authority.
+func (k *Keeper) GetAuthority() string {
+	return k.authority
+}
+
+// EndBlocker burns the base fee paid by the transactions of the block if
+// BurnBaseFee is set, and computes the base fee of the next block from the gas
+// of the block.
+//
+// The base fee is burned from the fee collector, so the fees of the block must
+// have been credited to it: with the parallel execution, the bank end blocker,
+// which credits the deferred fees, runs first.
+func (k *Keeper) EndBlocker(ctx context.Context) error {
+	params, err := k.Params.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	baseFee, err := k.BaseFee.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	blockGas, blockBaseFee, err := k.blockFees(ctx)
+	if err != nil {
+		return err
+	}
+
+	burned := sdk.NewCoins()
+	if params.BurnBaseFee && blockBaseFee.IsPositive() {
+		burned = sdk.NewCoins(sdk.NewCoin(params.FeeDenom, blockBaseFee))
+		if err := k.bankKeeper.SendCoinsFromModuleToModule(ctx, k.feeCollectorName, types.ModuleName, burned); err != nil {
+			return err
+		}
+		if err := k.bankKeeper.BurnCoins(ctx, k.moduleAddress, burned); err != nil {
+			return err
+		}
+	}
+
+	nextBaseFee := params.NextBaseFee(baseFee, blockGas)
+	if err := k.BaseFee.Set(ctx, nextBaseFee); err != nil {
+		return err
+	}
+	if err := k.BlockGas.Clear(ctx, nil); err != nil {
+		return err
+	}
+	if err := k.BlockBaseFee.Clear(ctx, nil); err != nil {
+		return err
+	}
+
+	return k.EventService.EventManager(ctx).EmitKV(
+		types.EventTypeBaseFee,
+		event.NewAttribute(types.AttributeKeyBaseFee, nextBaseFee.String()),
+		event.NewAttribute(types.AttributeKeyBlockGas, fmt.Sprint(blockGas)),
+		event.NewAttribute(types.AttributeKeyBurned, burned.String()),
+	)
+}
@@ -0,0 +1,204 @@
+package keeper_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"cosmossdk.io/core/header"
+	coretesting "cosmossdk.io/core/testing"
+	"cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+	"cosmossdk.io/x/feemarket"
+	"cosmossdk.io/x/feemarket/keeper"
+	feemarkettestutil "cosmossdk.io/x/feemarket/testutil"
+	"cosmossdk.io/x/feemarket/types"
+
+	codectestutil "github.com/cosmos/cosmos-sdk/codec/testutil"
+	"github.com/cosmos/cosmos-sdk/runtime"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	moduletestutil "github.com/cosmos/cosmos-sdk/types/module/testutil"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+const govModuleNameStr = "cosmos10d07y265gmmuvt4z0w9aw880jnsr700j6zn9kn"
+
+var moduleAddress = authtypes.NewModuleAddress(types.ModuleName)
+
+type KeeperTestSuite struct {
+	suite.Suite
+
+	feeMarketKeeper *keeper.Keeper
+	ctx             sdk.Context
+	msgServer       types.MsgServer
+	queryServer     types.QueryServer
+	accountKeeper   *feemarkettestutil.MockAccountKeeper
+	bankKeeper      *feemarkettestutil.MockBankKeeper
+}
+
+func TestKeeperTestSuite(t *testing.T) {
+	suite.Run(t, new(KeeperTestSuite))
+}
+
+func (s *KeeperTestSuite) SetupTest() {
+	encCfg := moduletestutil.MakeTestEncodingConfig(codectestutil.CodecOptions{}, feemarket.AppModule{})
+	key := storetypes.NewKVStoreKey(types.StoreKey)
+	storeService := runtime.NewKVStoreService(key)
+	env := runtime.NewEnvironment(storeService, coretesting.NewNopLogger())
+	testCtx := testutil.DefaultContextWithDB(s.T(), key, storetypes.NewTransientStoreKey("transient_test"))
+	s.ctx = testCtx.Ctx.WithHeaderInfo(header.Info{Height: 1})
+
+	// gomock initializations
+	ctrl := gomock.NewController(s.T())
+	s.accountKeeper = feemarkettestutil.NewMockAccountKeeper(ctrl)
+	s.bankKeeper = feemarkettestutil.NewMockBankKeeper(ctrl)
+
+	s.accountKeeper.EXPECT().GetModuleAddress(types.ModuleName).Return(sdk.AccAddress(moduleAddress))
+
+	s.feeMarketKeeper = keeper.NewKeeper(
+		encCfg.Codec,
+		env,
+		s.accountKeeper,
+		s.bankKeeper,
+		authtypes.FeeCollectorName,
+		govModuleNameStr,
+	)
+
+	params := types.NewParams("stake", math.LegacyNewDec(1), 1000, 8, true)
+	s.NoError(s.feeMarketKeeper.Params.Set(s.ctx, params))
+	s.NoError(s.feeMarketKeeper.BaseFee.Set(s.ctx, math.LegacyNewDec(10)))
+	s.msgServer = keeper.NewMsgServerImpl(s.feeMarketKeeper)
+	s.queryServer = keeper.NewQueryServerImpl(s.feeMarketKeeper)
+}
+
+// feeTx is a sdk.FeeTx with the given fee and gas.
+type feeTx struct {
+	sdk.FeeTx
+	fee sdk.Coins
+	gas uint64
+}
+
+func (tx feeTx) GetFee() sdk.Coins { return tx.fee }
+
+func (tx feeTx) GetGas() uint64 { return tx.gas }
+
+func (tx feeTx) Hash() [32]byte { return sha256.Sum256([]byte(fmt.Sprint(tx.fee, tx.gas))) }
+
+// blockTxs returns the hashes of the txs whose fees are tracked in the block.
+func (s *KeeperTestSuite) blockTxs(ctx sdk.Context) [][]byte {
+	iter, err := s.feeMarketKeeper.BlockGas.Iterate(ctx, nil)
+	s.Require().NoError(err)
+	hashes, err := iter.Keys()
+	s.Require().NoError(err)
+	return hashes
+}
+
+func (s *KeeperTestSuite) TestCheckTxFee() {
+	ctx := s.ctx.WithExecMode(sdk.ExecModeCheck)
+
+	// the base fee is 10stake per gas
+	_, _, err := s.feeMarketKeeper.CheckTxFee(ctx, feeTx{fee: sdk.NewCoins(sdk.NewInt64Coin("stake", 999)), gas: 100})
+	s.ErrorIs(err, sdkerrors.ErrInsufficientFee)
+	_, _, err = s.feeMarketKeeper.CheckTxFee(ctx, feeTx{fee: sdk.NewCoins(sdk.NewInt64Coin("atom", 1000)), gas: 100})
+	s.ErrorIs(err, sdkerrors.ErrInsufficientFee)
+
+	fee := sdk.NewCoins(sdk.NewInt64Coin("stake", 1500), sdk.NewInt64Coin("atom", 1))
+	effectiveFee, priority, err := s.feeMarketKeeper.CheckTxFee(ctx, feeTx{fee: fee, gas: 100})
+	s.NoError(err)
+	s.Equal(fee, effectiveFee)
+	s.Equal(int64(15), priority)
+
+	// the block fees are only tracked when finalizing a block
+	s.Empty(s.blockTxs(ctx))
+
+	// the fees of every tx are tracked separately
+	ctx = s.ctx.WithExecMode(sdk.ExecModeFinalize)
+	txs := []feeTx{{fee: fee, gas: 100}, {fee: sdk.NewCoins(sdk.NewInt64Coin("stake", 500)), gas: 50}}
+	for _, tx := range txs {
+		_, _, err = s.feeMarketKeeper.CheckTxFee(ctx, tx)
+		s.NoError(err)
+	}
+	s.Len(s.blockTxs(ctx), 2)
+
+	hash := txs[1].Hash()
+	txGas, err := s.feeMarketKeeper.BlockGas.Get(ctx, hash[:])
+	s.NoError(err)
+	s.Equal(uint64(50), txGas)
+	txBaseFee, err := s.feeMarketKeeper.BlockBaseFee.Get(ctx, hash[:])
+	s.NoError(err)
+	s.Equal(math.NewInt(500), txBaseFee)
+}
+
+func (s *KeeperTestSuite) TestCheckTxFee_Genesis() {
+	// the gentxs pay no fees, and may be delivered before the base fee is set
+	s.NoError(s.feeMarketKeeper.BaseFee.Remove(s.ctx))
+	ctx := s.ctx.WithExecMode(sdk.ExecModeFinalize).WithHeaderInfo(header.Info{Height: 0})
+	fee, priority, err := s.feeMarketKeeper.CheckTxFee(ctx, feeTx{gas: 100})
+	s.NoError(err)
+	s.True(fee.IsZero())
+	s.Zero(priority)
+	s.Empty(s.blockTxs(ctx))
+
+	// after genesis, a missing base fee is an error
+	_, _, err = s.feeMarketKeeper.CheckTxFee(s.ctx, feeTx{gas: 100})
+	s.ErrorContains(err, "base fee not set")
+}
+
+func (s *KeeperTestSuite) TestEndBlocker() {
+	ctx := s.ctx.WithExecMode(sdk.ExecModeFinalize)
+	for i := range 3 {
+		_, _, err := s.feeMarketKeeper.CheckTxFee(ctx, feeTx{fee: sdk.NewCoins(sdk.NewInt64Coin("stake", int64(5000+i))), gas: 500})
+		s.NoError(err)
+	}
+
+	// the base fee of the block is burned, the tips are left to the fee collector
+	burned := sdk.NewCoins(sdk.NewInt64Coin("stake", 15000))
+	s.bankKeeper.EXPECT().SendCoinsFromModuleToModule(ctx, authtypes.FeeCollectorName, types.ModuleName, burned).Return(nil)
+	s.bankKeeper.EXPECT().BurnCoins(ctx, []byte(moduleAddress), burned).Return(nil)
+	s.NoError(s.feeMarketKeeper.EndBlocker(ctx))
+
+	// a block of 1500 gas for a target of 1000 increases the base fee by 6.25%
+	baseFee, err := s.feeMarketKeeper.BaseFee.Get(ctx)
+	s.NoError(err)
+	s.Equal(math.LegacyMustNewDecFromStr("10.625"), baseFee)
+	s.Empty(s.blockTxs(ctx))
+	iter, err := s.feeMarketKeeper.BlockBaseFee.Iterate(ctx, nil)
+	s.NoError(err)
+	hashes, err := iter.Keys()
+	s.NoError(err)
+	s.Empty(hashes)
+
+	// an empty block decreases the base fee by 12.5%, and burns nothing
+	s.NoError(s.feeMarketKeeper.EndBlocker(ctx))
+	baseFee, err = s.feeMarketKeeper.BaseFee.Get(ctx)
+	s.NoError(err)
+	s.Equal(math.LegacyMustNewDecFromStr("9.296875"), baseFee)
+
+	// the base fee is left to the fee collector if it is not burned
+	params, err := s.feeMarketKeeper.Params.Get(ctx)
+	s.NoError(err)
+	params.BurnBaseFee = false
+	s.NoError(s.feeMarketKeeper.Params.Set(ctx, params))
+	_, _, err = s.feeMarketKeeper.CheckTxFee(ctx, feeTx{fee: sdk.NewCoins(sdk.NewInt64Coin("stake", 10000)), gas: 1000})
+	s.NoError(err)
+	s.NoError(s.feeMarketKeeper.EndBlocker(ctx))
+	baseFee, err = s.feeMarketKeeper.BaseFee.Get(ctx)
+	s.NoError(err)
+	s.Equal(math.LegacyMustNewDecFromStr("9.296875"), baseFee)
+}
+
+func (s *KeeperTestSuite) TestGenesis() {
+	s.accountKeeper.EXPECT().GetModuleAccount(s.ctx, types.ModuleName).Return(nil)
+
+	genesisState := types.NewGenesisState(types.DefaultParams(), math.LegacyNewDec(2))
+	s.NoError(s.feeMarketKeeper.InitGenesis(s.ctx, s.accountKeeper, genesisState))
+
+	exported, err := s.feeMarketKeeper.ExportGenesis(s.ctx)
+	s.NoError(err)
+	s.Equal(genesisState, exported)
+}
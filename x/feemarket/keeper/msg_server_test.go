@@ -0,0 +1,75 @@
+package keeper_test
+
+import (
+	"cosmossdk.io/math"
+	"cosmossdk.io/x/feemarket/types"
+)
+
+func (s *KeeperTestSuite) TestUpdateParams() {
+	testCases := []struct {
+		name            string
+		request         *types.MsgUpdateParams
+		expectErr       bool
+		expectedBaseFee math.LegacyDec
+	}{
+		{
+			name: "set invalid authority (not an address)",
+			request: &types.MsgUpdateParams{
+				Authority: "foo",
+			},
+			expectErr: true,
+		},
+		{
+			name: "set invalid authority (not defined authority)",
+			request: &types.MsgUpdateParams{
+				Authority: "cosmos139f7kncmglres2nf3h4hc4tade85ekfr8sulz5",
+			},
+			expectErr: true,
+		},
+		{
+			name: "set invalid params",
+			request: &types.MsgUpdateParams{
+				Authority: s.feeMarketKeeper.GetAuthority(),
+				Params:    types.NewParams("stake", math.LegacyNewDec(-1), 1000, 8, true),
+			},
+			expectErr: true,
+		},
+		{
+			name: "set full valid params",
+			request: &types.MsgUpdateParams{
+				Authority: s.feeMarketKeeper.GetAuthority(),
+				Params:    types.NewParams("stake", math.LegacyNewDec(5), 2000, 4, false),
+			},
+			expectErr:       false,
+			expectedBaseFee: math.LegacyNewDec(10),
+		},
+		{
+			name: "set a min base fee above the base fee",
+			request: &types.MsgUpdateParams{
+				Authority: s.feeMarketKeeper.GetAuthority(),
+				Params:    types.NewParams("stake", math.LegacyNewDec(20), 2000, 4, false),
+			},
+			expectErr:       false,
+			expectedBaseFee: math.LegacyNewDec(20),
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			_, err := s.msgServer.UpdateParams(s.ctx, tc.request)
+			if tc.expectErr {
+				s.Require().Error(err)
+				return
+			}
+			s.Require().NoError(err)
+
+			params, err := s.feeMarketKeeper.Params.Get(s.ctx)
+			s.Require().NoError(err)
+			s.Require().Equal(tc.request.Params, params)
+
+			baseFee, err := s.feeMarketKeeper.BaseFee.Get(s.ctx)
+			s.Require().NoError(err)
+			s.Require().Equal(tc.expectedBaseFee, baseFee)
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package types
+
+// Fee market module event types
+const (
+	EventTypeBaseFee = "base_fee"
+
+	AttributeKeyBaseFee  = "base_fee"
+	AttributeKeyBlockGas = "block_gas"
+	AttributeKeyBurned   = "burned"
+)
@@ -0,0 +1,86 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// NewParams returns Params instance with the given values.
+func NewParams(feeDenom string, minBaseFee math.LegacyDec, targetBlockGas, baseFeeChangeDenominator uint64, burnBaseFee bool) Params {
+	return Params{
+		FeeDenom:                 feeDenom,
+		MinBaseFee:               minBaseFee,
+		TargetBlockGas:           targetBlockGas,
+		BaseFeeChangeDenominator: baseFeeChangeDenominator,
+		BurnBaseFee:              burnBaseFee,
+	}
+}
+
+// DefaultParams returns default x/feemarket module parameters.
+func DefaultParams() Params {
+	return Params{
+		FeeDenom:                 sdk.DefaultBondDenom,
+		MinBaseFee:               math.LegacyNewDecWithPrec(1, 3),
+		TargetBlockGas:           10_000_000,
+		BaseFeeChangeDenominator: 8, // the base fee changes by at most 12.5% per block, as in EIP-1559
+		BurnBaseFee:              true,
+	}
+}
+
+// Validate does the sanity check on the params.
+func (p Params) Validate() error {
+	if strings.TrimSpace(p.FeeDenom) == "" {
+		return errors.New("fee denom cannot be blank")
+	}
+	if err := sdk.ValidateDenom(p.FeeDenom); err != nil {
+		return err
+	}
+	if p.MinBaseFee.IsNil() {
+		return errors.New("min base fee cannot be nil")
+	}
+	if p.MinBaseFee.IsNegative() {
+		return fmt.Errorf("min base fee cannot be negative: %s", p.MinBaseFee)
+	}
+	if p.BaseFeeChangeDenominator == 0 {
+		return errors.New("base fee change denominator must be positive")
+	}
+
+	return nil
+}
+
+// NextBaseFee returns the base fee of the next block, given the base fee and the
+// gas used by the current block, following EIP-1559: the base fee moves towards
+// the price at which blocks use TargetBlockGas, by at most
+// 1/BaseFeeChangeDenominator per block. It is never lower than MinBaseFee.
+func (p Params) NextBaseFee(baseFee math.LegacyDec, blockGas uint64) math.LegacyDec {
+	if p.TargetBlockGas == 0 {
+		return math.LegacyMaxDec(baseFee, p.MinBaseFee)
+	}
+
+	var gasDelta uint64
+	if blockGas > p.TargetBlockGas {
+		gasDelta = min(blockGas-p.TargetBlockGas, p.TargetBlockGas)
+	} else {
+		gasDelta = p.TargetBlockGas - blockGas
+	}
+
+	// baseFee * gasDelta / TargetBlockGas / BaseFeeChangeDenominator
+	change := baseFee.
+		MulInt(math.NewIntFromUint64(gasDelta)).
+		QuoInt(math.NewIntFromUint64(p.TargetBlockGas)).
+		QuoInt(math.NewIntFromUint64(p.BaseFeeChangeDenominator))
+
+	next := baseFee.Sub(change)
+	if blockGas > p.TargetBlockGas {
+		// as in EIP-1559, a block above the target always increases the base
+		// fee, so that a zero base fee does not stay zero
+		next = baseFee.Add(math.LegacyMaxDec(change, math.LegacySmallestDec()))
+	}
+
+	return math.LegacyMaxDec(next, p.MinBaseFee)
+}
@@ -0,0 +1,76 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+)
+
+func TestValidate(t *testing.T) {
+	params := DefaultParams()
+	require.NoError(t, params.Validate())
+
+	params2 := NewParams(
+		params.FeeDenom,
+		params.MinBaseFee,
+		params.TargetBlockGas,
+		params.BaseFeeChangeDenominator,
+		params.BurnBaseFee,
+	)
+	require.NoError(t, params2.Validate())
+	require.Equal(t, params, params2)
+
+	params.FeeDenom = ""
+	require.Error(t, params.Validate())
+
+	params.FeeDenom = "asd/$%!@#"
+	require.Error(t, params.Validate())
+
+	params = DefaultParams()
+	params.MinBaseFee = math.LegacyDec{}
+	require.Error(t, params.Validate())
+
+	params = DefaultParams()
+	params.MinBaseFee = math.LegacyNewDec(-1)
+	require.Error(t, params.Validate())
+
+	params = DefaultParams()
+	params.MinBaseFee = math.LegacyZeroDec()
+	require.NoError(t, params.Validate())
+
+	params = DefaultParams()
+	params.BaseFeeChangeDenominator = 0
+	require.Error(t, params.Validate())
+}
+
+func TestNextBaseFee(t *testing.T) {
+	params := NewParams("stake", math.LegacyNewDec(1), 1000, 8, true)
+	baseFee := math.LegacyNewDec(100)
+
+	testCases := []struct {
+		name     string
+		params   Params
+		baseFee  math.LegacyDec
+		blockGas uint64
+		expected math.LegacyDec
+	}{
+		{"at target", params, baseFee, 1000, math.LegacyNewDec(100)},
+		{"full block", params, baseFee, 2000, math.LegacyMustNewDecFromStr("112.5")},
+		{"above full block", params, baseFee, 5000, math.LegacyMustNewDecFromStr("112.5")},
+		{"above target", params, baseFee, 1500, math.LegacyMustNewDecFromStr("106.25")},
+		{"below target", params, baseFee, 500, math.LegacyMustNewDecFromStr("93.75")},
+		{"empty block", params, baseFee, 0, math.LegacyMustNewDecFromStr("87.5")},
+		{"min base fee", params, math.LegacyMustNewDecFromStr("1.1"), 0, math.LegacyNewDec(1)},
+		{"zero base fee", NewParams("stake", math.LegacyZeroDec(), 1000, 8, true), math.LegacyZeroDec(), 2000, math.LegacySmallestDec()},
+		{"constant base fee", NewParams("stake", math.LegacyNewDec(1), 0, 8, true), baseFee, 2000, math.LegacyNewDec(100)},
+		{"constant base fee below min", NewParams("stake", math.LegacyNewDec(1), 0, 8, true), math.LegacyZeroDec(), 0, math.LegacyNewDec(1)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected.String(), tc.params.NextBaseFee(tc.baseFee, tc.blockGas).String())
+		})
+	}
+}